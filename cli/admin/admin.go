@@ -19,6 +19,8 @@ const (
 	adminListFuncName = "__cartridge_admin_list"
 	adminHelpFuncName = "__cartridge_admin_help"
 	adminCallFuncName = "__cartridge_admin_call"
+
+	JSONFormat = "json"
 )
 
 type ProcessAdminFuncType func(conn net.Conn, funcName string, flagSet *pflag.FlagSet, args []string) error
@@ -52,5 +54,19 @@ func Help(conn net.Conn, funcName string, flagSet *pflag.FlagSet, args []string)
 }
 
 func Call(conn net.Conn, funcName string, flagSet *pflag.FlagSet, args []string) error {
-	return adminFuncCall(conn, funcName, flagSet, args)
+	return adminFuncCall(conn, funcName, flagSet, args, false)
+}
+
+// CallJSON calls admin function and prints its return value as JSON
+func CallJSON(conn net.Conn, funcName string, flagSet *pflag.FlagSet, args []string) error {
+	return adminFuncCall(conn, funcName, flagSet, args, true)
+}
+
+// CheckResultFormat checks that specified result format is supported
+func CheckResultFormat(format string) error {
+	if format != JSONFormat {
+		return fmt.Errorf("Unsupported format %q. Supported formats are: %s", format, JSONFormat)
+	}
+
+	return nil
 }
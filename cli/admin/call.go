@@ -1,8 +1,11 @@
 package admin
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"net"
+	"os"
 	"strings"
 
 	"github.com/apex/log"
@@ -36,7 +39,7 @@ type FuncCallArg struct {
 	Changed bool
 }
 
-func adminFuncCall(conn net.Conn, funcName string, flagSet *pflag.FlagSet, args []string) error {
+func adminFuncCall(conn net.Conn, funcName string, flagSet *pflag.FlagSet, args []string, jsonOutput bool) error {
 	funcCallArgs, err := getFuncCallArgs(conn, funcName, flagSet, args)
 	if err != nil {
 		return fmt.Errorf("Failed to parse function call args: %s", err)
@@ -47,19 +50,42 @@ func adminFuncCall(conn net.Conn, funcName string, flagSet *pflag.FlagSet, args
 		return fmt.Errorf("Failed to serialize function args: %s", err)
 	}
 
-	callFuncBody, err := templates.GetTemplatedStr(&adminCallFuncBodyTmpl, map[string]string{
-		"AdminCallFuncName": adminCallFuncName,
-		"FuncName":          funcName,
-		"Args":              argsSerialized,
+	callFuncBody, err := templates.GetTemplatedStr(&adminCallFuncBodyTmpl, map[string]interface{}{
+		"AdminCallFuncName":     adminCallFuncName,
+		"FuncName":              funcName,
+		"Args":                  argsSerialized,
+		"SanitizeRes":           jsonOutput,
+		"NonSerializableFormat": common.NonSerializablePlaceholderFmt,
 	})
+	if err != nil {
+		return project.InternalError("Failed to compute function call body: %s", err)
+	}
+
+	// messages are printed to stderr to keep stdout a valid JSON
+	messagesWriter := os.Stdout
+	if jsonOutput {
+		messagesWriter = os.Stderr
+	}
 
 	callResRaw, err := common.EvalTarantoolConn(conn, callFuncBody, common.ConnOpts{
-		PushCallback: printMessage,
+		PushCallback: func(receivedString string) {
+			printMessage(receivedString, messagesWriter)
+		},
 	})
 	if err != nil {
 		return fmt.Errorf("Failed to call %q: %s", funcName, err)
 	}
 
+	if jsonOutput {
+		callResJSON, err := getCallResJSON(callResRaw)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(callResJSON)
+		return nil
+	}
+
 	printCallRes(callResRaw)
 
 	return nil
@@ -189,13 +215,29 @@ func printCallRes(callResRaw interface{}) {
 	}
 }
 
-func printMessage(receivedString string) {
+// getCallResJSON returns function return value encoded to JSON.
+// Values that can't be serialized are replaced with placeholders
+func getCallResJSON(callResRaw interface{}) (string, error) {
+	var b strings.Builder
+
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(common.NormalizeYAMLValue(callResRaw)); err != nil {
+		return "", fmt.Errorf("Failed to encode function return value to JSON: %s", err)
+	}
+
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+func printMessage(receivedString string, w io.Writer) {
 	parts := strings.SplitN(receivedString, "\n", 2)
 	msgEncoded := parts[1]
 
 	var msg string
 	if err := yaml.UnmarshalStrict([]byte(msgEncoded), &msg); err != nil {
-		fmt.Printf("%s", msgEncoded)
+		fmt.Fprintf(w, "%s", msgEncoded)
 		return
 	}
 
@@ -208,6 +250,52 @@ var (
 		'{{ .FuncName }}',
 		{{ .Args }}
 	)
+{{- if .SanitizeRes }}
+
+	-- values that can't be encoded are replaced with placeholders
+	-- to not fail the whole result encoding
+	local yaml = require('yaml').new()
+
+	local function sanitize(value, seen)
+		local value_type = type(value)
+
+		if value_type == 'table' then
+			if seen[value] then
+				return string.format('{{ .NonSerializableFormat }}', 'recursive table')
+			end
+
+			seen[value] = true
+			local res = {}
+			for k, v in pairs(value) do
+				res[sanitize(k, seen)] = sanitize(v, seen)
+			end
+			seen[value] = nil
+
+			return res
+		end
+
+		if value_type == 'cdata' and box.tuple.is(value) then
+			return sanitize(value:totable(), seen)
+		end
+
+		if value_type == 'userdata' or value_type == 'cdata' then
+			if pcall(yaml.encode, value) then
+				return value
+			end
+		end
+
+		if value_type == 'function' or value_type == 'thread'
+				or value_type == 'userdata' or value_type == 'cdata' then
+			return string.format('{{ .NonSerializableFormat }}', value_type)
+		end
+
+		return value
+	end
+
+	if func_help ~= nil then
+		func_help = sanitize(func_help, {})
+	end
+{{- end }}
 	return func_help, err
 `
 )
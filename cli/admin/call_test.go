@@ -0,0 +1,50 @@
+package admin
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCallResJSON(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	callResJSON, err := getCallResJSON("Hi, <Elizabeth>!")
+	assert.Nil(err)
+	assert.Equal(`"Hi, <Elizabeth>!"`, callResJSON)
+
+	callResJSON, err = getCallResJSON(nil)
+	assert.Nil(err)
+	assert.Equal(`null`, callResJSON)
+
+	callResJSON, err = getCallResJSON(map[interface{}]interface{}{
+		"name":   "Elizabeth",
+		"cakes":  []interface{}{"cheesecake", "tiramisu"},
+		"opaque": "<non-serializable: userdata>",
+		"ratio":  math.NaN(),
+		1:        true,
+	})
+	assert.Nil(err)
+	assert.Equal(`{
+  "1": true,
+  "cakes": [
+    "cheesecake",
+    "tiramisu"
+  ],
+  "name": "Elizabeth",
+  "opaque": "<non-serializable: userdata>",
+  "ratio": "<non-serializable: NaN>"
+}`, callResJSON)
+}
+
+func TestCheckResultFormat(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	assert.Nil(CheckResultFormat("json"))
+	assert.EqualError(CheckResultFormat("yaml"), `Unsupported format "yaml". Supported formats are: json`)
+}
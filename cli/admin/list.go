@@ -17,26 +17,39 @@ func adminFuncList(conn net.Conn) error {
 	}
 
 	if len(listResRawMap) == 0 {
-		return fmt.Errorf("No available admin functions found")
+		log.Infof("No admin functions registered")
+		return nil
 	}
 
+	funcUsages, err := getFuncUsages(listResRawMap)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Available admin functions:\n\n%s", funcUsages.Format())
+
+	return nil
+}
+
+// getFuncUsages returns functions usages sorted by name
+func getFuncUsages(listResRawMap map[interface{}]interface{}) (NameUsages, error) {
 	funcUsages := make(NameUsages, len(listResRawMap))
 
 	i := 0
 	for funcNameRaw, funcSpecRaw := range listResRawMap {
 		funcName, ok := funcNameRaw.(string)
 		if !ok {
-			return getCliExtError("Functions map key isn't a string: %#v", funcNameRaw)
+			return nil, getCliExtError("Functions map key isn't a string: %#v", funcNameRaw)
 		}
 
 		adminFuncSpec, ok := funcSpecRaw.(map[interface{}]interface{})
 		if !ok {
-			return getCliExtError("Function %q spec isn't a map: %#v", funcName, funcSpecRaw)
+			return nil, getCliExtError("Function %q spec isn't a map: %#v", funcName, funcSpecRaw)
 		}
 
 		funcUsage, err := getStrValueFromRawMap(adminFuncSpec, "usage")
 		if err != nil {
-			return getCliExtError("Failed to get function %q usage: %s", funcName, err)
+			return nil, getCliExtError("Failed to get function %q usage: %s", funcName, err)
 		}
 
 		funcUsages[i] = NameUsage{
@@ -48,9 +61,8 @@ func adminFuncList(conn net.Conn) error {
 	}
 
 	sort.Sort(funcUsages)
-	log.Infof("Available admin functions:\n\n%s", funcUsages.Format())
 
-	return nil
+	return funcUsages, nil
 }
 
 func getFuncListRawMap(conn net.Conn) (map[interface{}]interface{}, error) {
@@ -63,6 +75,16 @@ func getFuncListRawMap(conn net.Conn) (map[interface{}]interface{}, error) {
 		return nil, fmt.Errorf("Failed to call %s(): %s", adminListFuncName, err)
 	}
 
+	// no functions are registered
+	// (empty table is encoded as an empty list)
+	if listResRaw == nil {
+		return nil, nil
+	}
+
+	if listResRawSlice, ok := listResRaw.([]interface{}); ok && len(listResRawSlice) == 0 {
+		return nil, nil
+	}
+
 	listResRawMap, err := convertToMap(listResRaw)
 	if err != nil {
 		return nil, getCliExtError("Failed to convert %q return value to map", adminListFuncName)
@@ -73,7 +95,13 @@ func getFuncListRawMap(conn net.Conn) (map[interface{}]interface{}, error) {
 
 var (
 	adminListFuncBodyTmpl = `
-	local func_help, err = {{ .AdminListFuncName }}('{{ .FuncName }}')
-	return func_help, err
+	if rawget(_G, '{{ .AdminListFuncName }}') == nil then
+		return nil, "{{ .AdminListFuncName }} function isn't found. " ..
+			"Make sure that cartridge-cli-extensions module is installed and " ..
+			"admin extension is initialized (see cartridge-cli-extensions docs)"
+	end
+
+	local func_list, err = {{ .AdminListFuncName }}()
+	return func_list, err
 `
 )
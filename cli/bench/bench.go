@@ -0,0 +1,238 @@
+package bench
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/FZambia/tarantool"
+	"github.com/apex/log"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+	"github.com/tarantool/cartridge-cli/cli/connect"
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+
+	benchSpaceNamePrefix = "cartridge_cli_bench_"
+
+	// the space has a unique name, so it doesn't touch the application data.
+	// It requires the instance to be writable.
+	// Space ID is returned to be used in the native requests
+	createSpaceFuncBody = `
+local space_name = ...
+local space = box.schema.space.create(space_name, {
+	format = {
+		{name = 'id', type = 'unsigned'},
+		{name = 'payload', type = 'string'},
+	},
+})
+space:create_index('primary', {parts = {'id'}})
+return space.id
+`
+
+	dropSpaceFuncBody = `
+local space_name = ...
+if box.space[space_name] ~= nil then
+	box.space[space_name]:drop()
+end
+`
+
+	benchPrimaryIndexID = uint32(0)
+)
+
+// worker runs the benchmark requests over its own connection
+type worker struct {
+	conn *tarantool.Connection
+	rand *rand.Rand
+
+	latencies []time.Duration
+	errorsNum int
+	lastErr   error
+}
+
+// Run creates the scratch space on the instance, runs the insert/select workload
+// over the specified number of connections and prints the results.
+// The scratch space is dropped after the benchmark
+func Run(ctx *context.Ctx, connString string) error {
+	connOpts, err := connect.GetConnOpts(connString, ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to get connection opts: %s", err)
+	}
+
+	setupConn, err := connectToInstance(connOpts)
+	if err != nil {
+		return err
+	}
+	defer setupConn.Close()
+
+	// benchmark is stopped on SIGINT and SIGTERM,
+	// so the scratch space is dropped and the collected results are shown
+	stopCh := make(chan os.Signal, 1)
+	signal.Notify(stopCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(stopCh)
+
+	spaceName := benchSpaceNamePrefix + common.RandomString(8)
+
+	log.Debugf("Create space %s", spaceName)
+	resp, err := setupConn.Exec(tarantool.Eval(createSpaceFuncBody, []interface{}{spaceName}))
+	if err != nil {
+		return fmt.Errorf("Failed to create benchmark space: %s", err)
+	}
+
+	defer func() {
+		log.Debugf("Drop space %s", spaceName)
+		if _, err := setupConn.Exec(tarantool.Eval(dropSpaceFuncBody, []interface{}{spaceName})); err != nil {
+			log.Warnf("Failed to drop benchmark space %s: %s", spaceName, err)
+		}
+	}()
+
+	spaceID, err := getSpaceID(resp.Data)
+	if err != nil {
+		return fmt.Errorf("Failed to get benchmark space ID: %s", err)
+	}
+
+	workers := make([]*worker, ctx.Bench.Connections)
+	for i := range workers {
+		conn, err := connectToInstance(connOpts)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		workers[i] = &worker{
+			conn: conn,
+			rand: rand.New(rand.NewSource(time.Now().UnixNano() + int64(i))),
+		}
+	}
+
+	if ctx.Bench.Count > 0 {
+		log.Infof("Run %d requests over %d connection(s)", ctx.Bench.Count, ctx.Bench.Connections)
+	} else {
+		log.Infof("Run benchmark for %s over %d connection(s)", ctx.Bench.Duration, ctx.Bench.Connections)
+	}
+
+	elapsed := runWorkers(ctx, spaceID, workers, stopCh)
+
+	results := getResults(workers, elapsed)
+
+	for _, w := range workers {
+		if w.lastErr != nil {
+			log.Debugf("Request failed: %s", w.lastErr)
+		}
+	}
+
+	return printResults(os.Stdout, results, ctx.Bench.Format)
+}
+
+func connectToInstance(connOpts *connect.ConnOpts) (*tarantool.Connection, error) {
+	connectStr := fmt.Sprintf("%s://%s", connOpts.Network, connOpts.Address)
+
+	conn, err := tarantool.Connect(connectStr, tarantool.Opts{
+		User:     connOpts.Username,
+		Password: connOpts.Password,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to %s: %s", connOpts.Address, err)
+	}
+
+	return conn, nil
+}
+
+// getSpaceID returns the space ID returned by the space creation function
+func getSpaceID(data []interface{}) (uint32, error) {
+	if len(data) == 0 {
+		return 0, fmt.Errorf("Space ID isn't returned")
+	}
+
+	// the number type depends on the value
+	spaceID, err := strconv.ParseUint(fmt.Sprint(data[0]), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("Space ID isn't a number: %#v", data[0])
+	}
+
+	return uint32(spaceID), nil
+}
+
+// runWorkers runs workers until the requests count or the duration is reached
+// or the stop signal is received.
+// It returns the elapsed time
+func runWorkers(ctx *context.Ctx, spaceID uint32, workers []*worker, stopCh <-chan os.Signal) time.Duration {
+	payload := common.RandomString(ctx.Bench.PayloadSize)
+
+	// lastID is the last inserted tuple ID, selected IDs are taken from [1, lastID]
+	var lastID int64
+	// requestsLeft is used only if the requests count is specified
+	requestsLeft := int64(ctx.Bench.Count)
+
+	var stopped int32
+	if ctx.Bench.Count == 0 {
+		time.AfterFunc(ctx.Bench.Duration, func() {
+			atomic.StoreInt32(&stopped, 1)
+		})
+	}
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	go func() {
+		select {
+		case <-stopCh:
+			log.Warnf("Benchmark is interrupted")
+			atomic.StoreInt32(&stopped, 1)
+		case <-doneCh:
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(len(workers))
+
+	startTime := time.Now()
+
+	for _, w := range workers {
+		go func(w *worker) {
+			defer wg.Done()
+
+			for atomic.LoadInt32(&stopped) == 0 {
+				if ctx.Bench.Count > 0 && atomic.AddInt64(&requestsLeft, -1) < 0 {
+					return
+				}
+
+				var request *tarantool.Request
+
+				maxID := atomic.LoadInt64(&lastID)
+				if maxID > 0 && w.rand.Float64() < ctx.Bench.ReadRatio {
+					id := uint64(w.rand.Int63n(maxID) + 1)
+					request = tarantool.Select(
+						spaceID, benchPrimaryIndexID, 0, 1, tarantool.IterEq, []interface{}{id},
+					)
+				} else {
+					id := uint64(atomic.AddInt64(&lastID, 1))
+					request = tarantool.Insert(spaceID, []interface{}{id, payload})
+				}
+
+				requestStartTime := time.Now()
+				_, err := w.conn.Exec(request)
+				w.latencies = append(w.latencies, time.Since(requestStartTime))
+
+				if err != nil {
+					w.errorsNum++
+					w.lastErr = err
+				}
+			}
+		}(w)
+	}
+
+	wg.Wait()
+
+	return time.Since(startTime)
+}
@@ -0,0 +1,27 @@
+package bench
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSpaceID(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	spaceID, err := getSpaceID([]interface{}{uint64(512)})
+	assert.Nil(err)
+	assert.Equal(uint32(512), spaceID)
+
+	spaceID, err = getSpaceID([]interface{}{int16(513)})
+	assert.Nil(err)
+	assert.Equal(uint32(513), spaceID)
+
+	_, err = getSpaceID([]interface{}{})
+	assert.EqualError(err, "Space ID isn't returned")
+
+	_, err = getSpaceID([]interface{}{"space"})
+	assert.EqualError(err, `Space ID isn't a number: "space"`)
+}
@@ -0,0 +1,125 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// latencyPercentiles are request latencies in milliseconds
+type latencyPercentiles struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+type benchResults struct {
+	Requests    int                `json:"requests"`
+	Errors      int                `json:"errors"`
+	DurationSec float64            `json:"duration_sec"`
+	OpsPerSec   float64            `json:"ops_per_sec"`
+	LatencyMs   latencyPercentiles `json:"latency_ms"`
+}
+
+// CheckFormat checks that the results output format is supported
+func CheckFormat(format string) error {
+	switch format {
+	case FormatText, FormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("Unknown format %q. Supported formats are %s and %s",
+			format, FormatText, FormatJSON)
+	}
+}
+
+func getResults(workers []*worker, elapsed time.Duration) *benchResults {
+	results := benchResults{
+		DurationSec: elapsed.Seconds(),
+	}
+
+	latencies := make([]time.Duration, 0)
+	for _, w := range workers {
+		latencies = append(latencies, w.latencies...)
+		results.Errors += w.errorsNum
+	}
+
+	results.Requests = len(latencies)
+	if elapsed > 0 {
+		results.OpsPerSec = float64(results.Requests) / elapsed.Seconds()
+	}
+
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+
+	results.LatencyMs = latencyPercentiles{
+		P50: getPercentileMs(latencies, 50),
+		P90: getPercentileMs(latencies, 90),
+		P99: getPercentileMs(latencies, 99),
+		Max: getPercentileMs(latencies, 100),
+	}
+
+	return &results
+}
+
+// getPercentileMs returns the nearest-rank percentile of the sorted latencies
+func getPercentileMs(sortedLatencies []time.Duration, percentile float64) float64 {
+	if len(sortedLatencies) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(percentile / 100 * float64(len(sortedLatencies))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return float64(sortedLatencies[rank-1]) / float64(time.Millisecond)
+}
+
+// printResults writes the benchmark results in the specified format to w
+func printResults(w io.Writer, results *benchResults, format string) error {
+	var data []byte
+	var err error
+
+	switch format {
+	case FormatText:
+		data = []byte(getResultsText(results))
+	case FormatJSON:
+		if data, err = json.MarshalIndent(results, "", "  "); err == nil {
+			data = append(data, '\n')
+		}
+	default:
+		return fmt.Errorf("Unknown format %q", format)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed to encode benchmark results: %s", err)
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("Failed to write benchmark results: %s", err)
+	}
+
+	return nil
+}
+
+func getResultsText(results *benchResults) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Requests:     %d\n", results.Requests)
+	fmt.Fprintf(&b, "Errors:       %d\n", results.Errors)
+	fmt.Fprintf(&b, "Duration:     %.2fs\n", results.DurationSec)
+	fmt.Fprintf(&b, "Ops/sec:      %.2f\n", results.OpsPerSec)
+	fmt.Fprintf(&b, "Latency (ms):\n")
+	fmt.Fprintf(&b, "  p50:        %.3f\n", results.LatencyMs.P50)
+	fmt.Fprintf(&b, "  p90:        %.3f\n", results.LatencyMs.P90)
+	fmt.Fprintf(&b, "  p99:        %.3f\n", results.LatencyMs.P99)
+	fmt.Fprintf(&b, "  max:        %.3f\n", results.LatencyMs.Max)
+
+	return b.String()
+}
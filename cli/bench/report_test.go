@@ -0,0 +1,104 @@
+package bench
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetResults(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	// no requests
+	results := getResults([]*worker{{}}, time.Second)
+	assert.Equal(benchResults{DurationSec: 1}, *results)
+
+	workers := []*worker{{errorsNum: 1}, {errorsNum: 2}}
+	for i := 1; i <= 100; i++ {
+		w := workers[i%2]
+		w.latencies = append(w.latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	results = getResults(workers, 2*time.Second)
+	assert.Equal(benchResults{
+		Requests:    100,
+		Errors:      3,
+		DurationSec: 2,
+		OpsPerSec:   50,
+		LatencyMs: latencyPercentiles{
+			P50: 50,
+			P90: 90,
+			P99: 99,
+			Max: 100,
+		},
+	}, *results)
+}
+
+func TestGetPercentileMs(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	latencies := []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond}
+
+	assert.Equal(0.0, getPercentileMs(nil, 50))
+	assert.Equal(1.0, getPercentileMs(latencies, 0))
+	assert.Equal(1.0, getPercentileMs(latencies, 33))
+	assert.Equal(2.0, getPercentileMs(latencies, 50))
+	assert.Equal(4.0, getPercentileMs(latencies, 99))
+	assert.Equal(4.0, getPercentileMs(latencies, 100))
+}
+
+func TestPrintResults(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	results := &benchResults{
+		Requests:    1000,
+		Errors:      2,
+		DurationSec: 2,
+		OpsPerSec:   500,
+		LatencyMs: latencyPercentiles{
+			P50: 1.5,
+			P90: 2.25,
+			P99: 4,
+			Max: 10.125,
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.Nil(printResults(&buf, results, FormatJSON))
+	assert.Equal(`{
+  "requests": 1000,
+  "errors": 2,
+  "duration_sec": 2,
+  "ops_per_sec": 500,
+  "latency_ms": {
+    "p50": 1.5,
+    "p90": 2.25,
+    "p99": 4,
+    "max": 10.125
+  }
+}
+`, buf.String())
+
+	buf.Reset()
+	assert.Nil(printResults(&buf, results, FormatText))
+	assert.Equal(`Requests:     1000
+Errors:       2
+Duration:     2.00s
+Ops/sec:      500.00
+Latency (ms):
+  p50:        1.500
+  p90:        2.250
+  p99:        4.000
+  max:        10.125
+`, buf.String())
+
+	assert.EqualError(printResults(&buf, results, "yaml"), `Unknown format "yaml"`)
+}
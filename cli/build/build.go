@@ -3,6 +3,8 @@ package build
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/apex/log"
 
@@ -14,6 +16,8 @@ import (
 const (
 	preBuildHookName  = "cartridge.pre-build"
 	postBuildHookName = "cartridge.post-build"
+
+	buildTargetEnv = "CARTRIDGE_BUILD_TARGET"
 )
 
 // Run builds project in ctx.Build.Dir
@@ -36,11 +40,17 @@ func Run(ctx *context.Ctx) error {
 
 	log.Infof("Build application in %s", ctx.Build.Dir)
 
+	// rocks cache dir is used from the build directory and docker volume
+	if ctx.Build.RocksCacheDir != "" {
+		var err error
+		if ctx.Build.RocksCacheDir, err = filepath.Abs(ctx.Build.RocksCacheDir); err != nil {
+			return fmt.Errorf("Failed to get rocks cache directory absolute path: %s", err)
+		}
+	}
+
 	// check that application directory contains rockspec
-	if rockspecPath, err := common.FindRockspec(ctx.Project.Path); err != nil {
+	if err := checkRockspec(ctx); err != nil {
 		return err
-	} else if rockspecPath == "" {
-		return fmt.Errorf("Application directory should contain rockspec")
 	}
 
 	if ctx.Build.InDocker {
@@ -68,6 +78,60 @@ func FillCtx(ctx *context.Ctx) error {
 	return nil
 }
 
+// checkRockspec checks that the rockspec to build can be chosen.
+// If ctx.Build.Spec isn't specified, application directory
+// should contain exactly one rockspec
+func checkRockspec(ctx *context.Ctx) error {
+	if ctx.Build.Spec != "" {
+		specPath := getSpecPath(ctx)
+
+		if !strings.HasSuffix(specPath, ".rockspec") {
+			return fmt.Errorf("Specified --spec %s isn't a rockspec", ctx.Build.Spec)
+		}
+
+		if fileInfo, err := os.Stat(specPath); err != nil {
+			return fmt.Errorf("Unable to use specified --spec: %s", err)
+		} else if !fileInfo.Mode().IsRegular() {
+			return fmt.Errorf("Specified --spec %s isn't a regular file", ctx.Build.Spec)
+		}
+
+		return nil
+	}
+
+	rockspecs, err := common.FindRockspecs(ctx.Project.Path)
+	if err != nil {
+		return err
+	}
+
+	if len(rockspecs) == 0 {
+		return fmt.Errorf("Application directory should contain rockspec")
+	}
+
+	if len(rockspecs) > 1 {
+		rockspecNames := make([]string, len(rockspecs))
+		for i, rockspec := range rockspecs {
+			rockspecNames[i] = filepath.Base(rockspec)
+		}
+
+		return fmt.Errorf(
+			"Found multiple rockspecs in %s:\n  %s\nPlease, specify the rockspec to build via --spec",
+			ctx.Project.Path, strings.Join(rockspecNames, "\n  "),
+		)
+	}
+
+	return nil
+}
+
+// getSpecPath returns the path of the specified rockspec in the build directory.
+// Relative path is considered relative to the application directory
+func getSpecPath(ctx *context.Ctx) string {
+	if filepath.IsAbs(ctx.Build.Spec) {
+		return ctx.Build.Spec
+	}
+
+	return filepath.Join(ctx.Build.Dir, ctx.Build.Spec)
+}
+
 func checkCtx(ctx *context.Ctx) error {
 	if ctx.Build.Dir == "" {
 		return fmt.Errorf("BuildDir is missed")
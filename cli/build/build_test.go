@@ -0,0 +1,57 @@
+package build
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func TestCheckRockspec(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	projectDir, err := ioutil.TempDir("", "project")
+	assert.Nil(err)
+	defer os.RemoveAll(projectDir)
+
+	var ctx context.Ctx
+	ctx.Project.Path = projectDir
+	ctx.Build.Dir = projectDir
+
+	// no rockspecs
+	assert.EqualError(checkRockspec(&ctx), "Application directory should contain rockspec")
+
+	// one rockspec
+	assert.Nil(ioutil.WriteFile(filepath.Join(projectDir, "myapp-scm-1.rockspec"), []byte{}, 0644))
+	assert.Nil(checkRockspec(&ctx))
+
+	// multiple rockspecs
+	assert.Nil(ioutil.WriteFile(filepath.Join(projectDir, "mymodule-scm-1.rockspec"), []byte{}, 0644))
+	assert.EqualError(checkRockspec(&ctx), "Found multiple rockspecs in "+projectDir+":\n"+
+		"  myapp-scm-1.rockspec\n"+
+		"  mymodule-scm-1.rockspec\n"+
+		"Please, specify the rockspec to build via --spec")
+
+	// specified rockspec
+	ctx.Build.Spec = "mymodule-scm-1.rockspec"
+	assert.Nil(checkRockspec(&ctx))
+	assert.Equal(filepath.Join(projectDir, "mymodule-scm-1.rockspec"), getSpecPath(&ctx))
+
+	ctx.Build.Spec = filepath.Join(projectDir, "myapp-scm-1.rockspec")
+	assert.Nil(checkRockspec(&ctx))
+	assert.Equal(ctx.Build.Spec, getSpecPath(&ctx))
+
+	ctx.Build.Spec = "init.lua"
+	assert.EqualError(checkRockspec(&ctx), "Specified --spec init.lua isn't a rockspec")
+
+	ctx.Build.Spec = "other-scm-1.rockspec"
+	err = checkRockspec(&ctx)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Unable to use specified --spec")
+}
@@ -24,7 +24,8 @@ func buildProjectInDocker(ctx *context.Ctx) error {
 	}
 
 	log.Debugf("Check specified base build Dockerfile")
-	if ctx.Build.DockerFrom != "" {
+	// base image check is skipped if it's replaced by --build-base-image
+	if ctx.Build.DockerFrom != "" && ctx.Build.BaseImage == "" {
 		if err := project.CheckBaseDockerfile(ctx.Build.DockerFrom); err != nil {
 			return fmt.Errorf("Invalid base build Dockerfile %s: %s", ctx.Build.DockerFrom, err)
 		}
@@ -83,13 +84,20 @@ func buildProjectInDocker(ctx *context.Ctx) error {
 
 	// create build image
 	buildImageTag := fmt.Sprintf("%s-build", ctx.Project.Name)
-	log.Infof("Building base image %s", buildImageTag)
+	if ctx.Docker.Platform != "" {
+		log.Infof("Building base image %s for %s", buildImageTag, ctx.Docker.Platform)
+	} else {
+		log.Infof("Building base image %s", buildImageTag)
+	}
 
 	err = docker.BuildImage(docker.BuildOpts{
 		Tag:        []string{buildImageTag},
 		Dockerfile: buildImageDockerfileName,
 		NoCache:    ctx.Docker.NoCache,
 		CacheFrom:  ctx.Docker.CacheFrom,
+		Platform:   ctx.Docker.Platform,
+		BuildArgs:  ctx.Docker.BuildArgs,
+		Secrets:    ctx.Docker.Secrets,
 
 		BuildDir:   ctx.Build.Dir,
 		TmpDir:     ctx.Cli.TmpDir,
@@ -100,14 +108,49 @@ func buildProjectInDocker(ctx *context.Ctx) error {
 		return fmt.Errorf("Failed to build base image: %s", err)
 	}
 
-	// create build script
-	log.Debugf("Create build script")
-	buildScriptName := fmt.Sprintf("build.%s.sh", ctx.Build.ID)
+	rocksCacheFlavor := fmt.Sprintf(
+		"docker %s %s %s", ctx.Docker.Platform, ctx.Tarantool.TarantoolVersion, ctx.Build.BaseImage,
+	)
+
+	return buildWithRocksCache(ctx, rocksCacheFlavor, func(useCache bool) error {
+		return runBuildContainer(ctx, buildImageTag, useCache)
+	})
+}
+
+// runBuildContainer runs the build script in the build image container.
+// If useCache is set, the rocks cache dir is mounted to the container
+// and luarocks local_cache is set to it
+func runBuildContainer(ctx *context.Ctx, buildImageTag string, useCache bool) error {
+	volumes := map[string]string{
+		ctx.Build.Dir: containerBuildDir,
+	}
 
 	buildScriptCtx := map[string]interface{}{
-		"PreBuildHookName": preBuildHookName,
+		"PreBuildHookName":   preBuildHookName,
+		"LuarocksConfigEnv":  luarocksConfigEnv,
+		"LuarocksConfigPath": "",
 	}
 
+	if useCache {
+		// config is created in the build dir to be available in the container
+		configPath, cleanup, err := writeRocksCacheConfig(
+			ctx.Build.Dir,
+			filepath.Join(containerRocksCacheDir, rocksCacheDownloadsDir),
+			"",
+		)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
+		volumes[ctx.Build.RocksCacheDir] = containerRocksCacheDir
+		buildScriptCtx["LuarocksConfigPath"] = filepath.Join(containerBuildDir, filepath.Base(configPath))
+	}
+
+	// create build script
+	log.Debugf("Create build script")
+	buildScriptName := fmt.Sprintf("build.%s.sh", ctx.Build.ID)
+
 	buildScriptTemplate := getBuildScriptTemplate(ctx)
 	buildScriptTemplate.Path = buildScriptName
 	if err := buildScriptTemplate.Instantiate(ctx.Build.Dir, buildScriptCtx); err != nil {
@@ -121,14 +164,12 @@ func buildProjectInDocker(ctx *context.Ctx) error {
 	// run build script on image
 	log.Infof("Build application in %s", buildImageTag)
 
-	err = docker.RunContainer(docker.RunOpts{
+	err := docker.RunContainer(docker.RunOpts{
 		ImageTags:  buildImageTag,
 		WorkingDir: containerBuildDir,
 		Cmd:        []string{fmt.Sprintf("./%s", buildScriptName)},
 
-		Volumes: map[string]string{
-			ctx.Build.Dir: containerBuildDir,
-		},
+		Volumes: volumes,
 
 		ShowOutput: ctx.Cli.Verbose,
 		Debug:      ctx.Cli.Debug,
@@ -197,6 +238,10 @@ const (
 	buildScriptContent = `#!/bin/bash
 set -xe
 
+{{- if .LuarocksConfigPath }}
+export {{ .LuarocksConfigEnv }}={{ .LuarocksConfigPath }}
+{{- end }}
+
 if [ -f {{ .PreBuildHookName }} ]; then
     . {{ .PreBuildHookName }}
 fi
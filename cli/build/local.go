@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 
 	"github.com/apex/log"
 
@@ -19,11 +20,62 @@ func buildProjectLocally(ctx *context.Ctx) error {
 	common.CheckRecommendedBinaries("cmake", "make", "git", "unzip", "gcc")
 
 	// pre-build
+	if err := runPreBuildHook(ctx); err != nil {
+		return err
+	}
+
+	// tarantoolctl rocks make
+	rocksMakeArgs := []string{"rocks", "make"}
+	if ctx.Build.Spec != "" {
+		rocksMakeArgs = append(rocksMakeArgs, getSpecPath(ctx))
+		log.Infof("Running `tarantoolctl rocks make %s`", ctx.Build.Spec)
+	} else {
+		log.Infof("Running `tarantoolctl rocks make`")
+	}
+
+	rocksCacheFlavor := fmt.Sprintf("local %s/%s %s", runtime.GOOS, runtime.GOARCH, ctx.Tarantool.TarantoolVersion)
+
+	return buildWithRocksCache(ctx, rocksCacheFlavor, func(useCache bool) error {
+		rocksMakeCmd := exec.Command("tarantoolctl", rocksMakeArgs...)
+
+		if useCache {
+			configPath, cleanup, err := writeRocksCacheConfig(
+				"",
+				filepath.Join(ctx.Build.RocksCacheDir, rocksCacheDownloadsDir),
+				os.Getenv(luarocksConfigEnv),
+			)
+			if err != nil {
+				return err
+			}
+			defer cleanup()
+
+			rocksMakeCmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", luarocksConfigEnv, configPath))
+		}
+
+		err := common.RunCommand(rocksMakeCmd, ctx.Build.Dir, ctx.Cli.Verbose)
+		if err != nil {
+			return fmt.Errorf("Failed to install rocks: %s", err)
+		}
+
+		return nil
+	})
+}
+
+// runPreBuildHook runs pre-build hook if it exists.
+// Build target is passed to the hook via CARTRIDGE_BUILD_TARGET
+func runPreBuildHook(ctx *context.Ctx) error {
 	preBuildHookPath := filepath.Join(ctx.Build.Dir, preBuildHookName)
 
 	if _, err := os.Stat(preBuildHookPath); err == nil {
-		log.Infof("Running `%s`", preBuildHookName)
-		err = common.RunHook(preBuildHookPath, ctx.Cli.Verbose)
+		var env []string
+		if ctx.Build.Target != "" {
+			log.Infof("Running `%s` with target %s", preBuildHookName, ctx.Build.Target)
+			env = append(env, fmt.Sprintf("%s=%s", buildTargetEnv, ctx.Build.Target))
+		} else {
+			log.Infof("Running `%s`", preBuildHookName)
+		}
+
+		err = common.RunHookWithEnv(preBuildHookPath, env, ctx.Cli.Verbose)
 		if err != nil {
 			return fmt.Errorf("Failed to run pre-build hook: %s", err)
 		}
@@ -31,13 +83,5 @@ func buildProjectLocally(ctx *context.Ctx) error {
 		return fmt.Errorf("Unable to use pre-build hook: %s", err)
 	}
 
-	// tarantoolctl rocks make
-	log.Infof("Running `tarantoolctl rocks make`")
-	rocksMakeCmd := exec.Command("tarantoolctl", "rocks", "make")
-	err := common.RunCommand(rocksMakeCmd, ctx.Build.Dir, ctx.Cli.Verbose)
-	if err != nil {
-		return fmt.Errorf("Failed to install rocks: %s", err)
-	}
-
 	return nil
 }
@@ -0,0 +1,67 @@
+package build
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+const phaseAwareHookContent = `#!/bin/sh
+set -e
+
+echo "$CARTRIDGE_BUILD_TARGET" > target.txt
+
+echo deps >> phases.txt
+if [ "$CARTRIDGE_BUILD_TARGET" = "deps" ]; then
+    exit 0
+fi
+
+echo compile >> phases.txt
+if [ "$CARTRIDGE_BUILD_TARGET" = "compile" ]; then
+    exit 0
+fi
+
+echo assets >> phases.txt
+`
+
+func TestRunPreBuildHookTarget(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	testCases := []struct {
+		target    string
+		expPhases string
+	}{
+		{"", "deps\ncompile\nassets\n"},
+		{"deps", "deps\n"},
+		{"compile", "deps\ncompile\n"},
+	}
+
+	for _, tc := range testCases {
+		buildDir, err := ioutil.TempDir("", "build")
+		assert.Nil(err)
+		defer os.RemoveAll(buildDir)
+
+		hookPath := filepath.Join(buildDir, preBuildHookName)
+		assert.Nil(ioutil.WriteFile(hookPath, []byte(phaseAwareHookContent), 0755))
+
+		var ctx context.Ctx
+		ctx.Build.Dir = buildDir
+		ctx.Build.Target = tc.target
+
+		assert.Nil(runPreBuildHook(&ctx))
+
+		target, err := ioutil.ReadFile(filepath.Join(buildDir, "target.txt"))
+		assert.Nil(err)
+		assert.Equal(tc.target+"\n", string(target))
+
+		phases, err := ioutil.ReadFile(filepath.Join(buildDir, "phases.txt"))
+		assert.Nil(err)
+		assert.Equal(tc.expPhases, string(phases))
+	}
+}
@@ -0,0 +1,216 @@
+package build
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/apex/log"
+	"github.com/otiai10/copy"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+const (
+	luarocksConfigEnv = "LUAROCKS_CONFIG"
+
+	rocksDir = ".rocks"
+
+	// rocks cache dir contains the installed rocks trees
+	// and the luarocks local_cache (downloaded rocks and manifests)
+	rocksCacheTreesDir     = "trees"
+	rocksCacheDownloadsDir = "downloads"
+
+	containerRocksCacheDir = "/opt/rocks-cache"
+)
+
+// buildWithRocksCache calls build using the rocks cache dir.
+// Before the build, the cached rocks tree is copied to the build directory
+// (if it doesn't contain .rocks yet), after the build the result tree is saved to the cache.
+// The tree is cached by the rockspec content and the build flavor
+// (e.g. OS and Tarantool version), so rocks built for another environment aren't reused.
+// If the cache can't be used, a warning is shown and build is called with useCache=false
+func buildWithRocksCache(ctx *context.Ctx, flavor string, build func(useCache bool) error) error {
+	if ctx.Build.RocksCacheDir == "" {
+		return build(false)
+	}
+
+	treeKey, err := prepareRocksCache(ctx, flavor)
+	if err != nil {
+		log.Warnf(
+			"Rocks cache directory %s can't be used: %s. Rocks are installed without cache",
+			ctx.Build.RocksCacheDir, err,
+		)
+
+		return build(false)
+	}
+
+	log.Debugf("Using rocks cache directory %s", ctx.Build.RocksCacheDir)
+
+	if restored, err := restoreRocksTree(ctx.Build.RocksCacheDir, treeKey, ctx.Build.Dir); err != nil {
+		log.Warnf("Failed to restore rocks from cache: %s", err)
+	} else if restored {
+		log.Infof("Rocks are restored from cache %s", ctx.Build.RocksCacheDir)
+	}
+
+	if err := build(true); err != nil {
+		return err
+	}
+
+	if err := saveRocksTree(ctx.Build.RocksCacheDir, treeKey, ctx.Build.Dir); err != nil {
+		log.Warnf("Failed to save rocks to cache: %s", err)
+	}
+
+	return nil
+}
+
+// prepareRocksCache checks the rocks cache dir and returns the key
+// of the rocks tree built from the rockspec for the specified flavor
+func prepareRocksCache(ctx *context.Ctx, flavor string) (string, error) {
+	if err := checkRocksCacheDir(ctx.Build.RocksCacheDir); err != nil {
+		return "", err
+	}
+
+	specPath := ""
+	if ctx.Build.Spec != "" {
+		specPath = getSpecPath(ctx)
+	} else {
+		var err error
+		if specPath, err = common.FindRockspec(ctx.Build.Dir); err != nil {
+			return "", err
+		}
+	}
+
+	specContent, err := common.GetFileContentBytes(specPath)
+	if err != nil {
+		return "", fmt.Errorf("Failed to read rockspec: %s", err)
+	}
+
+	return getRocksTreeKey(specContent, flavor), nil
+}
+
+func getRocksTreeKey(specContent []byte, flavor string) string {
+	hasher := sha256.New()
+	hasher.Write([]byte(flavor))
+	hasher.Write([]byte{'\n'})
+	hasher.Write(specContent)
+
+	return fmt.Sprintf("%x", hasher.Sum(nil))
+}
+
+// restoreRocksTree copies the cached rocks tree to the build directory.
+// Nothing is done if the tree isn't cached or build directory already contains .rocks
+func restoreRocksTree(cacheDir string, treeKey string, buildDir string) (bool, error) {
+	cachedTreePath := filepath.Join(cacheDir, rocksCacheTreesDir, treeKey)
+	buildTreePath := filepath.Join(buildDir, rocksDir)
+
+	if _, err := os.Stat(buildTreePath); err == nil {
+		return false, nil
+	} else if !os.IsNotExist(err) {
+		return false, err
+	}
+
+	if _, err := os.Stat(cachedTreePath); os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if err := copy.Copy(cachedTreePath, buildTreePath); err != nil {
+		// partially copied tree shouldn't be used by build
+		os.RemoveAll(buildTreePath)
+		return false, err
+	}
+
+	return true, nil
+}
+
+// saveRocksTree saves the build directory rocks tree to the cache.
+// The tree is copied to the temporary directory first and then renamed,
+// so concurrent builds never restore partially copied tree
+func saveRocksTree(cacheDir string, treeKey string, buildDir string) error {
+	treesDir := filepath.Join(cacheDir, rocksCacheTreesDir)
+	buildTreePath := filepath.Join(buildDir, rocksDir)
+
+	if _, err := os.Stat(buildTreePath); os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(treesDir, 0755); err != nil {
+		return err
+	}
+
+	tmpTreePath, err := ioutil.TempDir(treesDir, fmt.Sprintf(".%s-*", treeKey))
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpTreePath)
+
+	if err := copy.Copy(buildTreePath, tmpTreePath); err != nil {
+		return err
+	}
+
+	cachedTreePath := filepath.Join(treesDir, treeKey)
+	if err := os.RemoveAll(cachedTreePath); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpTreePath, cachedTreePath)
+}
+
+// writeRocksCacheConfig writes luarocks config that sets local_cache to the specified path.
+// The config file is created in configDir (default temporary directory if it's empty).
+// If userConfigPath is specified, this config content is kept.
+// Returned cleanup function removes the config
+func writeRocksCacheConfig(configDir string, localCachePath string, userConfigPath string) (string, func(), error) {
+	var configContent string
+	if userConfigPath != "" {
+		var err error
+		if configContent, err = common.GetFileContent(userConfigPath); err != nil {
+			return "", nil, fmt.Errorf("Failed to read %s config: %s", luarocksConfigEnv, err)
+		}
+	}
+
+	// local_cache set last overrides the user config value
+	configContent += fmt.Sprintf("\nlocal_cache = %s\n", common.QuoteLua(localCachePath, '"'))
+
+	configFile, err := ioutil.TempFile(configDir, "luarocks-config-*.lua")
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed to create luarocks config: %s", err)
+	}
+	defer configFile.Close()
+
+	cleanup := func() {
+		os.Remove(configFile.Name())
+	}
+
+	if _, err := configFile.WriteString(configContent); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("Failed to write luarocks config: %s", err)
+	}
+
+	return configFile.Name(), cleanup, nil
+}
+
+// checkRocksCacheDir creates the rocks cache dir if it doesn't exist
+// and checks that it's writable
+func checkRocksCacheDir(cacheDir string) error {
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return fmt.Errorf("Failed to create rocks cache directory: %s", err)
+	}
+
+	checkFile, err := ioutil.TempFile(cacheDir, ".write-check")
+	if err != nil {
+		return fmt.Errorf("Rocks cache directory isn't writable: %s", err)
+	}
+
+	checkFile.Close()
+	os.Remove(checkFile.Name())
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package build
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteRocksCacheConfig(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "rocks-cache")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	// config is created in the specified dir
+	configPath, cleanup, err := writeRocksCacheConfig(tmpDir, "/opt/rocks-cache/downloads", "")
+	assert.Nil(err)
+	assert.Equal(tmpDir, filepath.Dir(configPath))
+
+	configContent, err := ioutil.ReadFile(configPath)
+	assert.Nil(err)
+	assert.Equal("\nlocal_cache = \"/opt/rocks-cache/downloads\"\n", string(configContent))
+
+	cleanup()
+	assert.NoFileExists(configPath)
+
+	// user config is kept
+	userConfigPath := filepath.Join(tmpDir, "config.lua")
+	assert.Nil(ioutil.WriteFile(userConfigPath, []byte(`rocks_servers = {"http://rocks.example.com"}`), 0644))
+
+	configPath, cleanup, err = writeRocksCacheConfig("", "/cache/downloads", userConfigPath)
+	assert.Nil(err)
+	defer cleanup()
+
+	configContent, err = ioutil.ReadFile(configPath)
+	assert.Nil(err)
+	assert.Equal(
+		"rocks_servers = {\"http://rocks.example.com\"}\nlocal_cache = \"/cache/downloads\"\n",
+		string(configContent),
+	)
+
+	// user config doesn't exist
+	_, _, err = writeRocksCacheConfig("", "/cache/downloads", filepath.Join(tmpDir, "missed.lua"))
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Failed to read LUAROCKS_CONFIG config")
+}
+
+func TestCheckRocksCacheDir(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "rocks-cache")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	// cache dir is created
+	cacheDir := filepath.Join(tmpDir, "cache")
+	assert.Nil(checkRocksCacheDir(cacheDir))
+	assert.DirExists(cacheDir)
+
+	// cache dir path is a file
+	filePath := filepath.Join(tmpDir, "file")
+	assert.Nil(ioutil.WriteFile(filePath, []byte{}, 0644))
+
+	err = checkRocksCacheDir(filePath)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Failed to create rocks cache directory")
+
+	// non-writable cache dir
+	if os.Geteuid() != 0 {
+		readOnlyDir := filepath.Join(tmpDir, "read-only")
+		assert.Nil(os.Mkdir(readOnlyDir, 0555))
+
+		err = checkRocksCacheDir(readOnlyDir)
+		assert.NotNil(err)
+		assert.Contains(err.Error(), "Rocks cache directory isn't writable")
+	}
+}
+
+func TestGetRocksTreeKey(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	spec := []byte(`dependencies = {'cartridge == 2.7.0'}`)
+
+	key := getRocksTreeKey(spec, "local linux/amd64 2.8")
+	assert.Len(key, 64)
+	assert.Equal(key, getRocksTreeKey(spec, "local linux/amd64 2.8"))
+
+	assert.NotEqual(key, getRocksTreeKey(spec, "docker linux/arm64 2.8"))
+	assert.NotEqual(key, getRocksTreeKey([]byte(`dependencies = {'cartridge == 2.8.0'}`), "local linux/amd64 2.8"))
+}
+
+func TestSaveRestoreRocksTree(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "rocks-cache")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	cacheDir := filepath.Join(tmpDir, "cache")
+	buildDir := filepath.Join(tmpDir, "build")
+	rockPath := filepath.Join(rocksDir, "share", "tarantool", "cartridge.lua")
+
+	assert.Nil(os.MkdirAll(filepath.Join(buildDir, filepath.Dir(rockPath)), 0755))
+
+	// tree isn't cached yet
+	restored, err := restoreRocksTree(cacheDir, "key", buildDir)
+	assert.Nil(err)
+	assert.False(restored)
+
+	// build dir without .rocks isn't saved
+	assert.Nil(saveRocksTree(cacheDir, "key", filepath.Join(tmpDir, "not-built")))
+	assert.NoDirExists(filepath.Join(cacheDir, rocksCacheTreesDir, "key"))
+
+	// tree is saved
+	assert.Nil(ioutil.WriteFile(filepath.Join(buildDir, rockPath), []byte("return {}"), 0644))
+	assert.Nil(saveRocksTree(cacheDir, "key", buildDir))
+	assert.FileExists(filepath.Join(cacheDir, rocksCacheTreesDir, "key", "share", "tarantool", "cartridge.lua"))
+
+	// existing .rocks isn't replaced
+	restored, err = restoreRocksTree(cacheDir, "key", buildDir)
+	assert.Nil(err)
+	assert.False(restored)
+
+	// tree is restored to the clean build dir
+	cleanBuildDir := filepath.Join(tmpDir, "clean-build")
+	assert.Nil(os.Mkdir(cleanBuildDir, 0755))
+
+	restored, err = restoreRocksTree(cacheDir, "key", cleanBuildDir)
+	assert.Nil(err)
+	assert.True(restored)
+
+	rockContent, err := ioutil.ReadFile(filepath.Join(cleanBuildDir, rockPath))
+	assert.Nil(err)
+	assert.Equal("return {}", string(rockContent))
+
+	// saved tree is replaced, temporary dirs are removed
+	assert.Nil(ioutil.WriteFile(filepath.Join(buildDir, rockPath), []byte("return {version = 2}"), 0644))
+	assert.Nil(saveRocksTree(cacheDir, "key", buildDir))
+
+	cachedRockPath := filepath.Join(cacheDir, rocksCacheTreesDir, "key", "share", "tarantool", "cartridge.lua")
+	rockContent, err = ioutil.ReadFile(cachedRockPath)
+	assert.Nil(err)
+	assert.Equal("return {version = 2}", string(rockContent))
+
+	treesDirEntries, err := ioutil.ReadDir(filepath.Join(cacheDir, rocksCacheTreesDir))
+	assert.Nil(err)
+	assert.Len(treesDirEntries, 1)
+}
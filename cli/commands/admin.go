@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/apex/log"
@@ -42,7 +43,9 @@ func addAdminFlags(flagSet *pflag.FlagSet) {
 	flagSet.BoolVarP(&ctx.Admin.Help, "help", "h", false, "Help for admin function")
 
 	flagSet.StringVar(&ctx.Admin.InstanceName, "instance", "", "Instance name")
+	flagSet.StringVar(&ctx.Admin.Format, "format", "", adminFormatUsage)
 	flagSet.StringVar(&ctx.Running.RunDir, "run-dir", "", prodRunDirUsage)
+	addSSHFlag(flagSet)
 
 	flagSet.StringVar(&timeoutStr, "timeout", "", timeoutUsage)
 
@@ -66,8 +69,16 @@ func runAdminCommand(cmd *cobra.Command, args []string) error {
 	// log level is usually set in rootCmd.PersistentPreRun
 	setLogLevel()
 
+	if ctx.Admin.Format != "" {
+		if err := admin.CheckResultFormat(ctx.Admin.Format); err != nil {
+			return fmt.Errorf(`Invalid argument %q for "--%s" flag: %s`, ctx.Admin.Format, "format", err)
+		}
+	}
+
 	if ctx.Admin.List && !ctx.Admin.Help {
-		return admin.Run(admin.List, &ctx, "", nil, nil)
+		return runWithSSHTunnel(func() error {
+			return admin.Run(admin.List, &ctx, "", nil, nil)
+		})
 	}
 
 	if len(flagSet.Args()) == 0 {
@@ -77,9 +88,15 @@ func runAdminCommand(cmd *cobra.Command, args []string) error {
 
 	funcName := strings.Join(flagSet.Args(), ".")
 
-	if ctx.Admin.Help {
-		return admin.Run(admin.Help, &ctx, funcName, flagSet, nil)
-	}
+	return runWithSSHTunnel(func() error {
+		if ctx.Admin.Help {
+			return admin.Run(admin.Help, &ctx, funcName, flagSet, nil)
+		}
+
+		if ctx.Admin.Format == admin.JSONFormat {
+			return admin.Run(admin.CallJSON, &ctx, funcName, flagSet, args)
+		}
 
-	return admin.Run(admin.Call, &ctx, funcName, flagSet, args)
+		return admin.Run(admin.Call, &ctx, funcName, flagSet, args)
+	})
 }
@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+
+	"github.com/tarantool/cartridge-cli/cli/bench"
+)
+
+var (
+	benchDurationStr string
+)
+
+func init() {
+	var benchCmd = &cobra.Command{
+		Use:   "bench URI",
+		Short: "Run benchmark on running instance",
+		Long: `Run insert/select workload on running instance and report its throughput
+The workload is run on the temporary space that is dropped after the benchmark,
+so the application data isn't affected.
+URI is the instance binary port address, e.g. admin:secret@localhost:3301`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := runBenchCmd(cmd, args)
+			if err != nil {
+				log.Fatalf(err.Error())
+			}
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	rootCmd.AddCommand(benchCmd)
+
+	// FLAGS
+	configureFlags(benchCmd)
+
+	// connection flags
+	benchCmd.Flags().StringVarP(&ctx.Connect.Username, "username", "u", "", connectUsernameUsage)
+	benchCmd.Flags().StringVarP(&ctx.Connect.Password, "password", "p", "", connectPasswordUsage)
+
+	// workload flags
+	benchCmd.Flags().IntVar(&ctx.Bench.Connections, "connections", 10, benchConnectionsUsage)
+	benchCmd.Flags().StringVar(&benchDurationStr, "duration", "10s", benchDurationUsage)
+	benchCmd.Flags().IntVar(&ctx.Bench.Count, "count", 0, benchCountUsage)
+	benchCmd.Flags().IntVar(&ctx.Bench.PayloadSize, "payload-size", 100, benchPayloadSizeUsage)
+	benchCmd.Flags().Float64Var(&ctx.Bench.ReadRatio, "read-ratio", 0.5, benchReadRatioUsage)
+
+	// output format flag
+	benchCmd.Flags().StringVar(&ctx.Bench.Format, "format", bench.FormatText, benchFormatUsage)
+}
+
+func runBenchCmd(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if ctx.Bench.Connections <= 0 {
+		cmd.Usage()
+		return fmt.Errorf(`Invalid argument %d for "--%s" flag: should be positive`, ctx.Bench.Connections, "connections")
+	}
+
+	if ctx.Bench.Count < 0 {
+		cmd.Usage()
+		return fmt.Errorf(`Invalid argument %d for "--%s" flag: should be positive`, ctx.Bench.Count, "count")
+	}
+
+	if ctx.Bench.Count > 0 && cmd.Flags().Changed("duration") {
+		return fmt.Errorf("You can specify only one of --count and --duration options")
+	}
+
+	if ctx.Bench.Duration, err = getDuration(benchDurationStr); err != nil {
+		cmd.Usage()
+		return fmt.Errorf(`Invalid argument %q for "--%s" flag: %s`, benchDurationStr, "duration", err)
+	}
+
+	if ctx.Bench.Count == 0 && ctx.Bench.Duration == 0 {
+		cmd.Usage()
+		return fmt.Errorf(`Invalid argument %q for "--%s" flag: should be positive`, benchDurationStr, "duration")
+	}
+
+	if ctx.Bench.PayloadSize < 0 {
+		cmd.Usage()
+		return fmt.Errorf(`Invalid argument %d for "--%s" flag: should be positive`, ctx.Bench.PayloadSize, "payload-size")
+	}
+
+	if ctx.Bench.ReadRatio < 0 || ctx.Bench.ReadRatio > 1 {
+		cmd.Usage()
+		return fmt.Errorf(`Invalid argument %v for "--%s" flag: should be in range [0, 1]`, ctx.Bench.ReadRatio, "read-ratio")
+	}
+
+	if err := bench.CheckFormat(ctx.Bench.Format); err != nil {
+		cmd.Usage()
+		return fmt.Errorf(`Invalid argument %q for "--%s" flag: %s`, ctx.Bench.Format, "format", err)
+	}
+
+	if err := bench.Run(&ctx, args[0]); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -1,6 +1,9 @@
 package commands
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
@@ -25,6 +28,10 @@ func init() {
 
 	// FLAGS
 	configureFlags(buildCmd)
+
+	buildCmd.Flags().StringVar(&ctx.Build.Target, "target", "", buildTargetUsage)
+	buildCmd.Flags().StringVar(&ctx.Build.Spec, "spec", "", buildSpecUsage)
+	addRocksCacheDirFlag(buildCmd)
 }
 
 func runBuildCommand(cmd *cobra.Command, args []string) error {
@@ -32,6 +39,13 @@ func runBuildCommand(cmd *cobra.Command, args []string) error {
 
 	ctx.Project.Path = cmd.Flags().Arg(0)
 
+	if cmd.Flags().Changed("target") && strings.TrimSpace(ctx.Build.Target) == "" {
+		return fmt.Errorf(`Invalid argument %q for "--%s" flag: Build target should be non-empty`,
+			ctx.Build.Target, "target")
+	}
+
+	setRocksCacheDir(cmd)
+
 	err = build.FillCtx(&ctx)
 	if err != nil {
 		return err
@@ -33,6 +33,8 @@ func init() {
 }
 
 func Execute() {
+	setUnknownSubcommandChecks(rootCmd)
+
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatalf(err.Error())
 	}
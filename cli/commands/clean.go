@@ -39,6 +39,12 @@ func init() {
 	cleanCmd.Flags().StringVar(&ctx.Running.DataDir, "data-dir", "", dataDirUsage)
 	// common running paths
 	addCommonRunningPathsFlags(cleanCmd)
+
+	// add --allow-unknown flag
+	addAllowUnknownFlag(cleanCmd)
+
+	// add --dry-run flag
+	cleanCmd.Flags().BoolVar(&ctx.Running.CleanDryRun, "dry-run", false, cleanDryRunUsage)
 }
 
 func runCleanCmd(cmd *cobra.Command, args []string) error {
@@ -2,11 +2,13 @@ package commands
 
 import (
 	"fmt"
+	"os"
 	"strconv"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"github.com/tarantool/cartridge-cli/cli/common"
 )
 
 func setDefaultValue(flags *pflag.FlagSet, name string, value string) error {
@@ -39,6 +41,50 @@ func getDuration(durationStr string) (time.Duration, error) {
 	return duration, nil
 }
 
+// setUnknownSubcommandChecks makes commands that only group sub-commands
+// fail on unknown sub-command and suggest the closest one.
+// By default, cobra checks it only for the root command
+// and prints help for nested commands
+func setUnknownSubcommandChecks(cmd *cobra.Command) {
+	for _, subCmd := range cmd.Commands() {
+		setUnknownSubcommandChecks(subCmd)
+	}
+
+	if !cmd.HasSubCommands() || cmd.Runnable() {
+		return
+	}
+
+	cmd.Args = checkUnknownSubcommand
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	}
+}
+
+func checkUnknownSubcommand(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+
+	typedName := args[0]
+
+	suggestion := ""
+	minDistance := -1
+
+	for _, name := range cmd.SuggestionsFor(typedName) {
+		distance := common.LevenshteinDistance(typedName, name)
+		if minDistance == -1 || distance < minDistance {
+			suggestion = name
+			minDistance = distance
+		}
+	}
+
+	if suggestion == "" {
+		return fmt.Errorf("unknown command '%s' for '%s'", typedName, cmd.CommandPath())
+	}
+
+	return fmt.Errorf("unknown command '%s'; did you mean '%s'?", typedName, suggestion)
+}
+
 func configureFlags(cmd *cobra.Command) {
 	cmd.Flags().SortFlags = false
 }
@@ -47,6 +93,18 @@ func addNameFlag(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&ctx.Project.Name, "name", "", nameUsage)
 }
 
+func addRocksCacheDirFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&ctx.Build.RocksCacheDir, "rocks-cache-dir", "", rocksCacheDirUsage)
+}
+
+// setRocksCacheDir sets rocks cache dir from the environment
+// if --rocks-cache-dir flag isn't specified
+func setRocksCacheDir(cmd *cobra.Command) {
+	if !cmd.Flags().Changed("rocks-cache-dir") {
+		ctx.Build.RocksCacheDir = os.Getenv(rocksCacheDirEnv)
+	}
+}
+
 func addStateboardRunningFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&ctx.Running.WithStateboard, "stateboard", false, stateboardUsage)
 	cmd.Flags().BoolVar(&ctx.Running.StateboardOnly, "stateboard-only", false, stateboardOnlyUsage)
@@ -57,6 +115,10 @@ func addCommonRunningPathsFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&ctx.Running.ConfPath, "cfg", "", cfgUsage)
 }
 
+func addAllowUnknownFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&ctx.Running.AllowUnknownInstances, "allow-unknown", false, allowUnknownUsage)
+}
+
 func addCommonRepairFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&ctx.Project.Name, "name", "", "Application name")
 	cmd.Flags().BoolVarP(&ctx.Repair.Force, "force", "f", false, repairForceUsage)
@@ -73,6 +135,7 @@ func addCommonReplicasetsFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&ctx.Project.Name, "name", "", "Application name")
 	cmd.Flags().StringVar(&ctx.Running.RunDir, "run-dir", "", runDirUsage)
 	cmd.Flags().StringVar(&ctx.Running.ConfPath, "cfg", "", cfgUsage)
+	addSSHFlag(cmd.Flags())
 }
 
 func addReplicasetFlag(cmd *cobra.Command) {
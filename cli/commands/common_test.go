@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -35,3 +36,50 @@ func TestGetDuration(t *testing.T) {
 	assert.NotNil(err)
 	assert.True(strings.Contains(err.Error(), `Negative duration is specified`), err.Error())
 }
+
+func TestCheckUnknownSubcommand(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	rootCmd := &cobra.Command{Use: "cartridge"}
+	replicasetsCmd := &cobra.Command{Use: "replicasets"}
+	runFunc := func(cmd *cobra.Command, args []string) {}
+
+	for _, name := range []string{"start", "status", "stop", "pack"} {
+		rootCmd.AddCommand(&cobra.Command{Use: name, Run: runFunc})
+	}
+
+	for _, name := range []string{"join", "list", "setup"} {
+		replicasetsCmd.AddCommand(&cobra.Command{Use: name, Run: runFunc})
+	}
+
+	rootCmd.AddCommand(replicasetsCmd)
+
+	setUnknownSubcommandChecks(rootCmd)
+
+	assert.Nil(checkUnknownSubcommand(rootCmd, nil))
+	assert.Nil(checkUnknownSubcommand(replicasetsCmd, nil))
+
+	assert.EqualError(
+		checkUnknownSubcommand(rootCmd, []string{"statsu"}),
+		"unknown command 'statsu'; did you mean 'status'?",
+	)
+
+	assert.EqualError(
+		checkUnknownSubcommand(rootCmd, []string{"packr"}),
+		"unknown command 'packr'; did you mean 'pack'?",
+	)
+
+	assert.EqualError(
+		checkUnknownSubcommand(replicasetsCmd, []string{"jion"}),
+		"unknown command 'jion'; did you mean 'join'?",
+	)
+
+	assert.EqualError(
+		checkUnknownSubcommand(rootCmd, []string{"deploy"}),
+		"unknown command 'deploy' for 'cartridge'",
+	)
+
+	assert.True(replicasetsCmd.Runnable())
+	assert.NotNil(replicasetsCmd.Args)
+}
@@ -47,6 +47,16 @@ func ShellCompRunningInstances(cmd *cobra.Command, args []string, toComplete str
 	return filteredInstances, cobra.ShellCompDirectiveNoFileComp
 }
 
+// ShellCompEnterInstance completes the only instance name for `cartridge enter`.
+// Instances aren't completed if the address is specified via --conn
+func ShellCompEnterInstance(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 || ctx.Connect.Conn != "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return ShellCompRunningInstances(cmd, args, toComplete)
+}
+
 // REPAIR
 
 func ShellCompRepairSetURI(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -1,21 +1,27 @@
 package commands
 
 import (
+	"fmt"
+
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
 	"github.com/tarantool/cartridge-cli/cli/connect"
 )
 
+var (
+	evalTimeoutStr string
+)
+
 func init() {
 	var enterCmd = &cobra.Command{
 		Use:   "enter INSTANCE_NAME",
 		Short: "Enter to application instance console",
 		Run: func(cmd *cobra.Command, args []string) {
-			if err := connect.Enter(&ctx, args); err != nil {
+			if err := runEnterCmd(cmd, args); err != nil {
 				log.Fatalf(err.Error())
 			}
 		},
-		ValidArgsFunction: ShellCompRunningInstances,
+		ValidArgsFunction: ShellCompEnterInstance,
 		Args:              cobra.MaximumNArgs(1),
 	}
 
@@ -28,12 +34,21 @@ func init() {
 	addNameFlag(enterCmd)
 	// run-dir flag
 	enterCmd.Flags().StringVar(&ctx.Running.RunDir, "run-dir", "", runDirUsage)
+	// connection flags
+	enterCmd.Flags().StringVar(&ctx.Connect.Conn, "conn", "", enterConnUsage)
+	enterCmd.Flags().StringVarP(&ctx.Connect.Username, "username", "u", "", connectUsernameUsage)
+	enterCmd.Flags().StringVarP(&ctx.Connect.Password, "password", "p", "", connectPasswordUsage)
+	addSSHFlag(enterCmd.Flags())
+	// expression flag
+	enterCmd.Flags().StringVarP(&ctx.Connect.Expression, "eval", "e", "", enterEvalUsage)
+	// eval timeout flag
+	enterCmd.Flags().StringVar(&evalTimeoutStr, "eval-timeout", "", connectEvalTimeoutUsage)
 
 	var connectCmd = &cobra.Command{
 		Use:   "connect URI",
 		Short: "Connect to specified URI",
 		Run: func(cmd *cobra.Command, args []string) {
-			if err := connect.Connect(&ctx, args); err != nil {
+			if err := runConnectCmd(cmd, args); err != nil {
 				log.Fatalf(err.Error())
 			}
 		},
@@ -49,4 +64,48 @@ func init() {
 	connectCmd.Flags().StringVarP(&ctx.Connect.Username, "username", "u", "", connectUsernameUsage)
 	// password flag
 	connectCmd.Flags().StringVarP(&ctx.Connect.Password, "password", "p", "", connectPasswordUsage)
+	// eval timeout flag
+	connectCmd.Flags().StringVar(&evalTimeoutStr, "eval-timeout", "", connectEvalTimeoutUsage)
+	// result format flag
+	connectCmd.Flags().StringVar(&ctx.Connect.Format, "format", string(connect.LuaFormat), connectFormatUsage)
+}
+
+func runEnterCmd(cmd *cobra.Command, args []string) error {
+	if err := setConnectEvalTimeout(cmd); err != nil {
+		return err
+	}
+
+	return runWithSSHTunnel(func() error {
+		return connect.Enter(&ctx, args)
+	})
+}
+
+func runConnectCmd(cmd *cobra.Command, args []string) error {
+	if err := setConnectEvalTimeout(cmd); err != nil {
+		return err
+	}
+
+	if err := connect.CheckResultFormat(ctx.Connect.Format); err != nil {
+		cmd.Usage()
+		return fmt.Errorf(`Invalid argument %q for "--%s" flag: %s`, ctx.Connect.Format, "format", err)
+	}
+
+	if err := connect.Connect(&ctx, args); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func setConnectEvalTimeout(cmd *cobra.Command) error {
+	var err error
+
+	if evalTimeoutStr != "" {
+		if ctx.Connect.EvalTimeout, err = getDuration(evalTimeoutStr); err != nil {
+			cmd.Usage()
+			return fmt.Errorf(`Invalid argument %q for "--%s" flag: %s`, evalTimeoutStr, "eval-timeout", err)
+		}
+	}
+
+	return nil
 }
@@ -5,10 +5,18 @@ import "time"
 // DEFAULT VALUES
 const (
 	defaultStartTimeout = 1 * time.Minute
+	defaultStopTimeout  = 30 * time.Second
+	defaultWaitTimeout  = 1 * time.Minute
 	defaultLogLines     = 15
+
+	// run directory on the remote host (see --ssh)
+	defaultRemoteRunDir = "/var/run/tarantool"
 )
 
 // ENV
 const (
-	cartridgeTmpDirEnv = "CARTRIDGE_TEMPDIR"
+	cartridgeTmpDirEnv  = "CARTRIDGE_TEMPDIR"
+	sourceDateEpochEnv  = "SOURCE_DATE_EPOCH"
+	rpmDigestWorkersEnv = "CARTRIDGE_RPM_DIGEST_WORKERS"
+	rocksCacheDirEnv    = "CARTRIDGE_ROCKS_CACHE_DIR"
 )
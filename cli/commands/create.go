@@ -34,7 +34,11 @@ func init() {
 
 	createCmd.Flags().StringVar(&ctx.Project.Name, "name", "", createNameUsage)
 	createCmd.Flags().StringVar(&ctx.Create.From, "from", "", createFromUsage)
+	createCmd.Flags().StringVar(&ctx.Create.Ref, "ref", "", createRefUsage)
 	createCmd.Flags().StringVar(&ctx.Create.Template, "template", "", templateUsage)
+	createCmd.Flags().StringArrayVar(&ctx.Create.SetVars, "set", nil, createSetUsage)
+	createCmd.Flags().StringVar(&ctx.Create.PostCreateHook, "post-create-hook", "", postCreateHookUsage)
+	createCmd.Flags().BoolVar(&ctx.Create.DryRun, "dry-run", false, createDryRunUsage)
 }
 
 func runCreateCommand(cmd *cobra.Command, args []string) error {
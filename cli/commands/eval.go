@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+
+	"github.com/tarantool/cartridge-cli/cli/eval"
+	"github.com/tarantool/cartridge-cli/cli/running"
+)
+
+var (
+	evalReadTimeoutStr string
+	evalRetryDelayStr  string
+)
+
+func init() {
+	var evalCmd = &cobra.Command{
+		Use:   "eval FUNCTION_BODY [INSTANCE_NAME...]",
+		Short: "Evaluate function body on instance(s)",
+		Long: `Evaluate Lua function body on instance(s) using console socket
+Function body should return a value, e.g. "return box.info.status".
+If --connect-all flag is specified, function body is evaluated on all configured instances.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := runEvalCmd(cmd, args)
+			if err != nil {
+				log.Fatalf(err.Error())
+			}
+		},
+		Args: cobra.MinimumNArgs(1),
+	}
+
+	rootCmd.AddCommand(evalCmd)
+
+	// FLAGS
+	configureFlags(evalCmd)
+
+	// application name flag
+	addNameFlag(evalCmd)
+
+	// eval-specific flags
+	evalCmd.Flags().BoolVar(&ctx.Eval.ConnectAll, "connect-all", false, evalConnectAllUsage)
+	evalCmd.Flags().IntVar(&ctx.Eval.Quorum, "quorum", 0, evalQuorumUsage)
+	evalCmd.Flags().StringVar(&evalReadTimeoutStr, "timeout", "", evalTimeoutUsage)
+	evalCmd.Flags().IntVar(&ctx.Eval.Retry, "retry", 0, evalRetryUsage)
+	evalCmd.Flags().StringVar(&evalRetryDelayStr, "retry-delay", "", evalRetryDelayUsage)
+	evalCmd.Flags().StringArrayVar(&ctx.Eval.RetryOn, "retry-on", []string{}, evalRetryOnUsage)
+
+	// common running paths
+	addCommonRunningPathsFlags(evalCmd)
+
+	// add --allow-unknown flag
+	addAllowUnknownFlag(evalCmd)
+}
+
+func runEvalCmd(cmd *cobra.Command, args []string) error {
+	var err error
+
+	ctx.Eval.FunctionBody = args[0]
+
+	if evalReadTimeoutStr != "" {
+		if ctx.Eval.Timeout, err = getDuration(evalReadTimeoutStr); err != nil {
+			cmd.Usage()
+			return fmt.Errorf(`Invalid argument %q for "--%s" flag: %s`, evalReadTimeoutStr, "timeout", err)
+		}
+	}
+
+	if ctx.Eval.Quorum < 0 {
+		cmd.Usage()
+		return fmt.Errorf(`Invalid argument %d for "--%s" flag: should be positive`, ctx.Eval.Quorum, "quorum")
+	}
+
+	if ctx.Eval.Retry < 0 {
+		cmd.Usage()
+		return fmt.Errorf(`Invalid argument %d for "--%s" flag: should be positive`, ctx.Eval.Retry, "retry")
+	}
+
+	if ctx.Eval.Retry == 0 {
+		if evalRetryDelayStr != "" {
+			return fmt.Errorf("--retry-delay option can be used only with --retry flag")
+		}
+
+		if len(ctx.Eval.RetryOn) > 0 {
+			return fmt.Errorf("--retry-on option can be used only with --retry flag")
+		}
+	}
+
+	if evalRetryDelayStr != "" {
+		if ctx.Eval.RetryDelay, err = getDuration(evalRetryDelayStr); err != nil {
+			cmd.Usage()
+			return fmt.Errorf(`Invalid argument %q for "--%s" flag: %s`, evalRetryDelayStr, "retry-delay", err)
+		}
+	}
+
+	if err := running.FillCtx(&ctx, args[1:]); err != nil {
+		return err
+	}
+
+	if err := eval.Run(&ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
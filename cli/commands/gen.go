@@ -18,17 +18,23 @@ var (
 
 	bashCompFilePath string
 	zshCompFilePath  string
+	fishCompFilePath string
+	psCompFilePath   string
 
 	defaultBashCompFilePath string
 	defaultZshCompFilePath  string
+	defaultFishCompFilePath string
+	defaultPsCompFilePath   string
 
 	skipBash bool
 	skipZsh  bool
+	skipFish bool
+	skipPs   bool
 )
 
 /*
  * `cartridge gen` command is used to generate shell
- * autocompletions for Bash and Zsh.
+ * autocompletions for Bash, Zsh, Fish and PowerShell.
  *
  * Autocompletion is generated by cobra, see
  * https://github.com/spf13/cobra/blob/master/shell_completions.md.
@@ -45,6 +51,8 @@ var (
 func init() {
 	defaultBashCompFilePath = filepath.Join(completionsDirName, "bash", rootCmd.Name())
 	defaultZshCompFilePath = filepath.Join(completionsDirName, "zsh", fmt.Sprintf("_%s", rootCmd.Name()))
+	defaultFishCompFilePath = filepath.Join(completionsDirName, "fish", fmt.Sprintf("%s.fish", rootCmd.Name()))
+	defaultPsCompFilePath = filepath.Join(completionsDirName, "powershell", fmt.Sprintf("%s.ps1", rootCmd.Name()))
 
 	var genCmd = &cobra.Command{
 		Use:   "gen",
@@ -74,9 +82,13 @@ func init() {
 
 	genCompletionCmd.Flags().StringVar(&bashCompFilePath, "bash", defaultBashCompFilePath, "Bash completion file path")
 	genCompletionCmd.Flags().StringVar(&zshCompFilePath, "zsh", defaultZshCompFilePath, "Zsh completion file path")
+	genCompletionCmd.Flags().StringVar(&fishCompFilePath, "fish", defaultFishCompFilePath, "Fish completion file path")
+	genCompletionCmd.Flags().StringVar(&psCompFilePath, "powershell", defaultPsCompFilePath, "PowerShell completion file path")
 
 	genCompletionCmd.Flags().BoolVar(&skipBash, "skip-bash", false, "Do not generate bash completion")
 	genCompletionCmd.Flags().BoolVar(&skipZsh, "skip-zsh", false, "Do not generate zsh completion")
+	genCompletionCmd.Flags().BoolVar(&skipFish, "skip-fish", false, "Do not generate fish completion")
+	genCompletionCmd.Flags().BoolVar(&skipPs, "skip-powershell", false, "Do not generate PowerShell completion")
 
 	genSubCommands := []*cobra.Command{
 		genCompletionCmd,
@@ -102,13 +114,27 @@ func cutFlagsDescription(cmd *cobra.Command) {
 }
 
 func genCompletion(cmd *cobra.Command, args []string) error {
-	curDir, err := os.Getwd()
+	// filepath.Abs keeps absolute paths (including Windows ones, e.g. C:\completion)
+	// as is and joins relative ones with the current directory
+	bashCompFilePath, err := filepath.Abs(bashCompFilePath)
 	if err != nil {
-		return fmt.Errorf("Cailed to get current directory path: %s", err)
+		return fmt.Errorf("Failed to get bash completion file path: %s", err)
 	}
 
-	bashCompFilePath := filepath.Join(curDir, bashCompFilePath)
-	zshCompFilePath := filepath.Join(curDir, zshCompFilePath)
+	zshCompFilePath, err := filepath.Abs(zshCompFilePath)
+	if err != nil {
+		return fmt.Errorf("Failed to get zsh completion file path: %s", err)
+	}
+
+	fishCompFilePath, err := filepath.Abs(fishCompFilePath)
+	if err != nil {
+		return fmt.Errorf("Failed to get fish completion file path: %s", err)
+	}
+
+	psCompFilePath, err := filepath.Abs(psCompFilePath)
+	if err != nil {
+		return fmt.Errorf("Failed to get PowerShell completion file path: %s", err)
+	}
 
 	// create directories
 	bashCompFileDir := filepath.Dir(bashCompFilePath)
@@ -121,6 +147,18 @@ func genCompletion(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("Failed to create zsh completion directory: %s", err)
 	}
 
+	fishCompFileDir := filepath.Dir(fishCompFilePath)
+	if err := os.MkdirAll(fishCompFileDir, 0755); err != nil {
+		return fmt.Errorf("Failed to create fish completion directory: %s", err)
+	}
+
+	psCompFileDir := filepath.Dir(psCompFilePath)
+	if err := os.MkdirAll(psCompFileDir, 0755); err != nil {
+		return fmt.Errorf("Failed to create PowerShell completion directory: %s", err)
+	}
+
+	var generatedFiles []string
+
 	// gen completions
 	if !skipBash {
 		if err := os.RemoveAll(bashCompFilePath); err != nil {
@@ -136,6 +174,8 @@ func genCompletion(cmd *cobra.Command, args []string) error {
 		if err := common.ReplaceFileLinesByRe(bashCompFilePath, twoWordsFlagRgx, "# $1"); err != nil {
 			return fmt.Errorf("Failed to comment two words flags in the bash completion: %s", err)
 		}
+
+		generatedFiles = append(generatedFiles, fmt.Sprintf("Bash: %s", bashCompFilePath))
 	}
 
 	if !skipZsh {
@@ -146,6 +186,36 @@ func genCompletion(cmd *cobra.Command, args []string) error {
 		if err := cmd.Root().GenZshCompletionFile(zshCompFilePath); err != nil {
 			return fmt.Errorf("Failed to generate zsh completion: %s", err)
 		}
+
+		generatedFiles = append(generatedFiles, fmt.Sprintf("Zsh: %s", zshCompFilePath))
+	}
+
+	if !skipFish {
+		if err := os.RemoveAll(fishCompFilePath); err != nil {
+			return fmt.Errorf("Failed to remove existent fish completion: %s", err)
+		}
+
+		if err := cmd.Root().GenFishCompletionFile(fishCompFilePath, true); err != nil {
+			return fmt.Errorf("Failed to generate fish completion: %s", err)
+		}
+
+		generatedFiles = append(generatedFiles, fmt.Sprintf("Fish: %s", fishCompFilePath))
+	}
+
+	if !skipPs {
+		if err := os.RemoveAll(psCompFilePath); err != nil {
+			return fmt.Errorf("Failed to remove existent PowerShell completion: %s", err)
+		}
+
+		if err := cmd.Root().GenPowerShellCompletionFile(psCompFilePath); err != nil {
+			return fmt.Errorf("Failed to generate PowerShell completion: %s", err)
+		}
+
+		generatedFiles = append(generatedFiles, fmt.Sprintf("PowerShell: %s", psCompFilePath))
+	}
+
+	if len(generatedFiles) > 0 {
+		log.Infof("Completion files are generated:\n  %s", strings.Join(generatedFiles, "\n  "))
 	}
 
 	return nil
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/apex/log"
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
 	"github.com/tarantool/cartridge-cli/cli/project"
@@ -36,6 +37,11 @@ func init() {
 	// log-specific flags
 	logCmd.Flags().BoolVarP(&ctx.Running.LogFollow, "follow", "f", false, logFollowUsage)
 	logCmd.Flags().IntVarP(&ctx.Running.LogLines, "lines", "n", 0, logLinesUsage)
+	logCmd.Flags().StringVar(&ctx.Running.LogOutputDir, "output-dir", "", logOutputDirUsage)
+	logCmd.Flags().IntVar(&ctx.Running.LogMaxLineLength, "max-line-length", 0, logMaxLineLengthUsage)
+	logCmd.Flags().StringVar(&ctx.Running.LogLevel, "level", "", logLevelUsage)
+	logCmd.Flags().StringVar(&ctx.Running.LogGrep, "grep", "", logGrepUsage)
+	logCmd.Flags().BoolVar(&ctx.Running.LogNoColor, "no-color", false, logNoColorUsage)
 
 	// stateboard flags
 	addStateboardRunningFlags(logCmd)
@@ -44,6 +50,9 @@ func init() {
 	logCmd.Flags().StringVar(&ctx.Running.LogDir, "log-dir", "", logDirUsage)
 	// common running paths
 	addCommonRunningPathsFlags(logCmd)
+
+	// add --allow-unknown flag
+	addAllowUnknownFlag(logCmd)
 }
 
 func runLogCmd(cmd *cobra.Command, args []string) error {
@@ -51,6 +60,20 @@ func runLogCmd(cmd *cobra.Command, args []string) error {
 		return project.InternalError("Failed to set default lines value: %s", err)
 	}
 
+	if ctx.Running.LogFollow && ctx.Running.LogOutputDir != "" {
+		cmd.Usage()
+		return fmt.Errorf("--follow and --output-dir flags can't be used together")
+	}
+
+	if ctx.Running.LogMaxLineLength < 0 {
+		return fmt.Errorf(`Invalid argument %d for "--%s" flag: Length should be positive`,
+			ctx.Running.LogMaxLineLength, "max-line-length")
+	}
+
+	if ctx.Running.LogNoColor {
+		color.NoColor = true
+	}
+
 	if err := running.FillCtx(&ctx, args); err != nil {
 		return err
 	}
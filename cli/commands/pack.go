@@ -1,16 +1,25 @@
 package commands
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
+	"github.com/tarantool/cartridge-cli/cli/common"
+	"github.com/tarantool/cartridge-cli/cli/docker"
 	"github.com/tarantool/cartridge-cli/cli/pack"
 )
 
 var (
-	packTypeArgs = []string{"tgz", "rpm", "deb", "docker"}
+	packTypeArgs = []string{"tgz", "rpm", "deb", "apk", "docker"}
+
+	includeVCS   bool
+	splitSizeStr string
+	buildArgs    []string
 )
 
 func init() {
@@ -20,14 +29,72 @@ func init() {
 	addNameFlag(packCmd)
 
 	packCmd.Flags().StringVar(&ctx.Pack.Version, "version", "", versionUsage)
+	packCmd.Flags().StringVar(&ctx.Pack.ReleaseOverride, "release", "", releaseUsage)
 	packCmd.Flags().StringVar(&ctx.Pack.Suffix, "suffix", "", suffixUsage)
 	packCmd.Flags().StringSliceVar(&ctx.Pack.ImageTags, "tag", []string{}, tagUsage)
+	packCmd.Flags().BoolVar(&ctx.Pack.Push, "push", false, pushUsage)
+
+	packCmd.Flags().BoolVar(&ctx.Pack.IncludeEmptyDirs, "include-empty-dirs", false, includeEmptyDirsUsage)
+	packCmd.Flags().StringSliceVar(&ctx.Pack.EnsureDirs, "ensure-dir", []string{}, ensureDirUsage)
+	packCmd.Flags().BoolVar(&ctx.Pack.ExcludeVCS, "exclude-vcs", true, excludeVCSUsage)
+	packCmd.Flags().BoolVar(&includeVCS, "include-vcs", false, includeVCSUsage)
+	packCmd.Flags().StringVar(&splitSizeStr, "split-size", "", splitSizeUsage)
+	packCmd.Flags().StringVar(&ctx.Pack.OutputDir, "output-dir", "", outputDirUsage)
+	packCmd.Flags().BoolVar(&ctx.Pack.Checksum, "checksum", false, checksumUsage)
+	packCmd.Flags().StringVar(&ctx.Pack.Compression, "compression", "", compressionUsage)
+	packCmd.Flags().IntVar(&ctx.Pack.CompressionLevel, "compression-level", 0, compressionLevelUsage)
+	packCmd.Flags().StringArrayVar(&ctx.Pack.Transforms, "transform", []string{}, transformUsage)
+	packCmd.Flags().BoolVar(&ctx.Pack.RocksManifest, "rocks-manifest", true, rocksManifestUsage)
+	packCmd.Flags().BoolVar(
+		&ctx.Pack.VerifyNoAbsSymlinks, "verify-no-absolute-symlinks", false, verifyNoAbsSymlinksUsage,
+	)
+	packCmd.Flags().BoolVar(
+		&ctx.Pack.NormalizeLineEndings, "normalize-line-endings", false, normalizeLineEndingsUsage,
+	)
+	packCmd.Flags().BoolVar(&ctx.Pack.KeepGoing, "keep-going", false, keepGoingUsage)
+	packCmd.Flags().BoolVar(&ctx.Pack.DryRun, "dry-run", false, packDryRunUsage)
+	packCmd.Flags().BoolVar(&ctx.Pack.NoBuild, "no-build", false, noBuildUsage)
+	addRocksCacheDirFlag(packCmd)
+	packCmd.Flags().StringVar(&ctx.Pack.DefaultFileMode, "default-file-mode", "", defaultFileModeUsage)
+	packCmd.Flags().StringVar(&ctx.Pack.DefaultDirMode, "default-dir-mode", "", defaultDirModeUsage)
+	packCmd.Flags().StringArrayVar(&ctx.Pack.FileModes, "file-mode", []string{}, fileModeUsage)
+
+	packCmd.Flags().StringSliceVar(&ctx.Pack.RpmRecommends, "recommends", []string{}, recommendsUsage)
+	packCmd.Flags().StringSliceVar(&ctx.Pack.RpmSupplements, "supplements", []string{}, supplementsUsage)
+	packCmd.Flags().StringSliceVar(&ctx.Pack.RpmEnhances, "enhances", []string{}, enhancesUsage)
+	packCmd.Flags().StringVar(&ctx.Pack.RpmMinVersion, "rpm-min-version", "", rpmMinVersionUsage)
+	packCmd.Flags().StringVar(&ctx.Pack.RpmBuildHost, "rpm-build-host", "", rpmBuildHostUsage)
+	packCmd.Flags().StringVar(&ctx.Pack.RpmPayloadDigestAlgo, "payload-digest-algo", "", payloadDigestAlgoUsage)
+	packCmd.Flags().StringVar(&ctx.Pack.RpmRelocateDocsDir, "relocate-docs", "", relocateDocsUsage)
+	packCmd.Flags().StringSliceVar(&ctx.Pack.RpmDocPatterns, "doc-pattern", []string{}, docPatternUsage)
+	packCmd.Flags().StringVar(&ctx.Pack.RpmSignKey, "sign-key", "", signKeyUsage)
+	packCmd.Flags().BoolVar(&ctx.Pack.Reproducible, "reproducible", false, reproducibleUsage)
+	packCmd.Flags().BoolVar(&ctx.Pack.RpmSignHeader, "sign-header", false, signHeaderUsage)
+	packCmd.Flags().BoolVar(&ctx.Pack.RpmVerify, "verify", false, rpmVerifyUsage)
+
+	packCmd.Flags().StringSliceVar(&ctx.Pack.Deps, "deps", []string{}, depsUsage)
+	packCmd.Flags().StringVar(&ctx.Pack.DepsFile, "deps-file", "", depsFileUsage)
+
+	packCmd.Flags().StringVar(&ctx.Pack.DebConffilesFrom, "deb-conffiles-from", "", debConffilesFromUsage)
+	packCmd.Flags().StringSliceVar(&ctx.Pack.DebConffiles, "deb-conffile", []string{}, debConffileUsage)
+	packCmd.Flags().StringVar(&ctx.Pack.DebArch, "deb-arch", "", debArchUsage)
+
+	packCmd.Flags().StringVar(&ctx.Pack.PreInstScriptPath, "preinst", "", preInstUsage)
+	packCmd.Flags().StringVar(&ctx.Pack.PostInstScriptPath, "postinst", "", postInstUsage)
+	packCmd.Flags().StringVar(&ctx.Pack.PreRmScriptPath, "prerm", "", preRmUsage)
+	packCmd.Flags().StringVar(&ctx.Pack.PostRmScriptPath, "postrm", "", postRmUsage)
 
 	packCmd.Flags().BoolVar(&ctx.Build.InDocker, "use-docker", false, useDockerUsage)
 	packCmd.Flags().BoolVar(&ctx.Docker.NoCache, "no-cache", false, noCacheUsage)
 	packCmd.Flags().StringVar(&ctx.Build.DockerFrom, "build-from", "", buildFromUsage)
 	packCmd.Flags().StringVar(&ctx.Pack.DockerFrom, "from", "", fromUsage)
+	packCmd.Flags().StringVar(&ctx.Build.BaseImage, "build-base-image", "", buildBaseImageUsage)
+	packCmd.Flags().StringVar(&ctx.Pack.BaseImage, "base-image", "", baseImageUsage)
+	packCmd.Flags().StringVar(&ctx.Pack.RuntimeDockerfile, "dockerfile", "", dockerfileUsage)
 	packCmd.Flags().StringSliceVar(&ctx.Docker.CacheFrom, "cache-from", []string{}, cacheFromUsage)
+	packCmd.Flags().StringSliceVar(&ctx.Docker.Platforms, "platform", []string{}, platformUsage)
+	packCmd.Flags().StringArrayVar(&buildArgs, "build-arg", []string{}, buildArgUsage)
+	packCmd.Flags().StringArrayVar(&ctx.Docker.Secrets, "secret", []string{}, secretUsage)
 
 	packCmd.Flags().BoolVar(&ctx.Build.SDKLocal, "sdk-local", false, sdkLocalUsage)
 	packCmd.Flags().StringVar(&ctx.Build.SDKPath, "sdk-path", "", sdkPathUsage)
@@ -39,14 +106,18 @@ func init() {
 	packCmd.Flags().StringVar(
 		&ctx.Pack.StatboardUnitTemplatePath, "stateboard-unit-template", "", stateboardUnitTemplateUsage,
 	)
+
+	packCmd.Flags().StringArrayVar(&ctx.Pack.TmpfilesEntries, "tmpfiles-entry", []string{}, tmpfilesEntryUsage)
+	packCmd.Flags().StringArrayVar(&ctx.Pack.SysusersEntries, "sysusers-entry", []string{}, sysusersEntryUsage)
 }
 
 var packCmd = &cobra.Command{
-	Use:   "pack TYPE [PATH]",
+	Use:   "pack TYPE[,TYPE...] [PATH]",
 	Short: "Pack application into a distributable bundle",
 	Long: `Pack application into a distributable bundle
 
-The supported types are: rpm, tgz, docker, deb`,
+The supported types are: rpm, tgz, docker, deb, apk
+Several comma-separated types can be specified`,
 	Args: cobra.RangeArgs(1, 2),
 	Run: func(cmd *cobra.Command, args []string) {
 		err := runPackCommand(cmd, args)
@@ -64,21 +135,101 @@ The supported types are: rpm, tgz, docker, deb`,
 }
 
 func runPackCommand(cmd *cobra.Command, args []string) error {
-	ctx.Pack.Type = cmd.Flags().Arg(0)
+	var err error
+
+	packTypes, err := getPackTypes(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
 	ctx.Project.Path = cmd.Flags().Arg(1)
 	ctx.Cli.CartridgeTmpDir = os.Getenv(cartridgeTmpDirEnv)
 
-	if err := pack.Validate(&ctx); err != nil {
-		return err
+	if sourceDateEpochStr := os.Getenv(sourceDateEpochEnv); sourceDateEpochStr != "" {
+		ctx.Pack.SourceDateEpoch, err = strconv.ParseInt(sourceDateEpochStr, 10, 64)
+		if err != nil || ctx.Pack.SourceDateEpoch < 0 {
+			return fmt.Errorf("Invalid %s value %q: should be a Unix timestamp", sourceDateEpochEnv, sourceDateEpochStr)
+		}
+
+		ctx.Pack.SourceDateEpochIsSet = true
 	}
 
-	if err := pack.FillCtx(&ctx); err != nil {
-		return err
+	if rpmDigestWorkersStr := os.Getenv(rpmDigestWorkersEnv); rpmDigestWorkersStr != "" {
+		ctx.Pack.RpmDigestWorkers, err = strconv.Atoi(rpmDigestWorkersStr)
+		if err != nil || ctx.Pack.RpmDigestWorkers <= 0 {
+			return fmt.Errorf("Invalid %s value %q: should be a positive integer", rpmDigestWorkersEnv, rpmDigestWorkersStr)
+		}
+	}
+
+	if includeVCS {
+		if cmd.Flags().Changed("exclude-vcs") && ctx.Pack.ExcludeVCS {
+			return fmt.Errorf("--exclude-vcs and --include-vcs options can't be used together")
+		}
+
+		ctx.Pack.ExcludeVCS = false
+	}
+
+	if ctx.Pack.NoBuild && ctx.Build.InDocker {
+		return fmt.Errorf("--no-build and --use-docker options can't be used together")
+	}
+
+	if ctx.Pack.NoBuild && cmd.Flags().Changed("rocks-cache-dir") {
+		return fmt.Errorf("--no-build and --rocks-cache-dir options can't be used together")
+	}
+
+	setRocksCacheDir(cmd)
+
+	if splitSizeStr != "" {
+		if ctx.Pack.SplitSize, err = common.ParseSize(splitSizeStr); err != nil {
+			return fmt.Errorf(`Invalid argument %q for "--%s" flag: %s`, splitSizeStr, "split-size", err)
+		}
+
+		if ctx.Pack.SplitSize == 0 {
+			return fmt.Errorf(`Invalid argument %q for "--%s" flag: Size should be positive`,
+				splitSizeStr, "split-size")
+		}
+	}
+
+	ctx.Docker.BuildArgs = make(map[string]string)
+	for _, buildArg := range buildArgs {
+		name, value, err := docker.ParseBuildArg(buildArg)
+		if err != nil {
+			return fmt.Errorf(`Invalid argument %q for "--%s" flag: %s`, buildArg, "build-arg", err)
+		}
+
+		ctx.Docker.BuildArgs[name] = value
+	}
+
+	for _, secret := range ctx.Docker.Secrets {
+		if err := docker.CheckSecret(secret); err != nil {
+			return fmt.Errorf(`Invalid argument %q for "--%s" flag: %s`, secret, "secret", err)
+		}
 	}
 
-	if err := pack.Run(&ctx); err != nil {
+	if err := pack.RunTypes(&ctx, packTypes); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func getPackTypes(packTypesStr string) ([]string, error) {
+	var packTypes []string
+	specifiedTypes := make(map[string]bool)
+
+	for _, packType := range strings.Split(packTypesStr, ",") {
+		packType = strings.TrimSpace(packType)
+		if packType == "" {
+			return nil, fmt.Errorf("Invalid distribution types %q: empty type is specified", packTypesStr)
+		}
+
+		if specifiedTypes[packType] {
+			return nil, fmt.Errorf("Invalid distribution types %q: %s is specified twice", packTypesStr, packType)
+		}
+		specifiedTypes[packType] = true
+
+		packTypes = append(packTypes, packType)
+	}
+
+	return packTypes, nil
+}
@@ -71,6 +71,7 @@ All configuration files across directories <data-dir>/<app-name>.* are patched.`
 		ValidArgsFunction: ShellCompRepairRemove,
 	}
 	addCommonRepairPatchFlags(repairRemoveCmd)
+	repairRemoveCmd.Flags().BoolVar(&ctx.Repair.RemoveLeader, "remove-leader", false, repairRemoveLeaderUsage)
 
 	// set replicaset leader
 	var repairSetLeaderCmd = &cobra.Command{
@@ -92,11 +93,31 @@ All configuration files across directories <data-dir>/<app-name>.* are patched.`
 	}
 	addCommonRepairPatchFlags(repairSetLeaderCmd)
 
+	// restore instances config
+	var repairReloadConfigCmd = &cobra.Command{
+		Use:   "reload-config",
+		Short: "Restore instances configuration by running instances",
+		Long: `Find instances by PID files and console sockets in the run directory,
+get their names and advertise URIs using console and rewrite instances configuration file.
+Existing configuration file is backed up.`,
+
+		Args: cobra.ExactValidArgs(0),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runRepairCommand(repair.ReloadInstancesConf); err != nil {
+				log.Fatalf(err.Error())
+			}
+		},
+	}
+	repairReloadConfigCmd.Flags().StringVar(&ctx.Running.RunDir, "run-dir", "", prodRunDirUsage)
+	repairReloadConfigCmd.Flags().StringVar(&ctx.Running.ConfPath, "cfg", "", prodCfgUsage)
+	repairReloadConfigCmd.Flags().BoolVar(&ctx.Repair.DryRun, "dry-run", false, dryRunUsage)
+
 	repairSubCommands := []*cobra.Command{
 		repairListCmd,
 		repairURICmd,
 		repairRemoveCmd,
 		repairSetLeaderCmd,
+		repairReloadConfigCmd,
 	}
 
 	for _, cmd := range repairSubCommands {
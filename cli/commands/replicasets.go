@@ -1,6 +1,9 @@
 package commands
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
 	"github.com/tarantool/cartridge-cli/cli/context"
@@ -30,6 +33,8 @@ func init() {
 		},
 	}
 
+	listCmd.Flags().StringVar(&ctx.Replicasets.ListFormat, "format", replicasets.ListFormatText, replicasetsListFormatUsage)
+
 	// setup topology from file
 	var setupCmd = &cobra.Command{
 		Use:   "setup",
@@ -61,6 +66,20 @@ func init() {
 	}
 	saveCmd.Flags().StringVar(&ctx.Replicasets.File, "file", "", replicasetsSaveFileUsage)
 
+	// export topology and failover configuration to file
+	var exportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "Export current topology and failover configuration to file",
+
+		Args: cobra.ExactValidArgs(0),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runReplicasetsCommand(replicasets.Export, args); err != nil {
+				log.Fatalf(err.Error())
+			}
+		},
+	}
+	exportCmd.Flags().StringVar(&ctx.Replicasets.File, "file", "", replicasetsExportFileUsage)
+
 	// join instances to replicaset
 	var joinCmd = &cobra.Command{
 		Use:   "join INSTANCE_NAME...",
@@ -156,6 +175,39 @@ func init() {
 
 	addReplicasetFlag(setFailoverPriorityCmd)
 
+	// set failover configuration
+	var setFailoverCmd = &cobra.Command{
+		Use:   "set-failover MODE",
+		Short: "Set cluster failover configuration",
+		Long: fmt.Sprintf("Set cluster failover configuration\n\nMODE should be one of: %s",
+			strings.Join(replicasets.FailoverModes, ", ")),
+
+		Args:      cobra.ExactValidArgs(1),
+		ValidArgs: replicasets.FailoverModes,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx.Replicasets.FencingEnabledSet = cmd.Flags().Changed("fencing")
+
+			if err := runReplicasetsCommand(replicasets.SetFailover, args); err != nil {
+				log.Fatalf(err.Error())
+			}
+		},
+	}
+
+	setFailoverCmd.Flags().StringVar(&ctx.Replicasets.StateProvider, "state-provider", "", failoverStateProviderUsage)
+	setFailoverCmd.Flags().StringVar(&ctx.Replicasets.StateboardURI, "stateboard-uri", "", failoverStateboardURIUsage)
+	setFailoverCmd.Flags().StringVar(
+		&ctx.Replicasets.StateboardPassword, "stateboard-password", "", failoverStateboardPasswordUsage,
+	)
+	setFailoverCmd.Flags().StringSliceVar(
+		&ctx.Replicasets.Etcd2Endpoints, "etcd2-endpoints", []string{}, failoverEtcd2EndpointsUsage,
+	)
+	setFailoverCmd.Flags().StringVar(&ctx.Replicasets.Etcd2Prefix, "etcd2-prefix", "", failoverEtcd2PrefixUsage)
+	setFailoverCmd.Flags().Float64Var(&ctx.Replicasets.Etcd2LockDelay, "etcd2-lock-delay", 0, failoverEtcd2LockDelayUsage)
+	setFailoverCmd.Flags().StringVar(&ctx.Replicasets.Etcd2Username, "etcd2-username", "", failoverEtcd2UsernameUsage)
+	setFailoverCmd.Flags().StringVar(&ctx.Replicasets.Etcd2Password, "etcd2-password", "", failoverEtcd2PasswordUsage)
+	setFailoverCmd.Flags().Float64Var(&ctx.Replicasets.FailoverTimeout, "failover-timeout", 0, failoverTimeoutUsage)
+	setFailoverCmd.Flags().BoolVar(&ctx.Replicasets.FencingEnabled, "fencing", false, failoverFencingUsage)
+
 	// bootstrap vshard
 	var bootstrapVshardCmd = &cobra.Command{
 		Use:   "bootstrap-vshard",
@@ -197,21 +249,52 @@ func init() {
 		},
 	}
 
+	// backup topology configuration to file
+	var configBackupCmd = &cobra.Command{
+		Use:   "config-backup FILE",
+		Short: "Save current topology configuration to file",
+
+		Args: cobra.ExactValidArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runReplicasetsCommand(replicasets.ConfigBackup, args); err != nil {
+				log.Fatalf(err.Error())
+			}
+		},
+	}
+
+	// restore topology configuration from file
+	var configRestoreCmd = &cobra.Command{
+		Use:   "config-restore FILE",
+		Short: "Restore topology configuration from file",
+
+		Args: cobra.ExactValidArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runReplicasetsCommand(replicasets.ConfigRestore, args); err != nil {
+				log.Fatalf(err.Error())
+			}
+		},
+	}
+	configRestoreCmd.Flags().BoolVar(&ctx.Replicasets.Yes, "yes", false, replicasetsRestoreYesUsage)
+
 	// add all sub-commands
 
 	replicasetsSubCommands := []*cobra.Command{
 		listCmd,
 		setupCmd,
 		saveCmd,
+		exportCmd,
 		joinCmd,
 		expelCmd,
 		listRolesCmd,
 		addRolesCmd,
 		removeRolesCmd,
 		setFailoverPriorityCmd,
+		setFailoverCmd,
 		bootstrapVshardCmd,
 		setWeightCmd,
 		listVshardGroupsCmd,
+		configBackupCmd,
+		configRestoreCmd,
 	}
 
 	for _, cmd := range replicasetsSubCommands {
@@ -222,13 +305,15 @@ func init() {
 }
 
 func runReplicasetsCommand(replicasetsFunc func(ctx *context.Ctx, args []string) error, args []string) error {
-	if err := replicasets.FillCtx(&ctx); err != nil {
-		return err
-	}
+	return runWithSSHTunnel(func() error {
+		if err := replicasets.FillCtx(&ctx); err != nil {
+			return err
+		}
 
-	if err := replicasetsFunc(&ctx, args); err != nil {
-		return err
-	}
+		if err := replicasetsFunc(&ctx, args); err != nil {
+			return err
+		}
 
-	return nil
+		return nil
+	})
 }
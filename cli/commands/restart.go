@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+
+	"github.com/tarantool/cartridge-cli/cli/project"
+	"github.com/tarantool/cartridge-cli/cli/running"
+)
+
+func init() {
+	var restartCmd = &cobra.Command{
+		Use:   "restart [INSTANCE_NAME...]",
+		Short: "Restart application instance(s)",
+		Long:  fmt.Sprintf("Stop instance(s), wait for them to exit and start them again\n\n%s", runningCommonUsage),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := runRestartCmd(cmd, args)
+			if err != nil {
+				log.Fatalf(err.Error())
+			}
+		},
+		ValidArgsFunction: ShellCompRunningInstances,
+	}
+
+	rootCmd.AddCommand(restartCmd)
+
+	// FLAGS
+	configureFlags(restartCmd)
+
+	// application name flag
+	addNameFlag(restartCmd)
+
+	// stop-specific flags
+	restartCmd.Flags().BoolVarP(&ctx.Running.StopForced, "force", "f", false, stopForceUsage)
+	restartCmd.Flags().StringVar(&stopTimeoutStr, "stop-timeout", "", stopTimeoutUsage)
+
+	// start flags
+	addStartFlags(restartCmd, "start-timeout")
+}
+
+func runRestartCmd(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if err := setStartFlags(cmd, "start-timeout"); err != nil {
+		return err
+	}
+
+	if err := setDefaultValue(cmd.Flags(), "stop-timeout", defaultStopTimeout.String()); err != nil {
+		return project.InternalError("Failed to set default stop timeout value: %s", err)
+	}
+
+	if ctx.Running.StopTimeout, err = getDuration(stopTimeoutStr); err != nil {
+		cmd.Usage()
+		return fmt.Errorf(`Invalid argument %q for "--%s" flag: %s`, stopTimeoutStr, "stop-timeout", err)
+	}
+
+	if err := running.FillCtx(&ctx, args); err != nil {
+		return err
+	}
+
+	if err := running.Restart(&ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+)
+
+func addSSHFlag(flagSet *pflag.FlagSet) {
+	flagSet.StringVar(&ctx.Connect.SSH, "ssh", "", sshUsage)
+}
+
+// runWithSSHTunnel runs the command function with the console sockets of the
+// remote run directory forwarded to the local ones if --ssh is specified.
+// Run directory is replaced with the directory that contains local sockets,
+// so the command works with remote instances the same way as with local ones.
+// Tunnel is closed after the function is finished
+func runWithSSHTunnel(run func() error) error {
+	if ctx.Connect.SSH == "" {
+		return run()
+	}
+
+	if ctx.Connect.Conn != "" {
+		return fmt.Errorf("--ssh and --conn options can't be used together")
+	}
+
+	remoteRunDir := ctx.Running.RunDir
+	if remoteRunDir == "" {
+		remoteRunDir = defaultRemoteRunDir
+	}
+
+	tunnel, err := common.OpenSSHTunnel(ctx.Connect.SSH)
+	if err != nil {
+		return err
+	}
+	defer tunnel.Close()
+
+	if ctx.Running.RunDir, err = tunnel.ForwardConsoleSockets(remoteRunDir); err != nil {
+		return err
+	}
+
+	return run()
+}
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"runtime"
 
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
@@ -11,7 +12,8 @@ import (
 )
 
 var (
-	timeoutStr string
+	timeoutStr     string
+	waitTimeoutStr string
 )
 
 func init() {
@@ -36,40 +38,105 @@ func init() {
 	// application name flag
 	addNameFlag(startCmd)
 
-	// start-specific flags
-	startCmd.Flags().BoolVarP(&ctx.Running.Daemonize, "daemonize", "d", false, daemonizeUsage)
-	startCmd.Flags().StringVar(&timeoutStr, "timeout", "", timeoutUsage)
+	// start flags
+	addStartFlags(startCmd, "timeout")
+}
+
+func runStartCmd(cmd *cobra.Command, args []string) error {
+	if err := setStartFlags(cmd, "timeout"); err != nil {
+		return err
+	}
+
+	if err := running.FillCtx(&ctx, args); err != nil {
+		return err
+	}
+
+	if err := running.Start(&ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// addStartFlags adds flags of the instances start,
+// they are used by start and restart commands.
+// timeoutFlag is the name of the start timeout flag
+// (restart uses "start-timeout" to distinguish it from the stop one)
+func addStartFlags(cmd *cobra.Command, timeoutFlag string) {
+	cmd.Flags().BoolVarP(&ctx.Running.Daemonize, "daemonize", "d", false, daemonizeUsage)
+	cmd.Flags().StringVar(&timeoutStr, timeoutFlag, "", timeoutUsage)
+	cmd.Flags().IntVar(&ctx.Running.StartParallelism, "parallelism", runtime.NumCPU(), parallelismUsage)
+	cmd.Flags().BoolVar(&ctx.Running.WaitSocket, "wait-socket", false, waitSocketUsage)
+	cmd.Flags().BoolVar(&ctx.Running.WaitHealthy, "wait", false, waitHealthyUsage)
+	cmd.Flags().StringVar(&waitTimeoutStr, "wait-timeout", "", waitTimeoutUsage)
 
 	// stateboard flags
-	addStateboardRunningFlags(startCmd)
+	addStateboardRunningFlags(cmd)
 
 	// common running paths
-	addCommonRunningPathsFlags(startCmd)
-	// start-specific paths
-	startCmd.Flags().StringVar(&ctx.Running.DataDir, "data-dir", "", dataDirUsage)
-	startCmd.Flags().StringVar(&ctx.Running.LogDir, "log-dir", "", logDirUsage)
-	startCmd.Flags().StringVar(&ctx.Running.Entrypoint, "script", "", scriptUsage)
+	addCommonRunningPathsFlags(cmd)
+
+	// add --allow-unknown flag
+	addAllowUnknownFlag(cmd)
 
+	// start-specific paths
+	cmd.Flags().StringVar(&ctx.Running.DataDir, "data-dir", "", dataDirUsage)
+	cmd.Flags().StringVar(&ctx.Running.LogDir, "log-dir", "", logDirUsage)
+	cmd.Flags().StringVar(&ctx.Running.Entrypoint, "script", "", scriptUsage)
+
+	// credentials flags
+	cmd.Flags().StringVar(&ctx.Running.RunUser, "run-user", "", runUserUsage)
+	cmd.Flags().StringVar(&ctx.Running.RunGroup, "run-group", "", runGroupUsage)
+
+	// instance options flags
+	cmd.Flags().StringVar(&ctx.Running.ClusterCookie, "cluster-cookie", "", clusterCookieUsage)
+	cmd.Flags().StringVar(&ctx.Running.AdvertiseURI, "advertise-uri", "", advertiseURIUsage)
+	cmd.Flags().IntVar(&ctx.Running.HTTPPort, "http-port", 0, httpPortUsage)
+	cmd.Flags().StringArrayVar(&ctx.Running.EnvFiles, "env-file", []string{}, envFileUsage)
 }
 
-func runStartCmd(cmd *cobra.Command, args []string) error {
+// setStartFlags checks start flags and sets the context values
+func setStartFlags(cmd *cobra.Command, timeoutFlag string) error {
 	var err error
 
-	if err := setDefaultValue(cmd.Flags(), "timeout", defaultStartTimeout.String()); err != nil {
+	if err := setDefaultValue(cmd.Flags(), timeoutFlag, defaultStartTimeout.String()); err != nil {
 		return project.InternalError("Failed to set default timeout value: %s", err)
 	}
 
 	if ctx.Running.StartTimeout, err = getDuration(timeoutStr); err != nil {
 		cmd.Usage()
-		return fmt.Errorf(`Invalid argument %q for "--%s" flag: %s`, timeoutStr, "timeout", err)
+		return fmt.Errorf(`Invalid argument %q for "--%s" flag: %s`, timeoutStr, timeoutFlag, err)
 	}
 
-	if err := running.FillCtx(&ctx, args); err != nil {
-		return err
+	if err := setDefaultValue(cmd.Flags(), "wait-timeout", defaultWaitTimeout.String()); err != nil {
+		return project.InternalError("Failed to set default wait timeout value: %s", err)
 	}
 
-	if err := running.Start(&ctx); err != nil {
-		return err
+	if ctx.Running.WaitTimeout, err = getDuration(waitTimeoutStr); err != nil {
+		cmd.Usage()
+		return fmt.Errorf(`Invalid argument %q for "--%s" flag: %s`, waitTimeoutStr, "wait-timeout", err)
+	}
+
+	if ctx.Running.WaitSocket && !ctx.Running.Daemonize {
+		cmd.Usage()
+		return fmt.Errorf("--wait-socket flag can be used only with --daemonize flag")
+	}
+
+	if ctx.Running.WaitHealthy && !ctx.Running.Daemonize {
+		cmd.Usage()
+		return fmt.Errorf("--wait flag can be used only with --daemonize flag")
+	}
+
+	if ctx.Running.StartParallelism <= 0 {
+		cmd.Usage()
+		return fmt.Errorf(`Invalid argument %d for "--%s" flag: should be positive`,
+			ctx.Running.StartParallelism, "parallelism")
+	}
+
+	if ctx.Running.HTTPPort < 0 || ctx.Running.HTTPPort > 65535 {
+		cmd.Usage()
+		return fmt.Errorf(`Invalid argument %d for "--%s" flag: should be in range [1, 65535]`,
+			ctx.Running.HTTPPort, "http-port")
 	}
 
 	return nil
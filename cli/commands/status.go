@@ -2,13 +2,24 @@ package commands
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/apex/log"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 
+	"github.com/tarantool/cartridge-cli/cli/replicasets"
 	"github.com/tarantool/cartridge-cli/cli/running"
 )
 
+var (
+	waitForExpectedStr string
+	ageWarnStr         string
+	statusJSONPretty   bool
+	statusJSONCompact  bool
+	statusIntervalStr  string
+)
+
 func init() {
 	var statusCmd = &cobra.Command{
 		Use:   "status [INSTANCE_NAME...]",
@@ -36,16 +47,177 @@ func init() {
 
 	// common running paths
 	addCommonRunningPathsFlags(statusCmd)
+
+	// add --allow-unknown flag
+	addAllowUnknownFlag(statusCmd)
+
+	// status-specific flags
+	statusCmd.Flags().IntVar(&ctx.Running.InstancesExpected, "instances-expected", 0, instancesExpectedUsage)
+	statusCmd.Flags().StringVar(&waitForExpectedStr, "wait-for-expected", "", waitForExpectedUsage)
+	statusCmd.Flags().StringVar(&ageWarnStr, "age-warn", "", ageWarnUsage)
+	statusCmd.Flags().BoolVar(&ctx.Running.ReplicasetHealth, "replicaset-health", false, replicasetHealthUsage)
+	statusCmd.Flags().BoolVar(&ctx.Running.GroupTags, "group-tags", false, groupTagsUsage)
+	statusCmd.Flags().BoolVar(&ctx.Running.PidFileCheck, "pid-file-check", false, pidFileCheckUsage)
+	statusCmd.Flags().BoolVar(&ctx.Running.SocketCheck, "socket-check", false, socketCheckUsage)
+	statusCmd.Flags().BoolVar(&ctx.Running.StatusExitCode, "exit-code", false, statusExitCodeUsage)
+	statusCmd.Flags().StringVar(&ctx.Running.StatusFormat, "format", running.StatusFormatText, statusFormatUsage)
+	statusCmd.Flags().BoolVar(&statusJSONPretty, "json-pretty", false, statusJSONPrettyUsage)
+	statusCmd.Flags().BoolVar(&statusJSONCompact, "json-compact", false, statusJSONCompactUsage)
+
+	// watch flags
+	statusCmd.Flags().BoolVar(&ctx.Running.StatusWatch, "watch", false, statusWatchUsage)
+	statusCmd.Flags().StringVar(&statusIntervalStr, "interval", "2s", statusWatchIntervalUsage)
 }
 
 func runStatusCmd(cmd *cobra.Command, args []string) error {
+	var err error
+
+	ctx.Running.CheckInstancesExpected = cmd.Flags().Changed("instances-expected")
+
+	if ctx.Running.PidFileCheck {
+		for _, flagName := range []string{"instances-expected", "age-warn", "replicaset-health", "group-tags", "socket-check", "exit-code"} {
+			if cmd.Flags().Changed(flagName) {
+				return fmt.Errorf("--pid-file-check and --%s options can't be used together", flagName)
+			}
+		}
+	}
+
+	if err := running.CheckStatusFormat(ctx.Running.StatusFormat); err != nil {
+		return fmt.Errorf(`Invalid argument %q for "--%s" flag: %s`, ctx.Running.StatusFormat, "format", err)
+	}
+
+	if err := setStatusJSONCompact(cmd); err != nil {
+		return err
+	}
+
+	if ctx.Running.CheckInstancesExpected && ctx.Running.InstancesExpected < 0 {
+		return fmt.Errorf(`Invalid argument %d for "--%s" flag: Negative count is specified`,
+			ctx.Running.InstancesExpected, "instances-expected")
+	}
+
+	if waitForExpectedStr != "" {
+		if !ctx.Running.CheckInstancesExpected {
+			return fmt.Errorf("--wait-for-expected option can be used only with --instances-expected flag")
+		}
+
+		if ctx.Running.WaitForExpected, err = getDuration(waitForExpectedStr); err != nil {
+			cmd.Usage()
+			return fmt.Errorf(`Invalid argument %q for "--%s" flag: %s`, waitForExpectedStr, "wait-for-expected", err)
+		}
+	}
+
+	if ageWarnStr != "" {
+		if ctx.Running.AgeWarn, err = getDuration(ageWarnStr); err != nil {
+			cmd.Usage()
+			return fmt.Errorf(`Invalid argument %q for "--%s" flag: %s`, ageWarnStr, "age-warn", err)
+		}
+	}
+
+	if err := setStatusWatch(cmd); err != nil {
+		return err
+	}
+
 	if err := running.FillCtx(&ctx, args); err != nil {
 		return err
 	}
 
+	if ctx.Running.StatusWatch {
+		// instances are collected from the configuration on each redraw
+		// if they aren't specified, so the configuration changes are shown
+		instances := ctx.Running.Instances
+
+		running.Watch(ctx.Running.StatusWatchInterval, func() error {
+			ctx.Running.Instances = instances
+			return showStatus()
+		})
+
+		return nil
+	}
+
+	return showStatus()
+}
+
+func showStatus() error {
 	if err := running.Status(&ctx); err != nil {
 		return err
 	}
 
+	if ctx.Running.ReplicasetHealth {
+		if err := replicasets.ReplicasetsHealth(&ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setStatusWatch checks watch mode flags.
+// Watch mode redraws the terminal, so stdout should be a terminal
+func setStatusWatch(cmd *cobra.Command) error {
+	var err error
+
+	if !ctx.Running.StatusWatch {
+		if cmd.Flags().Changed("interval") {
+			return fmt.Errorf("--interval option can be used only with --watch flag")
+		}
+
+		return nil
+	}
+
+	if !isatty.IsTerminal(os.Stdout.Fd()) {
+		return fmt.Errorf("--watch option can be used only if stdout is a terminal")
+	}
+
+	for _, flagName := range []string{"format", "wait-for-expected", "pid-file-check"} {
+		if cmd.Flags().Changed(flagName) {
+			return fmt.Errorf("--watch and --%s options can't be used together", flagName)
+		}
+	}
+
+	if ctx.Running.StatusWatchInterval, err = getDuration(statusIntervalStr); err != nil {
+		cmd.Usage()
+		return fmt.Errorf(`Invalid argument %q for "--%s" flag: %s`, statusIntervalStr, "interval", err)
+	}
+
+	if ctx.Running.StatusWatchInterval <= 0 {
+		cmd.Usage()
+		return fmt.Errorf(`Invalid argument %q for "--%s" flag: should be positive`, statusIntervalStr, "interval")
+	}
+
+	return nil
+}
+
+// setStatusJSONCompact chooses JSON output style.
+// By default, JSON is indented if stdout is a terminal and compact otherwise
+func setStatusJSONCompact(cmd *cobra.Command) error {
+	jsonFlagsChanged := cmd.Flags().Changed("json-pretty") || cmd.Flags().Changed("json-compact")
+
+	if ctx.Running.StatusFormat != running.StatusFormatJSON {
+		if jsonFlagsChanged {
+			return fmt.Errorf("--json-pretty and --json-compact options can be used only with --format json")
+		}
+
+		return nil
+	}
+
+	if statusJSONPretty && statusJSONCompact {
+		return fmt.Errorf("You can specify only one of --json-pretty and --json-compact options")
+	}
+
+	for _, flagName := range []string{"group-tags", "pid-file-check"} {
+		if cmd.Flags().Changed(flagName) {
+			return fmt.Errorf("--format json and --%s options can't be used together", flagName)
+		}
+	}
+
+	switch {
+	case statusJSONPretty:
+		ctx.Running.StatusJSONCompact = false
+	case statusJSONCompact:
+		ctx.Running.StatusJSONCompact = true
+	default:
+		ctx.Running.StatusJSONCompact = !isatty.IsTerminal(os.Stdout.Fd())
+	}
+
 	return nil
 }
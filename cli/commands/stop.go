@@ -6,14 +6,22 @@ import (
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
+	"github.com/tarantool/cartridge-cli/cli/project"
 	"github.com/tarantool/cartridge-cli/cli/running"
 )
 
+var (
+	stopTimeoutStr string
+)
+
 func init() {
 	var stopCmd = &cobra.Command{
 		Use:   "stop [INSTANCE_NAME...]",
 		Short: "Stop instance(s)",
-		Long:  fmt.Sprintf("Stop instance(s) (sends SIGTERM)\n%s", runningCommonUsage),
+		Long: fmt.Sprintf(
+			"Stop instance(s) (sends SIGTERM, SIGKILL is sent if instance doesn't exit in --timeout)\n%s",
+			runningCommonUsage,
+		),
 		Run: func(cmd *cobra.Command, args []string) {
 			err := runStopCmd(cmd, args)
 			if err != nil {
@@ -37,11 +45,27 @@ func init() {
 	// common running paths
 	addCommonRunningPathsFlags(stopCmd)
 
+	// add --allow-unknown flag
+	addAllowUnknownFlag(stopCmd)
+
 	// add --force flag
 	stopCmd.Flags().BoolVarP(&ctx.Running.StopForced, "force", "f", false, stopForceUsage)
+	// add --timeout flag
+	stopCmd.Flags().StringVar(&stopTimeoutStr, "timeout", "", stopTimeoutUsage)
 }
 
 func runStopCmd(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if err := setDefaultValue(cmd.Flags(), "timeout", defaultStopTimeout.String()); err != nil {
+		return project.InternalError("Failed to set default timeout value: %s", err)
+	}
+
+	if ctx.Running.StopTimeout, err = getDuration(stopTimeoutStr); err != nil {
+		cmd.Usage()
+		return fmt.Errorf(`Invalid argument %q for "--%s" flag: %s`, stopTimeoutStr, "timeout", err)
+	}
+
 	if err := running.FillCtx(&ctx, args); err != nil {
 		return err
 	}
@@ -7,7 +7,21 @@ const (
 	createNameUsage = `Application name`
 	templateUsage   = `Application template name
 defaults to cartridge`
-	createFromUsage = `Path to the application template`
+	createFromUsage = `Path to the application template
+or git repository URL (e.g. git@host:org/template.git)`
+
+	createRefUsage = `Branch or tag of the template git repository
+(used with --from)`
+
+	createSetUsage = `Template variable value in KEY=VALUE format
+(variables are described in the template.yml file of the template)`
+
+	postCreateHookUsage = `Path to the script that should be run in the
+application directory after it's created
+defaults to cartridge.post-create from the template (if exists)`
+
+	createDryRunUsage = `List files that would be created without writing anything
+on disk, initializing git repository and running post-create hook`
 )
 
 // COMMON
@@ -22,6 +36,9 @@ const (
 The default version is determined by
 "git describe --tags --long"`
 
+	releaseUsage = `Application package release
+overrides the release determined by --version or "git describe"`
+
 	suffixUsage = `Result file (or image) name suffix`
 
 	unitTemplateUsage = `systemd unit template`
@@ -30,13 +47,39 @@ The default version is determined by
 
 	stateboardUnitTemplateUsage = `Stateboard systemd unit template`
 
+	tmpfilesEntryUsage = `tmpfiles.d entry(ies) of the package
+The entry replaces the default one with the same path
+Used for rpm, deb and apk types`
+
+	sysusersEntryUsage = `sysusers.d entry(ies) of the package
+The entry replaces the default one with the same type and name
+Used for rpm and deb types`
+
+	buildTargetUsage = `Build target (phase) that is passed to the pre-build
+hook via CARTRIDGE_BUILD_TARGET environment variable`
+
+	buildSpecUsage = `Rockspec to build (relative to the application directory)
+Required if the application directory contains multiple rockspecs`
+
+	rocksCacheDirUsage = `Directory to cache installed rocks and luarocks downloads,
+so rocks are reused across builds (mounted to the container on Docker build)
+Defaults to CARTRIDGE_ROCKS_CACHE_DIR environment variable value`
+
 	useDockerUsage = `Forces to build the application in Docker`
 
-	tagUsage = `Tag(s) of the result Docker image`
+	tagUsage = `Tag(s) of the result Docker image (can be specified multiple times)
+Tag can contain {{ .Name }}, {{ .Version }}, {{ .Release }}
+and {{ .GitSHA }} template variables`
+
+	pushUsage = `Push the result Docker image tags to the registry
+using the current docker credentials`
 
 	fromUsage = `Base runtime image Dockerfile
 defaults to Dockerfile.cartridge`
 
+	dockerfileUsage = `Runtime image Dockerfile that is used
+instead of the generated one`
+
 	buildFromUsage = `Base build image Dockerfile
 defaults to Dockerfile.build.cartridge`
 
@@ -46,10 +89,161 @@ on creation build and runtime images`
 	cacheFromUsage = `Use "--cache-from" docker flag
 on creation build and runtime images`
 
+	buildBaseImageUsage = `Image that replaces the base image (FROM instruction)
+of the build image Dockerfile (default is centos:8)`
+
+	baseImageUsage = `Image that replaces the base image (FROM instruction)
+of the runtime image Dockerfile (default is centos:8)
+Used for docker type`
+
+	buildArgUsage = `Build argument KEY=VALUE passed to docker build
+on creation build and runtime images (can be specified multiple times)`
+
+	secretUsage = `Secret passed to docker build on creation build and runtime images
+(e.g. id=token,src=token.txt), requires BuildKit
+Secrets aren't stored in the image layers (can be specified multiple times)`
+
+	platformUsage = `Target platforms of the multi-platform image
+(e.g. linux/amd64,linux/arm64), requires docker buildx
+Images are pushed and combined into the manifest list
+Used for docker type
+For deb type, the only platform can be specified with --use-docker flag`
+
 	sdkPathUsage = `Path to the SDK to be delivered
 defaults to "TARANTOOL_SDK_PATH" env`
 
 	sdkLocalUsage = `Deliver the SDK from the local machine`
+
+	includeEmptyDirsUsage = `Deliver empty directories from the
+application directory to the result package`
+
+	ensureDirUsage = `Directory(ies) that should be created in the
+result package (relative to the application directory)`
+
+	excludeVCSUsage = `Remove VCS and editor metadata files
+(.git, .hg, .svn, *.swp, .DS_Store) from the result package
+enabled by default`
+
+	includeVCSUsage = `Deliver VCS and editor metadata files
+to the result package (overrides --exclude-vcs)`
+
+	verifyNoAbsSymlinksUsage = `Fail if the result package contains
+symlinks to absolute paths`
+
+	normalizeLineEndingsUsage = `Convert CRLF line endings to LF in the application
+text files (binary files are detected by content and left untouched)`
+
+	rocksManifestUsage = `Write versions of the rocks installed to the application
+to rocks-manifest.json file of the result package
+(use --rocks-manifest=false to disable)`
+
+	noBuildUsage = `Pack the application directory as is without building it
+(the application should be built by "cartridge build" before)`
+
+	keepGoingUsage = `Continue packing into the rest types if packing
+into one of them fails (used if several types are specified)`
+
+	packDryRunUsage = `Print files that would be packed and the package metadata
+(version, release, dependencies, install scripts) without building
+the application and creating the package`
+
+	defaultFileModeUsage = `Octal mode set to all package files (e.g. 0644)
+Used for rpm, deb and apk types`
+
+	defaultDirModeUsage = `Octal mode set to all package directories (e.g. 0755)
+Used for rpm, deb and apk types`
+
+	fileModeUsage = `GLOB=MODE octal mode set to the package entries matching GLOB
+GLOB that contains "/" is matched against the absolute path,
+otherwise it's matched against the base name
+Can be specified several times, the last matching one wins
+Used for rpm, deb and apk types`
+
+	transformUsage = `Sed-style expression s|FROM|TO|[g] applied to the package
+files paths (e.g. "s|^/usr/share/tarantool/myapp/conf/|/etc/myapp/|")
+Can be specified several times, expressions are applied in order`
+
+	splitSizeUsage = `Split result TGZ archive into parts of specified size
+(e.g. 100M) and write manifest that describes them`
+
+	outputDirUsage = `Directory to write the result package to
+(default is the current directory, created if doesn't exist)`
+
+	checksumUsage = `Write <package>.sha256 file with the result package
+SHA256 in the sha256sum format`
+
+	compressionUsage = `Result TGZ archive compression: gzip (default) or zstd
+(zstd archive has .tar.zst extension)`
+
+	compressionLevelUsage = `Compression level of the TGZ archive, RPM payload or DEB data archive:
+from 1 (fastest) to 9 (best) for gzip, from 1 to 22 for zstd
+By default, gzip default level is used for TGZ and DEB, best level for RPM`
+
+	recommendsUsage = `RPM package weak dependency(ies) (Recommends)
+For example, "tarantool-metrics >= 0.6.0"`
+
+	supplementsUsage = `RPM package weak reverse dependency(ies) (Supplements)
+For example, "tarantool >= 2.5"`
+
+	enhancesUsage = `RPM package weak reverse dependency(ies) (Enhances)
+For example, "tarantool"`
+
+	rpmMinVersionUsage = `Minimal RPM version on the target systems
+Fails if weak dependencies are specified and it's lower than 4.12`
+
+	rpmBuildHostUsage = `RPM package build host (BUILDHOST tag)
+Defaults to "localhost"`
+
+	payloadDigestAlgoUsage = `RPM package payload digest algorithm
+(PAYLOADDIGESTALGO tag): sha1, sha256 or sha512
+Defaults to "sha256"`
+
+	signKeyUsage = `GPG key (ID, user ID or path to the secret key file)
+used to sign RPM package header and payload (requires gpg)`
+
+	reproducibleUsage = `Build RPM package reproducibly: mtimes are clamped to
+SOURCE_DATE_EPOCH (zero if it isn't set), inodes are renumbered`
+
+	signHeaderUsage = `Additionally sign RPM package header only
+(v4 header signature), can be used only with --sign-key`
+
+	rpmVerifyUsage = `Verify the result RPM package: all package files should be
+present in the header and in the payload with the same sizes and digests`
+
+	relocateDocsUsage = `Directory in the RPM package to move documentation
+files to (e.g. /usr/share/doc/myapp), moved files are marked as %doc`
+
+	docPatternUsage = `Pattern(s) of the documentation files paths
+relative to the application directory, used with --relocate-docs
+Defaults to README*, LICENSE*, docs`
+
+	depsUsage = `Package dependency(ies) in the "name [operator version]" format
+Tarantool dependency is auto-detected unless it's specified
+Used for rpm and deb types`
+
+	depsFileUsage = `File that lists package dependencies (one per line)
+Used for rpm and deb types`
+
+	debConffilesFromUsage = `File that lists DEB package conffiles (one path per line)
+Relative paths are considered relative to the application directory`
+
+	debConffileUsage = `DEB package conffile(s)
+Relative paths are considered relative to the application directory`
+
+	debArchUsage = `DEB package architecture (e.g. amd64, arm64 or all)
+By default, it's detected by the target platform`
+
+	preInstUsage = `Shell script appended to the generated pre-install script
+Used for rpm and deb types`
+
+	postInstUsage = `Shell script appended to the generated post-install script
+Used for rpm and deb types`
+
+	preRmUsage = `Shell script run before the package removal
+Used for rpm and deb types`
+
+	postRmUsage = `Shell script run after the package removal
+Used for rpm and deb types`
 )
 
 // RUNNING
@@ -78,27 +272,141 @@ defaults to ./tmp/log ("log-dir" in .cartridge.yml)`
 	cfgUsage = `Configuration file for instances
 defaults to ./instances.yml ("cfg" in .cartridge.yml)`
 
+	allowUnknownUsage = `Don't check that specified instances are
+described in the configuration file (see --cfg)`
+
 	daemonizeUsage = `Start instance(s) in background`
 
+	parallelismUsage = `Maximum number of instances that are started
+at the same time, defaults to the number of CPUs`
+
+	waitSocketUsage = `Wait until instance(s) console socket is available
+instead of waiting for instance(s) full readiness (used with --daemonize)`
+
+	runUserUsage = `User to run instance(s) as (name or UID)
+Run and work directories are owned by this user`
+
+	runGroupUsage = `Group to run instance(s) as (name or GID)
+defaults to the --run-user primary group`
+
+	clusterCookieUsage = `Cluster cookie passed to instance(s) as TARANTOOL_CLUSTER_COOKIE
+Overrides TARANTOOL_CLUSTER_COOKIE environment variable,
+that overrides "cluster_cookie" in the instances config`
+
+	advertiseURIUsage = `Advertise URI passed to instance as TARANTOOL_ADVERTISE_URI
+Can be used only if one instance is started
+Overrides TARANTOOL_ADVERTISE_URI environment variable,
+that overrides "advertise_uri" in the instances config`
+
+	httpPortUsage = `HTTP port passed to instance as TARANTOOL_HTTP_PORT
+Can be used only if one instance is started
+Overrides TARANTOOL_HTTP_PORT environment variable,
+that overrides "http_port" in the instances config`
+
+	envFileUsage = `File with KEY=VALUE lines to pass to instance(s) environment
+Can be specified multiple times, files are merged in order
+Variables from files override the environment ones,
+but are overridden by the flags`
+
+	waitHealthyUsage = `Wait until started instance(s) box.info.status is "running"
+(used with --daemonize)`
+
 	stateboardUsage = `Manage application stateboard as well as instances`
 
 	stateboardOnlyUsage = `Manage only application stateboard`
 
 	logFollowUsage = `Output appended data as the log grows`
 
+	logOutputDirUsage = `Directory to write each instance log to
+the separate <instance>.log file instead of stdout`
+
+	logMaxLineLengthUsage = `Truncate log lines longer than specified
+bytes count`
+
+	logLevelUsage = `Show only lines of the specified log level and more severe ones
+(FATAL, SYSERROR, ERROR, CRIT, WARN, INFO, VERBOSE, DEBUG)`
+
+	logGrepUsage = `Show only lines that match the regular expression`
+
+	logNoColorUsage = `Don't colorize instances prefixes and log lines`
+
 	stopForceUsage = `Force instance(s) stop (sends SIGKILL)`
+
+	cleanDryRunUsage = `Print files that would be removed without removing them`
+
+	instancesExpectedUsage = `Expected count of running instances
+Command fails if the count doesn't match`
+
+	waitForExpectedUsage = `Time to wait for expected count of running instances
+Can be used only with --instances-expected`
+
+	ageWarnUsage = `Warn about running instances with uptime
+less than specified duration (e.g. crash loops)`
+
+	replicasetHealthUsage = `Show replica sets health and fail if some
+replica set is unhealthy (leader is down or there is no quorum)`
+
+	groupTagsUsage = `Show running/total instances count for each tag
+specified in instances configuration`
+
+	pidFileCheckUsage = `Report PID files state instead of instances status
+Command fails if some PID files are stale`
+
+	socketCheckUsage = `Ping console sockets of running instances and
+report instances that don't respond in 1s as HUNG
+and instances whose sockets can't be dialed as UNREACHABLE`
+
+	statusExitCodeUsage = `Fail if some instances aren't running
+With --age-warn, fail if some instances are running less than specified duration`
+
+	statusFormatUsage = `Status output format (text or json)
+table is the alias of text`
+
+	statusJSONPrettyUsage = `Indent JSON status output
+(default if stdout is a terminal)`
+
+	statusJSONCompactUsage = `Write JSON status output in a single line
+(default if stdout isn't a terminal)`
+
+	statusWatchUsage = `Redraw status periodically until interrupted
+Can be used only if stdout is a terminal`
+
+	statusWatchIntervalUsage = `Status redraw interval
+Can be used only with --watch`
 )
 
 // REPLICASETS
 const (
+	replicasetsListFormatUsage = `Replica sets list output format (text, json or yaml)`
+
 	replicasetsSetupFileUsage = `File where replica sets configuration is described
 Defaults to replicasets.yml`
 
 	replicasetsSaveFileUsage = `File where replica sets configuration should be saved
 Defaults to replicasets.yml`
 
+	replicasetsExportFileUsage = `File where topology configuration should be exported,
+"-" to write it to stdout
+Defaults to replicasets.yml`
+
 	replicasetsBootstrapVshardUsage = `Bootstrap vshard`
 
+	replicasetsRestoreYesUsage = `Apply destructive changes (roles removal,
+leader, weight or vshard group change) without confirmation`
+
+	failoverStateProviderUsage = `Failover state provider (stateboard or etcd2),
+required for stateful failover`
+	failoverStateboardURIUsage      = `Stateboard URI, required for stateboard state provider`
+	failoverStateboardPasswordUsage = `Stateboard password, required for stateboard state provider`
+	failoverEtcd2EndpointsUsage     = `etcd2 endpoints (comma-separated)`
+	failoverEtcd2PrefixUsage        = `etcd2 prefix`
+	failoverEtcd2LockDelayUsage     = `etcd2 lock delay in seconds`
+	failoverEtcd2UsernameUsage      = `etcd2 username`
+	failoverEtcd2PasswordUsage      = `etcd2 password`
+	failoverTimeoutUsage            = `Failover timeout in seconds`
+	failoverFencingUsage            = `Enable fencing (for stateful failover only),
+use --fencing=false to disable it`
+
 	replicasetNameUsage = `Name of replica set`
 	vshardGroupUsage    = `Vshard group for vshard-storage replica set`
 )
@@ -110,6 +418,10 @@ Defaults to /var/lib/tarantool`
 
 	prodRunDirUsage = `Directory where PID and socket files are stored
 Defaults to /var/run/tarantool`
+
+	prodCfgUsage = `Instances configuration file or directory
+If it's a directory, <app-name>.yml file in it is used
+Defaults to /etc/tarantool/conf.d`
 )
 
 // REPAIR
@@ -119,19 +431,94 @@ Show changes but don't apply them`
 
 	repairForceUsage = `Repair different configs separately`
 
+	repairRemoveLeaderUsage = `Allow to remove the current replicaset leader
+The next instance of the replicaset becomes the leader`
+
 	repairReloadUsage = `Reload config on instances after patch`
 )
 
 // CONNECT
 const (
+	enterConnUsage = `Instance console or binary port address (host:port)
+to connect over TCP instead of the console socket`
+
+	enterEvalUsage = `Lua expression to evaluate instead of running interactive console,
+"-" to read it from stdin`
+
 	connectUsernameUsage = `Username`
 	connectPasswordUsage = `Password`
+
+	sshUsage = `Remote host USER@HOST[:PORT] to connect over SSH
+Instances console sockets from the run directory on this host
+(defaults to /var/run/tarantool) are forwarded to the local ones
+Local SSH agent is used for authentication`
+
+	connectEvalTimeoutUsage = `Time to wait for each statement execution
+The statement is cancelled on the instance if timeout is reached
+By default, there is no timeout`
+
+	connectFormatUsage = `Format to render returned values in
+Supported formats are: lua, yaml, json
+Defaults to lua`
+
+	adminFormatUsage = `Format to print the function return value in
+Supported formats are: json
+Non-serializable values (e.g. userdata) are printed as "<non-serializable: TYPE>"
+By default, the returned strings are logged`
+)
+
+// EVAL
+const (
+	evalTimeoutUsage = `Time to wait for the result from each instance
+Defaults to 10s`
+
+	evalConnectAllUsage = `Evaluate function body on all configured instances
+and print the summary of returned values`
+
+	evalQuorumUsage = `Minimal number of instances that should return
+the same value, otherwise command fails
+By default, command fails if evaluation failed on some instance`
+
+	evalRetryUsage = `Maximum number of evaluation attempts on the instance
+(including the first one) if it fails with a transient error`
+
+	evalRetryDelayUsage = `Time to wait before each retry
+Can be used only with --retry`
+
+	evalRetryOnUsage = `Regular expression that matches transient errors
+Can be specified several times
+By default, connection and timeout errors are considered transient`
 )
 
 var (
 	timeoutUsage = fmt.Sprintf(`Time to wait for instance(s) start
 defaults to %s`, defaultStartTimeout.String())
 
+	waitTimeoutUsage = fmt.Sprintf(`Time to wait for instance(s) to be healthy (used with --wait)
+defaults to %s`, defaultWaitTimeout.String())
+
+	stopTimeoutUsage = fmt.Sprintf(`Time to wait for instance(s) exit after SIGTERM,
+then SIGKILL is sent (0 means no timeout)
+defaults to %s`, defaultStopTimeout.String())
+
 	logLinesUsage = fmt.Sprintf(`Count of last lines to output
 defaults to %d`, defaultLogLines)
 )
+
+// BENCH
+const (
+	benchConnectionsUsage = `Number of connections the requests are sent over concurrently`
+
+	benchDurationUsage = `Time to run the benchmark for`
+
+	benchCountUsage = `Total number of requests to send
+If specified, the benchmark runs until all requests are done
+instead of the --duration time`
+
+	benchPayloadSizeUsage = `Size of the inserted tuples payload in bytes`
+
+	benchReadRatioUsage = `Share of select requests in the workload, from 0 to 1
+The rest requests are inserts, e.g. 0.8 means 80% selects and 20% inserts`
+
+	benchFormatUsage = `Results output format (text or json)`
+)
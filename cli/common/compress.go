@@ -7,6 +7,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+
+	"github.com/klauspost/compress/zstd"
 )
 
 // WriteTarArchive creates Tar archive of specified path
@@ -50,14 +52,34 @@ func WriteTarArchive(srcDirPath string, compressWriter io.Writer) error {
 	return nil
 }
 
+const (
+	// DefaultCompressionLevel means that the compressor default level is used
+	DefaultCompressionLevel = 0
+
+	GzipMinCompressionLevel = gzip.BestSpeed
+	GzipMaxCompressionLevel = gzip.BestCompression
+
+	// zstd levels are mapped to the encoder ones, see zstd.EncoderLevelFromZstd
+	ZstdMinCompressionLevel = 1
+	ZstdMaxCompressionLevel = 22
+)
+
 // WriteTgzArchive creates TGZ archive of specified path
-func WriteTgzArchive(srcDirPath string, destFilePath string) error {
+// with specified gzip compression level
+func WriteTgzArchive(srcDirPath string, destFilePath string, level int) error {
 	destFile, err := os.Create(destFilePath)
 	if err != nil {
 		return fmt.Errorf("Failed to create result TGZ file %s: %s", destFilePath, err)
 	}
 
-	gzipWriter := gzip.NewWriter(destFile)
+	if level == DefaultCompressionLevel {
+		level = gzip.DefaultCompression
+	}
+
+	gzipWriter, err := gzip.NewWriterLevel(destFile, level)
+	if err != nil {
+		return fmt.Errorf("Failed to create GZIP writer %s: %s", destFilePath, err)
+	}
 	defer gzipWriter.Close()
 
 	err = WriteTarArchive(srcDirPath, gzipWriter)
@@ -68,8 +90,45 @@ func WriteTgzArchive(srcDirPath string, destFilePath string) error {
 	return nil
 }
 
-// CompressGzip compresses specified file  with gzip.BestCompression level
-func CompressGzip(srcFilePath string, destFilePath string) error {
+// WriteTarZstArchive creates zstd-compressed Tar archive of specified path
+// with specified zstd compression level.
+// Tar contents are streamed through the encoder
+func WriteTarZstArchive(srcDirPath string, destFilePath string, level int) error {
+	destFile, err := os.Create(destFilePath)
+	if err != nil {
+		return fmt.Errorf("Failed to create result TAR.ZST file %s: %s", destFilePath, err)
+	}
+	defer destFile.Close()
+
+	var encoderOpts []zstd.EOption
+	if level != DefaultCompressionLevel {
+		encoderOpts = append(encoderOpts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+	}
+
+	zstdWriter, err := zstd.NewWriter(destFile, encoderOpts...)
+	if err != nil {
+		return fmt.Errorf("Failed to create zstd writer: %s", err)
+	}
+
+	if err := WriteTarArchive(srcDirPath, zstdWriter); err != nil {
+		zstdWriter.Close()
+		return err
+	}
+
+	if err := zstdWriter.Close(); err != nil {
+		return fmt.Errorf("Failed to compress archive: %s", err)
+	}
+
+	if err := destFile.Close(); err != nil {
+		return fmt.Errorf("Failed to write result TAR.ZST file %s: %s", destFilePath, err)
+	}
+
+	return nil
+}
+
+// CompressGzip compresses specified file with specified level.
+// By default, gzip.BestCompression level is used
+func CompressGzip(srcFilePath string, destFilePath string, level int) error {
 	var err error
 
 	// src file reader
@@ -87,7 +146,11 @@ func CompressGzip(srcFilePath string, destFilePath string) error {
 	defer destFile.Close()
 
 	// dest file GZIP writer
-	gzipWriter, err := gzip.NewWriterLevel(destFile, gzip.BestCompression)
+	if level == DefaultCompressionLevel {
+		level = gzip.BestCompression
+	}
+
+	gzipWriter, err := gzip.NewWriterLevel(destFile, level)
 	if err != nil {
 		return fmt.Errorf("Failed to create GZIP writer %s: %s", destFilePath, err)
 	}
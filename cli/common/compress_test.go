@@ -0,0 +1,161 @@
+package common
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteTarZstArchive(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "archive")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	srcDirPath := filepath.Join(tmpDir, "src")
+	assert.Nil(os.MkdirAll(filepath.Join(srcDirPath, "myapp"), 0755))
+	assert.Nil(ioutil.WriteFile(filepath.Join(srcDirPath, "myapp", "init.lua"), []byte("print('ok')\n"), 0644))
+
+	archivePath := filepath.Join(tmpDir, "myapp-1.0.0-0.tar.zst")
+	assert.Nil(WriteTarZstArchive(srcDirPath, archivePath, DefaultCompressionLevel))
+
+	archiveFile, err := os.Open(archivePath)
+	assert.Nil(err)
+	defer archiveFile.Close()
+
+	zstdReader, err := zstd.NewReader(archiveFile)
+	assert.Nil(err)
+	defer zstdReader.Close()
+
+	contents := make(map[string]string)
+
+	tarReader := tar.NewReader(zstdReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(err)
+
+		content, err := ioutil.ReadAll(tarReader)
+		assert.Nil(err)
+
+		contents[header.Name] = string(content)
+	}
+
+	assert.Equal(map[string]string{
+		"myapp":          "",
+		"myapp/init.lua": "print('ok')\n",
+	}, contents)
+}
+
+func TestWriteArchivesCompressionLevel(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "archive")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var initContent strings.Builder
+	for i := 0; i < 20000; i++ {
+		fmt.Fprintf(&initContent, "print('line %d: %d')\n", i, i*i%997)
+	}
+
+	srcDirPath := filepath.Join(tmpDir, "src")
+	assert.Nil(os.MkdirAll(filepath.Join(srcDirPath, "myapp"), 0755))
+	assert.Nil(ioutil.WriteFile(filepath.Join(srcDirPath, "myapp", "init.lua"), []byte(initContent.String()), 0644))
+
+	expContents := map[string]string{
+		"myapp":          "",
+		"myapp/init.lua": initContent.String(),
+	}
+
+	getArchiveSize := func(archivePath string) int64 {
+		archiveInfo, err := os.Stat(archivePath)
+		assert.Nil(err)
+		return archiveInfo.Size()
+	}
+
+	// gzip
+	fastestPath := filepath.Join(tmpDir, "fastest.tar.gz")
+	bestPath := filepath.Join(tmpDir, "best.tar.gz")
+
+	assert.Nil(WriteTgzArchive(srcDirPath, fastestPath, GzipMinCompressionLevel))
+	assert.Nil(WriteTgzArchive(srcDirPath, bestPath, GzipMaxCompressionLevel))
+
+	assert.Greater(getArchiveSize(fastestPath), getArchiveSize(bestPath))
+
+	for _, archivePath := range []string{fastestPath, bestPath} {
+		archiveFile, err := os.Open(archivePath)
+		assert.Nil(err)
+		defer archiveFile.Close()
+
+		gzipReader, err := gzip.NewReader(archiveFile)
+		assert.Nil(err)
+
+		assert.Equal(expContents, readTarContents(t, gzipReader), archivePath)
+	}
+
+	// zstd
+	fastestPath = filepath.Join(tmpDir, "fastest.tar.zst")
+	bestPath = filepath.Join(tmpDir, "best.tar.zst")
+
+	assert.Nil(WriteTarZstArchive(srcDirPath, fastestPath, ZstdMinCompressionLevel))
+	assert.Nil(WriteTarZstArchive(srcDirPath, bestPath, ZstdMaxCompressionLevel))
+
+	assert.Greater(getArchiveSize(fastestPath), getArchiveSize(bestPath))
+
+	for _, archivePath := range []string{fastestPath, bestPath} {
+		archiveFile, err := os.Open(archivePath)
+		assert.Nil(err)
+		defer archiveFile.Close()
+
+		zstdReader, err := zstd.NewReader(archiveFile)
+		assert.Nil(err)
+		defer zstdReader.Close()
+
+		assert.Equal(expContents, readTarContents(t, zstdReader), archivePath)
+	}
+}
+
+func readTarContents(t *testing.T, r io.Reader) map[string]string {
+	contents := make(map[string]string)
+
+	tarReader := tar.NewReader(r)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read archive: %s", err)
+		}
+
+		content, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			t.Fatalf("Failed to read archive file: %s", err)
+		}
+
+		contents[header.Name] = string(content)
+	}
+
+	return contents
+}
@@ -117,6 +117,15 @@ func RunCommand(cmd *exec.Cmd, dir string, showOutput bool) error {
 // RunHook runs specified hook and returns an error
 // If showOutput is set to true, command output is shown
 func RunHook(hookPath string, showOutput bool) error {
+	return RunHookWithEnv(hookPath, nil, showOutput)
+}
+
+// RunHookWithEnv runs hook with specified environment variables
+// added to the current process environment.
+// Hook output lines are prefixed with the hook name.
+// If showOutput is set to true, output is shown while the hook is running,
+// else it's captured and shown only if the hook fails
+func RunHookWithEnv(hookPath string, env []string, showOutput bool) error {
 	hookName := filepath.Base(hookPath)
 	hookDir := filepath.Dir(hookPath)
 
@@ -127,14 +136,91 @@ func RunHook(hookPath string, showOutput bool) error {
 	}
 
 	hookCmd := exec.Command(hookPath)
-	err := RunCommand(hookCmd, hookDir, showOutput)
+	hookCmd.Dir = hookDir
+	if len(env) > 0 {
+		hookCmd.Env = append(os.Environ(), env...)
+	}
+
+	var capturedOutput bytes.Buffer
+	var hookOutput io.Writer = &capturedOutput
+	if showOutput {
+		hookOutput = os.Stdout
+	}
+
+	// the same writer is used to keep stdout and stderr lines order
+	prefixedOutput := newPrefixWriter(hookOutput, fmt.Sprintf("[%s] ", hookName))
+	hookCmd.Stdout = prefixedOutput
+	hookCmd.Stderr = prefixedOutput
+
+	startTime := time.Now()
+
+	var err error
+	if showOutput {
+		err = hookCmd.Run()
+	} else {
+		err = RunFunctionWithSpinner(hookCmd.Run, "")
+	}
+
+	duration := time.Since(startTime).Round(time.Millisecond)
+
 	if err != nil {
-		return fmt.Errorf("Failed to run hook `%s`: %s", hookName, err)
+		if !showOutput {
+			if _, err := io.Copy(os.Stdout, &capturedOutput); err != nil {
+				log.Warnf("Failed to show hook output: %s", err)
+			}
+		}
+
+		return fmt.Errorf("Hook `%s` failed in %s: %s", hookName, duration, err)
 	}
 
+	log.Infof("Hook `%s` finished in %s", hookName, duration)
+
 	return nil
 }
 
+// prefixWriter writes each line with the specified prefix
+type prefixWriter struct {
+	w         io.Writer
+	prefix    []byte
+	lineStart bool
+}
+
+func newPrefixWriter(w io.Writer, prefix string) *prefixWriter {
+	return &prefixWriter{
+		w:         w,
+		prefix:    []byte(prefix),
+		lineStart: true,
+	}
+}
+
+func (pw *prefixWriter) Write(p []byte) (int, error) {
+	for data := p; len(data) > 0; {
+		if pw.lineStart {
+			if _, err := pw.w.Write(pw.prefix); err != nil {
+				return 0, err
+			}
+			pw.lineStart = false
+		}
+
+		lineEnd := bytes.IndexByte(data, '\n')
+		if lineEnd < 0 {
+			if _, err := pw.w.Write(data); err != nil {
+				return 0, err
+			}
+			break
+		}
+
+		if _, err := pw.w.Write(data[:lineEnd+1]); err != nil {
+			return 0, err
+		}
+
+		data = data[lineEnd+1:]
+		pw.lineStart = true
+	}
+
+	return len(p), nil
+}
+
 // GetOutput runs specified command and returns it's stdout
 func GetOutput(cmd *exec.Cmd, dir *string) (string, error) {
 	var err error
@@ -0,0 +1,61 @@
+package common
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var output bytes.Buffer
+	w := newPrefixWriter(&output, "[hook] ")
+
+	for _, data := range []string{"first line\nsec", "ond line\n", "", "\n", "last line"} {
+		n, err := w.Write([]byte(data))
+		assert.Nil(err)
+		assert.Equal(len(data), n)
+	}
+
+	assert.Equal("[hook] first line\n[hook] second line\n[hook] \n[hook] last line", output.String())
+}
+
+func TestRunHookWithEnv(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	hookDir, err := ioutil.TempDir("", "hook")
+	assert.Nil(err)
+	defer os.RemoveAll(hookDir)
+
+	hookPath := filepath.Join(hookDir, "cartridge.pre-build")
+
+	// not executable
+	assert.Nil(ioutil.WriteFile(hookPath, []byte("#!/bin/sh\n"), 0644))
+	assert.EqualError(RunHookWithEnv(hookPath, nil, false), "Hook `cartridge.pre-build` should be executable")
+
+	// success
+	hookContent := "#!/bin/sh\necho \"$HOOK_VALUE\" > value.txt\n"
+	assert.Nil(ioutil.WriteFile(hookPath, []byte(hookContent), 0755))
+	assert.Nil(RunHookWithEnv(hookPath, []string{"HOOK_VALUE=42"}, false))
+
+	value, err := ioutil.ReadFile(filepath.Join(hookDir, "value.txt"))
+	assert.Nil(err)
+	assert.Equal("42\n", string(value))
+
+	// failure
+	assert.Nil(ioutil.WriteFile(hookPath, []byte("#!/bin/sh\necho failed >&2\nexit 3\n"), 0755))
+	err = RunHookWithEnv(hookPath, nil, false)
+	assert.NotNil(err)
+	assert.True(strings.HasPrefix(err.Error(), "Hook `cartridge.pre-build` failed in "), err.Error())
+	assert.True(strings.HasSuffix(err.Error(), ": exit status 3"), err.Error())
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/apex/log"
@@ -59,6 +60,23 @@ func IsSubDir(subdir string, dir string) (bool, error) {
 	return strings.HasPrefix(subdirAbs, fmt.Sprintf("%s/", dirAbs)), nil
 }
 
+// IsDirEmpty checks if specified directory has no files
+func IsDirEmpty(dirPath string) (bool, error) {
+	dir, err := os.Open(dirPath)
+	if err != nil {
+		return false, err
+	}
+	defer dir.Close()
+
+	if _, err := dir.Readdirnames(1); err == io.EOF {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return false, nil
+}
+
 // ClearDir removes all files from specified directory
 func ClearDir(dirPath string) error {
 	files, err := filepath.Glob(filepath.Join(dirPath, "*"))
@@ -153,6 +171,72 @@ func MergeFiles(destFilePath string, srcFilePaths ...string) error {
 	return nil
 }
 
+// SplitFile splits specified file into parts of partSize bytes
+// named <srcFilePath>.partNN. Paths of created parts are returned.
+// On failure, already created parts are removed
+func SplitFile(srcFilePath string, partSize int64) (partPaths []string, err error) {
+	if partSize <= 0 {
+		return nil, fmt.Errorf("Part size should be positive")
+	}
+
+	srcFileInfo, err := os.Stat(srcFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to use source file %s: %s", srcFilePath, err)
+	}
+
+	partsNum := srcFileInfo.Size() / partSize
+	if srcFileInfo.Size()%partSize != 0 || partsNum == 0 {
+		partsNum++
+	}
+
+	partNumWidth := len(strconv.FormatInt(partsNum-1, 10))
+	if partNumWidth < 2 {
+		partNumWidth = 2
+	}
+
+	srcFile, err := os.Open(srcFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open source file %s: %s", srcFilePath, err)
+	}
+	defer srcFile.Close()
+
+	createdPartPaths := make([]string, 0, partsNum)
+
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		for _, partPath := range createdPartPaths {
+			if err := os.Remove(partPath); err != nil {
+				log.Warnf("Failed to remove part file %s: %s", partPath, err)
+			}
+		}
+	}()
+
+	for i := int64(0); i < partsNum; i++ {
+		partPath := fmt.Sprintf("%s.part%0*d", srcFilePath, partNumWidth, i)
+
+		partFile, err := os.Create(partPath)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create part file %s: %s", partPath, err)
+		}
+
+		createdPartPaths = append(createdPartPaths, partPath)
+
+		_, err = io.CopyN(partFile, srcFile, partSize)
+		if closeErr := partFile.Close(); err == nil || err == io.EOF {
+			err = closeErr
+		}
+
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("Failed to write part file %s: %s", partPath, err)
+		}
+	}
+
+	return createdPartPaths, nil
+}
+
 func PrintFromStart(file *os.File) error {
 	if _, err := file.Seek(0, io.SeekStart); err != nil {
 		return fmt.Errorf("Failed to seek file begin: %s", err)
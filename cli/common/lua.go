@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/apex/log"
 	lua "github.com/yuin/gopher-lua"
@@ -13,6 +14,38 @@ const (
 	rocksManifestPath = ".rocks/share/tarantool/rocks/manifest"
 )
 
+// QuoteLua returns Lua string literal quoted with quote (' or ").
+// Special characters are escaped, so the literal can be safely
+// passed to the code evaluated by Tarantool
+func QuoteLua(s string, quote byte) string {
+	var b strings.Builder
+
+	b.WriteByte(quote)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case quote, '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if c < 0x20 || c == 0x7f {
+				fmt.Fprintf(&b, `\%03d`, c)
+			} else {
+				b.WriteByte(c)
+			}
+		}
+	}
+	b.WriteByte(quote)
+
+	return b.String()
+}
+
 // LuaReadStringVar reads global string variable from specified Lua file
 func LuaReadStringVar(filePath string, varName string) (string, error) {
 	L := lua.NewState()
@@ -0,0 +1,20 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteLua(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	assert.Equal(`''`, QuoteLua("", '\''))
+	assert.Equal(`'simple'`, QuoteLua("simple", '\''))
+	assert.Equal(`'it\'s "quoted"'`, QuoteLua(`it's "quoted"`, '\''))
+	assert.Equal(`"it's \"quoted\""`, QuoteLua(`it's "quoted"`, '"'))
+	assert.Equal(`'a\\b\nc\rd\te\000f\127'`, QuoteLua("a\\b\nc\rd\te\x00f\x7f", '\''))
+	assert.Equal(`'юникод'`, QuoteLua("юникод", '\''))
+}
@@ -0,0 +1,233 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/apex/log"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	defaultSSHPort = "22"
+	sshAuthSockEnv = "SSH_AUTH_SOCK"
+	sshDialTimeout = 10 * time.Second
+
+	consoleSockSuffix = ".control"
+)
+
+// SSHTunnel forwards local unix sockets to the remote ones over SSH
+type SSHTunnel struct {
+	host string
+
+	client    *ssh.Client
+	agentConn net.Conn
+
+	localDir  string
+	listeners []net.Listener
+}
+
+// OpenSSHTunnel connects to the SSH server specified as USER@HOST[:PORT].
+// Local SSH agent is used for authentication,
+// the server host key is checked using ~/.ssh/known_hosts
+func OpenSSHTunnel(dest string) (*SSHTunnel, error) {
+	user, host, err := parseSSHDest(dest)
+	if err != nil {
+		return nil, err
+	}
+
+	agentSock := os.Getenv(sshAuthSockEnv)
+	if agentSock == "" {
+		return nil, fmt.Errorf("Failed to connect to %s: SSH agent isn't running (%s isn't set)", host, sshAuthSockEnv)
+	}
+
+	agentConn, err := net.Dial("unix", agentSock)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to %s: failed to connect to SSH agent: %s", host, err)
+	}
+
+	hostKeyCallback, err := getSSHHostKeyCallback()
+	if err != nil {
+		agentConn.Close()
+		return nil, fmt.Errorf("Failed to connect to %s: %s", host, err)
+	}
+
+	config := &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeysCallback(agent.NewClient(agentConn).Signers)},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         sshDialTimeout,
+	}
+
+	client, err := ssh.Dial("tcp", host, config)
+	if err != nil {
+		agentConn.Close()
+		return nil, fmt.Errorf("Failed to connect to %s: %s", host, err)
+	}
+
+	log.Debugf("Connected to %s over SSH", host)
+
+	tunnel := SSHTunnel{
+		host:      host,
+		client:    client,
+		agentConn: agentConn,
+	}
+
+	return &tunnel, nil
+}
+
+// ForwardConsoleSockets creates local unix sockets that are forwarded
+// to the console sockets placed in the remote directory.
+// Local sockets have the same names as remote ones,
+// the local directory that contains them is returned
+func (tunnel *SSHTunnel) ForwardConsoleSockets(remoteDir string) (string, error) {
+	fileNames, err := tunnel.listRemoteDir(remoteDir)
+	if err != nil {
+		return "", err
+	}
+
+	if tunnel.localDir, err = ioutil.TempDir("", "ssh-tunnel"); err != nil {
+		return "", fmt.Errorf("Failed to create local sockets directory: %s", err)
+	}
+
+	for _, fileName := range fileNames {
+		if !strings.HasSuffix(fileName, consoleSockSuffix) {
+			continue
+		}
+
+		localPath := filepath.Join(tunnel.localDir, fileName)
+		remotePath := path.Join(remoteDir, fileName)
+
+		listener, err := net.Listen("unix", localPath)
+		if err != nil {
+			return "", fmt.Errorf("Failed to create local socket for %s: %s", remotePath, err)
+		}
+
+		tunnel.listeners = append(tunnel.listeners, listener)
+
+		log.Debugf("Forward %s to %s on %s", localPath, remotePath, tunnel.host)
+		go tunnel.serve(listener, remotePath)
+	}
+
+	return tunnel.localDir, nil
+}
+
+// Close stops forwarding, closes the SSH connection
+// and removes the local sockets directory
+func (tunnel *SSHTunnel) Close() {
+	for _, listener := range tunnel.listeners {
+		listener.Close()
+	}
+
+	tunnel.client.Close()
+	tunnel.agentConn.Close()
+
+	if tunnel.localDir != "" {
+		os.RemoveAll(tunnel.localDir)
+	}
+}
+
+func (tunnel *SSHTunnel) listRemoteDir(remoteDir string) ([]string, error) {
+	session, err := tunnel.client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to start SSH session on %s: %s", tunnel.host, err)
+	}
+	defer session.Close()
+
+	output, err := session.CombinedOutput(fmt.Sprintf("ls -1 -- %s", shellQuote(remoteDir)))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list %s on %s: %s: %s",
+			remoteDir, tunnel.host, err, strings.TrimSpace(string(output)))
+	}
+
+	var fileNames []string
+	for _, fileName := range strings.Split(string(output), "\n") {
+		if fileName != "" {
+			fileNames = append(fileNames, fileName)
+		}
+	}
+
+	return fileNames, nil
+}
+
+func (tunnel *SSHTunnel) serve(listener net.Listener, remotePath string) {
+	for {
+		localConn, err := listener.Accept()
+		if err != nil {
+			// listener is closed
+			return
+		}
+
+		go tunnel.forward(localConn, remotePath)
+	}
+}
+
+func (tunnel *SSHTunnel) forward(localConn net.Conn, remotePath string) {
+	defer localConn.Close()
+
+	remoteConn, err := tunnel.client.Dial("unix", remotePath)
+	if err != nil {
+		log.Warnf("Failed to connect to %s on %s: %s", remotePath, tunnel.host, err)
+		return
+	}
+	defer remoteConn.Close()
+
+	// connection is closed when one of the sides is done
+	done := make(chan struct{}, 2)
+
+	go func() {
+		io.Copy(remoteConn, localConn)
+		done <- struct{}{}
+	}()
+
+	go func() {
+		io.Copy(localConn, remoteConn)
+		done <- struct{}{}
+	}()
+
+	<-done
+}
+
+// parseSSHDest returns the user and the HOST:PORT address
+// of the SSH destination specified as USER@HOST[:PORT]
+func parseSSHDest(dest string) (string, string, error) {
+	parts := strings.SplitN(dest, "@", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("Invalid SSH destination %q: should be USER@HOST[:PORT]", dest)
+	}
+
+	user, host := parts[0], parts[1]
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(strings.Trim(host, "[]"), defaultSSHPort)
+	}
+
+	return user, host, nil
+}
+
+func getSSHHostKeyCallback() (ssh.HostKeyCallback, error) {
+	homeDir, err := GetHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get home directory: %s", err)
+	}
+
+	hostKeyCallback, err := knownhosts.New(filepath.Join(homeDir, ".ssh", "known_hosts"))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read known hosts: %s", err)
+	}
+
+	return hostKeyCallback, nil
+}
+
+// shellQuote quotes the string to be passed to the remote shell
+func shellQuote(str string) string {
+	return "'" + strings.Replace(str, "'", `'\''`, -1) + "'"
+}
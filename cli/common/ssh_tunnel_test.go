@@ -0,0 +1,40 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSSHDest(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	for dest, expected := range map[string][2]string{
+		"admin@example.com":      {"admin", "example.com:22"},
+		"admin@example.com:2222": {"admin", "example.com:2222"},
+		"admin@10.0.0.2":         {"admin", "10.0.0.2:22"},
+		"admin@[::1]":            {"admin", "[::1]:22"},
+		"admin@[::1]:2222":       {"admin", "[::1]:2222"},
+	} {
+		user, host, err := parseSSHDest(dest)
+		assert.Nil(err, dest)
+		assert.Equal(expected[0], user, dest)
+		assert.Equal(expected[1], host, dest)
+	}
+
+	for _, dest := range []string{"example.com", "@example.com", "admin@"} {
+		_, _, err := parseSSHDest(dest)
+		assert.EqualError(err, `Invalid SSH destination "`+dest+`": should be USER@HOST[:PORT]`)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	assert.Equal(`'/var/run/tarantool'`, shellQuote("/var/run/tarantool"))
+	assert.Equal(`'/tmp/it'\''s run'`, shellQuote("/tmp/it's run"))
+}
@@ -129,10 +129,9 @@ func GetMajorCartridgeVersion(conn net.Conn) (int, error) {
 // FindRockspec finds *.rockspec file in specified path
 // If multiple files are found, it returns an error
 func FindRockspec(path string) (string, error) {
-	rockspecs, err := filepath.Glob(filepath.Join(path, "*.rockspec"))
-
+	rockspecs, err := FindRockspecs(path)
 	if err != nil {
-		return "", fmt.Errorf("Failed to find rockspec: %s", err)
+		return "", err
 	}
 
 	if len(rockspecs) > 1 {
@@ -146,6 +145,16 @@ func FindRockspec(path string) (string, error) {
 	return "", nil
 }
 
+// FindRockspecs finds all *.rockspec files in specified path
+func FindRockspecs(path string) ([]string, error) {
+	rockspecs, err := filepath.Glob(filepath.Join(path, "*.rockspec"))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to find rockspec: %s", err)
+	}
+
+	return rockspecs, nil
+}
+
 const (
 	getCartridgeVersionBody = `return require('cartridge').VERSION`
 )
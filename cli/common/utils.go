@@ -1,14 +1,17 @@
 package common
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
 	"io"
+	"math"
 	"math/rand"
 	"os"
 	"os/exec"
 	"os/user"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -18,10 +21,55 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
-var bufSize int64 = 10000
+const (
+	// DefaultLastNLinesBufSize is the size of the buffer
+	// used by GetLastNLinesBegin to read the file from the end
+	DefaultLastNLinesBufSize int64 = 10000
+
+	// NonSerializablePlaceholderFmt is used to represent values
+	// that can't be serialized, e.g. userdata or NaN
+	NonSerializablePlaceholderFmt = "<non-serializable: %s>"
+)
+
+var (
+	// stdin is read via one reader to not lose buffered input between prompts
+	stdinReader = bufio.NewReader(os.Stdin)
+
+	sizeRgx *regexp.Regexp
+
+	sizeUnits = map[string]int64{
+		"":  1,
+		"K": 1 << 10,
+		"M": 1 << 20,
+		"G": 1 << 30,
+	}
+)
 
 func init() {
 	rand.Seed(time.Now().UTC().UnixNano())
+
+	sizeRgx = regexp.MustCompile(`^(\d+)\s*([KMG]?)(?:I?B)?$`)
+}
+
+// ParseSize parses size string like "1024", "100K", "10MB" or "1GiB".
+// K, M and G units are powers of 1024
+func ParseSize(sizeStr string) (int64, error) {
+	matches := sizeRgx.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(sizeStr)))
+	if matches == nil {
+		return 0, fmt.Errorf("Invalid size format: %q", sizeStr)
+	}
+
+	value, err := strconv.ParseInt(matches[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid size value: %s", err)
+	}
+
+	unit := sizeUnits[matches[2]]
+	if value > math.MaxInt64/unit {
+		return 0, fmt.Errorf("Size is too big: %q", sizeStr)
+	}
+
+	return value * unit, nil
 }
 
 // Prompt a value with given text and default value
@@ -32,8 +80,8 @@ func Prompt(text, defaultValue string) string {
 		fmt.Printf("%s [%s]: ", text, defaultValue)
 	}
 
-	var value string
-	fmt.Scanf("%s", &value)
+	value, _ := stdinReader.ReadString('\n')
+	value = strings.TrimSpace(value)
 
 	if value == "" {
 		value = defaultValue
@@ -161,8 +209,22 @@ func readFromPos(f *os.File, pos int64, buf *[]byte) (int, error) {
 	return n, nil
 }
 
-// GetLastNLinesBegin return the position of last lines begin
+// GetLastNLinesBegin return the position of last lines begin.
+// Both `\n` and `\r\n` are treated as line endings: lines are counted by `\n`,
+// so the returned position is always right after it and never
+// splits `\r\n` sequence
 func GetLastNLinesBegin(filepath string, lines int) (int64, error) {
+	return GetLastNLinesBeginWithBufSize(filepath, lines, DefaultLastNLinesBufSize)
+}
+
+// GetLastNLinesBeginWithBufSize is the same as GetLastNLinesBegin,
+// but the file is read by parts of the specified size.
+// The bigger buffer requires less reads for files with long lines
+func GetLastNLinesBeginWithBufSize(filepath string, lines int, bufSize int64) (int64, error) {
+	if bufSize <= 0 {
+		return 0, fmt.Errorf("Buffer size should be positive, got %d", bufSize)
+	}
+
 	if lines == 0 {
 		return 0, nil
 	}
@@ -207,7 +269,11 @@ func GetLastNLinesBegin(filepath string, lines int) (int64, error) {
 
 Loop:
 	for {
+		readSize := bufSize
+
 		if filePos < 0 {
+			// don't scan the bytes that are already scanned
+			readSize += filePos
 			filePos = 0
 			lastPart = true
 		}
@@ -217,6 +283,10 @@ Loop:
 			return 0, err
 		}
 
+		if int64(n) > readSize {
+			n = int(readSize)
+		}
+
 		for i := n - 1; i >= 0; i-- {
 			b := buf[i]
 
@@ -336,19 +406,22 @@ func InsertInStringSlice(s []string, i int, elem string) []string {
 	return res
 }
 
+// GetInstancesFromArgs returns instance names specified in args.
+// Instance IDs like APP_NAME.INSTANCE_NAME (e.g. copied from logs)
+// or APP_NAME@INSTANCE_NAME (systemd unit names) are accepted too
+// if APP_NAME is the current application name
 func GetInstancesFromArgs(args []string, ctx *context.Ctx) ([]string, error) {
 	foundInstances := make(map[string]struct{})
 	var instances []string
 
-	for _, instanceName := range args {
-		if instanceName == ctx.Project.Name {
+	for _, arg := range args {
+		if arg == ctx.Project.Name {
 			return nil, fmt.Errorf(appNameSpecifiedError)
 		}
 
-		parts := strings.SplitN(instanceName, ".", 2)
-
-		if len(parts) > 1 {
-			return nil, fmt.Errorf(instanceIDSpecified)
+		instanceName, err := getInstanceNameFromID(arg, ctx.Project.Name)
+		if err != nil {
+			return nil, err
 		}
 
 		if instanceName != "" {
@@ -364,6 +437,119 @@ func GetInstancesFromArgs(args []string, ctx *context.Ctx) ([]string, error) {
 	return instances, nil
 }
 
+// getInstanceNameFromID strips the application name from the
+// APP_NAME.INSTANCE_NAME or APP_NAME@INSTANCE_NAME instance ID.
+// Instance names are returned as is
+func getInstanceNameFromID(instanceID string, appName string) (string, error) {
+	sepIndex := strings.IndexAny(instanceID, instanceIDSeparators)
+	if sepIndex == -1 {
+		return instanceID, nil
+	}
+
+	instanceAppName := instanceID[:sepIndex]
+	instanceName := instanceID[sepIndex+1:]
+
+	if instanceAppName == "" || instanceName == "" || strings.ContainsAny(instanceName, instanceIDSeparators) {
+		return "", fmt.Errorf(instanceIDSpecified)
+	}
+
+	if instanceAppName != appName {
+		return "", fmt.Errorf(
+			"Instance %s belongs to application %q, but current application is %q. "+
+				"Please, specify instance name(s)", instanceID, instanceAppName, appName,
+		)
+	}
+
+	return instanceName, nil
+}
+
+// CheckInstancesConfigured checks that all specified instances
+// are described in the instances configuration.
+// The closest configured instance name is suggested for the unknown one
+func CheckInstancesConfigured(instances []string, configuredInstances []string) error {
+	for _, instanceName := range instances {
+		if StringSliceContains(configuredInstances, instanceName) {
+			continue
+		}
+
+		if suggestion := GetClosestString(instanceName, configuredInstances); suggestion != "" {
+			return fmt.Errorf("No such instance configured: %s, did you mean %s?", instanceName, suggestion)
+		}
+
+		return fmt.Errorf("No such instance configured: %s", instanceName)
+	}
+
+	return nil
+}
+
+// GetClosestString returns the candidate with the minimal Levenshtein distance
+// to the specified string if this distance is small enough to consider it a typo.
+// Empty string is returned if there is no such candidate
+func GetClosestString(str string, candidates []string) string {
+	maxDistance := len(str) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+
+	closest := ""
+	closestDistance := maxDistance + 1
+
+	for _, candidate := range candidates {
+		if distance := LevenshteinDistance(str, candidate); distance < closestDistance {
+			closest = candidate
+			closestDistance = distance
+		}
+	}
+
+	return closest
+}
+
+// LevenshteinDistance returns the minimal number of single-character
+// insertions, deletions or substitutions required to change one string to another
+func LevenshteinDistance(s1, s2 string) int {
+	r1 := []rune(s1)
+	r2 := []rune(s2)
+
+	prevRow := make([]int, len(r2)+1)
+	curRow := make([]int, len(r2)+1)
+
+	for j := range prevRow {
+		prevRow[j] = j
+	}
+
+	for i := 1; i <= len(r1); i++ {
+		curRow[0] = i
+
+		for j := 1; j <= len(r2); j++ {
+			substitutionCost := 1
+			if r1[i-1] == r2[j-1] {
+				substitutionCost = 0
+			}
+
+			curRow[j] = minInt(
+				prevRow[j]+1,
+				curRow[j-1]+1,
+				prevRow[j-1]+substitutionCost,
+			)
+		}
+
+		prevRow, curRow = curRow, prevRow
+	}
+
+	return prevRow[len(r2)]
+}
+
+func minInt(first int, others ...int) int {
+	min := first
+	for _, value := range others {
+		if value < min {
+			min = value
+		}
+	}
+
+	return min
+}
+
 func GetStringSlicesDifference(s1, s2 []string) []string {
 	uniqueStrings := arrayOperations.DifferenceString(s1, s2)
 	return arrayOperations.IntersectString(s1, uniqueStrings)
@@ -390,6 +576,37 @@ func StdinHasUnreadData() (bool, error) {
 const (
 	appNameSpecifiedError = "Application name is specified. " +
 		"Please, specify instance name(s)"
-	instanceIDSpecified = `[APP_NAME].INSTANCE_NAME is specified. ` +
+	instanceIDSpecified = `Invalid instance ID is specified. ` +
 		"Please, specify instance name(s)"
+
+	// APP_NAME.INSTANCE_NAME or APP_NAME@INSTANCE_NAME
+	instanceIDSeparators = ".@"
 )
+
+// NormalizeYAMLValue converts maps decoded from YAML to maps with string keys
+// and replaces values that can't be represented in all formats with placeholders
+func NormalizeYAMLValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case nil, bool, int, int64, uint64, string:
+		return value
+	case float64:
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return fmt.Sprintf(NonSerializablePlaceholderFmt, strconv.FormatFloat(value, 'g', -1, 64))
+		}
+		return value
+	case []interface{}:
+		res := make([]interface{}, len(value))
+		for i, item := range value {
+			res[i] = NormalizeYAMLValue(item)
+		}
+		return res
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			res[fmt.Sprintf("%v", key)] = NormalizeYAMLValue(item)
+		}
+		return res
+	default:
+		return fmt.Sprintf(NonSerializablePlaceholderFmt, fmt.Sprintf("%T", value))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -33,9 +34,11 @@ func getFileContentSinceOffset(file *os.File, offset int64) string {
 }
 
 func TestGetLastNLinesBegin(t *testing.T) {
+	t.Parallel()
+
 	assert := assert.New(t)
 
-	bufSize = 10
+	var bufSize int64 = 10
 
 	var n int64
 	var err error
@@ -50,60 +53,119 @@ func TestGetLastNLinesBegin(t *testing.T) {
 
 	// all lines w/o `\n` at the ent of file
 	writeFile(f, "one\ntwo\nthree\nfour\nfive\nsix\nseven")
-	n, err = GetLastNLinesBegin(f.Name(), 0)
+	n, err = GetLastNLinesBeginWithBufSize(f.Name(), 0, bufSize)
 	assert.Nil(err)
 	assert.EqualValues(0, n)
 
 	// all lines w/ `\n` at the ent of file
 	writeFile(f, "one\ntwo\nthree\nfour\nfive\nsix\nseven\n")
-	n, err = GetLastNLinesBegin(f.Name(), 0)
+	n, err = GetLastNLinesBeginWithBufSize(f.Name(), 0, bufSize)
 	assert.Nil(err)
 	assert.EqualValues(0, n)
 
 	// last 2 lines w/o `\n` at the ent of file
 	writeFile(f, "one\ntwo\nthree\nfour\nfive\nsix\nseven")
-	n, err = GetLastNLinesBegin(f.Name(), 2)
+	n, err = GetLastNLinesBeginWithBufSize(f.Name(), 2, bufSize)
 	assert.Nil(err)
 	assert.Equal("six\nseven", getFileContentSinceOffset(f, n))
 
 	// last 2 lines w/ `\n` at the ent of file
 	writeFile(f, "one\ntwo\nthree\nfour\nfive\nsix\nseven\n")
-	n, err = GetLastNLinesBegin(f.Name(), 2)
+	n, err = GetLastNLinesBeginWithBufSize(f.Name(), 2, bufSize)
 	assert.Nil(err)
 	assert.Equal("six\nseven\n", getFileContentSinceOffset(f, n))
 
 	// last 2 lines w/ n = -2
 	writeFile(f, "one\ntwo\nthree\nfour\nfive\nsix\nseven\n")
-	n, err = GetLastNLinesBegin(f.Name(), -2)
+	n, err = GetLastNLinesBeginWithBufSize(f.Name(), -2, bufSize)
 	assert.Nil(err)
 	assert.Equal("six\nseven\n", getFileContentSinceOffset(f, n))
 
 	// last 100 lines
 	writeFile(f, "one\ntwo\nthree\nfour\nfive\nsix\nseven")
-	n, err = GetLastNLinesBegin(f.Name(), 100)
+	n, err = GetLastNLinesBeginWithBufSize(f.Name(), 100, bufSize)
 	assert.Nil(err)
 	assert.EqualValues(0, n)
 
 	// last 100 lines w/ n = -100
 	writeFile(f, "one\ntwo\nthree\nfour\nfive\nsix\nseven")
-	n, err = GetLastNLinesBegin(f.Name(), -100)
+	n, err = GetLastNLinesBeginWithBufSize(f.Name(), -100, bufSize)
 	assert.Nil(err)
 	assert.EqualValues(0, n)
 
 	// last 2 lines w/ last line longer than buf size
 	longLine = strings.Repeat("a", int(bufSize+1))
 	writeFile(f, fmt.Sprintf("one\ntwo\nthree\nfour\nfive\nsix\n%s\n", longLine))
-	n, err = GetLastNLinesBegin(f.Name(), 2)
+	n, err = GetLastNLinesBeginWithBufSize(f.Name(), 2, bufSize)
 	assert.Nil(err)
 	assert.Equal(fmt.Sprintf("six\n%s\n", longLine), getFileContentSinceOffset(f, n))
 
 	// last 100 lines w/ first line longer than buf size
 	longLine = strings.Repeat("a", int(bufSize+1))
 	writeFile(f, fmt.Sprintf("%s\ntwo\nthree\nfour\nfive\nsix\nseven\n", longLine))
-	n, err = GetLastNLinesBegin(f.Name(), 0)
+	n, err = GetLastNLinesBeginWithBufSize(f.Name(), 0, bufSize)
 	assert.Nil(err)
 	assert.EqualValues(0, n)
 
+	// last 2 lines w/o `\r\n` at the end of file
+	writeFile(f, "one\r\ntwo\r\nthree\r\nfour\r\nfive\r\nsix\r\nseven")
+	n, err = GetLastNLinesBeginWithBufSize(f.Name(), 2, bufSize)
+	assert.Nil(err)
+	assert.Equal("six\r\nseven", getFileContentSinceOffset(f, n))
+
+	// last 2 lines w/ `\r\n` at the end of file
+	writeFile(f, "one\r\ntwo\r\nthree\r\nfour\r\nfive\r\nsix\r\nseven\r\n")
+	n, err = GetLastNLinesBeginWithBufSize(f.Name(), 2, bufSize)
+	assert.Nil(err)
+	assert.Equal("six\r\nseven\r\n", getFileContentSinceOffset(f, n))
+
+	// last 2 lines w/ `\r\n` split by the buffer bound
+	writeFile(f, "one\r\ntwo\r\nthree\r\nfour\r\nfive\r\nsix\r\nseventy\r\n")
+	n, err = GetLastNLinesBeginWithBufSize(f.Name(), 2, bufSize)
+	assert.Nil(err)
+	assert.Equal("six\r\nseventy\r\n", getFileContentSinceOffset(f, n))
+
+	// last 5 lines w/ the first part shorter than buf size
+	writeFile(f, "a\nb\nc\nd\ne\nf\ng\n")
+	n, err = GetLastNLinesBeginWithBufSize(f.Name(), 5, bufSize)
+	assert.Nil(err)
+	assert.Equal("c\nd\ne\nf\ng\n", getFileContentSinceOffset(f, n))
+
+	// last 100 lines w/ `\r\n`
+	writeFile(f, "one\r\ntwo\r\nthree\r\nfour\r\nfive\r\nsix\r\nseven\r\n")
+	n, err = GetLastNLinesBeginWithBufSize(f.Name(), 100, bufSize)
+	assert.Nil(err)
+	assert.EqualValues(0, n)
+
+	// last 3 lines w/ mixed `\n` and `\r\n`
+	writeFile(f, "one\ntwo\r\nthree\nfour\r\nfive\nsix\r\nseven\n")
+	n, err = GetLastNLinesBeginWithBufSize(f.Name(), 3, bufSize)
+	assert.Nil(err)
+	assert.Equal("five\nsix\r\nseven\n", getFileContentSinceOffset(f, n))
+
+	// last 2 lines w/ `\r\n` and last line longer than buf size
+	longLine = strings.Repeat("a", int(bufSize+1))
+	writeFile(f, fmt.Sprintf("one\r\ntwo\r\nthree\r\nfour\r\nfive\r\nsix\r\n%s\r\n", longLine))
+	n, err = GetLastNLinesBeginWithBufSize(f.Name(), 2, bufSize)
+	assert.Nil(err)
+	assert.Equal(fmt.Sprintf("six\r\n%s\r\n", longLine), getFileContentSinceOffset(f, n))
+
+	// last 100 lines w/ `\r\n` and first line longer than buf size
+	longLine = strings.Repeat("a", int(bufSize+1))
+	writeFile(f, fmt.Sprintf("%s\r\ntwo\r\nthree\r\nfour\r\nfive\r\nsix\r\nseven\r\n", longLine))
+	n, err = GetLastNLinesBeginWithBufSize(f.Name(), 100, bufSize)
+	assert.Nil(err)
+	assert.EqualValues(0, n)
+
+	// invalid buffer size
+	_, err = GetLastNLinesBeginWithBufSize(f.Name(), 2, 0)
+	assert.EqualError(err, "Buffer size should be positive, got 0")
+
+	// default buffer size
+	writeFile(f, "one\ntwo\nthree\nfour\nfive\nsix\nseven\n")
+	n, err = GetLastNLinesBegin(f.Name(), 2)
+	assert.Nil(err)
+	assert.Equal("six\nseven\n", getFileContentSinceOffset(f, n))
 }
 
 func TestGetInstancesFromArgs(t *testing.T) {
@@ -118,14 +180,39 @@ func TestGetInstancesFromArgs(t *testing.T) {
 	ctx := &context.Ctx{}
 	ctx.Project.Name = "myapp"
 
-	// wrong format
+	// instance IDs of the current application
 	args = []string{"myapp.instance-1", "myapp.instance-2"}
+	instances, err = GetInstancesFromArgs(args, ctx)
+	assert.Nil(err)
+	assert.Equal([]string{"instance-1", "instance-2"}, instances)
+
+	args = []string{"instance-1", "myapp@instance-2"}
+	instances, err = GetInstancesFromArgs(args, ctx)
+	assert.Nil(err)
+	assert.Equal([]string{"instance-1", "instance-2"}, instances)
+
+	args = []string{"myapp@instance-1", "myapp.instance-2"}
+	instances, err = GetInstancesFromArgs(args, ctx)
+	assert.Nil(err)
+	assert.Equal([]string{"instance-1", "instance-2"}, instances)
+
+	// instance IDs of another application
+	args = []string{"instance-1", "otherapp.instance-2"}
 	_, err = GetInstancesFromArgs(args, ctx)
-	assert.EqualError(err, instanceIDSpecified)
+	assert.EqualError(err, `Instance otherapp.instance-2 belongs to application "otherapp", `+
+		`but current application is "myapp". Please, specify instance name(s)`)
 
-	args = []string{"instance-1", "myapp.instance-2"}
+	args = []string{"otherapp@instance-1"}
 	_, err = GetInstancesFromArgs(args, ctx)
-	assert.EqualError(err, instanceIDSpecified)
+	assert.EqualError(err, `Instance otherapp@instance-1 belongs to application "otherapp", `+
+		`but current application is "myapp". Please, specify instance name(s)`)
+
+	// wrong format
+	for _, instanceID := range []string{"myapp.", "@instance-1", "myapp.instance-1.data", "myapp@instance-1@1"} {
+		args = []string{instanceID}
+		_, err = GetInstancesFromArgs(args, ctx)
+		assert.EqualError(err, instanceIDSpecified, instanceID)
+	}
 
 	args = []string{"myapp"}
 	_, err = GetInstancesFromArgs(args, ctx)
@@ -136,6 +223,10 @@ func TestGetInstancesFromArgs(t *testing.T) {
 	_, err = GetInstancesFromArgs(args, ctx)
 	assert.True(strings.Contains(err.Error(), "Duplicate instance name specified: instance-1"))
 
+	args = []string{"instance-1", "myapp@instance-1"}
+	_, err = GetInstancesFromArgs(args, ctx)
+	assert.True(strings.Contains(err.Error(), "Duplicate instance name specified: instance-1"))
+
 	// instances are specified
 	args = []string{"instance-1", "instance-2"}
 	instances, err = GetInstancesFromArgs(args, ctx)
@@ -151,3 +242,139 @@ func TestGetInstancesFromArgs(t *testing.T) {
 	instances, err = GetInstancesFromArgs(args, ctx)
 	assert.EqualError(err, appNameSpecifiedError)
 }
+
+func TestLevenshteinDistance(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	assert.Equal(0, LevenshteinDistance("", ""))
+	assert.Equal(0, LevenshteinDistance("router", "router"))
+	assert.Equal(6, LevenshteinDistance("", "router"))
+	assert.Equal(6, LevenshteinDistance("router", ""))
+	assert.Equal(2, LevenshteinDistance("instnace-1", "instance-1"))
+	assert.Equal(1, LevenshteinDistance("s1-mastr", "s1-master"))
+	assert.Equal(1, LevenshteinDistance("s1-master", "s2-master"))
+	assert.Equal(3, LevenshteinDistance("kitten", "sitting"))
+	assert.Equal(1, LevenshteinDistance("ключ", "клюв"))
+}
+
+func TestGetClosestString(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	candidates := []string{"router", "s1-master", "s1-replica", "s2-master"}
+
+	assert.Equal("router", GetClosestString("ruoter", candidates))
+	assert.Equal("s1-master", GetClosestString("s1-mastr", candidates))
+	assert.Equal("s1-replica", GetClosestString("s1-replika", candidates))
+	assert.Equal("", GetClosestString("stateboard", candidates))
+	assert.Equal("", GetClosestString("router", nil))
+}
+
+func TestCheckInstancesConfigured(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	configuredInstances := []string{"router", "instance-1", "instance-2"}
+
+	assert.Nil(CheckInstancesConfigured(nil, configuredInstances))
+	assert.Nil(CheckInstancesConfigured([]string{"router", "instance-2"}, configuredInstances))
+
+	assert.EqualError(
+		CheckInstancesConfigured([]string{"router", "instnace-1"}, configuredInstances),
+		"No such instance configured: instnace-1, did you mean instance-1?",
+	)
+
+	assert.EqualError(
+		CheckInstancesConfigured([]string{"storage"}, configuredInstances),
+		"No such instance configured: storage",
+	)
+}
+
+func TestParseSize(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var size int64
+	var err error
+
+	size, err = ParseSize("1024")
+	assert.Nil(err)
+	assert.EqualValues(1024, size)
+
+	size, err = ParseSize("100K")
+	assert.Nil(err)
+	assert.EqualValues(100*1024, size)
+
+	size, err = ParseSize("10MB")
+	assert.Nil(err)
+	assert.EqualValues(10*1024*1024, size)
+
+	size, err = ParseSize("2 GiB")
+	assert.Nil(err)
+	assert.EqualValues(2*1024*1024*1024, size)
+
+	size, err = ParseSize("5m")
+	assert.Nil(err)
+	assert.EqualValues(5*1024*1024, size)
+
+	_, err = ParseSize("")
+	assert.NotNil(err)
+
+	_, err = ParseSize("-1")
+	assert.NotNil(err)
+
+	_, err = ParseSize("10T")
+	assert.NotNil(err)
+
+	// overflow
+	_, err = ParseSize("9223372036854775807")
+	assert.Nil(err)
+
+	_, err = ParseSize("8589934592G")
+	assert.EqualError(err, `Size is too big: "8589934592G"`)
+
+	_, err = ParseSize("9223372036854775808")
+	assert.NotNil(err)
+}
+
+func TestSplitFile(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "split")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	srcFilePath := filepath.Join(tmpDir, "myapp.tar.gz")
+	assert.Nil(ioutil.WriteFile(srcFilePath, []byte("0123456789"), 0644))
+
+	partPaths, err := SplitFile(srcFilePath, 4)
+	assert.Nil(err)
+	assert.Equal([]string{srcFilePath + ".part00", srcFilePath + ".part01", srcFilePath + ".part02"}, partPaths)
+
+	lastPartContent, err := ioutil.ReadFile(partPaths[2])
+	assert.Nil(err)
+	assert.Equal("89", string(lastPartContent))
+
+	for _, partPath := range partPaths {
+		assert.Nil(os.Remove(partPath))
+	}
+
+	// created parts are removed on failure
+	assert.Nil(os.Mkdir(srcFilePath+".part01", 0755))
+
+	_, err = SplitFile(srcFilePath, 4)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Failed to create part file")
+
+	_, err = os.Stat(srcFilePath + ".part00")
+	assert.True(os.IsNotExist(err))
+}
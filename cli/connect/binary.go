@@ -5,7 +5,6 @@ import (
 	"io"
 
 	"github.com/FZambia/tarantool"
-	"github.com/apex/log"
 	"github.com/c-bata/go-prompt"
 )
 
@@ -65,25 +64,25 @@ func getBinaryCompleter(console *Console) prompt.Completer {
 	return completer
 }
 
-func binaryExecute(console *Console, in string) string {
+func binaryExecute(console *Console, in string) (string, error) {
 	dataRaw, err := console.Eval(
 		"return require('console').eval(...)",
 		in,
 	)
 
 	if err == io.EOF {
-		log.Fatalf("Connection was closed. Probably instance process isn't running anymore")
+		return "", errConnectionClosed
 	}
 	if err != nil {
-		log.Fatalf("Failed to eval: %s", err)
+		return "", fmt.Errorf("Failed to eval: %s", err)
 	}
 
 	data, ok := dataRaw.(string)
 	if !ok {
-		log.Fatalf("Failed to eval: Data received in wrong format")
+		return "", fmt.Errorf("Failed to eval: Data received in wrong format")
 	}
 
-	return data
+	return data, nil
 }
 
 const (
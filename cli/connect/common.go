@@ -3,6 +3,7 @@ package connect
 import (
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/adam-hanna/arrayOperations"
 	"github.com/c-bata/go-prompt"
@@ -22,14 +23,22 @@ type ConnOpts struct {
 	Address  string
 	Username string
 	Password string
+
+	EvalTimeout time.Duration
+	Format      ResultFormat
 }
 
 type GetRawSuggestionsFunc func(console *Console, lastWord string) interface{}
 
-func getConnOpts(connString string, ctx *context.Ctx) (*ConnOpts, error) {
+// GetConnOpts parses the [USER[:PASSWORD]@]ADDRESS connection string.
+// Credentials specified in the context have greater priority
+func GetConnOpts(connString string, ctx *context.Ctx) (*ConnOpts, error) {
 	connOpts := ConnOpts{
 		Username: ctx.Connect.Username,
 		Password: ctx.Connect.Password,
+
+		EvalTimeout: ctx.Connect.EvalTimeout,
+		Format:      ResultFormat(ctx.Connect.Format),
 	}
 
 	connStringParts := strings.SplitN(connString, "@", 2)
@@ -82,6 +91,11 @@ func getSuggestions(console *Console, in prompt.Document,
 		return nil
 	}
 
+	// the connection is used by the timed out statement
+	if !pendingStatementIsFinished(console) {
+		return nil
+	}
+
 	lastWordStart := in.FindStartOfPreviousWordUntilSeparator(tarantoolWordSeparators)
 	lastWord := in.Text[lastWordStart:]
 
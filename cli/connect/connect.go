@@ -2,6 +2,7 @@ package connect
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 
 	"github.com/tarantool/cartridge-cli/cli/common"
@@ -13,11 +14,17 @@ import (
 const (
 	// see https://github.com/tarantool/tarantool/blob/b53cb2aeceedc39f356ceca30bd0087ee8de7c16/src/box/lua/console.c#L265
 	tarantoolWordSeparators = "\t\r\n !\"#$%&'()*+,-/;<=>?@[\\]^`{|}~"
+
+	readExpressionFromStdin = "-"
 )
 
 func Enter(ctx *context.Ctx, args []string) error {
 	var err error
 
+	if ctx.Connect.Conn != "" {
+		return enterByConnString(ctx, args)
+	}
+
 	if err := FillCtx(ctx, args); err != nil {
 		return err
 	}
@@ -31,27 +38,89 @@ func Enter(ctx *context.Ctx, args []string) error {
 	}
 
 	instanceName := ctx.Running.Instances[0]
+	socketPath := project.GetInstanceConsoleSock(ctx, instanceName)
 
-	process := running.NewInstanceProcess(ctx, instanceName)
-	if !process.IsRunning() {
-		return fmt.Errorf("Instance %s is not running", instanceName)
+	if !running.IsInstanceRunning(ctx, instanceName) {
+		return fmt.Errorf(
+			"Instance %s is not running. %s", instanceName, getEnterOptionsHint(socketPath),
+		)
+	}
+
+	if _, err := os.Stat(socketPath); os.IsNotExist(err) {
+		return fmt.Errorf(
+			"Console socket of instance %s isn't found. %s", instanceName, getEnterOptionsHint(socketPath),
+		)
 	}
 
-	socketPath := project.GetInstanceConsoleSock(ctx, instanceName)
 	title := project.GetInstanceID(ctx, instanceName)
 
 	connOpts := ConnOpts{
 		Network: "unix",
 		Address: socketPath,
+
+		EvalTimeout: ctx.Connect.EvalTimeout,
 	}
 
-	if err := runConsole(&connOpts, title); err != nil {
-		return fmt.Errorf("Failed to run interactive console: %s", err)
+	return runEnterConsole(ctx, &connOpts, title)
+}
+
+// enterByConnString enters instance console by address specified via --conn.
+// It's used for instances that aren't started by `cartridge start`,
+// so the project and its run directory aren't required
+func enterByConnString(ctx *context.Ctx, args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("Should be specified one instance name")
+	}
+
+	connOpts, err := GetConnOpts(ctx.Connect.Conn, ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to get connection opts: %s", err)
+	}
+
+	// instance name is used only as a console title
+	title := ""
+	if len(args) == 1 {
+		title = args[0]
+	}
+
+	return runEnterConsole(ctx, connOpts, title)
+}
+
+// runEnterConsole runs interactive console or evaluates
+// the expression specified via -e if it's set
+func runEnterConsole(ctx *context.Ctx, connOpts *ConnOpts, title string) error {
+	if ctx.Connect.Expression == "" {
+		if err := runConsole(connOpts, title); err != nil {
+			return fmt.Errorf("Failed to run interactive console: %s", err)
+		}
+
+		return nil
+	}
+
+	expression := ctx.Connect.Expression
+	if expression == readExpressionFromStdin {
+		expressionBytes, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("Failed to read expression from stdin: %s", err)
+		}
+
+		expression = string(expressionBytes)
+	}
+
+	if err := runExpression(connOpts, title, expression); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+func getEnterOptionsHint(socketPath string) string {
+	return fmt.Sprintf(
+		"Start the instance via `cartridge start` to create its console socket %s "+
+			"or specify the instance address via --conn to connect over TCP", socketPath,
+	)
+}
+
 func Connect(ctx *context.Ctx, args []string) error {
 	if len(args) != 1 {
 		return fmt.Errorf("Should be specified one connection string")
@@ -59,7 +128,7 @@ func Connect(ctx *context.Ctx, args []string) error {
 
 	connString := args[0]
 
-	connOpts, err := getConnOpts(connString, ctx)
+	connOpts, err := GetConnOpts(connString, ctx)
 	if err != nil {
 		return fmt.Errorf("Failed to get connection opts: %s", err)
 	}
@@ -98,6 +167,27 @@ func FillCtx(ctx *context.Ctx, args []string) error {
 	return nil
 }
 
+// runExpression evaluates the expression on the instance
+// and prints returned values, one per line
+func runExpression(connOpts *ConnOpts, title string, expression string) error {
+	console, err := NewConsole(connOpts, title)
+	if err != nil {
+		return fmt.Errorf("Failed to create new console: %s", err)
+	}
+	defer console.Close()
+
+	values, err := console.EvalExpression(expression)
+	if err != nil {
+		return fmt.Errorf("Failed to evaluate expression: %s", err)
+	}
+
+	for _, value := range values {
+		fmt.Println(value)
+	}
+
+	return nil
+}
+
 func runConsole(connOpts *ConnOpts, title string) error {
 	console, err := NewConsole(connOpts, title)
 	if err != nil {
@@ -2,6 +2,8 @@ package connect
 
 import (
 	"bufio"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"net"
 	"os"
@@ -21,6 +23,7 @@ type ConsoleOutputMode string
 type Protocol string
 
 type EvalFunc func(console *Console, funcBodyFmt string, args ...interface{}) (interface{}, error)
+type ExecuteFunc func(console *Console, in string) (string, error)
 
 const (
 	ConsoleYAMLOutput ConsoleOutputMode = "yaml"
@@ -29,18 +32,29 @@ const (
 	PlainTextProtocol Protocol = "plain text"
 	BinaryProtocol    Protocol = "binary"
 
-	HistoryFileName = ".tarantool_history"
+	// HistoryPathEnv overrides the directory where consoles history files are stored,
+	// by default it's ~/.cartridge/console_history
+	HistoryPathEnv = "CARTRIDGE_CONSOLE_HISTORY"
 
 	MaxLivePrefixIndent = 15
 
 	readGreetingTimeout     = 3 * time.Second
 	evalCompletionTimeout   = 3 * time.Second
 	readFromConnExecTimeout = 0
+
+	// time to wait for the cancelled statement output
+	cancelledStatementWaitTimeout = 3 * time.Second
+
+	evalKeyLength = 16
 )
 
 var (
 	ControlLeftBytes  []byte
 	ControlRightBytes []byte
+
+	errConnectionClosed = errors.New("Connection was closed. Probably instance process isn't running anymore")
+	errEvalTimeout      = errors.New("Statement execution timeout was reached")
+	errStatementPending = errors.New("Previous statement is still being executed")
 )
 
 func init() {
@@ -57,6 +71,11 @@ type Console struct {
 	historyFilePath string
 	historyLines    []string
 
+	// Ctrl+R history search state
+	historySearchQuery string
+	historySearchIndex int
+	historySearchMatch string
+
 	prefix            string
 	livePrefixEnabled bool
 	livePrefix        string
@@ -66,6 +85,13 @@ type Console struct {
 	conn       net.Conn
 	binaryConn *tarantool.Connection
 
+	// evalKey identifies statements fibers of this console on the instance,
+	// it's used to cancel the statement if eval timeout is reached
+	evalKey string
+	// pendingResCh receives the result of the timed out statement
+	// that wasn't finished after cancellation
+	pendingResCh chan executeRes
+
 	evalFunc  EvalFunc
 	executor  func(in string)
 	completer func(in prompt.Document) []prompt.Suggest
@@ -84,15 +110,11 @@ func NewConsole(connOpts *ConnOpts, title string) (*Console, error) {
 		outputMode: ConsoleYAMLOutput,
 		connOpts:   connOpts,
 		luaState:   lua.NewState(),
+		evalKey:    common.RandomString(evalKeyLength),
 	}
 
 	var err error
 
-	// load Tarantool console history from file
-	if err := loadHistory(console); err != nil {
-		log.Debugf("Failed to load Tarantool console history: %s", err)
-	}
-
 	// connect to specified address
 	console.conn, err = net.Dial(connOpts.Network, connOpts.Address)
 	if err != nil {
@@ -129,6 +151,12 @@ func NewConsole(connOpts *ConnOpts, title string) (*Console, error) {
 	setTitle(console)
 	setPrefix(console)
 
+	// load console history from file
+	// history is stored separately for each console title
+	if err := loadHistory(console); err != nil {
+		log.Debugf("Failed to load console history: %s", err)
+	}
+
 	return console, nil
 }
 
@@ -178,23 +206,45 @@ func (console *Console) Eval(funcBody string, args ...interface{}) (interface{},
 	return console.evalFunc(console, funcBody, args...)
 }
 
-func loadHistory(console *Console) error {
-	var err error
+// EvalExpression evaluates Lua expression or statement (e.g. `box.info.version`
+// or `return box.cfg.listen`) and returns returned values formatted as strings.
+// Tables are JSON-encoded. Lua error is returned as an error
+func (console *Console) EvalExpression(expression string) ([]string, error) {
+	// expression is passed base64-encoded since eval function body
+	// is formatted and joined to one line on plain text eval
+	encodedExpression := base64.StdEncoding.EncodeToString([]byte(expression))
+	funcBody := fmt.Sprintf(evalExpressionFuncBodyFmt, encodedExpression)
 
-	homeDir, err := common.GetHomeDir()
+	valuesRaw, err := console.Eval(funcBody)
 	if err != nil {
-		return fmt.Errorf("Failed to get home directory: %s", err)
+		return nil, err
 	}
 
-	console.historyFilePath = filepath.Join(homeDir, HistoryFileName)
+	values, err := common.ConvertToStringsSlice(valuesRaw)
+	if err != nil {
+		return nil, fmt.Errorf("Returned values received in wrong format: %s", err)
+	}
 
-	console.historyLines, err = common.GetLastNLines(console.historyFilePath, MaxHistoryLines)
+	return values, nil
+}
+
+func loadHistory(console *Console) error {
+	var err error
+
+	historyDir, err := getHistoryDir()
 	if err != nil {
-		return fmt.Errorf("Failed to read history from file: %s", err)
+		return err
+	}
+
+	if err := os.MkdirAll(historyDir, 0755); err != nil {
+		return fmt.Errorf("Failed to create history directory: %s", err)
 	}
 
+	console.historyFilePath = filepath.Join(historyDir, getHistoryFileName(console.title))
+
 	// open history file for appending
 	// see https://unix.stackexchange.com/questions/346062/concurrent-writing-to-a-log-file-from-many-processes
+	// it's opened before reading to create the file on the first run
 	console.historyFile, err = os.OpenFile(
 		console.historyFilePath,
 		os.O_APPEND|os.O_CREATE|os.O_WRONLY,
@@ -205,9 +255,84 @@ func loadHistory(console *Console) error {
 		log.Debugf("Failed to open history file for append: %s", err)
 	}
 
+	console.historyLines, err = common.GetLastNLines(console.historyFilePath, MaxHistoryLines)
+	if err != nil {
+		return fmt.Errorf("Failed to read history from file: %s", err)
+	}
+
 	return nil
 }
 
+func getHistoryDir() (string, error) {
+	if historyDir := os.Getenv(HistoryPathEnv); historyDir != "" {
+		return historyDir, nil
+	}
+
+	homeDir, err := common.GetHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("Failed to get home directory: %s", err)
+	}
+
+	return filepath.Join(homeDir, ".cartridge", "console_history"), nil
+}
+
+// getHistoryFileName returns history file name for the console title,
+// e.g. <app-name>.<instance-name> or <host>_<port>
+func getHistoryFileName(title string) string {
+	fileName := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune(".-_", r) {
+			return r
+		}
+		return '_'
+	}, title)
+
+	if fileName == "" || strings.Trim(fileName, ".") == "" {
+		return "default"
+	}
+
+	return fileName
+}
+
+// searchHistoryBackward returns the index of the last history line
+// before the specified one that contains the query, or -1 if there is no such line
+func searchHistoryBackward(historyLines []string, query string, before int) int {
+	if before > len(historyLines) {
+		before = len(historyLines)
+	}
+
+	for i := before - 1; i >= 0; i-- {
+		if strings.Contains(historyLines[i], query) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// reverseSearchHistory replaces the input with the last history line
+// that contains the text typed before the first Ctrl+R press.
+// Next Ctrl+R presses continue search from the found line
+func reverseSearchHistory(console *Console, buf *prompt.Buffer) {
+	text := buf.Text()
+
+	if console.historySearchMatch == "" || text != console.historySearchMatch {
+		console.historySearchQuery = text
+		console.historySearchIndex = len(console.historyLines)
+	}
+
+	index := searchHistoryBackward(console.historyLines, console.historySearchQuery, console.historySearchIndex)
+	if index < 0 {
+		return
+	}
+
+	console.historySearchIndex = index
+	console.historySearchMatch = strings.TrimSpace(console.historyLines[index])
+
+	buf.DeleteBeforeCursor(len([]rune(buf.Document().TextBeforeCursor())))
+	buf.Delete(len([]rune(buf.Document().TextAfterCursor())))
+	buf.InsertText(console.historySearchMatch, false, true)
+}
+
 func detectProtocolAndReconnectIfRequired(console *Console) error {
 	greeting, err := readGreeting(console.conn)
 	if err != nil {
@@ -243,7 +368,7 @@ func getEvalFunc(console *Console) (EvalFunc, error) {
 }
 
 func getExecutor(console *Console) (prompt.Executor, error) {
-	var executeFunc func(console *Console, in string) string
+	var executeFunc ExecuteFunc
 	switch {
 	case console.protocol == PlainTextProtocol:
 		executeFunc = plainTextExecute
@@ -264,10 +389,28 @@ func getExecutor(console *Console) (prompt.Executor, error) {
 		if err := appendToHistoryFile(console, console.input); err != nil {
 			log.Debugf("Failed to append command to history file: %s", err)
 		}
+		console.historyLines = append(console.historyLines, console.input)
+		console.historySearchMatch = ""
 
-		data := executeFunc(console, console.input)
+		statement := console.input
+		if console.connOpts.EvalTimeout > 0 {
+			statement = getCancellableStatement(console.input, console.evalKey)
+		}
 
-		fmt.Printf("%s\n", data)
+		cancel := func() error {
+			return cancelStatement(console)
+		}
+
+		data, err := executeWithTimeout(console, executeFunc, statement, console.connOpts.EvalTimeout, cancel)
+		if err == errEvalTimeout {
+			log.Errorf("%s (%s), statement is cancelled", err, console.connOpts.EvalTimeout)
+		} else if err == errStatementPending {
+			log.Errorf(err.Error())
+		} else if err != nil {
+			log.Fatalf(err.Error())
+		} else {
+			fmt.Printf("%s\n", formatConsoleOutput(console, data))
+		}
 
 		console.input = ""
 		console.livePrefixEnabled = false
@@ -276,6 +419,129 @@ func getExecutor(console *Console) (prompt.Executor, error) {
 	return executor, nil
 }
 
+type executeRes struct {
+	data string
+	err  error
+}
+
+// executeWithTimeout calls executeFunc and waits for it's result.
+// If timeout is exceeded, the statement is cancelled via cancel
+// and errEvalTimeout is returned. The connection is kept: the cancelled
+// statement output is read before the next statement is executed,
+// errStatementPending is returned until it's received
+func executeWithTimeout(console *Console, executeFunc ExecuteFunc, in string,
+	timeout time.Duration, cancel func() error) (string, error) {
+
+	if !pendingStatementIsFinished(console) {
+		return "", errStatementPending
+	}
+
+	if timeout == 0 {
+		return executeFunc(console, in)
+	}
+
+	// channel is buffered to don't block goroutine after timeout
+	resCh := make(chan executeRes, 1)
+
+	go func() {
+		data, err := executeFunc(console, in)
+		resCh <- executeRes{data, err}
+	}()
+
+	select {
+	case res := <-resCh:
+		return res.data, res.err
+	case <-time.After(timeout):
+	}
+
+	if err := cancel(); err != nil {
+		log.Warnf("Failed to cancel the statement: %s", err)
+	}
+
+	cancelledWaitTimeout := cancelledStatementWaitTimeout
+	if timeout < cancelledWaitTimeout {
+		cancelledWaitTimeout = timeout
+	}
+
+	// the cancelled statement output is skipped
+	select {
+	case <-resCh:
+	case <-time.After(cancelledWaitTimeout):
+		console.pendingResCh = resCh
+	}
+
+	return "", errEvalTimeout
+}
+
+// pendingStatementIsFinished checks that the connection isn't used
+// by the previously timed out statement
+func pendingStatementIsFinished(console *Console) bool {
+	if console.pendingResCh == nil {
+		return true
+	}
+
+	select {
+	case <-console.pendingResCh:
+		console.pendingResCh = nil
+		return true
+	default:
+		return false
+	}
+}
+
+// getCancellableStatement returns the statement that evaluates the specified one
+// in a separate fiber. The fiber is registered by the console eval key,
+// so it can be found and cancelled from another connection.
+// Returned values and errors are the same as for the specified statement.
+// Console commands (e.g. `\set output lua`) are returned as is
+func getCancellableStatement(in string, evalKey string) string {
+	if strings.HasPrefix(strings.TrimSpace(in), "\\") {
+		return in
+	}
+
+	encodedStatement := base64.StdEncoding.EncodeToString([]byte(in))
+	return fmt.Sprintf(cancellableStatementFmt, encodedStatement, evalKey, evalKey)
+}
+
+// cancelStatement cancels the statement fiber over the separate connection.
+// The console session isn't closed
+func cancelStatement(console *Console) error {
+	var err error
+
+	cancelConsole := &Console{
+		outputMode: ConsoleYAMLOutput,
+		connOpts:   console.connOpts,
+	}
+
+	if cancelConsole.conn, err = net.Dial(console.connOpts.Network, console.connOpts.Address); err != nil {
+		return fmt.Errorf("Failed to dial: %s", err)
+	}
+	defer cancelConsole.conn.Close()
+
+	if err := detectProtocolAndReconnectIfRequired(cancelConsole); err != nil {
+		return err
+	}
+
+	if cancelConsole.binaryConn != nil {
+		defer cancelConsole.binaryConn.Close()
+	}
+
+	if cancelConsole.evalFunc, err = getEvalFunc(cancelConsole); err != nil {
+		return err
+	}
+
+	cancelledRaw, err := cancelConsole.Eval(fmt.Sprintf(cancelStatementFuncBodyFmt, console.evalKey))
+	if err != nil {
+		return err
+	}
+
+	if cancelled, ok := cancelledRaw.(bool); ok && !cancelled {
+		return fmt.Errorf("Statement fiber isn't found")
+	}
+
+	return nil
+}
+
 func inputIsCompleted(input string, luaState *lua.LState) bool {
 	// see https://github.com/tarantool/tarantool/blob/b53cb2aeceedc39f356ceca30bd0087ee8de7c16/src/box/lua/console.lua#L575
 	if _, err := luaState.LoadString(input); err == nil || !strings.Contains(err.Error(), "at EOF") {
@@ -351,6 +617,15 @@ func getPromptOptions(console *Console) []prompt.Option {
 
 		prompt.OptionCompletionWordSeparator(tarantoolWordSeparators),
 
+		prompt.OptionAddKeyBind(
+			prompt.KeyBind{ // search history backward
+				Key: prompt.ControlR,
+				Fn: func(buf *prompt.Buffer) {
+					reverseSearchHistory(console, buf)
+				},
+			},
+		),
+
 		prompt.OptionAddASCIICodeBind(
 			prompt.ASCIICodeBind{ // move to one word left
 				ASCIICode: ControlLeftBytes,
@@ -402,6 +677,72 @@ func appendToHistoryFile(console *Console, in string) error {
 }
 
 const (
+	// it's sent to the console as a single line
+	cancellableStatementFmt = "local fiber = require('fiber') " +
+		"local statement = require('digest').base64_decode('%s') " +
+		"local fn, err = loadstring('return ' .. statement) " +
+		"if fn == nil then fn, err = loadstring(statement) end " +
+		"if fn == nil then error(err, 0) end " +
+		"local function pack(...) return select('#', ...), {...} end " +
+		"local f = fiber.new(function() return pack(fn()) end) " +
+		"f:set_joinable(true) " +
+		"local fibers = rawget(_G, '__cartridge_cli_statements') or {} " +
+		"rawset(_G, '__cartridge_cli_statements', fibers) " +
+		"fibers['%s'] = f:id() " +
+		"local ok, n, values = f:join() " +
+		"fibers['%s'] = nil " +
+		"if not ok then error(n, 0) end " +
+		"return unpack(values, 1, n)"
+
+	cancelStatementFuncBodyFmt = `
+local fibers = rawget(_G, '__cartridge_cli_statements') or {}
+
+local fiber_id = fibers['%s']
+if fiber_id == nil then
+	return false
+end
+
+local f = require('fiber').find(fiber_id)
+if f == nil then
+	return false
+end
+
+f:cancel()
+return true
+`
+
+	evalExpressionFuncBodyFmt = `
+local expression = require('digest').base64_decode('%s')
+
+local fn, err = loadstring('return ' .. expression)
+if fn == nil then
+	fn, err = loadstring(expression)
+end
+if fn == nil then
+	error(err, 0)
+end
+
+local function pack(...)
+	return select('#', ...), {...}
+end
+
+local n, values = pack(fn())
+
+local formatted_values = {}
+for i = 1, n do
+	local value = values[i]
+	if type(value) == 'string' then
+		formatted_values[i] = value
+	elseif type(value) == 'table' then
+		formatted_values[i] = require('json').encode(value)
+	else
+		formatted_values[i] = tostring(value)
+	end
+end
+
+return formatted_values
+`
+
 	getTitleFuncBody = `
 local ok, api_topology = pcall(require, 'cartridge.lua-api.topology')
 if not ok then
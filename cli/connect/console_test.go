@@ -0,0 +1,132 @@
+package connect
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getDelayedExecuteFunc(delay time.Duration) ExecuteFunc {
+	return func(console *Console, in string) (string, error) {
+		time.Sleep(delay)
+		return fmt.Sprintf("---\n- %s\n...\n", in), nil
+	}
+}
+
+func TestExecuteWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var err error
+	var data string
+
+	console := &Console{}
+
+	noCancel := func() error {
+		return fmt.Errorf("Shouldn't be called")
+	}
+
+	// no timeout
+	data, err = executeWithTimeout(console, getDelayedExecuteFunc(10*time.Millisecond), "1", 0, noCancel)
+	assert.Nil(err)
+	assert.Equal("---\n- 1\n...\n", data)
+
+	// statement is executed before timeout
+	data, err = executeWithTimeout(console, getDelayedExecuteFunc(10*time.Millisecond), "2", time.Second, noCancel)
+	assert.Nil(err)
+	assert.Equal("---\n- 2\n...\n", data)
+
+	// statement execution error
+	failedExecuteFunc := func(console *Console, in string) (string, error) {
+		return "", errConnectionClosed
+	}
+
+	_, err = executeWithTimeout(console, failedExecuteFunc, "3", time.Second, noCancel)
+	assert.Equal(errConnectionClosed, err)
+
+	// timeout is reached, statement is cancelled
+	cancelledCh := make(chan struct{})
+	cancellableExecuteFunc := func(console *Console, in string) (string, error) {
+		<-cancelledCh
+		return "---\n- error: fiber is cancelled\n...\n", nil
+	}
+	cancel := func() error {
+		close(cancelledCh)
+		return nil
+	}
+
+	_, err = executeWithTimeout(console, cancellableExecuteFunc, "4", 100*time.Millisecond, cancel)
+	assert.Equal(errEvalTimeout, err)
+	assert.Nil(console.pendingResCh)
+
+	// timeout is reached, statement isn't finished after cancellation
+	timeStart := time.Now()
+	failedCancel := func() error {
+		return fmt.Errorf("Failed to cancel")
+	}
+
+	_, err = executeWithTimeout(console, getDelayedExecuteFunc(500*time.Millisecond), "5", 100*time.Millisecond, failedCancel)
+	assert.Equal(errEvalTimeout, err)
+	assert.True(time.Since(timeStart) < 500*time.Millisecond)
+	assert.NotNil(console.pendingResCh)
+
+	// the connection isn't used until the previous statement is finished
+	_, err = executeWithTimeout(console, getDelayedExecuteFunc(0), "6", time.Second, noCancel)
+	assert.Equal(errStatementPending, err)
+
+	time.Sleep(500 * time.Millisecond)
+
+	data, err = executeWithTimeout(console, getDelayedExecuteFunc(0), "7", time.Second, noCancel)
+	assert.Nil(err)
+	assert.Equal("---\n- 7\n...\n", data)
+	assert.Nil(console.pendingResCh)
+}
+
+func TestGetCancellableStatement(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	statement := getCancellableStatement("box.info() ", "key")
+	assert.NotContains(statement, "\n")
+	assert.Contains(statement, "base64_decode('Ym94LmluZm8oKSA=')")
+	assert.Contains(statement, "fibers['key'] = f:id()")
+
+	// console commands are sent as is
+	assert.Equal("\\set output lua ", getCancellableStatement("\\set output lua ", "key"))
+}
+
+func TestGetHistoryFileName(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	assert.Equal("myapp.router", getHistoryFileName("myapp.router"))
+	assert.Equal("localhost_3301", getHistoryFileName("localhost:3301"))
+	assert.Equal("_var_run_myapp.sock", getHistoryFileName("/var/run/myapp.sock"))
+	assert.Equal("default", getHistoryFileName(""))
+	assert.Equal("default", getHistoryFileName(".."))
+}
+
+func TestSearchHistoryBackward(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	historyLines := []string{
+		"box.info() ",
+		"box.cfg.listen ",
+		"require('cartridge') ",
+		"box.info.ro ",
+	}
+
+	assert.Equal(3, searchHistoryBackward(historyLines, "box.info", len(historyLines)))
+	assert.Equal(0, searchHistoryBackward(historyLines, "box.info", 3))
+	assert.Equal(-1, searchHistoryBackward(historyLines, "box.info", 0))
+	assert.Equal(2, searchHistoryBackward(historyLines, "cartridge", 100))
+	assert.Equal(-1, searchHistoryBackward(historyLines, "vshard", len(historyLines)))
+	assert.Equal(-1, searchHistoryBackward(nil, "box", 0))
+}
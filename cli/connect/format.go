@@ -0,0 +1,182 @@
+package connect
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apex/log"
+	"gopkg.in/yaml.v2"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+)
+
+type ResultFormat string
+
+const (
+	LuaFormat  ResultFormat = "lua"
+	YAMLFormat ResultFormat = "yaml"
+	JSONFormat ResultFormat = "json"
+)
+
+var (
+	luaIdentifierRgx = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	luaKeywords = map[string]bool{
+		"and": true, "break": true, "do": true, "else": true, "elseif": true,
+		"end": true, "false": true, "for": true, "function": true, "goto": true,
+		"if": true, "in": true, "local": true, "nil": true, "not": true,
+		"or": true, "repeat": true, "return": true, "then": true, "true": true,
+		"until": true, "while": true,
+	}
+)
+
+// CheckResultFormat checks that specified result format is supported
+func CheckResultFormat(format string) error {
+	switch ResultFormat(format) {
+	case LuaFormat, YAMLFormat, JSONFormat:
+		return nil
+	default:
+		return fmt.Errorf("Unsupported format %q. Supported formats are: lua, yaml, json", format)
+	}
+}
+
+// formatConsoleOutput converts console output to the format specified in connection opts.
+// Only YAML output can be converted, output is returned as is on failure.
+// YAML output is returned as is if YAML format is specified
+func formatConsoleOutput(console *Console, data string) string {
+	if console.connOpts == nil || console.connOpts.Format == "" || console.outputMode != ConsoleYAMLOutput {
+		return data
+	}
+
+	if console.connOpts.Format == YAMLFormat {
+		return data
+	}
+
+	formatted, err := formatResult(data, console.connOpts.Format)
+	if err != nil {
+		log.Debugf("Failed to format result: %s", err)
+		return data
+	}
+
+	return formatted
+}
+
+// formatResult converts YAML console output to specified format.
+// The console output is a YAML document that contains the list of returned values
+func formatResult(data string, format ResultFormat) (string, error) {
+	var values []interface{}
+	if err := yaml.Unmarshal([]byte(data), &values); err != nil {
+		return "", fmt.Errorf("Failed to parse console output: %s", err)
+	}
+
+	for i, value := range values {
+		values[i] = common.NormalizeYAMLValue(value)
+	}
+
+	switch format {
+	case LuaFormat:
+		return formatResultLua(values), nil
+	case YAMLFormat:
+		return formatResultYAML(values)
+	case JSONFormat:
+		return formatResultJSON(values)
+	default:
+		return "", fmt.Errorf("Unknown format: %s", format)
+	}
+}
+
+func formatResultLua(values []interface{}) string {
+	if len(values) == 0 {
+		return ";"
+	}
+
+	encodedValues := make([]string, len(values))
+	for i, value := range values {
+		encodedValues[i] = encodeLua(value)
+	}
+
+	return strings.Join(encodedValues, ", ") + ";"
+}
+
+func encodeLua(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return strconv.FormatBool(value)
+	case int:
+		return strconv.Itoa(value)
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case uint64:
+		return strconv.FormatUint(value, 10)
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	case string:
+		return quoteLua(value)
+	case []interface{}:
+		items := make([]string, len(value))
+		for i, item := range value {
+			items[i] = encodeLua(item)
+		}
+		return fmt.Sprintf("{%s}", strings.Join(items, ", "))
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		items := make([]string, len(keys))
+		for i, key := range keys {
+			keyStr := key
+			if !luaIdentifierRgx.MatchString(key) || luaKeywords[key] {
+				keyStr = fmt.Sprintf("[%s]", quoteLua(key))
+			}
+			items[i] = fmt.Sprintf("%s = %s", keyStr, encodeLua(value[key]))
+		}
+		return fmt.Sprintf("{%s}", strings.Join(items, ", "))
+	default:
+		return quoteLua(fmt.Sprintf(common.NonSerializablePlaceholderFmt, fmt.Sprintf("%T", value)))
+	}
+}
+
+// quoteLua returns double-quoted Lua string literal
+func quoteLua(s string) string {
+	return common.QuoteLua(s, '"')
+}
+
+func formatResultYAML(values []interface{}) (string, error) {
+	if len(values) == 0 {
+		return "---\n...\n", nil
+	}
+
+	encoded, err := yaml.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("Failed to encode result to YAML: %s", err)
+	}
+
+	return fmt.Sprintf("---\n%s...\n", encoded), nil
+}
+
+func formatResultJSON(values []interface{}) (string, error) {
+	if values == nil {
+		values = []interface{}{}
+	}
+
+	var b strings.Builder
+
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(values); err != nil {
+		return "", fmt.Errorf("Failed to encode result to JSON: %s", err)
+	}
+
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
@@ -0,0 +1,118 @@
+package connect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatResult(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var err error
+	var res string
+
+	// nested tables
+	data := "---\n- {a: 1, b: [1, 2, {c: x}]}\n- 2\n...\n"
+
+	res, err = formatResult(data, LuaFormat)
+	assert.Nil(err)
+	assert.Equal(`{a = 1, b = {1, 2, {c = "x"}}}, 2;`, res)
+
+	res, err = formatResult(data, YAMLFormat)
+	assert.Nil(err)
+	assert.Equal("---\n- a: 1\n  b:\n  - 1\n  - 2\n  - c: x\n- 2\n...\n", res)
+
+	res, err = formatResult(data, JSONFormat)
+	assert.Nil(err)
+	assert.Equal(`[
+  {
+    "a": 1,
+    "b": [
+      1,
+      2,
+      {
+        "c": "x"
+      }
+    ]
+  },
+  2
+]`, res)
+
+	// keys that aren't Lua identifiers
+	res, err = formatResult("---\n- {x-y: 1, end: 2, s: \"a\\\"b\\n\"}\n...\n", LuaFormat)
+	assert.Nil(err)
+	assert.Equal(`{["end"] = 2, ["x-y"] = 1, s = "a\"b\n"}`+";", res)
+
+	// nil and booleans
+	res, err = formatResult("---\n- null\n- true\n...\n", LuaFormat)
+	assert.Nil(err)
+	assert.Equal("nil, true;", res)
+
+	// non-serializable values
+	data = "---\n- .nan\n- {a: .inf}\n...\n"
+
+	res, err = formatResult(data, LuaFormat)
+	assert.Nil(err)
+	assert.Equal(`"<non-serializable: NaN>", {a = "<non-serializable: +Inf>"};`, res)
+
+	res, err = formatResult(data, JSONFormat)
+	assert.Nil(err)
+	assert.Equal(`[
+  "<non-serializable: NaN>",
+  {
+    "a": "<non-serializable: +Inf>"
+  }
+]`, res)
+
+	// empty result
+	data = "---\n...\n"
+
+	res, err = formatResult(data, LuaFormat)
+	assert.Nil(err)
+	assert.Equal(";", res)
+
+	res, err = formatResult(data, YAMLFormat)
+	assert.Nil(err)
+	assert.Equal("---\n...\n", res)
+
+	res, err = formatResult(data, JSONFormat)
+	assert.Nil(err)
+	assert.Equal("[]", res)
+
+	// invalid output
+	_, err = formatResult("---\n- [\n...\n", LuaFormat)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Failed to parse console output")
+}
+
+func TestFormatConsoleOutput(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	data := "---\n- {a: 1}\n...\n"
+	console := &Console{outputMode: ConsoleYAMLOutput}
+
+	// format isn't specified
+	assert.Equal(data, formatConsoleOutput(console, data))
+
+	// format is specified
+	console.connOpts = &ConnOpts{Format: LuaFormat}
+	assert.Equal("{a = 1};", formatConsoleOutput(console, data))
+
+	// YAML output is kept for YAML format
+	console.connOpts = &ConnOpts{Format: YAMLFormat}
+	assert.Equal(data, formatConsoleOutput(console, data))
+
+	// Lua output mode
+	console.connOpts = &ConnOpts{Format: LuaFormat}
+	console.outputMode = ConsoleLuaOutput
+	assert.Equal(data, formatConsoleOutput(console, data))
+
+	// invalid output is returned as is
+	console.outputMode = ConsoleYAMLOutput
+	assert.Equal("---\n- [\n...\n", formatConsoleOutput(console, "---\n- [\n...\n"))
+}
@@ -57,11 +57,11 @@ func getPlainTextCompleter(console *Console) prompt.Completer {
 	return completer
 }
 
-func plainTextExecute(console *Console, in string) string {
+func plainTextExecute(console *Console, in string) (string, error) {
 	var readFromConnFunc ReadFromConnFunc
 	if err := common.WriteToConn(console.conn, in+"\n"); err != nil {
 		log.Debugf("Failed to write to instance socket: %s", err)
-		log.Fatalf("Connection was closed. Probably instance process isn't running anymore")
+		return "", errConnectionClosed
 	}
 
 	console.conn.SetReadDeadline(time.Now().Add(1 * time.Second))
@@ -76,7 +76,7 @@ func plainTextExecute(console *Console, in string) string {
 	case console.outputMode == ConsoleLuaOutput:
 		readFromConnFunc = common.ReadFromConnLua
 	default:
-		log.Fatalf("Unknown output mode: %s", console.outputMode)
+		return "", fmt.Errorf("Unknown output mode: %s", console.outputMode)
 	}
 
 	dataBytes, err := readFromConnFunc(console.conn, common.ConnOpts{
@@ -87,10 +87,10 @@ func plainTextExecute(console *Console, in string) string {
 
 	if err != nil {
 		log.Debugf(err.Error())
-		log.Fatalf("Connection was closed. Probably instance process isn't running anymore")
+		return "", errConnectionClosed
 	}
 
-	return string(dataBytes)
+	return string(dataBytes), nil
 }
 
 func pushTagIsReceived(console *Console, dataPortion string) bool {
@@ -18,18 +18,27 @@ type Ctx struct {
 	Admin       AdminCtx
 	Replicasets ReplicasetsCtx
 	Connect     ConnectCtx
+	Eval        EvalCtx
+	Bench       BenchCtx
 }
 
 type ProjectCtx struct {
 	Name           string
 	StateboardName string
 	Path           string
+
+	Vars map[string]string
 }
 
 type CreateCtx struct {
 	TemplateFS http.FileSystem
 	Template   string
 	From       string
+	Ref        string
+	SetVars    []string
+
+	PostCreateHook string
+	DryRun         bool
 }
 
 type RepairCtx struct {
@@ -41,6 +50,7 @@ type RepairCtx struct {
 	NewURI             string
 
 	RemoveInstanceUUID string
+	RemoveLeader       bool
 
 	SetLeaderReplicasetUUID string
 	SetLeaderInstanceUUID   string
@@ -52,24 +62,71 @@ type BuildCtx struct {
 
 	InDocker   bool
 	DockerFrom string
+	BaseImage  string
 
 	SDKLocal        bool
 	SDKPath         string
 	BuildSDKDirname string
+
+	Target        string
+	Spec          string
+	RocksCacheDir string
 }
 
 type RunningCtx struct {
-	Instances      []string
-	WithStateboard bool
-	StateboardOnly bool
+	Instances             []string
+	WithStateboard        bool
+	StateboardOnly        bool
+	AllowUnknownInstances bool
+
+	Daemonize        bool
+	StartTimeout     time.Duration
+	StartParallelism int
+	WaitSocket       bool
+	WaitHealthy      bool
+	WaitTimeout      time.Duration
+
+	RunUser  string
+	RunGroup string
+
+	ClusterCookie string
+	AdvertiseURI  string
+	HTTPPort      int
+
+	EnvFiles     []string
+	EnvFilesVars map[string]string
+
+	LogFollow        bool
+	LogLines         int
+	LogMaxLineLength int
+	LogLevel         string
+	LogGrep          string
+	LogNoColor       bool
 
-	Daemonize    bool
-	StartTimeout time.Duration
+	LogOutputDir string
 
-	LogFollow bool
-	LogLines  int
+	StopForced  bool
+	StopTimeout time.Duration
 
-	StopForced bool
+	CleanDryRun bool
+
+	AgeWarn time.Duration
+
+	ReplicasetHealth bool
+	GroupTags        bool
+	PidFileCheck     bool
+	SocketCheck      bool
+	StatusExitCode   bool
+
+	StatusFormat      string
+	StatusJSONCompact bool
+
+	StatusWatch         bool
+	StatusWatchInterval time.Duration
+
+	CheckInstancesExpected bool
+	InstancesExpected      int
+	WaitForExpected        time.Duration
 
 	Entrypoint           string
 	StateboardEntrypoint string
@@ -86,21 +143,82 @@ type PackCtx struct {
 
 	Type string
 
-	DockerFrom string
+	DockerFrom        string
+	BaseImage         string
+	RuntimeDockerfile string
 
 	PackageFilesDir string
 	ResPackagePath  string
 	ResImageTags    []string
-
-	Version        string
-	Release        string
-	VersionRelease string
-	Suffix         string
-	ImageTags      []string
+	Push            bool
+
+	Version         string
+	Release         string
+	ReleaseOverride string
+	VersionRelease  string
+	Suffix          string
+	ImageTags       []string
+
+	IncludeEmptyDirs bool
+	EnsureDirs       []string
+	ExcludeVCS       bool
+	SplitSize        int64
+	Compression      string
+	CompressionLevel int
+	OutputDir        string
+	Checksum         bool
+
+	VerifyNoAbsSymlinks  bool
+	NormalizeLineEndings bool
+	Transforms           []string
+	RocksManifest        bool
+
+	KeepGoing bool
+	DryRun    bool
+	NoBuild   bool
+
+	DefaultFileMode string
+	DefaultDirMode  string
+	FileModes       []string
+
+	RpmRecommends  []string
+	RpmSupplements []string
+	RpmEnhances    []string
+	RpmMinVersion  string
+	RpmBuildHost   string
+
+	RpmPayloadDigestAlgo string
+	RpmRelocateDocsDir   string
+	RpmDocPatterns       []string
+
+	RpmSignKey    string
+	RpmSignHeader bool
+
+	RpmDigestWorkers int
+	RpmVerify        bool
+
+	Reproducible         bool
+	SourceDateEpoch      int64
+	SourceDateEpochIsSet bool
+
+	Deps     []string
+	DepsFile string
+
+	DebConffilesFrom string
+	DebConffiles     []string
+	DebArch          string
+
+	PreInstScriptPath  string
+	PostInstScriptPath string
+	PreRmScriptPath    string
+	PostRmScriptPath   string
 
 	UnitTemplatePath          string
 	InstUnitTemplatePath      string
 	StatboardUnitTemplatePath string
+
+	TmpfilesEntries []string
+	SysusersEntries []string
 }
 
 type TarantoolCtx struct {
@@ -121,6 +239,14 @@ type CliCtx struct {
 type DockerCtx struct {
 	NoCache   bool
 	CacheFrom []string
+
+	BuildArgs map[string]string
+	Secrets   []string
+
+	// Platforms are the target platforms of the multi-platform build,
+	// Platform is the one the current image is built for
+	Platforms []string
+	Platform  string
 }
 
 type AdminCtx struct {
@@ -128,6 +254,9 @@ type AdminCtx struct {
 	List bool
 
 	InstanceName string
+
+	// Format is the format function call result is printed in
+	Format string
 }
 
 type ReplicasetsCtx struct {
@@ -140,9 +269,58 @@ type ReplicasetsCtx struct {
 	RolesList             []string
 	VshardGroup           string
 	FailoverPriorityNames []string
+
+	ListFormat string
+
+	Yes bool
+
+	StateProvider      string
+	StateboardURI      string
+	StateboardPassword string
+	Etcd2Endpoints     []string
+	Etcd2Prefix        string
+	Etcd2LockDelay     float64
+	Etcd2Username      string
+	Etcd2Password      string
+	FailoverTimeout    float64
+	FencingEnabled     bool
+	FencingEnabledSet  bool
 }
 
 type ConnectCtx struct {
+	Conn     string
 	Username string
 	Password string
+
+	// SSH is the USER@HOST[:PORT] of the host
+	// the console sockets are forwarded from
+	SSH string
+
+	Expression string
+
+	EvalTimeout time.Duration
+	Format      string
+}
+
+type EvalCtx struct {
+	FunctionBody string
+	Timeout      time.Duration
+
+	ConnectAll bool
+	Quorum     int
+
+	Retry      int
+	RetryDelay time.Duration
+	RetryOn    []string
+}
+
+type BenchCtx struct {
+	Connections int
+	Duration    time.Duration
+	Count       int
+
+	PayloadSize int
+	ReadRatio   float64
+
+	Format string
 }
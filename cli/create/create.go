@@ -2,6 +2,7 @@ package create
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 
 	"github.com/tarantool/cartridge-cli/cli/common"
@@ -29,10 +30,16 @@ func Run(ctx *context.Ctx) error {
 		return fmt.Errorf("Unable to create application in %s: %s", ctx.Project.Path, err)
 	}
 
-	log.Infof("Create application %s", ctx.Project.Name)
+	if ctx.Create.From != "" && isGitTemplateURL(ctx.Create.From) {
+		templateDir, err := cloneTemplate(ctx.Create.From, ctx.Create.Ref, ctx.Cli.Verbose)
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(templateDir)
 
-	if err := os.Mkdir(ctx.Project.Path, 0755); err != nil {
-		return fmt.Errorf("Failed to create application directory: %s", err)
+		ctx.Create.From = templateDir
+	} else if ctx.Create.Ref != "" {
+		return fmt.Errorf("--ref can be specified only for the template from git repository")
 	}
 
 	if ctx.Create.From == "" {
@@ -44,6 +51,20 @@ func Run(ctx *context.Ctx) error {
 		}
 	}
 
+	if err := fillTemplateVars(ctx); err != nil {
+		return err
+	}
+
+	if ctx.Create.DryRun {
+		return runDryRun(ctx, os.Stdout)
+	}
+
+	log.Infof("Create application %s", ctx.Project.Name)
+
+	if err := os.Mkdir(ctx.Project.Path, 0755); err != nil {
+		return fmt.Errorf("Failed to create application directory: %s", err)
+	}
+
 	log.Infof("Generate application files")
 
 	if err := templates.Instantiate(ctx); err != nil {
@@ -51,11 +72,33 @@ func Run(ctx *context.Ctx) error {
 		return fmt.Errorf("Failed to instantiate application template: %s", err)
 	}
 
+	hooksDir, err := ioutil.TempDir("", "post-create-hook")
+	if err != nil {
+		return fmt.Errorf("Failed to create tmp directory for post-create hook: %s", err)
+	}
+	defer os.RemoveAll(hooksDir)
+
+	if err := moveTemplateHook(ctx, hooksDir); err != nil {
+		return err
+	}
+
 	log.Infof("Initialize application git repository")
 	if err := initGitRepo(ctx); err != nil {
 		log.Warnf("Failed to initialize git repository: %s", err)
 	}
 
+	hookPath, err := getPostCreateHookPath(ctx, hooksDir)
+	if err != nil {
+		return err
+	}
+
+	if hookPath != "" {
+		if err := runPostCreateHook(hookPath, ctx); err != nil {
+			return fmt.Errorf("Application %q is created in %s, but post-create hook failed: %s",
+				ctx.Project.Name, ctx.Project.Path, err)
+		}
+	}
+
 	log.Infof("Application %q created successfully", ctx.Project.Name)
 
 	return nil
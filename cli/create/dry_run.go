@@ -0,0 +1,57 @@
+package create
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/apex/log"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+	"github.com/tarantool/cartridge-cli/cli/create/templates"
+)
+
+// runDryRun renders the application template in memory and writes
+// the list of files that would be created (and skipped) to w.
+// Nothing is written on disk, git repository isn't initialized
+// and post-create hook isn't run
+func runDryRun(ctx *context.Ctx, w io.Writer) error {
+	log.Infof("Dry run: application %s isn't created", ctx.Project.Name)
+
+	entries, skipped, err := templates.Preview(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to render application template: %s", err)
+	}
+
+	log.Infof("Files that would be created in %s:", ctx.Project.Path)
+
+	hookFound := false
+	for _, entry := range entries {
+		// template hook isn't left in the application directory
+		if !entry.IsDir && entry.Path == postCreateHookName {
+			hookFound = true
+			continue
+		}
+
+		if entry.IsDir {
+			fmt.Fprintf(w, "%s/\n", entry.Path)
+		} else {
+			fmt.Fprintln(w, entry.Path)
+		}
+	}
+
+	if len(skipped) > 0 {
+		log.Infof("Template paths that would be skipped:")
+
+		for _, skippedPath := range skipped {
+			fmt.Fprintf(w, "%s (skipped)\n", skippedPath)
+		}
+	}
+
+	if ctx.Create.PostCreateHook != "" {
+		log.Infof("Post-create hook %s would be run", ctx.Create.PostCreateHook)
+	} else if hookFound {
+		log.Infof("Post-create hook %s would be run", postCreateHookName)
+	}
+
+	return nil
+}
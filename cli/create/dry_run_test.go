@@ -0,0 +1,64 @@
+package create
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "dry-run")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	templateDir := filepath.Join(tmpDir, "template")
+	templateFiles := map[string]string{
+		"init.lua":                            "local app = '{{ .Name }}'",
+		"{{ .Name }}-scm-1.rockspec":          "package = '{{ .Name }}'",
+		"app/roles/{{ .StateboardName }}.lua": "",
+		".git/HEAD":                           "ref: refs/heads/master",
+		postCreateHookName:                    "#!/bin/sh",
+	}
+
+	for filePath, content := range templateFiles {
+		fullPath := filepath.Join(templateDir, filePath)
+		assert.Nil(os.MkdirAll(filepath.Dir(fullPath), 0755))
+		assert.Nil(ioutil.WriteFile(fullPath, []byte(content), 0644))
+	}
+
+	ctx := &context.Ctx{}
+	ctx.Project.Name = "myapp"
+	ctx.Project.StateboardName = "myapp-stateboard"
+	ctx.Project.Path = filepath.Join(tmpDir, "myapp")
+	ctx.Create.From = templateDir
+	ctx.Create.DryRun = true
+
+	var out bytes.Buffer
+	assert.Nil(runDryRun(ctx, &out))
+
+	assert.Equal([]string{
+		"app/",
+		"app/roles/",
+		"app/roles/myapp-stateboard.lua",
+		"init.lua",
+		"myapp-scm-1.rockspec",
+		".git (skipped)",
+	}, strings.Split(strings.TrimSpace(out.String()), "\n"))
+
+	// nothing is created on disk
+	assert.Nil(Run(ctx))
+
+	_, err = os.Stat(ctx.Project.Path)
+	assert.True(os.IsNotExist(err))
+}
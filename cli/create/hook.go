@@ -0,0 +1,93 @@
+package create
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/apex/log"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+const (
+	postCreateHookName = "cartridge.post-create"
+	appNameEnv         = "CARTRIDGE_APP_NAME"
+)
+
+// moveTemplateHook moves the cartridge.post-create file rendered from the template
+// to hooksDir, so it isn't added to the application git repository
+func moveTemplateHook(ctx *context.Ctx, hooksDir string) error {
+	hookPath := filepath.Join(ctx.Project.Path, postCreateHookName)
+
+	hookFileInfo, err := os.Stat(hookPath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Failed to check %s hook: %s", postCreateHookName, err)
+	}
+
+	hookContent, err := ioutil.ReadFile(hookPath)
+	if err != nil {
+		return fmt.Errorf("Failed to read %s hook: %s", postCreateHookName, err)
+	}
+
+	movedHookPath := filepath.Join(hooksDir, postCreateHookName)
+	if err := ioutil.WriteFile(movedHookPath, hookContent, hookFileInfo.Mode()); err != nil {
+		return fmt.Errorf("Failed to write %s hook: %s", postCreateHookName, err)
+	}
+
+	if err := os.Remove(hookPath); err != nil {
+		return fmt.Errorf("Failed to remove %s hook from the application: %s", postCreateHookName, err)
+	}
+
+	return nil
+}
+
+// getPostCreateHookPath returns path to the hook specified by user or
+// path to the cartridge.post-create file moved to hooksDir if it exists.
+// If there is no hook to run, empty string is returned
+func getPostCreateHookPath(ctx *context.Ctx, hooksDir string) (string, error) {
+	if ctx.Create.PostCreateHook != "" {
+		hookPath, err := filepath.Abs(ctx.Create.PostCreateHook)
+		if err != nil {
+			return "", fmt.Errorf("Failed to get hook absolute path: %s", err)
+		}
+
+		return hookPath, nil
+	}
+
+	hookPath := filepath.Join(hooksDir, postCreateHookName)
+	if _, err := os.Stat(hookPath); os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("Failed to check %s hook: %s", postCreateHookName, err)
+	}
+
+	return hookPath, nil
+}
+
+// runPostCreateHook runs specified hook in the project directory
+func runPostCreateHook(hookPath string, ctx *context.Ctx) error {
+	hookName := filepath.Base(hookPath)
+
+	if isExec, err := common.IsExecOwner(hookPath); err != nil {
+		return fmt.Errorf("Failed to check hook file `%s`: %s", hookName, err)
+	} else if !isExec {
+		return fmt.Errorf("Hook `%s` should be executable", hookName)
+	}
+
+	log.Infof("Running `%s`", hookName)
+
+	hookCmd := exec.Command(hookPath)
+	hookCmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", appNameEnv, ctx.Project.Name))
+
+	if err := common.RunCommand(hookCmd, ctx.Project.Path, ctx.Cli.Verbose); err != nil {
+		return fmt.Errorf("Failed to run hook `%s`: %s", hookName, err)
+	}
+
+	return nil
+}
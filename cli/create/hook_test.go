@@ -0,0 +1,116 @@
+package create
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+const (
+	markerHookContent = `#!/bin/sh
+echo -n "$CARTRIDGE_APP_NAME" > post-create-marker
+`
+	failedHookContent = `#!/bin/sh
+exit 1
+`
+)
+
+func TestPostCreateHook(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var err error
+	var hookPath string
+
+	ctx := &context.Ctx{}
+	ctx.Project.Name = "myapp"
+
+	ctx.Project.Path, err = ioutil.TempDir("", "myapp")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(ctx.Project.Path)
+
+	hooksDir, err := ioutil.TempDir("", "hooks")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(hooksDir)
+
+	markerPath := filepath.Join(ctx.Project.Path, "post-create-marker")
+
+	// no hook
+	assert.Nil(moveTemplateHook(ctx, hooksDir))
+
+	hookPath, err = getPostCreateHookPath(ctx, hooksDir)
+	assert.Nil(err)
+	assert.Equal("", hookPath)
+
+	// hook from template is moved out of the project
+	templateHookPath := filepath.Join(ctx.Project.Path, postCreateHookName)
+	assert.Nil(ioutil.WriteFile(templateHookPath, []byte(markerHookContent), 0755))
+
+	assert.Nil(moveTemplateHook(ctx, hooksDir))
+
+	_, err = os.Stat(templateHookPath)
+	assert.True(os.IsNotExist(err))
+
+	hookPath, err = getPostCreateHookPath(ctx, hooksDir)
+	assert.Nil(err)
+	assert.Equal(filepath.Join(hooksDir, postCreateHookName), hookPath)
+
+	assert.Nil(runPostCreateHook(hookPath, ctx))
+
+	markerContent, err := ioutil.ReadFile(markerPath)
+	assert.Nil(err)
+	assert.Equal("myapp", string(markerContent))
+
+	// hook specified by user (has priority)
+	assert.Nil(os.Remove(markerPath))
+
+	userHooksDir, err := ioutil.TempDir("", "user-hooks")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(userHooksDir)
+
+	ctx.Create.PostCreateHook = filepath.Join(userHooksDir, "my-hook.sh")
+	assert.Nil(ioutil.WriteFile(ctx.Create.PostCreateHook, []byte(markerHookContent), 0755))
+
+	hookPath, err = getPostCreateHookPath(ctx, hooksDir)
+	assert.Nil(err)
+	assert.Equal(ctx.Create.PostCreateHook, hookPath)
+
+	assert.Nil(runPostCreateHook(hookPath, ctx))
+
+	// hook is ran in the project directory
+	markerContent, err = ioutil.ReadFile(markerPath)
+	assert.Nil(err)
+	assert.Equal("myapp", string(markerContent))
+
+	_, err = os.Stat(filepath.Join(userHooksDir, "post-create-marker"))
+	assert.True(os.IsNotExist(err))
+
+	// failed hook
+	assert.Nil(ioutil.WriteFile(ctx.Create.PostCreateHook, []byte(failedHookContent), 0755))
+
+	err = runPostCreateHook(hookPath, ctx)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Failed to run hook `my-hook.sh`")
+
+	// project is left in place
+	_, err = os.Stat(ctx.Project.Path)
+	assert.Nil(err)
+
+	// non-executable hook
+	assert.Nil(os.Chmod(ctx.Create.PostCreateHook, 0644))
+
+	err = runPostCreateHook(hookPath, ctx)
+	assert.EqualError(err, "Hook `my-hook.sh` should be executable")
+}
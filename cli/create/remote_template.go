@@ -0,0 +1,93 @@
+package create
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/apex/log"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+)
+
+const (
+	// git ls-remote --exit-code exits with this code if no matching refs are found
+	refNotFoundExitCode = 2
+)
+
+var (
+	// scheme://... or scp-like user@host:path
+	gitTemplateURLRgx = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.-]*://|[\w.-]+@[\w.-]+:)`)
+)
+
+// isGitTemplateURL checks if the template is specified by the git repository URL,
+// e.g. git@host:org/template.git or https://host/org/template.git
+func isGitTemplateURL(from string) bool {
+	return gitTemplateURLRgx.MatchString(from)
+}
+
+// cloneTemplate clones the template repository to the temporary directory
+// and returns its path. The directory should be removed by the caller.
+// Credentials are taken from the git config and SSH agent
+func cloneTemplate(url string, ref string, showOutput bool) (string, error) {
+	if !common.GitIsInstalled() {
+		return "", fmt.Errorf("git is required to use the template from git repository")
+	}
+
+	if ref != "" {
+		if err := checkTemplateRef(url, ref); err != nil {
+			return "", err
+		}
+	}
+
+	templateDir, err := ioutil.TempDir("", "cartridge-template")
+	if err != nil {
+		return "", fmt.Errorf("Failed to create directory for the template: %s", err)
+	}
+
+	cloneArgs := []string{"clone", "--depth", "1"}
+	if ref != "" {
+		cloneArgs = append(cloneArgs, "--branch", ref)
+	}
+	cloneArgs = append(cloneArgs, url, templateDir)
+
+	log.Infof("Clone template from %s", url)
+
+	cloneCmd := exec.Command("git", cloneArgs...)
+	if err := common.RunCommand(cloneCmd, "", showOutput); err != nil {
+		os.RemoveAll(templateDir)
+		return "", fmt.Errorf("Failed to clone template repository: %s", err)
+	}
+
+	// template repository .git directory isn't a part of the template
+	if err := os.RemoveAll(filepath.Join(templateDir, ".git")); err != nil {
+		os.RemoveAll(templateDir)
+		return "", fmt.Errorf("Failed to remove template .git directory: %s", err)
+	}
+
+	return templateDir, nil
+}
+
+// checkTemplateRef checks that the branch or tag exists in the template repository
+func checkTemplateRef(url string, ref string) error {
+	var stderrBuf bytes.Buffer
+
+	lsRemoteCmd := exec.Command("git", "ls-remote", "--exit-code", url, ref)
+	lsRemoteCmd.Stderr = &stderrBuf
+
+	err := lsRemoteCmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == refNotFoundExitCode {
+		return fmt.Errorf("Ref %q isn't found in template repository %s", ref, url)
+	} else if err != nil {
+		return fmt.Errorf(
+			"Failed to get template repository %s refs: %s. %s", url, err, strings.TrimSpace(stderrBuf.String()),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package create
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+)
+
+func TestIsGitTemplateURL(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	assert.True(isGitTemplateURL("git@github.com:org/template.git"))
+	assert.True(isGitTemplateURL("user@git.example.com:template"))
+	assert.True(isGitTemplateURL("https://github.com/org/template.git"))
+	assert.True(isGitTemplateURL("ssh://git@github.com/org/template.git"))
+	assert.True(isGitTemplateURL("file:///tmp/template"))
+
+	assert.False(isGitTemplateURL("template"))
+	assert.False(isGitTemplateURL("./template"))
+	assert.False(isGitTemplateURL("/tmp/template"))
+	assert.False(isGitTemplateURL("../templates/template.git"))
+}
+
+func TestCloneTemplate(t *testing.T) {
+	t.Parallel()
+
+	if !common.GitIsInstalled() {
+		t.Skip("git isn't installed")
+	}
+
+	assert := assert.New(t)
+
+	repoDir, err := ioutil.TempDir("", "template-repo")
+	assert.Nil(err)
+	defer os.RemoveAll(repoDir)
+
+	assert.Nil(ioutil.WriteFile(filepath.Join(repoDir, "init.lua"), []byte("-- {{ .Name }}"), 0644))
+
+	for _, args := range [][]string{
+		{"init"},
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-m", "Initial commit"},
+		{"tag", "v1.0.0"},
+	} {
+		gitCmd := exec.Command("git", args...)
+		gitCmd.Dir = repoDir
+		assert.Nil(gitCmd.Run(), args)
+	}
+
+	repoURL := "file://" + repoDir
+
+	// default branch
+	templateDir, err := cloneTemplate(repoURL, "", false)
+	assert.Nil(err)
+	defer os.RemoveAll(templateDir)
+
+	assert.FileExists(filepath.Join(templateDir, "init.lua"))
+	assert.NoDirExists(filepath.Join(templateDir, ".git"))
+
+	// tag
+	templateDir, err = cloneTemplate(repoURL, "v1.0.0", false)
+	assert.Nil(err)
+	defer os.RemoveAll(templateDir)
+
+	assert.FileExists(filepath.Join(templateDir, "init.lua"))
+
+	// non-existent ref
+	_, err = cloneTemplate(repoURL, "v2.0.0", false)
+	assert.EqualError(err, `Ref "v2.0.0" isn't found in template repository `+repoURL)
+}
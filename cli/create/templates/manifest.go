@@ -0,0 +1,89 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+)
+
+const (
+	// ManifestName is the name of the template manifest file
+	// that describes template variables. It isn't copied to the application
+	ManifestName = "template.yml"
+)
+
+var (
+	varNameRgx = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// Manifest describes the template variables.
+// Variables are available in the template files as {{ .Vars.<name> }}
+type Manifest struct {
+	Variables []ManifestVar `yaml:"variables"`
+}
+
+// ManifestVar is the template variable description.
+// Variable without default value is required
+type ManifestVar struct {
+	Name    string  `yaml:"name"`
+	Prompt  string  `yaml:"prompt"`
+	Default *string `yaml:"default"`
+}
+
+// ParseManifest parses the manifest from the template directory.
+// If the template doesn't contain the manifest, nil is returned
+func ParseManifest(from string) (*Manifest, error) {
+	manifestPath := filepath.Join(from, ManifestName)
+
+	if _, err := os.Stat(manifestPath); os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed to use template manifest: %s", err)
+	}
+
+	manifestContent, err := common.GetFileContentBytes(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read template manifest: %s", err)
+	}
+
+	var manifest Manifest
+	if err := yaml.UnmarshalStrict(manifestContent, &manifest); err != nil {
+		return nil, fmt.Errorf("Failed to parse template manifest %s: %s", ManifestName, err)
+	}
+
+	if err := checkManifest(&manifest); err != nil {
+		return nil, fmt.Errorf("Invalid template manifest %s: %s", ManifestName, err)
+	}
+
+	return &manifest, nil
+}
+
+func checkManifest(manifest *Manifest) error {
+	foundVars := make(map[string]struct{})
+
+	for i, variable := range manifest.Variables {
+		if variable.Name == "" {
+			return fmt.Errorf("variables[%d]: name is missed", i)
+		}
+
+		if !varNameRgx.MatchString(variable.Name) {
+			return fmt.Errorf(
+				"variables[%d]: name %q should contain only letters, digits and underscores "+
+					"and shouldn't start with a digit", i, variable.Name,
+			)
+		}
+
+		if _, found := foundVars[variable.Name]; found {
+			return fmt.Errorf("variables[%d]: duplicate variable %q", i, variable.Name)
+		}
+
+		foundVars[variable.Name] = struct{}{}
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/apex/log"
@@ -28,22 +29,70 @@ var (
 // Instantiate creates a file tree in a ctx.Project.Path according to ctx.Project.Template
 // It applies ctx.Project to the template
 func Instantiate(ctx *context.Ctx) error {
+	projectTmpl, _, err := getProjectTemplate(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := projectTmpl.Instantiate(ctx.Project.Path, ctx.Project); err != nil {
+		return fmt.Errorf("Failed to instantiate project template: %s", err)
+	}
+
+	return nil
+}
+
+// Preview renders the project template in memory without writing anything on disk.
+// It returns the entries that would be created (paths are relative to ctx.Project.Path)
+// and the template paths that would be skipped
+func Preview(ctx *context.Ctx) ([]templates.RenderedEntry, []string, error) {
+	projectTmpl, skipped, err := getProjectTemplate(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	renderedEntries, err := projectTmpl.Render(ctx.Project)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to render project template: %s", err)
+	}
+
+	var entries []templates.RenderedEntry
+	for _, entry := range renderedEntries {
+		entry.Path = strings.TrimPrefix(entry.Path, "/")
+		if entry.Path == "" || entry.Path == "." {
+			// project root
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+
+	return entries, skipped, nil
+}
+
+// getProjectTemplate returns the template specified in context
+// and the template paths that are skipped
+func getProjectTemplate(ctx *context.Ctx) (*templates.FileTreeTemplate, []string, error) {
 	var err error
 	var projectTmpl *templates.FileTreeTemplate
+	var skipped []string
 
 	if ctx.Create.From != "" {
 		log.Debugf("Template from %s is used", ctx.Create.From)
 
 		if fileInfo, err := os.Stat(ctx.Create.From); err != nil {
-			return fmt.Errorf("Failed to use specified path: %s", err)
+			return nil, nil, fmt.Errorf("Failed to use specified path: %s", err)
 		} else if !fileInfo.IsDir() {
-			return fmt.Errorf("Specified path is not a directory: %s", ctx.Create.From)
+			return nil, nil, fmt.Errorf("Specified path is not a directory: %s", ctx.Create.From)
 		}
 
 		// check specified template
 		rocksPath := filepath.Join(ctx.Create.From, ".rocks")
 		if _, err := os.Stat(rocksPath); !os.IsNotExist(err) {
-			return fmt.Errorf(
+			return nil, nil, fmt.Errorf(
 				"Project template shouldn't contain .rocks directory. " +
 					"To specify dependencies use rockspec and cartridge.pre-build hook",
 			)
@@ -57,23 +106,19 @@ func Instantiate(ctx *context.Ctx) error {
 			)
 		}
 
-		projectTmpl, err = parseTemplate(ctx.Create.From)
+		projectTmpl, skipped, err = parseTemplate(ctx.Create.From)
 		if err != nil {
-			return fmt.Errorf("Failed to parse template from specified path: %w", err)
+			return nil, nil, fmt.Errorf("Failed to parse template from specified path: %w", err)
 		}
 	} else {
 		projectTmpl, err = parseStaticTemplate(ctx.Create.TemplateFS)
 
 		if err != nil {
-			return fmt.Errorf("Failed to parse template: %w", err)
+			return nil, nil, fmt.Errorf("Failed to parse template: %w", err)
 		}
 	}
 
-	if err := projectTmpl.Instantiate(ctx.Project.Path, ctx.Project); err != nil {
-		return fmt.Errorf("Failed to instantiate project template: %s", err)
-	}
-
-	return nil
+	return projectTmpl, skipped, nil
 }
 
 func parseStaticTemplate(fs http.FileSystem) (*templates.FileTreeTemplate, error) {
@@ -112,8 +157,11 @@ func parseStaticTemplate(fs http.FileSystem) (*templates.FileTreeTemplate, error
 	return &tmpl, nil
 }
 
-func parseTemplate(from string) (*templates.FileTreeTemplate, error) {
+// parseTemplate parses the template from the specified directory.
+// It returns the template and the paths that are skipped
+func parseTemplate(from string) (*templates.FileTreeTemplate, []string, error) {
 	var tmpl templates.FileTreeTemplate
+	var skipped []string
 
 	err := filepath.Walk(from, func(filePath string, fileInfo os.FileInfo, err error) error {
 		if err != nil {
@@ -126,7 +174,18 @@ func parseTemplate(from string) (*templates.FileTreeTemplate, error) {
 		}
 
 		// skip .git folder
-		if relPath == "git" || strings.HasPrefix(relPath, ".git/") {
+		if relPath == ".git" {
+			skipped = append(skipped, relPath)
+
+			if fileInfo.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		// template manifest isn't a part of the application
+		if relPath == ManifestName && !fileInfo.IsDir() {
+			skipped = append(skipped, relPath)
 			return nil
 		}
 
@@ -152,10 +211,10 @@ func parseTemplate(from string) (*templates.FileTreeTemplate, error) {
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("Failed to parse template: %s", err)
+		return nil, nil, fmt.Errorf("Failed to parse template: %s", err)
 	}
 
-	return &tmpl, nil
+	return &tmpl, skipped, nil
 }
 
 // getStaticFileContent open file in generated static filesystem
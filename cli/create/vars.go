@@ -0,0 +1,122 @@
+package create
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+	"github.com/tarantool/cartridge-cli/cli/context"
+	"github.com/tarantool/cartridge-cli/cli/create/templates"
+)
+
+// fillTemplateVars sets ctx.Project.Vars according to the template manifest.
+// Values specified via --set are used as is, other variables are prompted
+// if stdout is a terminal. Otherwise, default values are used
+func fillTemplateVars(ctx *context.Ctx) error {
+	setVars, err := parseSetVars(ctx.Create.SetVars)
+	if err != nil {
+		return err
+	}
+
+	var manifest *templates.Manifest
+	if ctx.Create.From != "" {
+		if manifest, err = templates.ParseManifest(ctx.Create.From); err != nil {
+			return err
+		}
+	}
+
+	if manifest == nil {
+		if len(setVars) > 0 {
+			return fmt.Errorf("Template doesn't describe variables in %s, --set can't be used", templates.ManifestName)
+		}
+
+		return nil
+	}
+
+	interactive := isatty.IsTerminal(os.Stdout.Fd())
+
+	vars, err := collectTemplateVars(manifest, setVars, interactive)
+	if err != nil {
+		return err
+	}
+
+	ctx.Project.Vars = vars
+
+	return nil
+}
+
+func collectTemplateVars(manifest *templates.Manifest, setVars map[string]string, interactive bool) (map[string]string, error) {
+	vars := make(map[string]string)
+
+	for _, variable := range manifest.Variables {
+		if value, found := setVars[variable.Name]; found {
+			vars[variable.Name] = value
+			continue
+		}
+
+		defaultValue := ""
+		if variable.Default != nil {
+			defaultValue = *variable.Default
+		}
+
+		value := defaultValue
+		if interactive {
+			promptText := variable.Prompt
+			if promptText == "" {
+				promptText = fmt.Sprintf("Enter %s", variable.Name)
+			}
+
+			value = common.Prompt(promptText, defaultValue)
+		}
+
+		if value == "" && variable.Default == nil {
+			return nil, fmt.Errorf(
+				"Template variable %s is required. Please, specify it via --set %s=VALUE",
+				variable.Name, variable.Name,
+			)
+		}
+
+		vars[variable.Name] = value
+	}
+
+	// check that all specified variables are described in the manifest
+	var unknownVars []string
+	for name := range setVars {
+		if _, found := vars[name]; !found {
+			unknownVars = append(unknownVars, name)
+		}
+	}
+
+	if len(unknownVars) > 0 {
+		sort.Strings(unknownVars)
+		return nil, fmt.Errorf(
+			"Unknown template variable(s) specified via --set: %s", strings.Join(unknownVars, ", "),
+		)
+	}
+
+	return vars, nil
+}
+
+// parseSetVars parses values passed via --set KEY=VALUE
+func parseSetVars(setVarsStrs []string) (map[string]string, error) {
+	setVars := make(map[string]string)
+
+	for _, setVarStr := range setVarsStrs {
+		parts := strings.SplitN(setVarStr, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("Invalid --set value %q: should be in KEY=VALUE format", setVarStr)
+		}
+
+		if _, found := setVars[parts[0]]; found {
+			return nil, fmt.Errorf("Template variable %s is specified via --set twice", parts[0])
+		}
+
+		setVars[parts[0]] = parts[1]
+	}
+
+	return setVars, nil
+}
@@ -0,0 +1,113 @@
+package create
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+	"github.com/tarantool/cartridge-cli/cli/create/templates"
+)
+
+const (
+	testManifestContent = `variables:
+  - name: author
+    prompt: Author name
+  - name: license
+    default: MIT
+  - name: cookie
+    default: ""
+`
+)
+
+func TestParseSetVars(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	setVars, err := parseSetVars(nil)
+	assert.Nil(err)
+	assert.Len(setVars, 0)
+
+	setVars, err = parseSetVars([]string{"author=John Doe", "cookie=a=b", "license="})
+	assert.Nil(err)
+	assert.Equal(map[string]string{
+		"author":  "John Doe",
+		"cookie":  "a=b",
+		"license": "",
+	}, setVars)
+
+	_, err = parseSetVars([]string{"author"})
+	assert.EqualError(err, `Invalid --set value "author": should be in KEY=VALUE format`)
+
+	_, err = parseSetVars([]string{"=value"})
+	assert.EqualError(err, `Invalid --set value "=value": should be in KEY=VALUE format`)
+
+	_, err = parseSetVars([]string{"author=John", "author=Jane"})
+	assert.EqualError(err, "Template variable author is specified via --set twice")
+}
+
+func TestFillTemplateVars(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	templateDir, err := ioutil.TempDir("", "template")
+	assert.Nil(err)
+	defer os.RemoveAll(templateDir)
+
+	ctx := &context.Ctx{}
+	ctx.Create.From = templateDir
+
+	// no manifest
+	assert.Nil(fillTemplateVars(ctx))
+	assert.Nil(ctx.Project.Vars)
+
+	ctx.Create.SetVars = []string{"author=John"}
+	assert.EqualError(fillTemplateVars(ctx), "Template doesn't describe variables in template.yml, --set can't be used")
+
+	manifestPath := filepath.Join(templateDir, templates.ManifestName)
+	assert.Nil(ioutil.WriteFile(manifestPath, []byte(testManifestContent), 0644))
+
+	// required variable is missed
+	ctx.Create.SetVars = nil
+	assert.EqualError(
+		fillTemplateVars(ctx),
+		"Template variable author is required. Please, specify it via --set author=VALUE",
+	)
+
+	// default values
+	ctx.Create.SetVars = []string{"author=John Doe"}
+	assert.Nil(fillTemplateVars(ctx))
+	assert.Equal(map[string]string{
+		"author":  "John Doe",
+		"license": "MIT",
+		"cookie":  "",
+	}, ctx.Project.Vars)
+
+	// specified values
+	ctx.Create.SetVars = []string{"author=John Doe", "license=BSD-2-Clause", "cookie=secret"}
+	assert.Nil(fillTemplateVars(ctx))
+	assert.Equal(map[string]string{
+		"author":  "John Doe",
+		"license": "BSD-2-Clause",
+		"cookie":  "secret",
+	}, ctx.Project.Vars)
+
+	// unknown variables
+	ctx.Create.SetVars = []string{"author=John Doe", "year=2020", "email=john@example.com"}
+	assert.EqualError(fillTemplateVars(ctx), "Unknown template variable(s) specified via --set: email, year")
+
+	// invalid manifest
+	assert.Nil(ioutil.WriteFile(manifestPath, []byte("variables:\n  - name: my-var\n"), 0644))
+	ctx.Create.SetVars = nil
+	assert.EqualError(fillTemplateVars(ctx), `Invalid template manifest template.yml: variables[0]: `+
+		`name "my-var" should contain only letters, digits and underscores and shouldn't start with a digit`)
+
+	assert.Nil(ioutil.WriteFile(manifestPath, []byte("variables:\n  - name: author\n  - name: author\n"), 0644))
+	assert.EqualError(fillTemplateVars(ctx), `Invalid template manifest template.yml: variables[1]: `+
+		`duplicate variable "author"`)
+}
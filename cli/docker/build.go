@@ -26,6 +26,16 @@ type BuildOpts struct {
 	CacheFrom  []string
 	NoCache    bool
 
+	// BuildArgs are passed to the build as is,
+	// Secrets are passed to BuildKit (see CheckSecret for the format)
+	BuildArgs map[string]string
+	Secrets   []string
+
+	// Platform is set for the build with buildx,
+	// the image is pushed if Push is set, otherwise it's loaded
+	Platform string
+	Push     bool
+
 	BuildDir string
 	TmpDir   string
 
@@ -134,6 +144,14 @@ func waitBuildOutput(resp types.ImageBuildResponse, showOutput bool) error {
 func BuildImage(opts BuildOpts) error {
 	var err error
 
+	if opts.Platform != "" {
+		return buildImageWithBuildx(opts)
+	}
+
+	if len(opts.Secrets) > 0 {
+		return buildImageWithBuildKit(opts)
+	}
+
 	cli, err := client.NewEnvClient()
 	if err != nil {
 		return err
@@ -156,11 +174,18 @@ func BuildImage(opts BuildOpts) error {
 		return fmt.Errorf("Failed to compress build context: %s", err)
 	}
 
+	buildArgs := make(map[string]*string, len(opts.BuildArgs))
+	for name, value := range opts.BuildArgs {
+		value := value
+		buildArgs[name] = &value
+	}
+
 	resp, err := cli.ImageBuild(ctx, tarReader, types.ImageBuildOptions{
 		Tags:       opts.Tag,
 		Dockerfile: opts.Dockerfile,
 		NoCache:    opts.NoCache,
 		CacheFrom:  opts.CacheFrom,
+		BuildArgs:  buildArgs,
 		Remove:     true,
 	})
 
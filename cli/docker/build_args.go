@@ -0,0 +1,155 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	goVersion "github.com/hashicorp/go-version"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+)
+
+var (
+	buildArgNameRgx = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	dockerServerBuildKitMinVersion = goVersion.Must(goVersion.NewSemver("18.09"))
+)
+
+// ParseBuildArg parses KEY=VALUE build argument.
+// The argument is split on the first `=`, so the value can contain `=`
+func ParseBuildArg(buildArg string) (string, string, error) {
+	parts := strings.SplitN(buildArg, "=", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("Build argument should be KEY=VALUE")
+	}
+
+	name := parts[0]
+	if !buildArgNameRgx.MatchString(name) {
+		return "", "", fmt.Errorf("Invalid build argument name %q", name)
+	}
+
+	return name, parts[1], nil
+}
+
+// CheckSecret checks the BuildKit secret specification,
+// e.g. id=token,src=token.txt or id=token,env=TOKEN
+func CheckSecret(secret string) error {
+	idIsSet := false
+
+	for _, field := range strings.Split(secret, ",") {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return fmt.Errorf("Secret field %q should be KEY=VALUE", field)
+		}
+
+		switch parts[0] {
+		case "id":
+			idIsSet = true
+		case "type", "src", "source", "env":
+		default:
+			return fmt.Errorf("Unknown secret field %q", parts[0])
+		}
+	}
+
+	if !idIsSet {
+		return fmt.Errorf("Secret id should be specified")
+	}
+
+	return nil
+}
+
+// CheckBuildKit checks that the docker server supports BuildKit.
+// BuildKit is required to pass secrets, since Docker Engine API
+// build doesn't allow to mount secrets and build arguments are
+// stored in the image history
+func CheckBuildKit() error {
+	if err := common.CheckRequiredBinaries("docker"); err != nil {
+		return err
+	}
+
+	serverVersionStr, err := getServerVersion()
+	if err != nil {
+		return fmt.Errorf("Failed to check docker server version: %s", err)
+	}
+
+	serverVersion, err := goVersion.NewSemver(serverVersionStr)
+	if err != nil {
+		return fmt.Errorf("Failed to parse docker server version: %s", err)
+	}
+
+	if serverVersion.LessThan(dockerServerBuildKitMinVersion) {
+		return fmt.Errorf(
+			"Secrets require BuildKit, but it isn't supported by docker %s. Minimal required docker version is %s",
+			serverVersion, dockerServerBuildKitMinVersion,
+		)
+	}
+
+	return nil
+}
+
+// getBuildArgsAndSecretsArgs returns docker CLI build arguments and secrets flags.
+// Build arguments are sorted by names to make the command stable
+func getBuildArgsAndSecretsArgs(opts BuildOpts) []string {
+	var args []string
+
+	buildArgNames := make([]string, 0, len(opts.BuildArgs))
+	for name := range opts.BuildArgs {
+		buildArgNames = append(buildArgNames, name)
+	}
+	sort.Strings(buildArgNames)
+
+	for _, name := range buildArgNames {
+		args = append(args, "--build-arg", fmt.Sprintf("%s=%s", name, opts.BuildArgs[name]))
+	}
+
+	for _, secret := range opts.Secrets {
+		args = append(args, "--secret", secret)
+	}
+
+	return args
+}
+
+// getBuildKitArgs returns `docker build` arguments
+// used to build the image with BuildKit
+func getBuildKitArgs(opts BuildOpts) []string {
+	args := []string{
+		"build",
+		"--file", filepath.Join(opts.BuildDir, opts.Dockerfile),
+	}
+
+	for _, tag := range opts.Tag {
+		args = append(args, "--tag", tag)
+	}
+
+	if opts.NoCache {
+		args = append(args, "--no-cache")
+	}
+
+	for _, cacheFrom := range opts.CacheFrom {
+		args = append(args, "--cache-from", cacheFrom)
+	}
+
+	args = append(args, getBuildArgsAndSecretsArgs(opts)...)
+
+	return append(args, opts.BuildDir)
+}
+
+func buildImageWithBuildKit(opts BuildOpts) error {
+	if err := CheckBuildKit(); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("docker", getBuildKitArgs(opts)...)
+	cmd.Env = append(os.Environ(), "DOCKER_BUILDKIT=1")
+
+	if err := common.RunCommand(cmd, opts.BuildDir, opts.ShowOutput); err != nil {
+		return err
+	}
+
+	return nil
+}
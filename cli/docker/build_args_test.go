@@ -0,0 +1,95 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBuildArg(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	name, value, err := ParseBuildArg("HTTP_PROXY=http://proxy:3128")
+	assert.Nil(err)
+	assert.Equal("HTTP_PROXY", name)
+	assert.Equal("http://proxy:3128", value)
+
+	// value contains =
+	name, value, err = ParseBuildArg("ROCKS_TOKEN=a=b==")
+	assert.Nil(err)
+	assert.Equal("ROCKS_TOKEN", name)
+	assert.Equal("a=b==", value)
+
+	// empty value
+	name, value, err = ParseBuildArg("EMPTY=")
+	assert.Nil(err)
+	assert.Equal("EMPTY", name)
+	assert.Equal("", value)
+
+	_, _, err = ParseBuildArg("HTTP_PROXY")
+	assert.EqualError(err, "Build argument should be KEY=VALUE")
+
+	_, _, err = ParseBuildArg("=value")
+	assert.EqualError(err, `Invalid build argument name ""`)
+
+	_, _, err = ParseBuildArg("1ARG=value")
+	assert.EqualError(err, `Invalid build argument name "1ARG"`)
+}
+
+func TestCheckSecret(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	assert.Nil(CheckSecret("id=token,src=token.txt"))
+	assert.Nil(CheckSecret("id=token,env=ROCKS_TOKEN"))
+	assert.Nil(CheckSecret("type=file,id=token,source=/run/token"))
+
+	assert.EqualError(CheckSecret("src=token.txt"), "Secret id should be specified")
+	assert.EqualError(CheckSecret("id=token,src"), `Secret field "src" should be KEY=VALUE`)
+	assert.EqualError(CheckSecret("id="), `Secret field "id=" should be KEY=VALUE`)
+	assert.EqualError(CheckSecret("id=token,path=token.txt"), `Unknown secret field "path"`)
+}
+
+func TestGetBuildKitArgs(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	opts := BuildOpts{
+		Tag:        []string{"myapp:1.0.0"},
+		Dockerfile: "Dockerfile.abc",
+		BuildDir:   "/tmp/build",
+		BuildArgs: map[string]string{
+			"ROCKS_SERVER": "https://rocks.local",
+			"HTTP_PROXY":   "http://proxy:3128",
+		},
+		Secrets: []string{"id=token,src=token.txt"},
+	}
+
+	assert.Equal([]string{
+		"build",
+		"--file", "/tmp/build/Dockerfile.abc",
+		"--tag", "myapp:1.0.0",
+		"--build-arg", "HTTP_PROXY=http://proxy:3128",
+		"--build-arg", "ROCKS_SERVER=https://rocks.local",
+		"--secret", "id=token,src=token.txt",
+		"/tmp/build",
+	}, getBuildKitArgs(opts))
+
+	opts.NoCache = true
+	opts.CacheFrom = []string{"myapp:cache"}
+	opts.BuildArgs = nil
+
+	assert.Equal([]string{
+		"build",
+		"--file", "/tmp/build/Dockerfile.abc",
+		"--tag", "myapp:1.0.0",
+		"--no-cache",
+		"--cache-from", "myapp:cache",
+		"--secret", "id=token,src=token.txt",
+		"/tmp/build",
+	}, getBuildKitArgs(opts))
+}
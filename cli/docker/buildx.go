@@ -0,0 +1,112 @@
+package docker
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+)
+
+// CheckBuildx checks that docker CLI supports buildx.
+// Docker Engine API doesn't allow to build multi-platform images,
+// so buildx plugin is required
+func CheckBuildx() error {
+	if err := common.CheckRequiredBinaries("docker"); err != nil {
+		return err
+	}
+
+	output, err := exec.Command("docker", "buildx", "version").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf(
+			"Multi-platform build requires docker buildx, but it isn't supported by local docker (%s): %s. "+
+				"See https://docs.docker.com/buildx/working-with-buildx/",
+			err, strings.TrimSpace(string(output)),
+		)
+	}
+
+	return nil
+}
+
+// getBuildxArgs returns `docker buildx build` arguments.
+// Image built for one platform is pushed or loaded to the local docker
+func getBuildxArgs(opts BuildOpts) []string {
+	args := []string{
+		"buildx", "build",
+		"--platform", opts.Platform,
+		"--file", filepath.Join(opts.BuildDir, opts.Dockerfile),
+	}
+
+	for _, tag := range opts.Tag {
+		args = append(args, "--tag", tag)
+	}
+
+	if opts.NoCache {
+		args = append(args, "--no-cache")
+	}
+
+	for _, cacheFrom := range opts.CacheFrom {
+		args = append(args, "--cache-from", cacheFrom)
+	}
+
+	args = append(args, getBuildArgsAndSecretsArgs(withTargetPlatformArg(opts))...)
+
+	if opts.Push {
+		args = append(args, "--push")
+	} else {
+		args = append(args, "--load")
+	}
+
+	return append(args, opts.BuildDir)
+}
+
+// withTargetPlatformArg returns opts with the TARGETPLATFORM build argument
+// set to the build platform, so base Dockerfiles can use
+// `FROM --platform=$TARGETPLATFORM` to pick the right base image.
+// The value specified by user isn't overridden
+func withTargetPlatformArg(opts BuildOpts) BuildOpts {
+	if _, found := opts.BuildArgs["TARGETPLATFORM"]; found {
+		return opts
+	}
+
+	buildArgs := make(map[string]string, len(opts.BuildArgs)+1)
+	for name, value := range opts.BuildArgs {
+		buildArgs[name] = value
+	}
+	buildArgs["TARGETPLATFORM"] = opts.Platform
+
+	opts.BuildArgs = buildArgs
+	return opts
+}
+
+func buildImageWithBuildx(opts BuildOpts) error {
+	cmd := exec.Command("docker", getBuildxArgs(opts)...)
+	if err := common.RunCommand(cmd, opts.BuildDir, opts.ShowOutput); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getManifestListArgs returns `docker buildx imagetools create` arguments
+func getManifestListArgs(tags []string, srcTags []string) []string {
+	args := []string{"buildx", "imagetools", "create"}
+
+	for _, tag := range tags {
+		args = append(args, "--tag", tag)
+	}
+
+	return append(args, srcTags...)
+}
+
+// CreateManifestList pushes manifest list that combines specified
+// pushed images under the specified tags
+func CreateManifestList(tags []string, srcTags []string, showOutput bool) error {
+	cmd := exec.Command("docker", getManifestListArgs(tags, srcTags)...)
+	if err := common.RunCommand(cmd, "", showOutput); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBuildxArgs(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	opts := BuildOpts{
+		Tag:        []string{"myapp-build"},
+		Dockerfile: "Dockerfile.build.abc",
+		Platform:   "linux/arm64",
+		BuildDir:   "/tmp/build",
+	}
+
+	assert.Equal([]string{
+		"buildx", "build",
+		"--platform", "linux/arm64",
+		"--file", "/tmp/build/Dockerfile.build.abc",
+		"--tag", "myapp-build",
+		"--build-arg", "TARGETPLATFORM=linux/arm64",
+		"--load",
+		"/tmp/build",
+	}, getBuildxArgs(opts))
+
+	opts.Tag = []string{"registry/myapp:1.0.0-linux-arm64"}
+	opts.NoCache = true
+	opts.CacheFrom = []string{"registry/myapp:cache"}
+	opts.BuildArgs = map[string]string{"HTTP_PROXY": "http://proxy:3128"}
+	opts.Secrets = []string{"id=token,env=ROCKS_TOKEN"}
+	opts.Push = true
+
+	assert.Equal([]string{
+		"buildx", "build",
+		"--platform", "linux/arm64",
+		"--file", "/tmp/build/Dockerfile.build.abc",
+		"--tag", "registry/myapp:1.0.0-linux-arm64",
+		"--no-cache",
+		"--cache-from", "registry/myapp:cache",
+		"--build-arg", "HTTP_PROXY=http://proxy:3128",
+		"--build-arg", "TARGETPLATFORM=linux/arm64",
+		"--secret", "id=token,env=ROCKS_TOKEN",
+		"--push",
+		"/tmp/build",
+	}, getBuildxArgs(opts))
+
+	// specified TARGETPLATFORM isn't overridden
+	opts.BuildArgs = map[string]string{"TARGETPLATFORM": "linux/arm/v7"}
+	opts.Secrets = nil
+
+	assert.Equal([]string{
+		"buildx", "build",
+		"--platform", "linux/arm64",
+		"--file", "/tmp/build/Dockerfile.build.abc",
+		"--tag", "registry/myapp:1.0.0-linux-arm64",
+		"--no-cache",
+		"--cache-from", "registry/myapp:cache",
+		"--build-arg", "TARGETPLATFORM=linux/arm/v7",
+		"--push",
+		"/tmp/build",
+	}, getBuildxArgs(opts))
+	assert.Equal(map[string]string{"TARGETPLATFORM": "linux/arm/v7"}, opts.BuildArgs)
+}
+
+func TestGetManifestListArgs(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	assert.Equal([]string{
+		"buildx", "imagetools", "create",
+		"--tag", "registry/myapp:1.0.0",
+		"--tag", "registry/myapp:latest",
+		"registry/myapp:1.0.0-linux-amd64",
+		"registry/myapp:1.0.0-linux-arm64",
+	}, getManifestListArgs(
+		[]string{"registry/myapp:1.0.0", "registry/myapp:latest"},
+		[]string{"registry/myapp:1.0.0-linux-amd64", "registry/myapp:1.0.0-linux-arm64"},
+	))
+}
@@ -0,0 +1,61 @@
+package docker
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+
+	"github.com/apex/log"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+)
+
+var (
+	pushedDigestRgx = regexp.MustCompile(`digest: (sha256:[0-9a-f]{64})`)
+)
+
+// PushImage pushes the image to the registry specified in the tag.
+// Docker CLI is used, so the ambient credentials (docker login,
+// credential helpers) are applied. It returns the pushed image digest.
+// If the digest can't be found in the output, the image is pushed anyway,
+// so a warning is shown and an empty digest is returned
+func PushImage(tag string, showOutput bool) (string, error) {
+	if err := common.CheckRequiredBinaries("docker"); err != nil {
+		return "", err
+	}
+
+	var output string
+
+	err := common.RunFunctionWithSpinner(func() error {
+		var err error
+		output, err = common.GetOutput(exec.Command("docker", "push", tag), nil)
+		return err
+	}, "Pushing image...")
+
+	if err != nil {
+		return "", err
+	}
+
+	if showOutput {
+		fmt.Print(output)
+	}
+
+	digest, err := getPushedDigest(output)
+	if err != nil {
+		log.Warnf("Image %s is pushed, but its digest is unknown: %s", tag, err)
+		return "", nil
+	}
+
+	return digest, nil
+}
+
+// getPushedDigest gets the digest from `docker push` output,
+// e.g. 1.0.0: digest: sha256:<hash> size: 1234
+func getPushedDigest(pushOutput string) (string, error) {
+	matches := pushedDigestRgx.FindAllStringSubmatch(pushOutput, -1)
+	if len(matches) == 0 {
+		return "", fmt.Errorf("Failed to get pushed image digest from docker push output")
+	}
+
+	return matches[len(matches)-1][1], nil
+}
@@ -0,0 +1,34 @@
+package docker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPushedDigest(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	digest := "sha256:" + strings.Repeat("0123456789abcdef", 4)
+
+	pushedDigest, err := getPushedDigest(`The push refers to repository [registry.local/myapp]
+5f70bf18a086: Pushed
+1.2.3-4: digest: ` + digest + ` size: 1570
+`)
+	assert.Nil(err)
+	assert.Equal(digest, pushedDigest)
+
+	// layer already exists
+	pushedDigest, err = getPushedDigest(`The push refers to repository [registry.local/myapp]
+5f70bf18a086: Layer already exists
+latest: digest: ` + digest + ` size: 1570
+`)
+	assert.Nil(err)
+	assert.Equal(digest, pushedDigest)
+
+	_, err = getPushedDigest("The push refers to repository [registry.local/myapp]\n")
+	assert.EqualError(err, "Failed to get pushed image digest from docker push output")
+}
@@ -0,0 +1,46 @@
+package docker
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var (
+	// imageReferenceRgx is the image reference grammar,
+	// see https://github.com/distribution/distribution/blob/main/reference/reference.go
+	imageReferenceRgx = regexp.MustCompile(
+		// [domain[:port]/]
+		`^(?:(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])` +
+			`(?:\.(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9]))*(?::[0-9]+)?/)?` +
+			// name components separated by /
+			`[a-z0-9]+(?:(?:[._]|__|[-]*)[a-z0-9]+)*` +
+			`(?:/[a-z0-9]+(?:(?:[._]|__|[-]*)[a-z0-9]+)*)*` +
+			// [:tag]
+			`(?::[\w][\w.-]{0,127})?` +
+			// [@digest]
+			`(?:@[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,})?$`,
+	)
+)
+
+const (
+	imageReferenceMaxNameLen = 255
+)
+
+// CheckImageReference checks that the image reference is well-formed,
+// e.g. centos:7, registry.local:5000/mirror/centos:7 or centos@sha256:<digest>
+func CheckImageReference(reference string) error {
+	if reference == "" {
+		return fmt.Errorf("Image reference should be non-empty")
+	}
+
+	if !imageReferenceRgx.MatchString(reference) {
+		return fmt.Errorf("Invalid image reference %q", reference)
+	}
+
+	if len(reference) > imageReferenceMaxNameLen {
+		return fmt.Errorf("Invalid image reference %q: should be no longer than %d characters",
+			reference, imageReferenceMaxNameLen)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package docker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckImageReference(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	digest := "sha256:" + strings.Repeat("a", 64)
+
+	for _, reference := range []string{
+		"centos",
+		"centos:7",
+		"library/centos:7",
+		"registry.local/mirror/centos:7.9.2009",
+		"registry.local:5000/mirror/centos",
+		"localhost:5000/centos:latest",
+		"my-registry.example.com/team/base_image:v1.0-scanned",
+		"centos@" + digest,
+		"registry.local/centos:7@" + digest,
+	} {
+		assert.Nil(CheckImageReference(reference), reference)
+	}
+
+	assert.EqualError(CheckImageReference(""), "Image reference should be non-empty")
+
+	for _, reference := range []string{
+		"CentOS:7",
+		"centos:",
+		"centos:7:8",
+		":7",
+		"registry.local/",
+		"centos 7",
+		"-centos",
+		"centos:-7",
+		"centos@sha256:abc",
+		"https://registry.local/centos",
+	} {
+		assert.EqualError(CheckImageReference(reference), `Invalid image reference "`+reference+`"`, reference)
+	}
+
+	longReference := "registry.local/" + strings.Repeat("a", 250)
+	assert.EqualError(CheckImageReference(longReference),
+		`Invalid image reference "`+longReference+`": should be no longer than 255 characters`)
+}
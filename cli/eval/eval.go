@@ -0,0 +1,121 @@
+package eval
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/apex/log"
+	"gopkg.in/yaml.v2"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+	"github.com/tarantool/cartridge-cli/cli/context"
+	"github.com/tarantool/cartridge-cli/cli/project"
+	"github.com/tarantool/cartridge-cli/cli/running"
+)
+
+const (
+	defaultEvalTimeout = 10 * time.Second
+)
+
+type evalInstanceFunc func(instanceName string) (interface{}, error)
+
+// Run evaluates the specified function body on the instances
+// and prints the results
+func Run(ctx *context.Ctx) error {
+	var err error
+
+	if ctx.Eval.ConnectAll {
+		if len(ctx.Running.Instances) > 0 {
+			log.Warnf("Specified instances are ignored due to --connect-all flag")
+		}
+
+		ctx.Running.Instances, err = running.CollectInstancesFromConf(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed to get configured instances from conf: %s", err)
+		}
+	}
+
+	if len(ctx.Running.Instances) == 0 {
+		return fmt.Errorf("Please, specify instance name(s) or use --connect-all flag")
+	}
+
+	if ctx.Eval.Quorum > len(ctx.Running.Instances) {
+		return fmt.Errorf(
+			"Quorum %d can't be reached: only %d instance(s) are specified",
+			ctx.Eval.Quorum, len(ctx.Running.Instances),
+		)
+	}
+
+	var evalFunc evalInstanceFunc = func(instanceName string) (interface{}, error) {
+		return evalOnInstance(ctx, instanceName)
+	}
+
+	if ctx.Eval.Retry > 0 {
+		opts, err := getRetryOpts(ctx)
+		if err != nil {
+			return err
+		}
+
+		evalFunc = withRetry(evalFunc, opts)
+	}
+
+	results := collectResults(ctx.Running.Instances, evalFunc)
+
+	summary := getSummary(results)
+
+	fmt.Println(summary.String())
+
+	return summary.check(ctx.Eval.Quorum)
+}
+
+func evalOnInstance(ctx *context.Ctx, instanceName string) (interface{}, error) {
+	consoleSock := project.GetInstanceConsoleSock(ctx, instanceName)
+
+	if _, err := os.Stat(consoleSock); err != nil {
+		return nil, fmt.Errorf("Failed to use console socket %s: %s", consoleSock, err)
+	}
+
+	conn, err := common.ConnectToTarantoolSocket(consoleSock)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to %s: %s", consoleSock, err)
+	}
+	defer conn.Close()
+
+	timeout := ctx.Eval.Timeout
+	if timeout == 0 {
+		timeout = defaultEvalTimeout
+	}
+
+	return common.EvalTarantoolConn(conn, ctx.Eval.FunctionBody, common.ConnOpts{
+		ReadTimeout: timeout,
+	})
+}
+
+func collectResults(instanceNames []string, evalFunc evalInstanceFunc) []instanceResult {
+	results := make([]instanceResult, len(instanceNames))
+
+	for i, instanceName := range instanceNames {
+		results[i].InstanceName = instanceName
+
+		data, err := evalFunc(instanceName)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		results[i].Value, results[i].Err = formatValue(data)
+	}
+
+	return results
+}
+
+func formatValue(data interface{}) (string, error) {
+	valueBytes, err := yaml.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("Failed to format returned value: %s", err)
+	}
+
+	return strings.TrimSpace(string(valueBytes)), nil
+}
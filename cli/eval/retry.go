@@ -0,0 +1,89 @@
+package eval
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/apex/log"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+var (
+	// defaultTransientErrorPatterns are used if --retry-on isn't specified
+	defaultTransientErrorPatterns = []string{
+		`(?i)connection refused`,
+		`(?i)connection reset`,
+		`(?i)broken pipe`,
+		`(?i)timeout|timed out`,
+	}
+)
+
+// retryOpts describes how evaluation is retried on transient errors
+type retryOpts struct {
+	Attempts int
+	Delay    time.Duration
+
+	TransientErrorRgxs []*regexp.Regexp
+}
+
+func getRetryOpts(ctx *context.Ctx) (*retryOpts, error) {
+	opts := retryOpts{
+		Attempts: ctx.Eval.Retry,
+		Delay:    ctx.Eval.RetryDelay,
+	}
+
+	patterns := ctx.Eval.RetryOn
+	if len(patterns) == 0 {
+		patterns = defaultTransientErrorPatterns
+	}
+
+	for _, pattern := range patterns {
+		rgx, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid transient error pattern %q: %s", pattern, err)
+		}
+
+		opts.TransientErrorRgxs = append(opts.TransientErrorRgxs, rgx)
+	}
+
+	return &opts, nil
+}
+
+func (opts *retryOpts) isTransient(err error) bool {
+	for _, rgx := range opts.TransientErrorRgxs {
+		if rgx.MatchString(err.Error()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withRetry returns evalInstanceFunc that calls evalFunc again
+// if it failed with the transient error.
+// Evaluation is performed at most opts.Attempts times (including the first attempt)
+func withRetry(evalFunc evalInstanceFunc, opts *retryOpts) evalInstanceFunc {
+	return func(instanceName string) (interface{}, error) {
+		for attempt := 1; ; attempt++ {
+			data, err := evalFunc(instanceName)
+			if err == nil {
+				return data, nil
+			}
+
+			if !opts.isTransient(err) {
+				return nil, err
+			}
+
+			if attempt >= opts.Attempts {
+				return nil, fmt.Errorf("%s (gave up after %d attempts)", err, attempt)
+			}
+
+			log.Warnf("%s: Evaluation failed with transient error, retrying in %s (%d/%d): %s",
+				instanceName, opts.Delay, attempt, opts.Attempts, err)
+
+			time.Sleep(opts.Delay)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package eval
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+// getFlakyEvalFunc returns evalInstanceFunc that fails
+// with specified error failsCount times and then succeeds
+func getFlakyEvalFunc(failsCount int, errMsg string, calls *int) evalInstanceFunc {
+	return func(instanceName string) (interface{}, error) {
+		*calls++
+		if *calls <= failsCount {
+			return nil, errors.New(errMsg)
+		}
+
+		return "running", nil
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var err error
+	var ctx context.Ctx
+	var calls int
+	var data interface{}
+
+	ctx.Eval.Retry = 3
+
+	opts, err := getRetryOpts(&ctx)
+	assert.Nil(err)
+
+	// transient error, succeeds on retry
+	calls = 0
+	data, err = withRetry(getFlakyEvalFunc(2, "Failed to connect: connection refused", &calls), opts)("router")
+	assert.Nil(err)
+	assert.Equal("running", data)
+	assert.Equal(3, calls)
+
+	// transient error, retries are exhausted
+	calls = 0
+	_, err = withRetry(getFlakyEvalFunc(10, "read: i/o timeout", &calls), opts)("router")
+	assert.EqualError(err, "read: i/o timeout (gave up after 3 attempts)")
+	assert.Equal(3, calls)
+
+	// non-transient error fails immediately
+	calls = 0
+	_, err = withRetry(getFlakyEvalFunc(2, "attempt to call a nil value", &calls), opts)("router")
+	assert.EqualError(err, "attempt to call a nil value")
+	assert.Equal(1, calls)
+
+	// no errors
+	calls = 0
+	data, err = withRetry(getFlakyEvalFunc(0, "", &calls), opts)("router")
+	assert.Nil(err)
+	assert.Equal("running", data)
+	assert.Equal(1, calls)
+
+	// single attempt
+	ctx.Eval.Retry = 1
+
+	opts, err = getRetryOpts(&ctx)
+	assert.Nil(err)
+
+	calls = 0
+	_, err = withRetry(getFlakyEvalFunc(1, "connection refused", &calls), opts)("router")
+	assert.EqualError(err, "connection refused (gave up after 1 attempts)")
+	assert.Equal(1, calls)
+}
+
+func TestWithRetryCustomPatterns(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var err error
+	var ctx context.Ctx
+	var calls int
+
+	ctx.Eval.Retry = 2
+	ctx.Eval.RetryOn = []string{`not yet configured`, `^Failed to use console socket`}
+
+	opts, err := getRetryOpts(&ctx)
+	assert.Nil(err)
+
+	// matches custom pattern
+	calls = 0
+	_, err = withRetry(getFlakyEvalFunc(1, "Cluster is not yet configured", &calls), opts)("router")
+	assert.Nil(err)
+	assert.Equal(2, calls)
+
+	// default patterns aren't used
+	calls = 0
+	_, err = withRetry(getFlakyEvalFunc(1, "connection refused", &calls), opts)("router")
+	assert.EqualError(err, "connection refused")
+	assert.Equal(1, calls)
+
+	// invalid pattern
+	ctx.Eval.RetryOn = []string{`(`}
+	_, err = getRetryOpts(&ctx)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), `Invalid transient error pattern "("`)
+}
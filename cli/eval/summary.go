@@ -0,0 +1,156 @@
+package eval
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+)
+
+type instanceResult struct {
+	InstanceName string
+	Value        string
+	Err          error
+}
+
+type valueGroup struct {
+	Value         string
+	InstanceNames []string
+}
+
+type evalSummary struct {
+	Results []instanceResult
+
+	// Groups contains instances grouped by the returned value,
+	// the most common value goes first
+	Groups []valueGroup
+	// Failed contains results of instances evaluation failed on
+	Failed []instanceResult
+}
+
+func getSummary(results []instanceResult) *evalSummary {
+	summary := evalSummary{
+		Results: results,
+	}
+
+	groupsByValue := make(map[string]*valueGroup)
+	valuesOrder := make([]string, 0)
+
+	for _, result := range results {
+		if result.Err != nil {
+			summary.Failed = append(summary.Failed, result)
+			continue
+		}
+
+		group, found := groupsByValue[result.Value]
+		if !found {
+			group = &valueGroup{Value: result.Value}
+			groupsByValue[result.Value] = group
+			valuesOrder = append(valuesOrder, result.Value)
+		}
+
+		group.InstanceNames = append(group.InstanceNames, result.InstanceName)
+	}
+
+	for _, value := range valuesOrder {
+		summary.Groups = append(summary.Groups, *groupsByValue[value])
+	}
+
+	// groups with equal size keep the order of the first occurrence
+	sort.SliceStable(summary.Groups, func(i, j int) bool {
+		return len(summary.Groups[i].InstanceNames) > len(summary.Groups[j].InstanceNames)
+	})
+
+	return &summary
+}
+
+// AgreedCount returns the number of instances that returned the most common value
+func (summary *evalSummary) AgreedCount() int {
+	if len(summary.Groups) == 0 {
+		return 0
+	}
+
+	return len(summary.Groups[0].InstanceNames)
+}
+
+// Outliers returns names of instances that returned a value
+// different from the most common one or failed
+func (summary *evalSummary) Outliers() []string {
+	outliers := make([]string, 0)
+
+	if len(summary.Groups) > 1 {
+		for _, group := range summary.Groups[1:] {
+			outliers = append(outliers, group.InstanceNames...)
+		}
+	}
+
+	for _, result := range summary.Failed {
+		outliers = append(outliers, result.InstanceName)
+	}
+
+	return outliers
+}
+
+func (summary *evalSummary) checkQuorum(quorum int) error {
+	if agreedCount := summary.AgreedCount(); agreedCount < quorum {
+		return fmt.Errorf(
+			"Quorum isn't reached: %d of %d instance(s) returned the same value, but %d required",
+			agreedCount, len(summary.Results), quorum,
+		)
+	}
+
+	return nil
+}
+
+func (summary *evalSummary) checkFailed() error {
+	if len(summary.Failed) > 0 {
+		return fmt.Errorf("Failed to evaluate on %d of %d instance(s)", len(summary.Failed), len(summary.Results))
+	}
+
+	return nil
+}
+
+// check returns an error if evaluation failed on some instance.
+// If quorum is specified, only the number of instances
+// that returned the same value is checked
+func (summary *evalSummary) check(quorum int) error {
+	if quorum > 0 {
+		return summary.checkQuorum(quorum)
+	}
+
+	return summary.checkFailed()
+}
+
+func (summary *evalSummary) String() string {
+	var lines []string
+
+	for _, result := range summary.Results {
+		if result.Err != nil {
+			lines = append(lines, fmt.Sprintf("• %s: %s", result.InstanceName, common.ColorErr.Sprintf("%s", result.Err)))
+		} else {
+			lines = append(lines, fmt.Sprintf("• %s:\n%s", result.InstanceName, indent(result.Value)))
+		}
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, fmt.Sprintf(
+		"%d of %d instance(s) returned the same value",
+		summary.AgreedCount(), len(summary.Results),
+	))
+
+	if outliers := summary.Outliers(); len(outliers) > 0 {
+		lines = append(lines, fmt.Sprintf("Outliers: %s", strings.Join(outliers, ", ")))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func indent(str string) string {
+	lines := strings.Split(str, "\n")
+	for i := range lines {
+		lines[i] = fmt.Sprintf("    %s", lines[i])
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,121 @@
+package eval
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getMockEvalFunc(values map[string]interface{}) evalInstanceFunc {
+	return func(instanceName string) (interface{}, error) {
+		value, found := values[instanceName]
+		if !found {
+			return nil, fmt.Errorf("Connection refused")
+		}
+
+		return value, nil
+	}
+}
+
+func TestSummaryAgreed(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	instanceNames := []string{"router", "s1-master", "s1-replica"}
+	evalFunc := getMockEvalFunc(map[string]interface{}{
+		"router":     "running",
+		"s1-master":  "running",
+		"s1-replica": "running",
+	})
+
+	summary := getSummary(collectResults(instanceNames, evalFunc))
+
+	assert.Len(summary.Results, 3)
+	assert.Len(summary.Groups, 1)
+	assert.Equal("running", summary.Groups[0].Value)
+	assert.Equal(instanceNames, summary.Groups[0].InstanceNames)
+	assert.Len(summary.Failed, 0)
+
+	assert.Equal(3, summary.AgreedCount())
+	assert.Len(summary.Outliers(), 0)
+
+	assert.Nil(summary.checkQuorum(3))
+	assert.Nil(summary.checkQuorum(1))
+
+	assert.Nil(summary.check(0))
+}
+
+func TestSummaryDiverged(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	instanceNames := []string{"router", "s1-master", "s1-replica", "s2-master", "s2-replica"}
+	evalFunc := getMockEvalFunc(map[string]interface{}{
+		"router":     map[string]interface{}{"version": "2.5.1"},
+		"s1-master":  map[string]interface{}{"version": "2.6.0"},
+		"s1-replica": map[string]interface{}{"version": "2.6.0"},
+		"s2-master":  map[string]interface{}{"version": "2.6.0"},
+	})
+
+	summary := getSummary(collectResults(instanceNames, evalFunc))
+
+	assert.Len(summary.Results, 5)
+
+	assert.Len(summary.Groups, 2)
+	assert.Equal("version: 2.6.0", summary.Groups[0].Value)
+	assert.Equal([]string{"s1-master", "s1-replica", "s2-master"}, summary.Groups[0].InstanceNames)
+	assert.Equal("version: 2.5.1", summary.Groups[1].Value)
+	assert.Equal([]string{"router"}, summary.Groups[1].InstanceNames)
+
+	assert.Len(summary.Failed, 1)
+	assert.Equal("s2-replica", summary.Failed[0].InstanceName)
+	assert.EqualError(summary.Failed[0].Err, "Connection refused")
+
+	assert.Equal(3, summary.AgreedCount())
+	assert.Equal([]string{"router", "s2-replica"}, summary.Outliers())
+
+	assert.Nil(summary.checkQuorum(3))
+	assert.EqualError(
+		summary.checkQuorum(4),
+		"Quorum isn't reached: 3 of 5 instance(s) returned the same value, but 4 required",
+	)
+
+	assert.Contains(summary.String(), "3 of 5 instance(s) returned the same value")
+	assert.Contains(summary.String(), "Outliers: router, s2-replica")
+
+	// s2-replica failed
+	assert.EqualError(summary.check(0), "Failed to evaluate on 1 of 5 instance(s)")
+	assert.Nil(summary.check(3))
+}
+
+func TestSummaryAllFailed(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	instanceNames := []string{"router", "s1-master"}
+	evalFunc := getMockEvalFunc(nil)
+
+	summary := getSummary(collectResults(instanceNames, evalFunc))
+
+	assert.Len(summary.Groups, 0)
+	assert.Len(summary.Failed, 2)
+
+	assert.Equal(0, summary.AgreedCount())
+	assert.Equal(instanceNames, summary.Outliers())
+
+	assert.EqualError(
+		summary.checkQuorum(1),
+		"Quorum isn't reached: 0 of 2 instance(s) returned the same value, but 1 required",
+	)
+
+	// command fails without quorum
+	assert.EqualError(summary.check(0), "Failed to evaluate on 2 of 2 instance(s)")
+
+	// single instance
+	summary = getSummary(collectResults([]string{"router"}, evalFunc))
+	assert.EqualError(summary.check(0), "Failed to evaluate on 1 of 1 instance(s)")
+}
@@ -0,0 +1,394 @@
+package pack
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/apex/log"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+	"github.com/tarantool/cartridge-cli/cli/context"
+	"github.com/tarantool/cartridge-cli/cli/project"
+	"github.com/tarantool/cartridge-cli/cli/templates"
+)
+
+const (
+	apkDataArchiveName    = "data.tar.gz"
+	apkControlArchiveName = "control.tar.gz"
+
+	apkPkgInfoFileName     = ".PKGINFO"
+	apkPreInstallFileName  = ".pre-install"
+	apkPostInstallFileName = ".post-install"
+
+	apkArch = "x86_64"
+
+	// apkChecksumPaxRecord is the PAX record that contains
+	// SHA1 of the file content (apk checks it on installation)
+	apkChecksumPaxRecord = "APK-TOOLS.checksum.SHA1"
+
+	apkPkgInfoContent = `# Generated by cartridge-cli
+pkgname = {{ .Name }}
+pkgver = {{ .Version }}
+pkgdesc = {{ .Name }}
+url =
+builddate = {{ .BuildDate }}
+size = {{ .Size }}
+arch = {{ .Arch }}
+origin = {{ .Name }}
+license = N/A
+{{- if .Commit }}
+commit = {{ .Commit }}
+{{- end }}
+{{- range .Depends }}
+depend = {{ . }}
+{{- end }}
+datahash = {{ .DataHash }}
+`
+
+	// Alpine has busybox addgroup and adduser instead of groupadd and useradd
+	apkPreInstallContent = `#!/bin/sh
+addgroup -S tarantool > /dev/null 2>&1 || :
+adduser -S -D -H -h /var/lib/tarantool -s /sbin/nologin -G tarantool \
+    -g "Tarantool Server" tarantool > /dev/null 2>&1 || :
+mkdir -p /etc/tarantool/conf.d/ /var/lib/tarantool/ /var/run/tarantool/ || :
+chown tarantool:tarantool /var/lib/tarantool /var/run/tarantool || :
+exit 0
+`
+
+	apkPostInstallContent = `#!/bin/sh
+chown -R root:root /usr/share/tarantool/{{ .Name }} || :
+exit 0
+`
+)
+
+// APK package (v2) is a concatenation of gzip streams:
+//
+// control.tar.gz : .PKGINFO and install scripts
+// data.tar.gz    : package files
+//
+// The control tar archive is written without the end-of-archive blocks,
+// so the whole package is read as a single tar.gz archive.
+// The package isn't signed, it should be installed with --allow-untrusted
+func packApk(ctx *context.Ctx) error {
+	// app dir
+	dataDirPath := filepath.Join(ctx.Pack.PackageFilesDir, dataDirName)
+	appDirPath := filepath.Join(dataDirPath, ctx.Running.AppDir)
+	if err := initAppDir(appDirPath, ctx); err != nil {
+		return err
+	}
+
+	// systemd dir
+	if err := initSystemdDir(dataDirPath, ctx); err != nil {
+		return err
+	}
+
+	// tmpfiles dir
+	if err := initTmpfilesDir(dataDirPath, ctx); err != nil {
+		return err
+	}
+
+	if len(ctx.Pack.Transforms) > 0 {
+		if err := applyTransforms(dataDirPath, true, ctx.Pack.Transforms,
+			getEnsuredDirsPaths(appDirPath, ctx.Pack.EnsureDirs)); err != nil {
+			return err
+		}
+	}
+
+	if fileModesAreSpecified(ctx) {
+		if err := normalizeFileModes(dataDirPath, ctx); err != nil {
+			return err
+		}
+	}
+
+	// data.tar.gz
+	log.Debugf("Create data archive")
+	dataArchivePath := filepath.Join(ctx.Pack.PackageFilesDir, apkDataArchiveName)
+	if err := writeApkDataArchive(dataDirPath, dataArchivePath); err != nil {
+		return fmt.Errorf("Failed to create APK data archive: %s", err)
+	}
+
+	// control.tar.gz
+	log.Debugf("Create APK control archive")
+	controlFiles, err := getApkControlFiles(dataDirPath, dataArchivePath, ctx)
+	if err != nil {
+		return err
+	}
+
+	controlArchivePath := filepath.Join(ctx.Pack.PackageFilesDir, apkControlArchiveName)
+	if err := writeApkControlArchive(controlFiles, controlArchivePath); err != nil {
+		return fmt.Errorf("Failed to create APK control archive: %s", err)
+	}
+
+	// create result package
+	log.Infof("Create result APK package...")
+	if err := common.MergeFiles(ctx.Pack.ResPackagePath, controlArchivePath, dataArchivePath); err != nil {
+		return fmt.Errorf("Failed to pack APK: %s", err)
+	}
+
+	log.Infof("Created result APK package: %s", ctx.Pack.ResPackagePath)
+
+	return nil
+}
+
+// getApkVersion returns pkgver in the APK format (<version>-r<count>)
+// and the commit hash that can't be a part of the version
+func getApkVersion(ctx *context.Ctx) (string, string) {
+	count := "0"
+	commit := ""
+
+	for _, part := range strings.Split(ctx.Pack.Release, "-") {
+		if strings.HasPrefix(part, "g") {
+			commit = strings.TrimPrefix(part, "g")
+		} else if part != "" {
+			count = part
+		}
+	}
+
+	return fmt.Sprintf("%s-r%s", ctx.Pack.Version, count), commit
+}
+
+// getApkDepends returns dependencies in the APK `depend` field format
+func getApkDepends(ctx *context.Ctx) ([]string, error) {
+	if ctx.Tarantool.TarantoolIsEnterprise {
+		return nil, nil
+	}
+
+	minTarantoolVersion := strings.SplitN(ctx.Tarantool.TarantoolVersion, "-", 2)[0]
+	maxTarantoolVersion, err := common.GetNextMajorVersion(minTarantoolVersion)
+	if err != nil {
+		return nil, project.InternalError("Failed to get next Tarantool major version: %s", err)
+	}
+
+	return []string{
+		fmt.Sprintf("tarantool>=%s", minTarantoolVersion),
+		fmt.Sprintf("tarantool<%s", maxTarantoolVersion),
+	}, nil
+}
+
+// getApkInstalledSize returns the total size of the package regular files
+func getApkInstalledSize(dataDirPath string) (int64, error) {
+	var installedSize int64
+
+	err := filepath.Walk(dataDirPath, func(filePath string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if fileInfo.Mode().IsRegular() {
+			installedSize += fileInfo.Size()
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return installedSize, nil
+}
+
+// getApkControlFiles returns the content of the control files by their names.
+// .PKGINFO should contain SHA256 of the data archive
+func getApkControlFiles(dataDirPath, dataArchivePath string, ctx *context.Ctx) (map[string]string, error) {
+	installedSize, err := getApkInstalledSize(dataDirPath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to compute APK installed size: %s", err)
+	}
+
+	dataHash, err := common.FileSHA256Hex(dataArchivePath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to compute APK data archive SHA256: %s", err)
+	}
+
+	depends, err := getApkDepends(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	version, commit := getApkVersion(ctx)
+
+	pkgInfoCtx := map[string]interface{}{
+		"Name":      ctx.Project.Name,
+		"Version":   version,
+		"Commit":    commit,
+		"BuildDate": time.Now().Unix(),
+		"Size":      installedSize,
+		"Arch":      apkArch,
+		"Depends":   depends,
+		"DataHash":  dataHash,
+	}
+
+	controlFiles := make(map[string]string)
+
+	controlFilesTemplates := map[string]string{
+		apkPkgInfoFileName:     apkPkgInfoContent,
+		apkPreInstallFileName:  apkPreInstallContent,
+		apkPostInstallFileName: apkPostInstallContent,
+	}
+
+	for fileName, fileTemplate := range controlFilesTemplates {
+		content, err := templates.GetTemplatedStr(&fileTemplate, pkgInfoCtx)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to instantiate APK %s file: %s", fileName, err)
+		}
+
+		controlFiles[fileName] = content
+	}
+
+	return controlFiles, nil
+}
+
+// writeApkControlArchive writes control files to the gzipped tar archive.
+// .PKGINFO goes first, end-of-archive blocks aren't written
+func writeApkControlArchive(controlFiles map[string]string, destFilePath string) error {
+	destFile, err := os.Create(destFilePath)
+	if err != nil {
+		return err
+	}
+	defer destFile.Close()
+
+	gzipWriter := gzip.NewWriter(destFile)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for _, fileName := range []string{apkPkgInfoFileName, apkPreInstallFileName, apkPostInstallFileName} {
+		content, found := controlFiles[fileName]
+		if !found {
+			continue
+		}
+
+		mode := int64(0755)
+		if fileName == apkPkgInfoFileName {
+			mode = 0644
+		}
+
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     fileName,
+			Mode:     mode,
+			Size:     int64(len(content)),
+			Uname:    "root",
+			Gname:    "root",
+			ModTime:  time.Now(),
+			Format:   tar.FormatUSTAR,
+		}
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if _, err := tarWriter.Write([]byte(content)); err != nil {
+			return err
+		}
+	}
+
+	// flush the last entry padding, but don't close tar writer
+	// since it writes end-of-archive blocks
+	if err := tarWriter.Flush(); err != nil {
+		return err
+	}
+
+	if err := gzipWriter.Close(); err != nil {
+		return err
+	}
+
+	return destFile.Close()
+}
+
+// writeApkDataArchive writes the package files to the gzipped tar archive.
+// Each regular file header contains SHA1 of the file content
+func writeApkDataArchive(dataDirPath, destFilePath string) error {
+	destFile, err := os.Create(destFilePath)
+	if err != nil {
+		return err
+	}
+	defer destFile.Close()
+
+	gzipWriter := gzip.NewWriter(destFile)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	err = filepath.Walk(dataDirPath, func(filePath string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if filePath == dataDirPath {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dataDirPath, filePath)
+		if err != nil {
+			return fmt.Errorf("Failed to get file rel path: %s", err)
+		}
+
+		linkTarget := ""
+		if fileInfo.Mode()&os.ModeSymlink != 0 {
+			if linkTarget, err = os.Readlink(filePath); err != nil {
+				return fmt.Errorf("Failed to read symlink %s: %s", relPath, err)
+			}
+		}
+
+		header, err := tar.FileInfoHeader(fileInfo, linkTarget)
+		if err != nil {
+			return err
+		}
+
+		header.Name = filepath.ToSlash(relPath)
+		if fileInfo.IsDir() {
+			header.Name += "/"
+		}
+
+		header.Uid, header.Gid = 0, 0
+		header.Uname, header.Gname = "root", "root"
+		header.Format = tar.FormatPAX
+
+		if fileInfo.Mode().IsRegular() {
+			checksum, err := common.FileSHA1Hex(filePath)
+			if err != nil {
+				return fmt.Errorf("Failed to get %s SHA1: %s", relPath, err)
+			}
+
+			header.PAXRecords = map[string]string{
+				apkChecksumPaxRecord: checksum,
+			}
+		}
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if fileInfo.Mode().IsRegular() {
+			content, err := os.Open(filePath)
+			if err != nil {
+				return err
+			}
+			defer content.Close()
+
+			if _, err := io.Copy(tarWriter, content); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+
+	if err := gzipWriter.Close(); err != nil {
+		return err
+	}
+
+	return destFile.Close()
+}
@@ -0,0 +1,177 @@
+package pack
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func TestGetApkVersion(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var ctx context.Ctx
+	ctx.Pack.Version = "1.2.3"
+
+	ctx.Pack.Release = "0"
+	version, commit := getApkVersion(&ctx)
+	assert.Equal("1.2.3-r0", version)
+	assert.Equal("", commit)
+
+	ctx.Pack.Release = "4"
+	version, commit = getApkVersion(&ctx)
+	assert.Equal("1.2.3-r4", version)
+	assert.Equal("", commit)
+
+	ctx.Pack.Release = "4-gdeadbeef"
+	version, commit = getApkVersion(&ctx)
+	assert.Equal("1.2.3-r4", version)
+	assert.Equal("deadbeef", commit)
+
+	ctx.Pack.Release = "gdeadbeef"
+	version, commit = getApkVersion(&ctx)
+	assert.Equal("1.2.3-r0", version)
+	assert.Equal("deadbeef", commit)
+}
+
+func TestGetApkDepends(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var ctx context.Ctx
+
+	ctx.Tarantool.TarantoolVersion = "2.4.2"
+	depends, err := getApkDepends(&ctx)
+	assert.Nil(err)
+	assert.Equal([]string{"tarantool>=2.4.2", "tarantool<3"}, depends)
+
+	ctx.Tarantool.TarantoolVersion = "2.4.2-1-g7f8c5e6"
+	depends, err = getApkDepends(&ctx)
+	assert.Nil(err)
+	assert.Equal([]string{"tarantool>=2.4.2", "tarantool<3"}, depends)
+
+	ctx.Tarantool.TarantoolIsEnterprise = true
+	depends, err = getApkDepends(&ctx)
+	assert.Nil(err)
+	assert.Len(depends, 0)
+}
+
+func TestWriteApkPackage(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "apk")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	dataDirPath := filepath.Join(tmpDir, "data")
+	appDirPath := filepath.Join(dataDirPath, "usr", "share", "tarantool", "myapp")
+	assert.Nil(os.MkdirAll(appDirPath, 0755))
+	assert.Nil(ioutil.WriteFile(filepath.Join(appDirPath, "init.lua"), []byte("print('hi')\n"), 0644))
+	assert.Nil(os.Symlink("init.lua", filepath.Join(appDirPath, "link.lua")))
+
+	var ctx context.Ctx
+	ctx.Project.Name = "myapp"
+	ctx.Pack.Version = "1.2.3"
+	ctx.Pack.Release = "4-gdeadbeef"
+	ctx.Tarantool.TarantoolVersion = "2.4.2"
+
+	dataArchivePath := filepath.Join(tmpDir, apkDataArchiveName)
+	assert.Nil(writeApkDataArchive(dataDirPath, dataArchivePath))
+
+	controlFiles, err := getApkControlFiles(dataDirPath, dataArchivePath, &ctx)
+	assert.Nil(err)
+
+	controlArchivePath := filepath.Join(tmpDir, apkControlArchiveName)
+	assert.Nil(writeApkControlArchive(controlFiles, controlArchivePath))
+
+	packagePath := filepath.Join(tmpDir, "myapp-1.2.3-4.apk")
+	assert.Nil(common.MergeFiles(packagePath, controlArchivePath, dataArchivePath))
+
+	// the whole package is read as a single tar.gz archive
+	packageFile, err := os.Open(packagePath)
+	assert.Nil(err)
+	defer packageFile.Close()
+
+	gzipReader, err := gzip.NewReader(packageFile)
+	assert.Nil(err)
+
+	tarReader := tar.NewReader(gzipReader)
+
+	var names []string
+	var pkgInfo string
+	checksums := make(map[string]string)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(err)
+
+		names = append(names, header.Name)
+
+		if header.Name == apkPkgInfoFileName {
+			content, err := ioutil.ReadAll(tarReader)
+			assert.Nil(err)
+			pkgInfo = string(content)
+		}
+
+		if header.Typeflag == tar.TypeSymlink {
+			assert.Equal("init.lua", header.Linkname)
+		}
+
+		if checksum, found := header.PAXRecords[apkChecksumPaxRecord]; found {
+			checksums[header.Name] = checksum
+		}
+
+		assert.Equal(0, header.Uid)
+		assert.Equal("root", header.Uname)
+	}
+
+	assert.Equal([]string{
+		".PKGINFO",
+		".pre-install",
+		".post-install",
+		"usr/",
+		"usr/share/",
+		"usr/share/tarantool/",
+		"usr/share/tarantool/myapp/",
+		"usr/share/tarantool/myapp/init.lua",
+		"usr/share/tarantool/myapp/link.lua",
+	}, names)
+
+	initChecksum, err := common.FileSHA1Hex(filepath.Join(appDirPath, "init.lua"))
+	assert.Nil(err)
+	assert.Equal(map[string]string{
+		"usr/share/tarantool/myapp/init.lua": initChecksum,
+	}, checksums)
+
+	dataHash, err := common.FileSHA256Hex(dataArchivePath)
+	assert.Nil(err)
+
+	pkgInfoLines := strings.Split(pkgInfo, "\n")
+	assert.Contains(pkgInfoLines, "pkgname = myapp")
+	assert.Contains(pkgInfoLines, "pkgver = 1.2.3-r4")
+	assert.Contains(pkgInfoLines, "commit = deadbeef")
+	assert.Contains(pkgInfoLines, "size = 12")
+	assert.Contains(pkgInfoLines, "arch = x86_64")
+	assert.Contains(pkgInfoLines, "depend = tarantool>=2.4.2")
+	assert.Contains(pkgInfoLines, "depend = tarantool<3")
+	assert.Contains(pkgInfoLines, "datahash = "+dataHash)
+}
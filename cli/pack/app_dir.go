@@ -1,7 +1,9 @@
 package pack
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -13,12 +15,26 @@ import (
 	"github.com/tarantool/cartridge-cli/cli/build"
 	"github.com/tarantool/cartridge-cli/cli/common"
 	"github.com/tarantool/cartridge-cli/cli/context"
+	"github.com/tarantool/cartridge-cli/cli/project"
+)
+
+var (
+	vcsFilesPatterns = []string{
+		".git",
+		".hg",
+		".svn",
+		"*.swp",
+		".DS_Store",
+	}
 )
 
 const (
 	fileReqPerms    = 0444
 	dirReqPerms     = 0555
+	emptyDirPerms   = 0755
 	versionFileName = "VERSION"
+
+	rocksManifestFileName = "rocks-manifest.json"
 )
 
 func initAppDir(appDirPath string, ctx *context.Ctx) error {
@@ -43,6 +59,27 @@ func initAppDir(appDirPath string, ctx *context.Ctx) error {
 		return fmt.Errorf("Failed to copy application files: %s", err)
 	}
 
+	if ctx.Pack.IncludeEmptyDirs {
+		log.Debugf("Restore empty directories")
+		if err := restoreEmptyDirs(ctx.Project.Path, appDirPath, ctx); err != nil {
+			return fmt.Errorf("Failed to restore empty directories: %s", err)
+		}
+	}
+
+	if len(ctx.Pack.EnsureDirs) > 0 {
+		log.Debugf("Create required directories")
+		if err := ensureDirs(appDirPath, ctx.Pack.EnsureDirs); err != nil {
+			return err
+		}
+	}
+
+	if ctx.Pack.NormalizeLineEndings {
+		log.Debugf("Normalize line endings")
+		if err := normalizeLineEndings(appDirPath); err != nil {
+			return err
+		}
+	}
+
 	log.Debugf("Check filemodes")
 	if err := checkFilemodes(appDirPath); err != nil {
 		return err
@@ -50,7 +87,9 @@ func initAppDir(appDirPath string, ctx *context.Ctx) error {
 
 	// build
 	ctx.Build.Dir = appDirPath
-	if err := build.Run(ctx); err != nil {
+	if ctx.Pack.NoBuild {
+		log.Infof("Build is skipped, application files are packed as is")
+	} else if err := build.Run(ctx); err != nil {
 		return err
 	}
 
@@ -59,11 +98,25 @@ func initAppDir(appDirPath string, ctx *context.Ctx) error {
 		return err
 	}
 
+	if ctx.Pack.VerifyNoAbsSymlinks {
+		log.Debugf("Check that there are no absolute symlinks")
+		if err := checkNoAbsoluteSymlinks(appDirPath); err != nil {
+			return err
+		}
+	}
+
 	// generate VERSION file
 	if err := generateVersionFile(appDirPath, ctx); err != nil {
 		log.Warnf("Failed to generate VERSION file: %s", err)
 	}
 
+	if ctx.Pack.RocksManifest {
+		log.Debugf("Generate rocks manifest file")
+		if err := generateRocksManifestFile(appDirPath); err != nil {
+			log.Warnf("Failed to generate rocks manifest file: %s", err)
+		}
+	}
+
 	if ctx.Tarantool.TarantoolIsEnterprise {
 		log.Debugf("Copy Tarantool binaries")
 		// copy Tarantool binaries to BuildDir to deliver in the result package
@@ -76,38 +129,217 @@ func initAppDir(appDirPath string, ctx *context.Ctx) error {
 }
 
 func copyProjectFiles(dst string, ctx *context.Ctx) error {
-	err := copy.Copy(ctx.Project.Path, dst, copy.Options{
-		Skip: func(src string) (bool, error) {
-			if strings.HasPrefix(src, fmt.Sprintf("%s/", ctx.Cli.CartridgeTmpDir)) {
-				return true, nil
-			}
+	ignore, err := getIgnoreRules(ctx.Project.Path)
+	if err != nil {
+		return err
+	}
 
-			relPath, err := filepath.Rel(ctx.Project.Path, src)
+	err = copy.Copy(ctx.Project.Path, dst, copy.Options{
+		Skip: func(src string) (bool, error) {
+			skipReason, err := getProjectFileSkipReason(src, ignore, ctx)
 			if err != nil {
-				return false, fmt.Errorf("Failed to get file rel path: %s", err)
+				return false, err
 			}
 
-			if relPath == ".rocks" || strings.HasPrefix(relPath, ".rocks/") {
-				return true, nil
+			return skipReason != "", nil
+		},
+	})
+
+	if err != nil {
+		return fmt.Errorf("Failed to copy: %s", err)
+	}
+
+	return nil
+}
+
+// getProjectFileSkipReason returns the reason why the project file
+// isn't copied to the application dir (empty string if it's copied)
+func getProjectFileSkipReason(src string, ignore *ignoreRules, ctx *context.Ctx) (string, error) {
+	if strings.HasPrefix(src, fmt.Sprintf("%s/", ctx.Cli.CartridgeTmpDir)) {
+		return "cartridge temporary directory", nil
+	}
+
+	relPath, err := filepath.Rel(ctx.Project.Path, src)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get file rel path: %s", err)
+	}
+
+	if !ctx.Pack.NoBuild && (relPath == ".rocks" || strings.HasPrefix(relPath, ".rocks/")) {
+		return "rocks are installed on build", nil
+	}
+
+	if isSocket, err := common.IsSocket(src); err != nil {
+		return "", fmt.Errorf("Failed to check if file is a socket: %s", src)
+	} else if isSocket {
+		return "socket", nil
+	}
+
+	if ignore != nil && relPath != "." {
+		ignored, rule, err := isIgnored(ignore, src, relPath)
+		if err != nil {
+			return "", err
+		}
+
+		if ignored {
+			return fmt.Sprintf("ignored by %s:%d %q", ignore.FileName, rule.Line, rule.Pattern), nil
+		}
+	}
+
+	return "", nil
+}
+
+// isIgnored checks if the project file is ignored by .cartridgeignore.
+// The decision is logged to make it possible to find out why the file isn't packed
+func isIgnored(ignore *ignoreRules, filePath string, relPath string) (bool, *ignoreRule, error) {
+	fileInfo, err := os.Lstat(filePath)
+	if err != nil {
+		return false, nil, fmt.Errorf("Failed to get file info: %s", err)
+	}
+
+	ignored, rule := ignore.Match(relPath, fileInfo.IsDir())
+	if rule == nil {
+		return false, nil, nil
+	}
+
+	if ignored {
+		log.Debugf("%s is ignored by %s:%d %q", relPath, ignore.FileName, rule.Line, rule.Pattern)
+	} else {
+		log.Debugf("%s is included by %s:%d %q", relPath, ignore.FileName, rule.Line, rule.Pattern)
+	}
+
+	return ignored, rule, nil
+}
+
+// restoreEmptyDirs creates in the application dir all empty directories
+// found in the project dir (they can be removed on cleanup).
+// Directories that are removed on cleanup on purpose (git-ignored ones
+// and VCS metadata if ctx.Pack.ExcludeVCS is set) aren't restored
+func restoreEmptyDirs(projectPath string, appDirPath string, ctx *context.Ctx) error {
+	ignore, err := getIgnoreRules(projectPath)
+	if err != nil {
+		return err
+	}
+
+	gitIgnoredPaths, err := getGitIgnoredPaths(projectPath)
+	if err != nil {
+		return err
+	}
+
+	err = filepath.Walk(projectPath, func(filePath string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !fileInfo.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(projectPath, filePath)
+		if err != nil {
+			return fmt.Errorf("Failed to get file rel path: %s", err)
+		}
+
+		if relPath == ".rocks" {
+			return filepath.SkipDir
+		}
+
+		if ctx.Cli.CartridgeTmpDir != "" && filePath == ctx.Cli.CartridgeTmpDir {
+			return filepath.SkipDir
+		}
+
+		if ctx.Pack.ExcludeVCS && relPath != "." {
+			if isVCS, err := isVCSFile(fileInfo.Name()); err != nil {
+				return err
+			} else if isVCS {
+				return filepath.SkipDir
 			}
+		}
 
-			if isSocket, err := common.IsSocket(src); err != nil {
-				return false, fmt.Errorf("Failed to check if file is a socket: %s", src)
-			} else if isSocket {
-				return true, nil
+		if gitIgnoredPaths[relPath] {
+			log.Debugf("%s is ignored by git, it isn't restored", relPath)
+			return filepath.SkipDir
+		}
+
+		if ignore != nil && relPath != "." {
+			if ignored, _ := ignore.Match(relPath, true); ignored {
+				return filepath.SkipDir
 			}
+		}
 
-			return false, nil
-		},
+		if isEmpty, err := common.IsDirEmpty(filePath); err != nil {
+			return err
+		} else if !isEmpty {
+			return nil
+		}
+
+		if err := os.MkdirAll(filepath.Join(appDirPath, relPath), emptyDirPerms); err != nil {
+			return fmt.Errorf("Failed to create directory %s: %s", relPath, err)
+		}
+
+		return nil
 	})
 
+	return err
+}
+
+// getGitIgnoredPaths returns the set of project paths that are ignored by git
+// (they are removed by `git clean -X` on cleanup).
+// Ignored directories are listed without their contents.
+// The set is empty if git isn't installed or the project isn't a git project
+func getGitIgnoredPaths(projectPath string) (map[string]bool, error) {
+	ignoredPaths := make(map[string]bool)
+
+	if !common.GitIsInstalled() || !common.IsGitProject(projectPath) {
+		return ignoredPaths, nil
+	}
+
+	gitLsFilesCmd := exec.Command(
+		"git", "ls-files", "--others", "--ignored", "--exclude-standard", "--directory",
+	)
+
+	output, err := common.GetOutput(gitLsFilesCmd, &projectPath)
 	if err != nil {
-		return fmt.Errorf("Failed to copy: %s", err)
+		return nil, fmt.Errorf("Failed to get files ignored by git: %s", err)
+	}
+
+	for _, ignoredPath := range strings.Split(output, "\n") {
+		if ignoredPath = strings.TrimSuffix(strings.TrimSpace(ignoredPath), "/"); ignoredPath != "" {
+			ignoredPaths[filepath.FromSlash(ignoredPath)] = true
+		}
+	}
+
+	return ignoredPaths, nil
+}
+
+// ensureDirs creates specified directories in the application dir
+func ensureDirs(appDirPath string, dirs []string) error {
+	for _, dir := range dirs {
+		dirPath := filepath.Join(appDirPath, dir)
+
+		if isSubDir, err := common.IsSubDir(dirPath, appDirPath); err != nil {
+			return fmt.Errorf("Failed to check directory %s: %s", dir, err)
+		} else if !isSubDir {
+			return fmt.Errorf("Directory %s should be placed inside the application directory", dir)
+		}
+
+		if err := os.MkdirAll(dirPath, emptyDirPerms); err != nil {
+			return fmt.Errorf("Failed to create directory %s: %s", dir, err)
+		}
 	}
 
 	return nil
 }
 
+// getEnsuredDirsPaths returns paths of the directories created by ensureDirs
+func getEnsuredDirsPaths(appDirPath string, dirs []string) []string {
+	dirsPaths := make([]string, len(dirs))
+	for i, dir := range dirs {
+		dirsPaths[i] = filepath.Join(appDirPath, dir)
+	}
+
+	return dirsPaths
+}
+
 func cleanupAppDir(appDirPath string, ctx *context.Ctx) error {
 	if !common.GitIsInstalled() {
 		log.Warnf("git not found. It is possible that some of the extra files " +
@@ -132,14 +364,68 @@ func cleanupAppDir(appDirPath string, ctx *context.Ctx) error {
 		}
 	}
 
-	log.Debugf("Remove `.git` directory")
-	if err := os.RemoveAll(filepath.Join(appDirPath, ".git")); err != nil {
-		return fmt.Errorf("Failed to remove .git directory: %s", err)
+	if ctx.Pack.ExcludeVCS {
+		log.Debugf("Remove VCS and editor metadata files")
+		if err := removeVCSFiles(appDirPath); err != nil {
+			return fmt.Errorf("Failed to remove VCS files: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// removeVCSFiles removes VCS and editor metadata files
+// from the specified directory recursively
+func removeVCSFiles(dirPath string) error {
+	var vcsPaths []string
+
+	err := filepath.Walk(dirPath, func(filePath string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if filePath == dirPath {
+			return nil
+		}
+
+		if isVCS, err := isVCSFile(fileInfo.Name()); err != nil {
+			return err
+		} else if isVCS {
+			vcsPaths = append(vcsPaths, filePath)
+			if fileInfo.IsDir() {
+				return filepath.SkipDir
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, vcsPath := range vcsPaths {
+		if err := os.RemoveAll(vcsPath); err != nil {
+			return fmt.Errorf("Failed to remove %s: %s", vcsPath, err)
+		}
 	}
 
 	return nil
 }
 
+// isVCSFile checks if the file name matches one of VCS and editor metadata files patterns
+func isVCSFile(fileName string) (bool, error) {
+	for _, pattern := range vcsFilesPatterns {
+		if matched, err := filepath.Match(pattern, fileName); err != nil {
+			return false, project.InternalError("Invalid VCS files pattern %q: %s", pattern, err)
+		} else if matched {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func checkFilemodes(appDirPath string) error {
 	if fileInfo, err := os.Stat(appDirPath); err != nil {
 		return err
@@ -171,6 +457,70 @@ func checkFilemodes(appDirPath string) error {
 	return nil
 }
 
+// checkPrebuiltApp checks that the application packed with --no-build
+// is built: it should contain installed rocks
+func checkPrebuiltApp(projectPath string) error {
+	rocksPath := filepath.Join(projectPath, ".rocks")
+
+	fileInfo, err := os.Stat(rocksPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf(
+			"Application isn't built: .rocks directory isn't found. " +
+				"Please, run `cartridge build` before packing with --no-build",
+		)
+	} else if err != nil {
+		return fmt.Errorf("Failed to use .rocks directory: %s", err)
+	}
+
+	if !fileInfo.IsDir() {
+		return fmt.Errorf("Application isn't built: .rocks is not a directory")
+	}
+
+	rocksDirIsEmpty, err := common.IsDirEmpty(rocksPath)
+	if err != nil {
+		return fmt.Errorf("Failed to check .rocks directory: %s", err)
+	}
+
+	if rocksDirIsEmpty {
+		log.Warnf(".rocks directory is empty, application dependencies can be missed in the package")
+	}
+
+	return nil
+}
+
+// checkNoAbsoluteSymlinks returns an error if the application dir
+// contains symlinks to absolute paths (they are broken after package installation)
+func checkNoAbsoluteSymlinks(appDirPath string) error {
+	err := filepath.Walk(appDirPath, func(filePath string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if fileInfo.Mode()&os.ModeSymlink == 0 {
+			return nil
+		}
+
+		target, err := os.Readlink(filePath)
+		if err != nil {
+			return fmt.Errorf("Failed to read symlink %s: %s", filePath, err)
+		}
+
+		if !filepath.IsAbs(target) {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(appDirPath, filePath)
+		if err != nil {
+			return fmt.Errorf("Failed to get file rel path: %s", err)
+		}
+
+		return fmt.Errorf("Symlink %s points to absolute path %s. "+
+			"Please, use relative symlinks", relPath, target)
+	})
+
+	return err
+}
+
 func generateVersionFile(appDirPath string, ctx *context.Ctx) error {
 	log.Infof("Generate %s file", versionFileName)
 
@@ -226,6 +576,29 @@ func generateVersionFile(appDirPath string, ctx *context.Ctx) error {
 	return nil
 }
 
+// generateRocksManifestFile writes {name: version} map of the rocks
+// installed to the application .rocks directory.
+// Rocks manifest is only read, so the application isn't affected
+func generateRocksManifestFile(appDirPath string) error {
+	rocksVersionsMap, err := common.LuaGetRocksVersions(appDirPath)
+	if err != nil {
+		return err
+	}
+
+	// json.Marshal sorts map keys, so the file content is stable
+	manifestContent, err := json.MarshalIndent(rocksVersionsMap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to encode rocks versions: %s", err)
+	}
+
+	manifestFilePath := filepath.Join(appDirPath, rocksManifestFileName)
+	if err := ioutil.WriteFile(manifestFilePath, append(manifestContent, '\n'), 0644); err != nil {
+		return fmt.Errorf("Failed to write rocks manifest file %s: %s", manifestFilePath, err)
+	}
+
+	return nil
+}
+
 func copyTarantoolBinaries(binariesPath string, appDirPath string) error {
 	tarantoolBinaries := []string{
 		"tarantool",
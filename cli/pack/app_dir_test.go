@@ -0,0 +1,336 @@
+package pack
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func getTarEntries(t *testing.T, srcDirPath string) map[string]byte {
+	var buf bytes.Buffer
+	if err := common.WriteTarArchive(srcDirPath, &buf); err != nil {
+		t.Fatalf("Failed to write tar archive: %s", err)
+	}
+
+	entries := make(map[string]byte)
+
+	tarReader := tar.NewReader(&buf)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Failed to read tar archive: %s", err)
+		}
+
+		entries[header.Name] = header.Typeflag
+	}
+
+	return entries
+}
+
+func TestRestoreEmptyDirs(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var ctx context.Ctx
+
+	projectPath, err := ioutil.TempDir("", "project")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(projectPath)
+
+	appDirPath, err := ioutil.TempDir("", "app")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(appDirPath)
+
+	assert.Nil(os.MkdirAll(filepath.Join(projectPath, "spool", "uploads"), 0755))
+	assert.Nil(os.MkdirAll(filepath.Join(projectPath, "app"), 0755))
+	assert.Nil(ioutil.WriteFile(filepath.Join(projectPath, "app", "init.lua"), []byte("return {}"), 0644))
+	assert.Nil(os.MkdirAll(filepath.Join(projectPath, ".rocks", "empty"), 0755))
+
+	assert.Nil(restoreEmptyDirs(projectPath, appDirPath, &ctx))
+
+	entries := getTarEntries(t, appDirPath)
+
+	assert.Contains(entries, "spool/uploads")
+	assert.Equal(byte(tar.TypeDir), entries["spool/uploads"])
+	assert.NotContains(entries, "app")
+	assert.NotContains(entries, ".rocks/empty")
+}
+
+func TestRestoreEmptyDirsIgnored(t *testing.T) {
+	t.Parallel()
+
+	if !common.GitIsInstalled() {
+		t.Skip("git isn't installed")
+	}
+
+	assert := assert.New(t)
+
+	var ctx context.Ctx
+	ctx.Pack.ExcludeVCS = true
+
+	projectPath, err := ioutil.TempDir("", "project")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(projectPath)
+
+	appDirPath, err := ioutil.TempDir("", "app")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(appDirPath)
+
+	assert.Nil(exec.Command("git", "init", "-q", projectPath).Run())
+	assert.Nil(ioutil.WriteFile(filepath.Join(projectPath, ".gitignore"), []byte("build/\n*.tmpdir\n"), 0644))
+
+	assert.Nil(os.MkdirAll(filepath.Join(projectPath, "spool", "uploads"), 0755))
+	assert.Nil(os.MkdirAll(filepath.Join(projectPath, "build", "sub"), 0755))
+	assert.Nil(os.MkdirAll(filepath.Join(projectPath, "cache.tmpdir"), 0755))
+	assert.Nil(os.MkdirAll(filepath.Join(projectPath, "module", ".hg", "store"), 0755))
+
+	assert.Nil(restoreEmptyDirs(projectPath, appDirPath, &ctx))
+
+	entries := getTarEntries(t, appDirPath)
+
+	assert.Contains(entries, "spool/uploads")
+	assert.NotContains(entries, "build/sub")
+	assert.NotContains(entries, "cache.tmpdir")
+	assert.NotContains(entries, "module/.hg/store")
+	assert.NotContains(entries, ".git/refs/tags")
+
+	// VCS metadata is restored if it isn't excluded
+	ctx.Pack.ExcludeVCS = false
+	assert.Nil(restoreEmptyDirs(projectPath, appDirPath, &ctx))
+
+	entries = getTarEntries(t, appDirPath)
+	assert.Contains(entries, "module/.hg/store")
+	assert.Contains(entries, ".git/refs/tags")
+}
+
+func TestEnsureDirs(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	appDirPath, err := ioutil.TempDir("", "app")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(appDirPath)
+
+	assert.Nil(ensureDirs(appDirPath, []string{"cache", "var/spool"}))
+
+	entries := getTarEntries(t, appDirPath)
+
+	assert.Equal(byte(tar.TypeDir), entries["cache"])
+	assert.Equal(byte(tar.TypeDir), entries["var/spool"])
+
+	fileInfo, err := os.Stat(filepath.Join(appDirPath, "var", "spool"))
+	assert.Nil(err)
+	assert.EqualValues(emptyDirPerms, fileInfo.Mode().Perm())
+
+	err = ensureDirs(appDirPath, []string{"../outside"})
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "should be placed inside the application directory")
+}
+
+func initVCSFiles(t *testing.T, dirPath string) {
+	dirs := []string{".git", ".hg", ".svn", "app/.git"}
+	files := []string{".git/HEAD", ".hg/store", ".svn/entries", "app/.git/HEAD",
+		"init.lua", ".init.lua.swp", ".DS_Store", "app/.DS_Store", "app/roles.lua"}
+
+	for _, dir := range dirs {
+		if err := os.MkdirAll(filepath.Join(dirPath, dir), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %s", err)
+		}
+	}
+
+	for _, file := range files {
+		if err := ioutil.WriteFile(filepath.Join(dirPath, file), []byte(""), 0644); err != nil {
+			t.Fatalf("Failed to create file: %s", err)
+		}
+	}
+}
+
+func TestCleanupAppDirVCS(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var ctx context.Ctx
+
+	vcsPaths := []string{".git", ".git/HEAD", ".hg", ".svn", "app/.git", "app/.git/HEAD",
+		".init.lua.swp", ".DS_Store", "app/.DS_Store"}
+
+	// exclude VCS files
+	appDirPath, err := ioutil.TempDir("", "app")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(appDirPath)
+
+	initVCSFiles(t, appDirPath)
+
+	ctx.Pack.ExcludeVCS = true
+	assert.Nil(cleanupAppDir(appDirPath, &ctx))
+
+	entries := getTarEntries(t, appDirPath)
+
+	assert.Contains(entries, "init.lua")
+	assert.Contains(entries, "app/roles.lua")
+	for _, vcsPath := range vcsPaths {
+		assert.NotContains(entries, vcsPath)
+	}
+
+	// include VCS files
+	appDirPath, err = ioutil.TempDir("", "app")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(appDirPath)
+
+	initVCSFiles(t, appDirPath)
+
+	ctx.Pack.ExcludeVCS = false
+	assert.Nil(cleanupAppDir(appDirPath, &ctx))
+
+	entries = getTarEntries(t, appDirPath)
+
+	assert.Contains(entries, "init.lua")
+	assert.Contains(entries, "app/roles.lua")
+	for _, vcsPath := range vcsPaths {
+		assert.Contains(entries, vcsPath)
+	}
+}
+
+func TestCheckNoAbsoluteSymlinks(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	appDirPath, err := ioutil.TempDir("", "app")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(appDirPath)
+
+	assert.Nil(os.MkdirAll(filepath.Join(appDirPath, "app", "roles"), 0755))
+	assert.Nil(ioutil.WriteFile(filepath.Join(appDirPath, "init.lua"), []byte(""), 0644))
+
+	// relative symlink
+	assert.Nil(os.Symlink("../../init.lua", filepath.Join(appDirPath, "app", "roles", "init.lua")))
+	assert.Nil(checkNoAbsoluteSymlinks(appDirPath))
+
+	// absolute symlink
+	assert.Nil(os.Symlink("/usr/bin/tarantool", filepath.Join(appDirPath, "app", "tarantool")))
+
+	err = checkNoAbsoluteSymlinks(appDirPath)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Symlink app/tarantool points to absolute path /usr/bin/tarantool")
+}
+
+func TestCheckPrebuiltApp(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	projectPath, err := ioutil.TempDir("", "project")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(projectPath)
+
+	rocksPath := filepath.Join(projectPath, ".rocks")
+
+	// .rocks doesn't exist
+	err = checkPrebuiltApp(projectPath)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Application isn't built: .rocks directory isn't found")
+
+	// .rocks is a file
+	assert.Nil(ioutil.WriteFile(rocksPath, []byte(""), 0644))
+	assert.EqualError(checkPrebuiltApp(projectPath), "Application isn't built: .rocks is not a directory")
+
+	// .rocks is empty
+	assert.Nil(os.Remove(rocksPath))
+	assert.Nil(os.MkdirAll(rocksPath, 0755))
+	assert.Nil(checkPrebuiltApp(projectPath))
+
+	// rocks are installed
+	assert.Nil(os.MkdirAll(filepath.Join(rocksPath, "share", "tarantool", "cartridge"), 0755))
+	assert.Nil(checkPrebuiltApp(projectPath))
+}
+
+func TestGenerateRocksManifestFile(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	appDirPath, err := ioutil.TempDir("", "app")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(appDirPath)
+
+	manifestFilePath := filepath.Join(appDirPath, rocksManifestFileName)
+
+	// no rocks are installed
+	assert.Nil(generateRocksManifestFile(appDirPath))
+
+	manifestContent, err := ioutil.ReadFile(manifestFilePath)
+	assert.Nil(err)
+	assert.Equal("{}\n", string(manifestContent))
+
+	// rocks are installed
+	rocksManifestDir := filepath.Join(appDirPath, ".rocks", "share", "tarantool", "rocks")
+	assert.Nil(os.MkdirAll(rocksManifestDir, 0755))
+	assert.Nil(ioutil.WriteFile(filepath.Join(rocksManifestDir, "manifest"), []byte(`
+commands = {}
+dependencies = {
+   myapp = {
+      ["scm-1"] = {}
+   },
+   cartridge = {
+      ["2.7.3-1"] = {}
+   },
+   checks = {
+      ["3.1.0-1"] = {}
+   },
+}
+`), 0644))
+
+	assert.Nil(generateRocksManifestFile(appDirPath))
+
+	manifestContent, err = ioutil.ReadFile(manifestFilePath)
+	assert.Nil(err)
+	assert.Equal(`{
+  "cartridge": "2.7.3-1",
+  "checks": "3.1.0-1",
+  "myapp": "scm-1"
+}
+`, string(manifestContent))
+
+	// bad manifest
+	assert.Nil(ioutil.WriteFile(filepath.Join(rocksManifestDir, "manifest"), []byte(`dependencies = 1`), 0644))
+
+	err = generateRocksManifestFile(appDirPath)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "dependencies is not a table")
+}
@@ -0,0 +1,49 @@
+package pack
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+)
+
+// writeChecksumFile writes <artifact>.sha256 file in the sha256sum format.
+// The file is written to the temporary file first and then renamed,
+// so the half-written checksum file is never left
+func writeChecksumFile(artifactPath string) (string, error) {
+	digest, err := common.FileSHA256Hex(artifactPath)
+	if err != nil {
+		return "", fmt.Errorf("Failed to compute SHA256: %s", err)
+	}
+
+	checksumPath := fmt.Sprintf("%s.sha256", artifactPath)
+	checksumContent := fmt.Sprintf("%s  %s\n", digest, filepath.Base(artifactPath))
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(checksumPath), ".sha256-*")
+	if err != nil {
+		return "", fmt.Errorf("Failed to create temporary file: %s", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.WriteString(checksumContent); err != nil {
+		tmpFile.Close()
+		return "", fmt.Errorf("Failed to write checksum: %s", err)
+	}
+
+	if err := tmpFile.Chmod(0644); err != nil {
+		tmpFile.Close()
+		return "", fmt.Errorf("Failed to set checksum file mode: %s", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return "", fmt.Errorf("Failed to write checksum: %s", err)
+	}
+
+	if err := os.Rename(tmpFile.Name(), checksumPath); err != nil {
+		return "", fmt.Errorf("Failed to write checksum file: %s", err)
+	}
+
+	return checksumPath, nil
+}
@@ -0,0 +1,48 @@
+package pack
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteChecksumFile(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "checksum")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	artifactPath := filepath.Join(tmpDir, "myapp-1.0.0-0.tar.gz")
+	assert.Nil(ioutil.WriteFile(artifactPath, []byte("hello\n"), 0644))
+
+	checksumPath, err := writeChecksumFile(artifactPath)
+	assert.Nil(err)
+	assert.Equal(artifactPath+".sha256", checksumPath)
+
+	checksumContent, err := ioutil.ReadFile(checksumPath)
+	assert.Nil(err)
+	assert.Equal(
+		"5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03  myapp-1.0.0-0.tar.gz\n",
+		string(checksumContent),
+	)
+
+	// no temporary files are left
+	fileInfos, err := ioutil.ReadDir(tmpDir)
+	assert.Nil(err)
+	assert.Len(fileInfos, 2)
+
+	// missing artifact
+	_, err = writeChecksumFile(filepath.Join(tmpDir, "missing.rpm"))
+	assert.NotNil(err)
+
+	_, err = os.Stat(filepath.Join(tmpDir, "missing.rpm.sha256"))
+	assert.True(os.IsNotExist(err))
+}
@@ -1,13 +1,18 @@
 package pack
 
 import (
+	"bytes"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
+	"text/template"
 
 	"github.com/tarantool/cartridge-cli/cli/common"
 	"github.com/tarantool/cartridge-cli/cli/context"
+	"github.com/tarantool/cartridge-cli/cli/docker"
 	"github.com/tarantool/cartridge-cli/cli/project"
 )
 
@@ -16,6 +21,7 @@ var (
 		TgzType: "tar.gz",
 		RpmType: "rpm",
 		DebType: "deb",
+		ApkType: "apk",
 	}
 
 	versionRgxps = []*regexp.Regexp{
@@ -28,6 +34,11 @@ var (
 			`^(?P<Major>\d+)\.(?P<Minor>\d+)\.(?P<Patch>\d+)-(?P<Count>\d+)-(?P<Hash>g\w+)$`,
 		),
 	}
+
+	releaseRgx = regexp.MustCompile(`^[0-9A-Za-z][0-9A-Za-z.+~_]*$`)
+
+	// matches the template action that uses version or release
+	imageTagVersionRgx = regexp.MustCompile(`{{[^}]*\.(Version|Release)\b[^}]*}}`)
 )
 
 func normalizeVersion(ctx *context.Ctx) error {
@@ -105,6 +116,28 @@ func detectVersion(ctx *context.Ctx) error {
 		return err
 	}
 
+	if ctx.Pack.ReleaseOverride != "" {
+		if err := overrideRelease(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// overrideRelease sets the release specified via --release
+// instead of the one determined by the version
+func overrideRelease(ctx *context.Ctx) error {
+	if !releaseRgx.MatchString(ctx.Pack.ReleaseOverride) {
+		return fmt.Errorf(
+			"Invalid release %q: it should start with alphanumeric character and contain "+
+				"only alphanumeric characters and '.', '+', '~', '_'", ctx.Pack.ReleaseOverride,
+		)
+	}
+
+	ctx.Pack.Release = ctx.Pack.ReleaseOverride
+	ctx.Pack.VersionRelease = fmt.Sprintf("%s-%s", ctx.Pack.Version, ctx.Pack.Release)
+
 	return nil
 }
 
@@ -114,6 +147,10 @@ func getPackageFullname(ctx *context.Ctx) string {
 		panic(project.InternalError("Unknown type: %s", ctx.Pack.Type))
 	}
 
+	if ctx.Pack.Type == TgzType && ctx.Pack.Compression == ZstdCompression {
+		ext = "tar.zst"
+	}
+
 	packageFullname := fmt.Sprintf(
 		"%s-%s",
 		ctx.Project.Name,
@@ -137,11 +174,100 @@ func getPackageFullname(ctx *context.Ctx) string {
 	return packageFullname
 }
 
-func getImageTags(ctx *context.Ctx) []string {
+// getResultDir returns the absolute path of the directory
+// the result package is written to (current directory by default).
+// The output directory is created if it doesn't exist (except dry run)
+func getResultDir(ctx *context.Ctx) (string, error) {
+	if ctx.Pack.OutputDir == "" {
+		curDir, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+
+		return curDir, nil
+	}
+
+	outputDir, err := filepath.Abs(ctx.Pack.OutputDir)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get output directory absolute path: %s", err)
+	}
+
+	if ctx.Pack.DryRun {
+		return outputDir, nil
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", fmt.Errorf("Failed to create output directory: %s", err)
+	}
+
+	return outputDir, nil
+}
+
+// imageTagCtx is the context of the --tag template
+type imageTagCtx struct {
+	Name    string
+	Version string
+	Release string
+
+	projectPath string
+}
+
+// GitSHA returns the short SHA of the project HEAD commit.
+// It's a method, so git is called only if the template uses it
+func (tagCtx imageTagCtx) GitSHA() (string, error) {
+	if !common.IsGitProject(tagCtx.projectPath) {
+		return "", fmt.Errorf("Project is not a git project")
+	} else if !common.GitIsInstalled() {
+		return "", fmt.Errorf("git not found")
+	}
+
+	gitRevParseCmd := exec.Command("git", "rev-parse", "--short", "HEAD")
+	gitSHA, err := common.GetOutput(gitRevParseCmd, &tagCtx.projectPath)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get commit SHA using git: %s", err)
+	}
+
+	return strings.TrimSpace(gitSHA), nil
+}
+
+// imageTagsUseVersion checks if some of the specified tags templates
+// uses {{ .Version }} or {{ .Release }}.
+// Version is detected only in this case, so the other templates
+// (e.g. {{ .Name }}) can be used in a non-git project
+func imageTagsUseVersion(imageTags []string) bool {
+	for _, imageTag := range imageTags {
+		if imageTagVersionRgx.MatchString(imageTag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getImageTags(ctx *context.Ctx) ([]string, error) {
 	var imageTags []string
 
 	if len(ctx.Pack.ImageTags) > 0 {
-		imageTags = ctx.Pack.ImageTags
+		tagCtx := imageTagCtx{
+			Name:    ctx.Project.Name,
+			Version: ctx.Pack.Version,
+			Release: ctx.Pack.Release,
+
+			projectPath: ctx.Project.Path,
+		}
+
+		for _, imageTagTmpl := range ctx.Pack.ImageTags {
+			imageTag, err := expandImageTag(imageTagTmpl, tagCtx)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid tag %q: %s", imageTagTmpl, err)
+			}
+
+			if err := docker.CheckImageReference(imageTag); err != nil {
+				return nil, fmt.Errorf("Invalid tag %q: %s", imageTagTmpl, err)
+			}
+
+			imageTags = append(imageTags, imageTag)
+		}
 	} else {
 		ImageTags := fmt.Sprintf(
 			"%s:%s",
@@ -160,15 +286,41 @@ func getImageTags(ctx *context.Ctx) []string {
 		imageTags = []string{ImageTags}
 	}
 
-	return imageTags
+	return imageTags, nil
+}
+
+// expandImageTag expands {{ .Name }}, {{ .Version }}, {{ .Release }}
+// and {{ .GitSHA }} in the image tag.
+// Unknown fields cause an error
+func expandImageTag(imageTagTmpl string, tagCtx imageTagCtx) (string, error) {
+	if !strings.Contains(imageTagTmpl, "{{") {
+		return imageTagTmpl, nil
+	}
+
+	tmpl, err := template.New("tag").Parse(imageTagTmpl)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, tagCtx); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
 }
 
 func checkTagVersionSuffix(ctx *context.Ctx) error {
-	if ctx.Pack.Type != DockerType {
+	if ctx.Pack.Type != DockerType || len(ctx.Pack.ImageTags) == 0 {
 		return nil
 	}
 
-	if len(ctx.Pack.ImageTags) > 0 && (ctx.Pack.Version != "" || ctx.Pack.Suffix != "") {
+	if ctx.Pack.Suffix != "" {
+		return fmt.Errorf(tagVersionSuffixErr)
+	}
+
+	// version and release can be used in the tags templates
+	if !imageTagsUseVersion(ctx.Pack.ImageTags) && (ctx.Pack.Version != "" || ctx.Pack.ReleaseOverride != "") {
 		return fmt.Errorf(tagVersionSuffixErr)
 	}
 
@@ -176,5 +328,5 @@ func checkTagVersionSuffix(ctx *context.Ctx) error {
 }
 
 const (
-	tagVersionSuffixErr = `You can specify only --version (and --suffix) or --tag options`
+	tagVersionSuffixErr = `You can specify only --version (--release and --suffix) or --tag options`
 )
@@ -1,6 +1,10 @@
 package pack
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -43,6 +47,111 @@ func TestGetPackageFullname(t *testing.T) {
 	assert.Equal("myapp-1.2.3-4-dev.rpm", getPackageFullname(&ctx))
 	ctx.Pack.Type = DebType
 	assert.Equal("myapp-1.2.3-4-dev.deb", getPackageFullname(&ctx))
+
+	// w/ zstd compression
+	ctx.Pack.Compression = ZstdCompression
+
+	ctx.Pack.Type = TgzType
+	assert.Equal("myapp-1.2.3-4-dev.tar.zst", getPackageFullname(&ctx))
+
+	ctx.Pack.Type = RpmType
+	assert.Equal("myapp-1.2.3-4-dev.rpm", getPackageFullname(&ctx))
+}
+
+func TestDetectVersion(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var ctx context.Ctx
+
+	// only version is specified
+	ctx.Pack.Version = "1.2.3"
+	assert.Nil(detectVersion(&ctx))
+	assert.Equal("1.2.3", ctx.Pack.Version)
+	assert.Equal("0", ctx.Pack.Release)
+	assert.Equal("1.2.3-0", ctx.Pack.VersionRelease)
+
+	ctx.Pack.Version = "1.2.3-4-g12345"
+	assert.Nil(detectVersion(&ctx))
+	assert.Equal("1.2.3", ctx.Pack.Version)
+	assert.Equal("4-g12345", ctx.Pack.Release)
+	assert.Equal("1.2.3-4-g12345", ctx.Pack.VersionRelease)
+
+	// release is overridden
+	ctx.Pack.Version = "1.2"
+	ctx.Pack.ReleaseOverride = "5"
+	assert.Nil(detectVersion(&ctx))
+	assert.Equal("1.2.0", ctx.Pack.Version)
+	assert.Equal("5", ctx.Pack.Release)
+	assert.Equal("1.2.0-5", ctx.Pack.VersionRelease)
+
+	ctx.Pack.Version = "1.2.3-4-g12345"
+	ctx.Pack.ReleaseOverride = "2.el7"
+	assert.Nil(detectVersion(&ctx))
+	assert.Equal("1.2.3", ctx.Pack.Version)
+	assert.Equal("2.el7", ctx.Pack.Release)
+	assert.Equal("1.2.3-2.el7", ctx.Pack.VersionRelease)
+
+	// invalid version
+	ctx.Pack.Version = "v1.2.3"
+	ctx.Pack.ReleaseOverride = ""
+	assert.EqualError(detectVersion(&ctx), "Version should be semantic (major.minor.patch[-count][-commit])")
+
+	// invalid release
+	for _, release := range []string{"1-2", ".1", "1 2", "1/2"} {
+		ctx.Pack.Version = "1.2.3"
+		ctx.Pack.ReleaseOverride = release
+		err := detectVersion(&ctx)
+		assert.NotNil(err, release)
+		assert.Contains(err.Error(), fmt.Sprintf("Invalid release %q", release))
+	}
+}
+
+func TestGetResultDir(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	var ctx context.Ctx
+
+	curDir, err := os.Getwd()
+	assert.Nil(err)
+
+	tmpDir, err := ioutil.TempDir("", "output")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// default
+	resDir, err := getResultDir(&ctx)
+	assert.Nil(err)
+	assert.Equal(curDir, resDir)
+
+	// absolute path
+	ctx.Pack.OutputDir = filepath.Join(tmpDir, "abs", "dist")
+
+	resDir, err = getResultDir(&ctx)
+	assert.Nil(err)
+	assert.Equal(ctx.Pack.OutputDir, resDir)
+	assert.DirExists(resDir)
+
+	// relative path
+	ctx.Pack.OutputDir, err = filepath.Rel(curDir, filepath.Join(tmpDir, "rel", "dist"))
+	assert.Nil(err)
+
+	resDir, err = getResultDir(&ctx)
+	assert.Nil(err)
+	assert.Equal(filepath.Join(tmpDir, "rel", "dist"), resDir)
+	assert.DirExists(resDir)
+
+	// output dir is a file
+	ctx.Pack.OutputDir = filepath.Join(tmpDir, "file")
+	assert.Nil(ioutil.WriteFile(ctx.Pack.OutputDir, []byte{}, 0644))
+
+	_, err = getResultDir(&ctx)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Failed to create output directory")
 }
 
 func TestGetImageTags(t *testing.T) {
@@ -50,6 +159,8 @@ func TestGetImageTags(t *testing.T) {
 	assert := assert.New(t)
 
 	var ctx context.Ctx
+	var imageTags []string
+	var err error
 
 	// TODO: internal error on bad type
 
@@ -59,7 +170,9 @@ func TestGetImageTags(t *testing.T) {
 	ctx.Pack.Suffix = ""
 	ctx.Pack.ImageTags = []string{}
 
-	assert.ElementsMatch([]string{"myapp:1.2.3-4"}, getImageTags(&ctx))
+	imageTags, err = getImageTags(&ctx)
+	assert.Nil(err)
+	assert.ElementsMatch([]string{"myapp:1.2.3-4"}, imageTags)
 
 	// VersionRelease + Suffix
 	ctx.Project.Name = "myapp"
@@ -67,7 +180,9 @@ func TestGetImageTags(t *testing.T) {
 	ctx.Pack.Suffix = "dev"
 	ctx.Pack.ImageTags = []string{}
 
-	assert.ElementsMatch([]string{"myapp:1.2.3-4-dev"}, getImageTags(&ctx))
+	imageTags, err = getImageTags(&ctx)
+	assert.Nil(err)
+	assert.ElementsMatch([]string{"myapp:1.2.3-4-dev"}, imageTags)
 
 	// ImageTags
 	ctx.Project.Name = "myapp"
@@ -75,5 +190,109 @@ func TestGetImageTags(t *testing.T) {
 	ctx.Pack.Suffix = ""
 	ctx.Pack.ImageTags = []string{"my-first-image", "my-lovely-image"}
 
-	assert.ElementsMatch([]string{"my-first-image", "my-lovely-image"}, getImageTags(&ctx))
+	imageTags, err = getImageTags(&ctx)
+	assert.Nil(err)
+	assert.ElementsMatch([]string{"my-first-image", "my-lovely-image"}, imageTags)
+
+	// invalid ImageTags
+	ctx.Pack.ImageTags = []string{"My-Image"}
+
+	_, err = getImageTags(&ctx)
+	assert.EqualError(err, `Invalid tag "My-Image": Invalid image reference "My-Image"`)
+}
+
+func TestGetImageTagsTemplated(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	var ctx context.Ctx
+	var imageTags []string
+	var err error
+
+	projectPath, err := ioutil.TempDir("", "project")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(projectPath)
+
+	ctx.Project.Name = "myapp"
+	ctx.Project.Path = projectPath
+	ctx.Pack.Version = "1.2.3"
+	ctx.Pack.Release = "4"
+	ctx.Pack.VersionRelease = "1.2.3-4"
+
+	ctx.Pack.ImageTags = []string{
+		"registry/{{ .Name }}:{{ .Version }}-{{ .Release }}",
+		"registry/{{ .Name }}:latest",
+		"myapp:literal",
+	}
+
+	imageTags, err = getImageTags(&ctx)
+	assert.Nil(err)
+	assert.Equal([]string{"registry/myapp:1.2.3-4", "registry/myapp:latest", "myapp:literal"}, imageTags)
+
+	// unknown field
+	ctx.Pack.ImageTags = []string{"myapp:{{ .Commit }}"}
+
+	_, err = getImageTags(&ctx)
+	assert.EqualError(err, `Invalid tag "myapp:{{ .Commit }}": template: tag:1:9: `+
+		`executing "tag" at <.Commit>: can't evaluate field Commit in type pack.imageTagCtx`)
+
+	// bad template
+	ctx.Pack.ImageTags = []string{"myapp:{{ .Version"}
+
+	_, err = getImageTags(&ctx)
+	assert.EqualError(err, `Invalid tag "myapp:{{ .Version": template: tag:1: unclosed action`)
+
+	// GitSHA in the non-git project
+	ctx.Pack.ImageTags = []string{"myapp:{{ .GitSHA }}"}
+
+	_, err = getImageTags(&ctx)
+	assert.EqualError(err, `Invalid tag "myapp:{{ .GitSHA }}": template: tag:1:9: `+
+		`executing "tag" at <.GitSHA>: error calling GitSHA: Project is not a git project`)
+
+	// expanded tag is invalid
+	ctx.Pack.ImageTags = []string{"myapp:{{ .Version }}:{{ .Release }}"}
+
+	_, err = getImageTags(&ctx)
+	assert.EqualError(err, `Invalid tag "myapp:{{ .Version }}:{{ .Release }}": `+
+		`Invalid image reference "myapp:1.2.3:4"`)
+}
+
+func TestImageTagsUseVersion(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	assert.False(imageTagsUseVersion([]string{}))
+	assert.False(imageTagsUseVersion([]string{"myapp:latest", "{{ .Name }}:{{ .GitSHA }}"}))
+	assert.False(imageTagsUseVersion([]string{"myapp:Version"}))
+
+	assert.True(imageTagsUseVersion([]string{"myapp:latest", "myapp:{{ .Version }}"}))
+	assert.True(imageTagsUseVersion([]string{"myapp:{{.Release}}"}))
+	assert.True(imageTagsUseVersion([]string{`myapp:{{ printf "%s-%s" .Version .Release }}`}))
+}
+
+func TestCheckTagVersionSuffix(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	var ctx context.Ctx
+
+	ctx.Pack.Type = DockerType
+	ctx.Pack.Version = "1.2.3"
+	assert.Nil(checkTagVersionSuffix(&ctx))
+
+	ctx.Pack.ImageTags = []string{"myapp:latest"}
+	assert.EqualError(checkTagVersionSuffix(&ctx), tagVersionSuffixErr)
+
+	// version can be used in the template
+	ctx.Pack.ImageTags = []string{"myapp:latest", "myapp:{{ .Version }}"}
+	assert.Nil(checkTagVersionSuffix(&ctx))
+
+	// template doesn't use version
+	ctx.Pack.ImageTags = []string{"{{ .Name }}:latest"}
+	assert.EqualError(checkTagVersionSuffix(&ctx), tagVersionSuffixErr)
+
+	ctx.Pack.Suffix = "dev"
+	assert.EqualError(checkTagVersionSuffix(&ctx), tagVersionSuffixErr)
 }
@@ -49,24 +49,46 @@ func packDeb(ctx *context.Ctx) error {
 		return err
 	}
 
+	if err := initSysusersDir(dataDirPath, ctx); err != nil {
+		return err
+	}
+
+	if len(ctx.Pack.Transforms) > 0 {
+		if err := applyTransforms(dataDirPath, true, ctx.Pack.Transforms,
+			getEnsuredDirsPaths(appDirPath, ctx.Pack.EnsureDirs)); err != nil {
+			return err
+		}
+	}
+
+	if fileModesAreSpecified(ctx) {
+		if err := normalizeFileModes(dataDirPath, ctx); err != nil {
+			return err
+		}
+	}
+
 	//  data.tar.gz
 	log.Debugf("Create data archive")
 	dataArchivePath := filepath.Join(ctx.Pack.PackageFilesDir, dataArchiveName)
-	err = common.WriteTgzArchive(dataDirPath, dataArchivePath)
+	err = common.WriteTgzArchive(dataDirPath, dataArchivePath, ctx.Pack.CompressionLevel)
 	if err != nil {
 		return err
 	}
 
 	// control dir
 	controlDirPath := filepath.Join(ctx.Pack.PackageFilesDir, controlDirName)
-	if err := initControlDir(controlDirPath, ctx); err != nil {
+	if err := initControlDir(controlDirPath, dataDirPath, ctx); err != nil {
+		return err
+	}
+
+	// conffiles
+	if err := writeDebConffiles(controlDirPath, dataDirPath, ctx); err != nil {
 		return err
 	}
 
 	// control.tar.gz
 	log.Debugf("Create deb control directory archive")
 	controlArchivePath := filepath.Join(ctx.Pack.PackageFilesDir, controlArchiveName)
-	err = common.WriteTgzArchive(controlDirPath, controlArchivePath)
+	err = common.WriteTgzArchive(controlDirPath, controlArchivePath, common.DefaultCompressionLevel)
 	if err != nil {
 		return err
 	}
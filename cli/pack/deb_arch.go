@@ -0,0 +1,79 @@
+package pack
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+const (
+	debArchAll = "all"
+)
+
+var (
+	// debArchs are the DEB architectures by the platform ARCH[/VARIANT]
+	debArchs = map[string]string{
+		"amd64":    "amd64",
+		"386":      "i386",
+		"arm64":    "arm64",
+		"arm64/v8": "arm64",
+		"arm":      "armhf",
+		"arm/v7":   "armhf",
+		"arm/v6":   "armel",
+		"arm/v5":   "armel",
+		"ppc64le":  "ppc64el",
+		"s390x":    "s390x",
+		"riscv64":  "riscv64",
+		"mips64le": "mips64el",
+	}
+)
+
+// getDebArch returns the Architecture control field value.
+// It's specified by --deb-arch or detected by the target platform:
+// the one application is built for in docker or the current machine one.
+// Packages are always built for linux, so only the current arch is used
+func getDebArch(ctx *context.Ctx) (string, error) {
+	if ctx.Pack.DebArch != "" {
+		return ctx.Pack.DebArch, nil
+	}
+
+	platform := ctx.Docker.Platform
+	if platform == "" {
+		platform = fmt.Sprintf("linux/%s", runtime.GOARCH)
+	}
+
+	arch, err := getPlatformDebArch(platform)
+	if err != nil {
+		return "", fmt.Errorf("%s. Please, specify it via --deb-arch", err)
+	}
+
+	return arch, nil
+}
+
+// getPlatformDebArch returns the DEB architecture by the OS/ARCH[/VARIANT] platform
+func getPlatformDebArch(platform string) (string, error) {
+	parts := strings.SplitN(platform, "/", 2)
+	if len(parts) != 2 || parts[0] != "linux" {
+		return "", fmt.Errorf("Unable to detect DEB architecture for %s platform", platform)
+	}
+
+	if arch, found := debArchs[parts[1]]; found {
+		return arch, nil
+	}
+
+	return "", fmt.Errorf("Unable to detect DEB architecture for %s platform", platform)
+}
+
+// getDebMultiArch returns the Multi-Arch control field value.
+// Architecture-dependent package is marked as foreign since it can satisfy
+// dependencies of the packages of any architecture (e.g. it provides services).
+// Architecture-independent package doesn't need this field
+func getDebMultiArch(arch string) string {
+	if arch == debArchAll {
+		return ""
+	}
+
+	return "foreign"
+}
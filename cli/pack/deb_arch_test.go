@@ -0,0 +1,48 @@
+package pack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func TestGetDebArch(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	for platform, expArch := range map[string]string{
+		"linux/amd64":    "amd64",
+		"linux/arm64":    "arm64",
+		"linux/arm64/v8": "arm64",
+		"linux/arm/v7":   "armhf",
+		"linux/arm/v6":   "armel",
+		"linux/386":      "i386",
+		"linux/ppc64le":  "ppc64el",
+	} {
+		arch, err := getPlatformDebArch(platform)
+		assert.Nil(err, platform)
+		assert.Equal(expArch, arch, platform)
+	}
+
+	_, err := getPlatformDebArch("windows/amd64")
+	assert.EqualError(err, "Unable to detect DEB architecture for windows/amd64 platform")
+
+	var ctx context.Ctx
+
+	ctx.Docker.Platform = "linux/mips"
+	_, err = getDebArch(&ctx)
+	assert.EqualError(err,
+		"Unable to detect DEB architecture for linux/mips platform. Please, specify it via --deb-arch")
+
+	// --deb-arch overrides the detected architecture
+	ctx.Pack.DebArch = "mips"
+	arch, err := getDebArch(&ctx)
+	assert.Nil(err)
+	assert.Equal("mips", arch)
+
+	assert.Equal("foreign", getDebMultiArch("amd64"))
+	assert.Equal("", getDebMultiArch("all"))
+}
@@ -2,7 +2,10 @@ package pack
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/apex/log"
 	"github.com/tarantool/cartridge-cli/cli/common"
@@ -12,6 +15,15 @@ import (
 )
 
 var (
+	// debOperators are the DEB relations by the dependency operators
+	debOperators = map[string]string{
+		"<":  "<<",
+		"<=": "<=",
+		"=":  "=",
+		">=": ">=",
+		">":  ">>",
+	}
+
 	debControlDirTemplate = templates.FileTreeTemplate{
 		Dirs: []templates.DirTemplate{},
 		Files: []templates.FileTemplate{
@@ -34,49 +46,242 @@ var (
 	}
 )
 
-func initControlDir(destDirPath string, ctx *context.Ctx) error {
+func initControlDir(destDirPath, dataDirPath string, ctx *context.Ctx) error {
 	log.Debugf("Create DEB control directory")
 	if err := os.MkdirAll(destDirPath, 0755); err != nil {
 		return fmt.Errorf("Failed to create DEB control directory: %s", err)
 	}
 
+	installedSize, err := getDebInstalledSize(dataDirPath)
+	if err != nil {
+		return fmt.Errorf("Failed to compute DEB installed size: %s", err)
+	}
+
+	depends, err := getDebDepends(ctx)
+	if err != nil {
+		return err
+	}
+
+	arch, err := getDebArch(ctx)
+	if err != nil {
+		return err
+	}
+
 	debControlCtx := map[string]interface{}{
-		"Name":         ctx.Project.Name,
-		"Version":      ctx.Pack.VersionRelease,
-		"Maintainer":   defaultMaintainer,
-		"Architecture": defaultArch,
-		"Depends":      "",
+		"Name":          ctx.Project.Name,
+		"Version":       ctx.Pack.VersionRelease,
+		"Maintainer":    defaultMaintainer,
+		"Architecture":  arch,
+		"MultiArch":     getDebMultiArch(arch),
+		"InstalledSize": installedSize,
+		"Depends":       strings.Join(depends, ", "),
+	}
+
+	if err := debControlDirTemplate.Instantiate(destDirPath, debControlCtx); err != nil {
+		return fmt.Errorf("Failed to instantiate DEB control directory: %s", err)
+	}
+
+	if err := addDebUserScripts(destDirPath, ctx); err != nil {
+		return err
 	}
 
-	if !ctx.Tarantool.TarantoolIsEnterprise {
+	return nil
+}
+
+// getDebDepends returns dependencies in the Depends control field format.
+// Tarantool dependency is added if it isn't specified by --deps or --deps-file
+func getDebDepends(ctx *context.Ctx) ([]string, error) {
+	userDeps, err := project.GetUserDependencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var depends []string
+
+	if !ctx.Tarantool.TarantoolIsEnterprise && !project.DependsOn(userDeps, "tarantool") {
 		minTarantoolVersion := ctx.Tarantool.TarantoolVersion
 		maxTarantoolVersion, err := common.GetNextMajorVersion(minTarantoolVersion)
 		if err != nil {
-			return project.InternalError("Failed to get next Tarantool major version: %s", err)
+			return nil, project.InternalError("Failed to get next Tarantool major version: %s", err)
 		}
 
-		debControlCtx["Depends"] = fmt.Sprintf(
-			"tarantool (>= %s), tarantool (<< %s)",
-			minTarantoolVersion,
-			maxTarantoolVersion,
+		depends = append(depends,
+			fmt.Sprintf("tarantool (>= %s)", minTarantoolVersion),
+			fmt.Sprintf("tarantool (<< %s)", maxTarantoolVersion),
 		)
 	}
 
-	if err := debControlDirTemplate.Instantiate(destDirPath, debControlCtx); err != nil {
-		return fmt.Errorf("Failed to instantiate DEB control directory: %s", err)
+	for _, dep := range userDeps {
+		if dep.Operator == "" {
+			depends = append(depends, dep.Name)
+		} else {
+			depends = append(depends, fmt.Sprintf("%s (%s %s)", dep.Name, debOperators[dep.Operator], dep.Version))
+		}
+	}
+
+	return depends, nil
+}
+
+// addDebUserScripts appends user-provided install scripts to the generated
+// maintainer scripts. If the script isn't generated, it's created
+func addDebUserScripts(controlDirPath string, ctx *context.Ctx) error {
+	for _, kind := range project.InstallScripts {
+		section, err := project.GetUserScriptSection(ctx, kind)
+		if err != nil {
+			return err
+		}
+
+		if section == "" {
+			continue
+		}
+
+		log.Debugf("Add user-provided %s script", kind)
+
+		scriptPath := filepath.Join(controlDirPath, kind)
+		if _, err := os.Stat(scriptPath); os.IsNotExist(err) {
+			section = "#!/bin/sh\n" + section
+		} else if err != nil {
+			return fmt.Errorf("Failed to use DEB %s script: %s", kind, err)
+		}
+
+		scriptFile, err := os.OpenFile(scriptPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0755)
+		if err != nil {
+			return fmt.Errorf("Failed to open DEB %s script: %s", kind, err)
+		}
+
+		_, err = scriptFile.WriteString(section)
+		scriptFile.Close()
+
+		if err != nil {
+			return fmt.Errorf("Failed to write DEB %s script: %s", kind, err)
+		}
+	}
+
+	return nil
+}
+
+// getDebInstalledSize returns the Installed-Size control field value.
+// It's computed the same way as dpkg-gencontrol does: the size of each regular
+// file is rounded up to KiB and each directory, symlink or other file counts as 1 KiB.
+// The data directory itself isn't counted since it's the filesystem root
+func getDebInstalledSize(dataDirPath string) (int64, error) {
+	var installedSize int64
+
+	err := filepath.Walk(dataDirPath, func(filePath string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if filePath == dataDirPath {
+			return nil
+		}
+
+		if fileInfo.Mode().IsRegular() {
+			installedSize += (fileInfo.Size() + 1023) / 1024
+		} else {
+			installedSize++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return installedSize, nil
+}
+
+// writeDebConffiles writes the conffiles control file that
+// lists files specified by --deb-conffiles-from and --deb-conffile flags
+func writeDebConffiles(controlDirPath, dataDirPath string, ctx *context.Ctx) error {
+	conffiles, err := getDebConffiles(dataDirPath, ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to collect DEB conffiles: %s", err)
+	}
+
+	if len(conffiles) == 0 {
+		return nil
+	}
+
+	log.Debugf("Create DEB conffiles file")
+
+	conffilesContent := strings.Join(conffiles, "\n") + "\n"
+	conffilesPath := filepath.Join(controlDirPath, conffilesFileName)
+
+	if err := ioutil.WriteFile(conffilesPath, []byte(conffilesContent), 0644); err != nil {
+		return fmt.Errorf("Failed to write DEB conffiles file: %s", err)
 	}
 
 	return nil
 }
 
+// getDebConffiles returns absolute paths of the files that should be marked as conffiles.
+// Relative paths are considered relative to the application directory.
+// All files should be delivered in the package.
+func getDebConffiles(dataDirPath string, ctx *context.Ctx) ([]string, error) {
+	var paths []string
+
+	if ctx.Pack.DebConffilesFrom != "" {
+		content, err := common.GetFileContent(ctx.Pack.DebConffilesFrom)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read conffiles list: %s", err)
+		}
+
+		for _, line := range strings.Split(content, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			paths = append(paths, line)
+		}
+	}
+
+	paths = append(paths, ctx.Pack.DebConffiles...)
+
+	conffiles := make([]string, 0, len(paths))
+	added := make(map[string]bool)
+
+	for _, path := range paths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(ctx.Running.AppDir, path)
+		}
+		path = filepath.Clean(path)
+
+		if added[path] {
+			continue
+		}
+
+		fileInfo, err := os.Stat(filepath.Join(dataDirPath, path))
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("File %s isn't delivered in the package", path)
+		} else if err != nil {
+			return nil, fmt.Errorf("Failed to use file %s: %s", path, err)
+		} else if !fileInfo.Mode().IsRegular() {
+			return nil, fmt.Errorf("%s isn't a regular file", path)
+		}
+
+		conffiles = append(conffiles, path)
+		added[path] = true
+	}
+
+	return conffiles, nil
+}
+
 const (
+	conffilesFileName = "conffiles"
+
 	defaultMaintainer = "Tarantool Cartridge Developer"
-	defaultArch       = "all"
 
 	controlFileContent = `Package: {{ .Name }}
 Version: {{ .Version }}
 Maintainer: {{ .Maintainer }}
 Architecture: {{ .Architecture }}
+{{- if .MultiArch }}
+Multi-Arch: {{ .MultiArch }}
+{{- end }}
+Installed-Size: {{ .InstalledSize }}
 Description: Tarantool Cartridge app: {{ .Name }}
 Depends: {{ .Depends }}
 
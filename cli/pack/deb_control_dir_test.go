@@ -0,0 +1,253 @@
+package pack
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func TestWriteDebConffiles(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "deb")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	dataDirPath := filepath.Join(tmpDir, "data")
+	controlDirPath := filepath.Join(tmpDir, "control")
+	appDir := "/usr/share/tarantool/myapp"
+
+	files := []string{
+		filepath.Join(appDir, "init.lua"),
+		filepath.Join(appDir, "config", "app.yml"),
+		filepath.Join(appDir, "config", "roles.yml"),
+		"/etc/tarantool/conf.d/myapp.yml",
+	}
+
+	for _, file := range files {
+		filePath := filepath.Join(dataDirPath, file)
+		assert.Nil(os.MkdirAll(filepath.Dir(filePath), 0755))
+		assert.Nil(ioutil.WriteFile(filePath, []byte(""), 0644))
+	}
+	assert.Nil(os.MkdirAll(controlDirPath, 0755))
+
+	conffilesListPath := filepath.Join(tmpDir, "conffiles.txt")
+	conffilesList := "# application configs\nconfig/app.yml\n\n/etc/tarantool/conf.d/myapp.yml\n"
+	assert.Nil(ioutil.WriteFile(conffilesListPath, []byte(conffilesList), 0644))
+
+	var ctx context.Ctx
+	ctx.Running.AppDir = appDir
+
+	// no conffiles specified
+	assert.Nil(writeDebConffiles(controlDirPath, dataDirPath, &ctx))
+	_, err = os.Stat(filepath.Join(controlDirPath, conffilesFileName))
+	assert.True(os.IsNotExist(err))
+
+	// conffiles are specified
+	ctx.Pack.DebConffilesFrom = conffilesListPath
+	ctx.Pack.DebConffiles = []string{"config/roles.yml", "config/app.yml"}
+
+	assert.Nil(writeDebConffiles(controlDirPath, dataDirPath, &ctx))
+
+	conffilesContent, err := ioutil.ReadFile(filepath.Join(controlDirPath, conffilesFileName))
+	assert.Nil(err)
+	assert.Equal(
+		"/usr/share/tarantool/myapp/config/app.yml\n"+
+			"/etc/tarantool/conf.d/myapp.yml\n"+
+			"/usr/share/tarantool/myapp/config/roles.yml\n",
+		string(conffilesContent),
+	)
+	assert.NotContains(string(conffilesContent), "init.lua")
+
+	// file isn't delivered in the package
+	ctx.Pack.DebConffiles = []string{"config/unknown.yml"}
+
+	err = writeDebConffiles(controlDirPath, dataDirPath, &ctx)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "File /usr/share/tarantool/myapp/config/unknown.yml isn't delivered in the package")
+
+	// directory
+	ctx.Pack.DebConffilesFrom = ""
+	ctx.Pack.DebConffiles = []string{"config"}
+
+	err = writeDebConffiles(controlDirPath, dataDirPath, &ctx)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "/usr/share/tarantool/myapp/config isn't a regular file")
+}
+
+func TestGetDebInstalledSize(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "deb")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	dataDirPath := filepath.Join(tmpDir, "data")
+	controlDirPath := filepath.Join(tmpDir, "control")
+	appDirPath := filepath.Join(dataDirPath, "usr", "share", "tarantool", "myapp")
+
+	// 4 directories: usr, share, tarantool, myapp
+	assert.Nil(os.MkdirAll(appDirPath, 0755))
+
+	// file sizes are rounded up to KiB: 0 + 1 + 1 + 2 + 3
+	fileSizes := map[string]int{
+		"empty.lua": 0,
+		"init.lua":  1,
+		"kib.bin":   1024,
+		"more.bin":  1025,
+		"big.bin":   3000,
+	}
+
+	for fileName, size := range fileSizes {
+		content := make([]byte, size)
+		assert.Nil(ioutil.WriteFile(filepath.Join(appDirPath, fileName), content, 0644))
+	}
+
+	// symlink counts as 1 KiB
+	assert.Nil(os.Symlink("init.lua", filepath.Join(appDirPath, "link.lua")))
+
+	installedSize, err := getDebInstalledSize(dataDirPath)
+	assert.Nil(err)
+	assert.EqualValues(4+(0+1+1+2+3)+1, installedSize)
+
+	// Installed-Size is written to the control file
+	var ctx context.Ctx
+	ctx.Project.Name = "myapp"
+	ctx.Pack.VersionRelease = "1.0.0-1"
+	ctx.Tarantool.TarantoolIsEnterprise = true
+
+	assert.Nil(initControlDir(controlDirPath, dataDirPath, &ctx))
+
+	controlContent, err := ioutil.ReadFile(filepath.Join(controlDirPath, "control"))
+	assert.Nil(err)
+	assert.Contains(string(controlContent), "Installed-Size: 12\n")
+
+	// Architecture is detected by the build platform
+	ctx.Docker.Platform = "linux/arm64"
+	assert.Nil(initControlDir(controlDirPath, dataDirPath, &ctx))
+
+	controlContent, err = ioutil.ReadFile(filepath.Join(controlDirPath, "control"))
+	assert.Nil(err)
+	assert.Contains(string(controlContent), "Architecture: arm64\nMulti-Arch: foreign\nInstalled-Size: 12\n")
+
+	// Multi-Arch isn't set for the architecture-independent package
+	ctx.Pack.DebArch = "all"
+	assert.Nil(initControlDir(controlDirPath, dataDirPath, &ctx))
+
+	controlContent, err = ioutil.ReadFile(filepath.Join(controlDirPath, "control"))
+	assert.Nil(err)
+	assert.Contains(string(controlContent), "Architecture: all\nInstalled-Size: 12\n")
+}
+
+func TestAddDebUserScripts(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "deb")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	controlDirPath := filepath.Join(tmpDir, "control")
+	assert.Nil(os.MkdirAll(controlDirPath, 0755))
+
+	generatedPostInst := "/bin/sh -c 'chown -R root:root /usr/share/tarantool/myapp'\n"
+	assert.Nil(ioutil.WriteFile(filepath.Join(controlDirPath, "postinst"), []byte(generatedPostInst), 0755))
+
+	var ctx context.Ctx
+
+	ctx.Pack.PostInstScriptPath = filepath.Join(tmpDir, "postinst.sh")
+	assert.Nil(ioutil.WriteFile(ctx.Pack.PostInstScriptPath, []byte("ldconfig"), 0644))
+
+	ctx.Pack.PostRmScriptPath = filepath.Join(tmpDir, "postrm.sh")
+	assert.Nil(ioutil.WriteFile(ctx.Pack.PostRmScriptPath, []byte("userdel myapp\n"), 0644))
+
+	assert.Nil(addDebUserScripts(controlDirPath, &ctx))
+
+	// user script is appended to the generated one
+	content, err := ioutil.ReadFile(filepath.Join(controlDirPath, "postinst"))
+	assert.Nil(err)
+	assert.Equal(generatedPostInst+`
+# ---- BEGIN user-provided postinst script (postinst.sh) ----
+ldconfig
+# ---- END user-provided postinst script ----
+`, string(content))
+
+	// script that isn't generated is created
+	content, err = ioutil.ReadFile(filepath.Join(controlDirPath, "postrm"))
+	assert.Nil(err)
+	assert.Equal(`#!/bin/sh
+
+# ---- BEGIN user-provided postrm script (postrm.sh) ----
+userdel myapp
+# ---- END user-provided postrm script ----
+`, string(content))
+
+	fileInfo, err := os.Stat(filepath.Join(controlDirPath, "postrm"))
+	assert.Nil(err)
+	assert.Equal(os.FileMode(0755), fileInfo.Mode().Perm())
+
+	_, err = os.Stat(filepath.Join(controlDirPath, "prerm"))
+	assert.True(os.IsNotExist(err))
+
+	// missing script
+	ctx.Pack.PreRmScriptPath = filepath.Join(tmpDir, "missing.sh")
+	err = addDebUserScripts(controlDirPath, &ctx)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Failed to read --prerm script")
+	assert.Contains(err.Error(), "It's run after the cartridge-generated prerm script")
+}
+
+func TestGetDebDepends(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var ctx context.Ctx
+	ctx.Tarantool.TarantoolVersion = "2.4.2"
+
+	depends, err := getDebDepends(&ctx)
+	assert.Nil(err)
+	assert.Equal([]string{"tarantool (>= 2.4.2)", "tarantool (<< 3)"}, depends)
+
+	ctx.Pack.Deps = []string{"logrotate", "openssl > 1.1", "libssl = 1.1.1", "curl <= 7"}
+
+	depends, err = getDebDepends(&ctx)
+	assert.Nil(err)
+	assert.Equal([]string{
+		"tarantool (>= 2.4.2)",
+		"tarantool (<< 3)",
+		"logrotate",
+		"openssl (>> 1.1)",
+		"libssl (= 1.1.1)",
+		"curl (<= 7)",
+	}, depends)
+
+	// Tarantool dependency is overridden
+	ctx.Pack.Deps = []string{"tarantool >= 2.5", "tarantool < 2.9"}
+
+	depends, err = getDebDepends(&ctx)
+	assert.Nil(err)
+	assert.Equal([]string{"tarantool (>= 2.5)", "tarantool (<< 2.9)"}, depends)
+
+	ctx.Pack.Deps = []string{"tarantool", "tarantool"}
+
+	depends, err = getDebDepends(&ctx)
+	assert.Nil(err)
+	assert.Equal([]string{"tarantool"}, depends)
+}
@@ -2,11 +2,13 @@ package pack
 
 import (
 	"fmt"
+	"io/ioutil"
 	"path/filepath"
 	"strings"
 
 	"github.com/apex/log"
 
+	"github.com/tarantool/cartridge-cli/cli/common"
 	"github.com/tarantool/cartridge-cli/cli/context"
 	"github.com/tarantool/cartridge-cli/cli/docker"
 	"github.com/tarantool/cartridge-cli/cli/project"
@@ -17,42 +19,29 @@ func packDocker(ctx *context.Ctx) error {
 		return err
 	}
 
-	if ctx.Pack.DockerFrom != "" {
+	// base image check is skipped if it's replaced by --base-image
+	if ctx.Pack.DockerFrom != "" && ctx.Pack.BaseImage == "" {
 		if err := project.CheckBaseDockerfile(ctx.Pack.DockerFrom); err != nil {
 			return fmt.Errorf("Invalid base runtime Dockerfile %s: %s", ctx.Pack.DockerFrom, err)
 		}
 	}
 
+	if len(ctx.Docker.Platforms) > 0 {
+		return packMultiPlatformDocker(ctx)
+	}
+
 	// app dir
 	appDirPath := filepath.Join(ctx.Pack.PackageFilesDir, ctx.Project.Name)
 	if err := initAppDir(appDirPath, ctx); err != nil {
 		return err
 	}
 
-	runtimeContext := map[string]interface{}{
-		"Name":              ctx.Project.Name,
-		"TmpFilesConf":      tmpFilesConfContent,
-		"AppDir":            ctx.Running.AppDir,
-		"AppEntrypointPath": project.GetAppEntrypointPath(ctx),
-		"WorkDir":           project.GetInstanceWorkDir(ctx, "${TARANTOOL_INSTANCE_NAME}"),
-		"PidFile":           project.GetInstancePidFile(ctx, "${TARANTOOL_INSTANCE_NAME}"),
-		"ConsoleSock":       project.GetInstanceConsoleSock(ctx, "${TARANTOOL_INSTANCE_NAME}"),
-	}
-
-	// get runtime image Dockerfile template
+	// create runtime image Dockerfile
 	log.Debugf("Create runtime image Dockerfile")
 
 	runtimeImageDockerfileName := fmt.Sprintf("Dockerfile.%s", ctx.Pack.ID)
-	dockerfileTemplate, err := project.GetRuntimeImageDockerfileTemplate(ctx)
-
-	if err != nil {
-		return fmt.Errorf("Failed to create runtime image Dockerfile: %s", err)
-	}
-
-	dockerfileTemplate.Path = runtimeImageDockerfileName
-
-	if err := dockerfileTemplate.Instantiate(ctx.Build.Dir, runtimeContext); err != nil {
-		return fmt.Errorf("Failed to create build image Dockerfile: %s", err)
+	if err := createRuntimeImageDockerfile(runtimeImageDockerfileName, getRuntimeContext(ctx), ctx); err != nil {
+		return err
 	}
 	defer project.RemoveTmpPath(
 		filepath.Join(ctx.Build.Dir, runtimeImageDockerfileName),
@@ -62,11 +51,13 @@ func packDocker(ctx *context.Ctx) error {
 	// create runtime image
 	log.Infof("Build result image %s", formatImageTags(ctx.Pack.ResImageTags))
 
-	err = docker.BuildImage(docker.BuildOpts{
+	err := docker.BuildImage(docker.BuildOpts{
 		Tag:        ctx.Pack.ResImageTags,
 		Dockerfile: runtimeImageDockerfileName,
 		NoCache:    ctx.Docker.NoCache,
 		CacheFrom:  ctx.Docker.CacheFrom,
+		BuildArgs:  ctx.Docker.BuildArgs,
+		Secrets:    ctx.Docker.Secrets,
 
 		BuildDir:   ctx.Build.Dir,
 		TmpDir:     ctx.Cli.TmpDir,
@@ -79,6 +70,93 @@ func packDocker(ctx *context.Ctx) error {
 
 	log.Infof("Created result image %s", formatImageTags(ctx.Pack.ResImageTags))
 
+	if ctx.Pack.Push {
+		if err := pushImageTags(ctx.Pack.ResImageTags, ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pushImageTags pushes all image tags and reports the pushed digests.
+// Push failures are reported after trying all tags,
+// the built image isn't removed in this case
+func pushImageTags(imageTags []string, ctx *context.Ctx) error {
+	var failedTags []string
+
+	for _, imageTag := range imageTags {
+		log.Infof("Push image %s", imageTag)
+
+		digest, err := docker.PushImage(imageTag, ctx.Cli.Verbose)
+		if err != nil {
+			log.Errorf("Failed to push image %s: %s", imageTag, err)
+			failedTags = append(failedTags, imageTag)
+			continue
+		}
+
+		if digest != "" {
+			log.Infof("Pushed image %s: %s", imageTag, digest)
+		} else {
+			log.Infof("Pushed image %s", imageTag)
+		}
+	}
+
+	if len(failedTags) > 0 {
+		return fmt.Errorf("Failed to push image %s. Built image is kept locally",
+			formatImageTags(failedTags))
+	}
+
+	return nil
+}
+
+func getRuntimeContext(ctx *context.Ctx) map[string]interface{} {
+	return map[string]interface{}{
+		"Name":              ctx.Project.Name,
+		"TmpFilesConf":      strings.Join(getDefaultTmpfilesEntries(ctx), "\n"),
+		"AppDir":            ctx.Running.AppDir,
+		"AppEntrypointPath": project.GetAppEntrypointPath(ctx),
+		"WorkDir":           project.GetInstanceWorkDir(ctx, "${TARANTOOL_INSTANCE_NAME}"),
+		"PidFile":           project.GetInstancePidFile(ctx, "${TARANTOOL_INSTANCE_NAME}"),
+		"ConsoleSock":       project.GetInstanceConsoleSock(ctx, "${TARANTOOL_INSTANCE_NAME}"),
+	}
+}
+
+// createRuntimeImageDockerfile creates runtime image Dockerfile in the build directory.
+// If custom Dockerfile is specified, it's used as is, otherwise Dockerfile is generated
+func createRuntimeImageDockerfile(dockerfileName string, runtimeContext map[string]interface{}, ctx *context.Ctx) error {
+	dockerfilePath := filepath.Join(ctx.Build.Dir, dockerfileName)
+
+	if ctx.Pack.RuntimeDockerfile != "" {
+		log.Debugf("Custom runtime image Dockerfile is used: %s", ctx.Pack.RuntimeDockerfile)
+
+		if err := project.CheckRuntimeDockerfile(ctx.Pack.RuntimeDockerfile, ctx.Running.AppDir); err != nil {
+			return fmt.Errorf("Invalid runtime image Dockerfile %s: %s", ctx.Pack.RuntimeDockerfile, err)
+		}
+
+		dockerfileContent, err := common.GetFileContentBytes(ctx.Pack.RuntimeDockerfile)
+		if err != nil {
+			return fmt.Errorf("Failed to read runtime image Dockerfile: %s", err)
+		}
+
+		if err := ioutil.WriteFile(dockerfilePath, dockerfileContent, 0644); err != nil {
+			return fmt.Errorf("Failed to create runtime image Dockerfile: %s", err)
+		}
+
+		return nil
+	}
+
+	dockerfileTemplate, err := project.GetRuntimeImageDockerfileTemplate(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to create runtime image Dockerfile: %s", err)
+	}
+
+	dockerfileTemplate.Path = dockerfileName
+
+	if err := dockerfileTemplate.Instantiate(ctx.Build.Dir, runtimeContext); err != nil {
+		return fmt.Errorf("Failed to create runtime image Dockerfile: %s", err)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,131 @@
+package pack
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/apex/log"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+	"github.com/tarantool/cartridge-cli/cli/docker"
+	"github.com/tarantool/cartridge-cli/cli/project"
+)
+
+var (
+	platformRgx = regexp.MustCompile(`^[a-z0-9]+/[a-z0-9_]+(/[a-z0-9]+)?$`)
+)
+
+func checkPlatform(platform string) error {
+	if !platformRgx.MatchString(platform) {
+		return fmt.Errorf("Invalid platform %q: should be OS/ARCH[/VARIANT] (e.g. linux/arm64)", platform)
+	}
+
+	return nil
+}
+
+// getPlatformSlug returns the platform name that can be used
+// in the paths and image tags (e.g. linux/arm64/v8 -> linux-arm64-v8)
+func getPlatformSlug(platform string) string {
+	return strings.Replace(platform, "/", "-", -1)
+}
+
+// getPlatformImageTag returns the tag of the image built for the platform.
+// The platform is added to the tag (or used as the tag if it isn't specified)
+func getPlatformImageTag(imageTag, platform string) string {
+	nameStart := strings.LastIndex(imageTag, "/") + 1
+	if strings.Contains(imageTag[nameStart:], ":") {
+		return fmt.Sprintf("%s-%s", imageTag, getPlatformSlug(platform))
+	}
+
+	return fmt.Sprintf("%s:%s", imageTag, getPlatformSlug(platform))
+}
+
+// packMultiPlatformDocker builds the image for each of the specified platforms using
+// docker buildx. Application is built in the build image of the target platform.
+// Images are pushed and then combined into the manifest list tagged with
+// the result image tags, so pulling it gives the image for the host platform.
+// Images are always pushed, so --push flag doesn't change anything
+func packMultiPlatformDocker(ctx *context.Ctx) error {
+	if err := docker.CheckBuildx(); err != nil {
+		return err
+	}
+
+	if ctx.Tarantool.TarantoolIsEnterprise {
+		return fmt.Errorf("Multi-platform build isn't supported for Tarantool Enterprise since SDK is platform-specific")
+	}
+
+	var platformImageTags []string
+
+	for _, platform := range ctx.Docker.Platforms {
+		platformImageTag := getPlatformImageTag(ctx.Pack.ResImageTags[0], platform)
+
+		platformCtx := *ctx
+		platformCtx.Docker.Platform = platform
+
+		if err := packPlatformDocker(platformImageTag, &platformCtx); err != nil {
+			return fmt.Errorf("Failed to build image for %s: %s", platform, err)
+		}
+
+		platformImageTags = append(platformImageTags, platformImageTag)
+	}
+
+	log.Infof("Create manifest list %s", formatImageTags(ctx.Pack.ResImageTags))
+
+	if err := docker.CreateManifestList(ctx.Pack.ResImageTags, platformImageTags, ctx.Cli.Verbose); err != nil {
+		return fmt.Errorf("Failed to create manifest list: %s", err)
+	}
+
+	log.Infof("Created result image %s for %s",
+		formatImageTags(ctx.Pack.ResImageTags), strings.Join(ctx.Docker.Platforms, ", "))
+
+	return nil
+}
+
+// packPlatformDocker builds and pushes the image for ctx.Docker.Platform
+func packPlatformDocker(imageTag string, ctx *context.Ctx) error {
+	// app dir
+	appDirPath := filepath.Join(ctx.Pack.PackageFilesDir, getPlatformSlug(ctx.Docker.Platform), ctx.Project.Name)
+	if err := initAppDir(appDirPath, ctx); err != nil {
+		return err
+	}
+
+	// create runtime image Dockerfile
+	log.Debugf("Create runtime image Dockerfile")
+
+	runtimeImageDockerfileName := fmt.Sprintf("Dockerfile.%s", ctx.Pack.ID)
+	if err := createRuntimeImageDockerfile(runtimeImageDockerfileName, getRuntimeContext(ctx), ctx); err != nil {
+		return err
+	}
+	defer project.RemoveTmpPath(
+		filepath.Join(ctx.Build.Dir, runtimeImageDockerfileName),
+		ctx.Cli.Debug,
+	)
+
+	// create runtime image
+	log.Infof("Build image %s for %s", imageTag, ctx.Docker.Platform)
+
+	err := docker.BuildImage(docker.BuildOpts{
+		Tag:        []string{imageTag},
+		Dockerfile: runtimeImageDockerfileName,
+		NoCache:    ctx.Docker.NoCache,
+		CacheFrom:  ctx.Docker.CacheFrom,
+		Platform:   ctx.Docker.Platform,
+		BuildArgs:  ctx.Docker.BuildArgs,
+		Secrets:    ctx.Docker.Secrets,
+		Push:       true,
+
+		BuildDir:   ctx.Build.Dir,
+		TmpDir:     ctx.Cli.TmpDir,
+		ShowOutput: ctx.Cli.Verbose,
+	})
+
+	if err != nil {
+		return fmt.Errorf("Failed to build image: %s", err)
+	}
+
+	log.Infof("Pushed image %s", imageTag)
+
+	return nil
+}
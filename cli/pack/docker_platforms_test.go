@@ -0,0 +1,40 @@
+package pack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPlatform(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	for _, platform := range []string{"linux/amd64", "linux/arm64", "linux/arm/v7", "linux/s390x"} {
+		assert.Nil(checkPlatform(platform), platform)
+	}
+
+	for _, platform := range []string{"", "amd64", "linux/", "/amd64", "linux/arm/v7/x", "Linux/AMD64"} {
+		err := checkPlatform(platform)
+		assert.NotNil(err, platform)
+		assert.Contains(err.Error(), "should be OS/ARCH[/VARIANT]", platform)
+	}
+}
+
+func TestGetPlatformImageTag(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	assert.Equal("myapp:1.0.0-0-linux-amd64", getPlatformImageTag("myapp:1.0.0-0", "linux/amd64"))
+	assert.Equal("myapp:linux-arm-v7", getPlatformImageTag("myapp", "linux/arm/v7"))
+	assert.Equal(
+		"registry:5000/team/myapp:1.0.0-linux-arm64",
+		getPlatformImageTag("registry:5000/team/myapp:1.0.0", "linux/arm64"),
+	)
+	assert.Equal(
+		"registry:5000/team/myapp:linux-arm64",
+		getPlatformImageTag("registry:5000/team/myapp", "linux/arm64"),
+	)
+}
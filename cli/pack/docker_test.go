@@ -0,0 +1,56 @@
+package pack
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func TestCreateRuntimeImageDockerfileCustom(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "build")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	buildDir := filepath.Join(tmpDir, "build")
+	assert.Nil(os.Mkdir(buildDir, 0755))
+
+	var ctx context.Ctx
+	ctx.Build.Dir = buildDir
+	ctx.Running.AppDir = "/usr/share/tarantool/myapp"
+
+	dockerfileName := "Dockerfile.runtime"
+	runtimeContext := map[string]interface{}{
+		"AppDir": ctx.Running.AppDir,
+	}
+
+	// custom Dockerfile is used as is
+	customDockerfileContent := `FROM centos:8
+RUN yum install -y tarantool
+COPY --chown=tarantool:tarantool . /usr/share/tarantool/myapp
+USER tarantool:tarantool
+CMD tarantool {{ .AppDir }}/init.lua
+`
+	ctx.Pack.RuntimeDockerfile = filepath.Join(tmpDir, "Dockerfile.reviewed")
+	assert.Nil(ioutil.WriteFile(ctx.Pack.RuntimeDockerfile, []byte(customDockerfileContent), 0644))
+
+	assert.Nil(createRuntimeImageDockerfile(dockerfileName, runtimeContext, &ctx))
+
+	dockerfileContent, err := ioutil.ReadFile(filepath.Join(buildDir, dockerfileName))
+	assert.Nil(err)
+	assert.Equal(customDockerfileContent, string(dockerfileContent))
+
+	// custom Dockerfile doesn't copy application files
+	assert.Nil(ioutil.WriteFile(ctx.Pack.RuntimeDockerfile, []byte("FROM centos:8\n"), 0644))
+
+	err = createRuntimeImageDockerfile(dockerfileName, runtimeContext, &ctx)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Application files should be copied to /usr/share/tarantool/myapp")
+}
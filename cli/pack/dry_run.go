@@ -0,0 +1,297 @@
+package pack
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/apex/log"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+	"github.com/tarantool/cartridge-cli/cli/context"
+	"github.com/tarantool/cartridge-cli/cli/project"
+	"github.com/tarantool/cartridge-cli/cli/templates"
+)
+
+var (
+	// generatedScripts are the install scripts generated by cartridge for each type
+	generatedScripts = map[string]map[string]string{
+		RpmType: {
+			project.PreInstScript: project.PreInstScriptContent,
+		},
+		DebType: {
+			project.PreInstScript:  project.PreInstScriptContent,
+			project.PostInstScript: project.PostInstScriptContent,
+		},
+		ApkType: {
+			project.PreInstScript:  apkPreInstallContent,
+			project.PostInstScript: apkPostInstallContent,
+		},
+	}
+)
+
+// dryRunFile is the project file that would be packed.
+// SkipReason is set if the file (or the directory) is skipped
+type dryRunFile struct {
+	Path       string
+	SkipReason string
+}
+
+// runDryRun writes the list of project files that would be packed
+// and the computed package metadata to w.
+// Application isn't built and the package isn't created, so the files
+// created on build (e.g. .rocks) aren't listed
+func runDryRun(ctx *context.Ctx, w io.Writer) error {
+	log.Infof("Dry run: application %s isn't packed", ctx.Project.Name)
+
+	files, err := getDryRunFiles(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to collect application files: %s", err)
+	}
+
+	log.Infof("Files that would be packed:")
+
+	for _, file := range files {
+		if file.SkipReason == "" {
+			fmt.Fprintln(w, file.Path)
+		} else if ctx.Cli.Verbose {
+			fmt.Fprintf(w, "%s (skipped: %s)\n", file.Path, file.SkipReason)
+		}
+	}
+
+	log.Infof("Package metadata:")
+
+	fmt.Fprintf(w, "Name: %s\n", ctx.Project.Name)
+	fmt.Fprintf(w, "Version: %s\n", ctx.Pack.Version)
+	fmt.Fprintf(w, "Release: %s\n", ctx.Pack.Release)
+
+	if ctx.Pack.Type == DockerType {
+		fmt.Fprintf(w, "Image: %s\n", strings.Join(ctx.Pack.ResImageTags, ", "))
+	} else {
+		fmt.Fprintf(w, "Package: %s\n", ctx.Pack.ResPackagePath)
+	}
+
+	dependencies, err := getDryRunDependencies(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, dependency := range dependencies {
+		fmt.Fprintf(w, "Dependency: %s\n", dependency)
+	}
+
+	return writeDryRunScripts(ctx, w)
+}
+
+// getDryRunFiles returns the project files in the order they are walked.
+// Files are skipped the same way as on copying them to the application dir
+// and cleaning it up, skipped directories files aren't listed
+func getDryRunFiles(ctx *context.Ctx) ([]dryRunFile, error) {
+	ignore, err := getIgnoreRules(ctx.Project.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	gitIgnoredPaths, err := getGitIgnoredPaths(ctx.Project.Path)
+	if err != nil {
+		log.Warnf("Failed to get files ignored by git: %s", err)
+	}
+
+	var files []dryRunFile
+
+	err = filepath.Walk(ctx.Project.Path, func(filePath string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if filePath == ctx.Project.Path {
+			return nil
+		}
+
+		skipReason, err := getProjectFileSkipReason(filePath, ignore, ctx)
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(ctx.Project.Path, filePath)
+		if err != nil {
+			return fmt.Errorf("Failed to get file rel path: %s", err)
+		}
+
+		if skipReason == "" && gitIgnoredPaths[relPath] {
+			skipReason = "ignored by git"
+		}
+
+		if skipReason == "" && ctx.Pack.ExcludeVCS {
+			if isVCS, err := isVCSFile(fileInfo.Name()); err != nil {
+				return err
+			} else if isVCS {
+				skipReason = "VCS or editor metadata file"
+			}
+		}
+
+		if skipReason != "" {
+			files = append(files, dryRunFile{Path: relPath, SkipReason: skipReason})
+			if fileInfo.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !fileInfo.IsDir() {
+			files = append(files, dryRunFile{Path: relPath})
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// getGitIgnoredPaths returns the paths (relative to the project root)
+// that are removed by `git clean -X` on the application dir cleanup
+func getGitIgnoredPaths(projectPath string) (map[string]bool, error) {
+	ignoredPaths := make(map[string]bool)
+
+	if !common.GitIsInstalled() || !common.IsGitProject(projectPath) {
+		return ignoredPaths, nil
+	}
+
+	gitCleanCmd := exec.Command("git", "clean", "-n", "-d", "-X")
+	output, err := common.GetOutput(gitCleanCmd, &projectPath)
+	if err != nil {
+		return nil, err
+	}
+	addGitCleanPaths(ignoredPaths, output)
+
+	gitSubmodulesCleanCmd := exec.Command(
+		"git", "submodule", "foreach", "--recursive", "git", "clean", "-n", "-d", "-X",
+	)
+	output, err = common.GetOutput(gitSubmodulesCleanCmd, &projectPath)
+	if err != nil {
+		return nil, err
+	}
+	addGitCleanPaths(ignoredPaths, output)
+
+	return ignoredPaths, nil
+}
+
+// addGitCleanPaths parses `git clean -n` output.
+// `git submodule foreach` prints "Entering '<path>'" before each submodule output
+func addGitCleanPaths(paths map[string]bool, output string) {
+	prefix := ""
+
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "Entering '") {
+			prefix = strings.TrimSuffix(strings.TrimPrefix(line, "Entering '"), "'")
+			continue
+		}
+
+		if !strings.HasPrefix(line, "Would remove ") {
+			continue
+		}
+
+		relPath := strings.TrimSuffix(strings.TrimPrefix(line, "Would remove "), "/")
+		paths[filepath.Join(prefix, relPath)] = true
+	}
+}
+
+// getDryRunDependencies returns the package dependencies
+// in the format of the package type
+func getDryRunDependencies(ctx *context.Ctx) ([]string, error) {
+	var dependencies []string
+
+	switch ctx.Pack.Type {
+	case RpmType:
+		userDeps, err := project.GetUserDependencies(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ctx.Tarantool.TarantoolIsEnterprise && !project.DependsOn(userDeps, "tarantool") {
+			minTarantoolVersion := strings.SplitN(ctx.Tarantool.TarantoolVersion, "-", 2)[0]
+			maxTarantoolVersion, err := common.GetNextMajorVersion(minTarantoolVersion)
+			if err != nil {
+				return nil, project.InternalError("Failed to get next Tarantool major version: %s", err)
+			}
+
+			dependencies = append(dependencies,
+				fmt.Sprintf("tarantool >= %s", minTarantoolVersion),
+				fmt.Sprintf("tarantool < %s", maxTarantoolVersion),
+			)
+		}
+
+		for _, dep := range userDeps {
+			dependencies = append(dependencies, dep.String())
+		}
+
+		for _, dep := range ctx.Pack.RpmRecommends {
+			dependencies = append(dependencies, fmt.Sprintf("%s (recommends)", dep))
+		}
+		for _, dep := range ctx.Pack.RpmSupplements {
+			dependencies = append(dependencies, fmt.Sprintf("%s (supplements)", dep))
+		}
+		for _, dep := range ctx.Pack.RpmEnhances {
+			dependencies = append(dependencies, fmt.Sprintf("%s (enhances)", dep))
+		}
+	case DebType:
+		return getDebDepends(ctx)
+	case ApkType:
+		return getApkDepends(ctx)
+	}
+
+	return dependencies, nil
+}
+
+// writeDryRunScripts writes install scripts that would be delivered in the package.
+// If ctx.Cli.Verbose is set, the scripts content is written
+func writeDryRunScripts(ctx *context.Ctx, w io.Writer) error {
+	typeGeneratedScripts := generatedScripts[ctx.Pack.Type]
+
+	for _, kind := range project.InstallScripts {
+		generatedScript, generated := typeGeneratedScripts[kind]
+		userScriptPath := project.GetUserScriptPath(ctx, kind)
+
+		var sources []string
+		if generated {
+			sources = append(sources, "generated")
+		}
+		if userScriptPath != "" {
+			sources = append(sources, userScriptPath)
+		}
+
+		if len(sources) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "Script %s: %s\n", kind, strings.Join(sources, " + "))
+
+		if !ctx.Cli.Verbose {
+			continue
+		}
+
+		script, err := templates.GetTemplatedStr(&generatedScript, map[string]interface{}{
+			"Name": ctx.Project.Name,
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to instantiate %s script: %s", kind, err)
+		}
+
+		userSection, err := project.GetUserScriptSection(ctx, kind)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprint(w, script+userSection)
+	}
+
+	return nil
+}
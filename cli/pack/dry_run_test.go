@@ -0,0 +1,110 @@
+package pack
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func TestRunDryRun(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "project")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	projectFiles := map[string]string{
+		"init.lua":                  "require('cartridge')",
+		"app/roles/custom.lua":      "return {}",
+		"app/roles/.custom.lua.swp": "",
+		"data.tmp":                  "",
+		".rocks/share/rock.lua":     "",
+		cartridgeIgnoreFileName:     "*.tmp\n",
+	}
+
+	for filePath, content := range projectFiles {
+		fullPath := filepath.Join(tmpDir, filePath)
+		assert.Nil(os.MkdirAll(filepath.Dir(fullPath), 0755))
+		assert.Nil(ioutil.WriteFile(fullPath, []byte(content), 0644))
+	}
+
+	preInstScriptPath := filepath.Join(tmpDir, "preinst.sh")
+	assert.Nil(ioutil.WriteFile(preInstScriptPath, []byte("echo preinst"), 0644))
+
+	var ctx context.Ctx
+	ctx.Project.Name = "myapp"
+	ctx.Project.Path = tmpDir
+	ctx.Cli.CartridgeTmpDir = filepath.Join(tmpDir, "cartridge.tmp")
+	ctx.Pack.Type = RpmType
+	ctx.Pack.Version = "1.2.3"
+	ctx.Pack.Release = "4"
+	ctx.Pack.ResPackagePath = "/tmp/myapp-1.2.3-4.rpm"
+	ctx.Pack.ExcludeVCS = true
+	ctx.Pack.RpmRecommends = []string{"logrotate"}
+	ctx.Pack.PreInstScriptPath = preInstScriptPath
+	ctx.Tarantool.TarantoolVersion = "2.4.2-1-g7f8c5e6"
+
+	var out bytes.Buffer
+	assert.Nil(runDryRun(&ctx, &out))
+
+	assert.Equal([]string{
+		".cartridgeignore",
+		"app/roles/custom.lua",
+		"init.lua",
+		"preinst.sh",
+		"Name: myapp",
+		"Version: 1.2.3",
+		"Release: 4",
+		"Package: /tmp/myapp-1.2.3-4.rpm",
+		"Dependency: tarantool >= 2.4.2",
+		"Dependency: tarantool < 3",
+		"Dependency: logrotate (recommends)",
+		"Script preinst: generated + " + preInstScriptPath,
+	}, strings.Split(strings.TrimSpace(out.String()), "\n"))
+
+	// skipped files and scripts content are shown in verbose mode
+	ctx.Cli.Verbose = true
+
+	out.Reset()
+	assert.Nil(runDryRun(&ctx, &out))
+
+	outLines := strings.Split(out.String(), "\n")
+	assert.Contains(outLines, ".rocks (skipped: rocks are installed on build)")
+	assert.Contains(outLines, "app/roles/.custom.lua.swp (skipped: VCS or editor metadata file)")
+	assert.Contains(outLines, `data.tmp (skipped: ignored by .cartridgeignore:1 "*.tmp")`)
+	assert.Contains(outLines, "echo preinst")
+	assert.Contains(outLines, "# ---- END user-provided preinst script ----")
+
+	// nothing is created
+	entries, err := ioutil.ReadDir(tmpDir)
+	assert.Nil(err)
+	assert.Len(entries, 6)
+}
+
+func TestAddGitCleanPaths(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	paths := make(map[string]bool)
+
+	addGitCleanPaths(paths, "Would remove tmp/\nWould remove data.log\n")
+	addGitCleanPaths(paths, "Entering 'libs/sub'\nWould remove build/\nEntering 'other'\n")
+
+	assert.Equal(map[string]bool{
+		"tmp":            true,
+		"data.log":       true,
+		"libs/sub/build": true,
+	}, paths)
+}
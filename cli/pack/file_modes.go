@@ -0,0 +1,221 @@
+package pack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/apex/log"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+// fileModeOverride sets the mode of the package entries matching the pattern.
+// Pattern that contains `/` is matched against the absolute entry path,
+// otherwise it's matched against the entry base name
+type fileModeOverride struct {
+	Pattern string
+	Mode    os.FileMode
+}
+
+// fileModes describes how the package entries modes are normalized
+// (like RPM %defattr does)
+type fileModes struct {
+	DefaultFileMode *os.FileMode
+	DefaultDirMode  *os.FileMode
+
+	Overrides []fileModeOverride
+}
+
+func fileModesAreSpecified(ctx *context.Ctx) bool {
+	return ctx.Pack.DefaultFileMode != "" || ctx.Pack.DefaultDirMode != "" || len(ctx.Pack.FileModes) > 0
+}
+
+func parseFileMode(modeStr string) (os.FileMode, error) {
+	mode, err := strconv.ParseUint(modeStr, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, fmt.Errorf("Invalid mode %q: should be an octal number from 0000 to 0777", modeStr)
+	}
+
+	return os.FileMode(mode), nil
+}
+
+// checkFileMode checks that the owner is able to read the file
+// (and search the directory), otherwise it can't be packed.
+// Modes are set to the package files on disk, so the owner should be able
+// to write to the directory, otherwise the temporary package files can't be removed
+func checkFileMode(mode os.FileMode, isDir bool) error {
+	if mode&0400 == 0 {
+		return fmt.Errorf("Mode %04o doesn't allow owner to read", mode)
+	}
+
+	if isDir && mode&0100 == 0 {
+		return fmt.Errorf("Mode %04o doesn't allow owner to search directory", mode)
+	}
+
+	if isDir && mode&0200 == 0 {
+		return fmt.Errorf("Mode %04o doesn't allow owner to write to directory", mode)
+	}
+
+	return nil
+}
+
+func parseFileModes(ctx *context.Ctx) (*fileModes, error) {
+	var modes fileModes
+
+	if ctx.Pack.DefaultFileMode != "" {
+		mode, err := parseFileMode(ctx.Pack.DefaultFileMode)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid --default-file-mode value: %s", err)
+		}
+
+		if err := checkFileMode(mode, false); err != nil {
+			return nil, fmt.Errorf("Invalid --default-file-mode value: %s", err)
+		}
+
+		modes.DefaultFileMode = &mode
+	}
+
+	if ctx.Pack.DefaultDirMode != "" {
+		mode, err := parseFileMode(ctx.Pack.DefaultDirMode)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid --default-dir-mode value: %s", err)
+		}
+
+		if err := checkFileMode(mode, true); err != nil {
+			return nil, fmt.Errorf("Invalid --default-dir-mode value: %s", err)
+		}
+
+		modes.DefaultDirMode = &mode
+	}
+
+	for _, overrideStr := range ctx.Pack.FileModes {
+		sepIndex := strings.LastIndex(overrideStr, "=")
+		if sepIndex <= 0 {
+			return nil, fmt.Errorf("Invalid --file-mode value %q: should be GLOB=MODE", overrideStr)
+		}
+
+		pattern := overrideStr[:sepIndex]
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("Invalid --file-mode value %q: bad pattern: %s", overrideStr, err)
+		}
+
+		mode, err := parseFileMode(overrideStr[sepIndex+1:])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid --file-mode value %q: %s", overrideStr, err)
+		}
+
+		if err := checkFileMode(mode, false); err != nil {
+			return nil, fmt.Errorf("Invalid --file-mode value %q: %s", overrideStr, err)
+		}
+
+		modes.Overrides = append(modes.Overrides, fileModeOverride{
+			Pattern: pattern,
+			Mode:    mode,
+		})
+	}
+
+	return &modes, nil
+}
+
+// getMode returns the mode that should be set for the package entry.
+// The last matching override wins, default modes are used otherwise
+func (modes *fileModes) getMode(entryPath string, isDir bool) (os.FileMode, bool, error) {
+	for i := len(modes.Overrides) - 1; i >= 0; i-- {
+		override := modes.Overrides[i]
+
+		name := entryPath
+		if !strings.Contains(override.Pattern, "/") {
+			name = filepath.Base(entryPath)
+		}
+
+		matched, err := filepath.Match(override.Pattern, name)
+		if err != nil {
+			return 0, false, fmt.Errorf("Invalid pattern %q: %s", override.Pattern, err)
+		}
+
+		if matched {
+			if err := checkFileMode(override.Mode, isDir); err != nil {
+				return 0, false, fmt.Errorf("Failed to set %s mode: %s", entryPath, err)
+			}
+
+			return override.Mode, true, nil
+		}
+	}
+
+	if isDir && modes.DefaultDirMode != nil {
+		return *modes.DefaultDirMode, true, nil
+	}
+
+	if !isDir && modes.DefaultFileMode != nil {
+		return *modes.DefaultFileMode, true, nil
+	}
+
+	return 0, false, nil
+}
+
+// normalizeFileModes sets modes of all files and directories in the package root directory.
+// Entries paths are absolute (starts with `/`) since RPM and DEB packages
+// are installed to the system root. Symlinks and special files are skipped
+func normalizeFileModes(rootDir string, ctx *context.Ctx) error {
+	modes, err := parseFileModes(ctx)
+	if err != nil {
+		return err
+	}
+
+	type entryMode struct {
+		Path string
+		Mode os.FileMode
+	}
+
+	var entriesModes []entryMode
+
+	err = filepath.Walk(rootDir, func(filePath string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if filePath == rootDir {
+			return nil
+		}
+
+		if !fileInfo.IsDir() && !fileInfo.Mode().IsRegular() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(rootDir, filePath)
+		if err != nil {
+			return fmt.Errorf("Failed to get file rel path: %s", err)
+		}
+
+		mode, found, err := modes.getMode("/"+filepath.ToSlash(relPath), fileInfo.IsDir())
+		if err != nil {
+			return err
+		}
+
+		if found && fileInfo.Mode().Perm() != mode {
+			entriesModes = append(entriesModes, entryMode{filePath, mode})
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("Failed to collect package files modes: %s", err)
+	}
+
+	// entries are collected in lexical order,
+	// so directories modes are changed after their content
+	for i := len(entriesModes) - 1; i >= 0; i-- {
+		entry := entriesModes[i]
+		log.Debugf("Set %s mode to %04o", entry.Path, entry.Mode)
+
+		if err := os.Chmod(entry.Path, entry.Mode); err != nil {
+			return fmt.Errorf("Failed to set file mode: %s", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package pack
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func getTarModes(t *testing.T, srcDir string) map[string]os.FileMode {
+	var buf bytes.Buffer
+	if err := common.WriteTarArchive(srcDir, &buf); err != nil {
+		t.Fatalf("Failed to write tar archive: %s", err)
+	}
+
+	modes := make(map[string]os.FileMode)
+
+	tarReader := tar.NewReader(&buf)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read tar archive: %s", err)
+		}
+
+		modes[filepath.ToSlash(header.Name)] = os.FileMode(header.Mode).Perm()
+	}
+
+	return modes
+}
+
+func TestNormalizeFileModes(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	rootDir, err := ioutil.TempDir("", "modes")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(rootDir)
+
+	appDir := filepath.Join(rootDir, "usr", "share", "tarantool", "myapp")
+	assert.Nil(os.MkdirAll(filepath.Join(appDir, "bin"), 0700))
+	assert.Nil(os.MkdirAll(filepath.Join(appDir, "secret"), 0777))
+
+	files := map[string]os.FileMode{
+		"init.lua":          0600,
+		"bin/start":         0644,
+		"scripts.sh":        0640,
+		"secret/key.pem":    0666,
+		"secret/readme.txt": 0777,
+	}
+
+	for fileName, mode := range files {
+		filePath := filepath.Join(appDir, fileName)
+		assert.Nil(ioutil.WriteFile(filePath, []byte("content"), mode))
+		assert.Nil(os.Chmod(filePath, mode))
+	}
+
+	assert.Nil(os.Symlink("init.lua", filepath.Join(appDir, "link.lua")))
+
+	var ctx context.Ctx
+	ctx.Pack.DefaultFileMode = "0644"
+	ctx.Pack.DefaultDirMode = "755"
+	ctx.Pack.FileModes = []string{
+		"*.sh=0755",
+		"/usr/share/tarantool/myapp/bin/*=0755",
+		"/usr/share/tarantool/myapp/secret=0700",
+		"/usr/share/tarantool/myapp/secret/*=0600",
+		"readme.txt=0444",
+	}
+
+	assert.True(fileModesAreSpecified(&ctx))
+	assert.Nil(normalizeFileModes(rootDir, &ctx))
+
+	modes := getTarModes(t, rootDir)
+
+	expModes := map[string]os.FileMode{
+		"usr":                                      0755,
+		"usr/share":                                0755,
+		"usr/share/tarantool":                      0755,
+		"usr/share/tarantool/myapp":                0755,
+		"usr/share/tarantool/myapp/init.lua":       0644,
+		"usr/share/tarantool/myapp/bin":            0755,
+		"usr/share/tarantool/myapp/bin/start":      0755,
+		"usr/share/tarantool/myapp/scripts.sh":     0755,
+		"usr/share/tarantool/myapp/secret":         0700,
+		"usr/share/tarantool/myapp/secret/key.pem": 0600,
+		// the last matching override wins
+		"usr/share/tarantool/myapp/secret/readme.txt": 0444,
+	}
+
+	for path, expMode := range expModes {
+		assert.Equal(expMode, modes[path], path)
+
+		fileInfo, err := os.Lstat(filepath.Join(rootDir, path))
+		assert.Nil(err)
+		assert.Equal(expMode, fileInfo.Mode().Perm(), path)
+	}
+
+	// symlink is left as is
+	fileInfo, err := os.Lstat(filepath.Join(appDir, "link.lua"))
+	assert.Nil(err)
+	assert.True(fileInfo.Mode()&os.ModeSymlink != 0)
+}
+
+func TestParseFileModesErrors(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var err error
+	var ctx context.Ctx
+
+	ctx = context.Ctx{}
+	ctx.Pack.DefaultFileMode = "rw-r--r--"
+	_, err = parseFileModes(&ctx)
+	assert.EqualError(err, `Invalid --default-file-mode value: Invalid mode "rw-r--r--": `+
+		`should be an octal number from 0000 to 0777`)
+
+	ctx = context.Ctx{}
+	ctx.Pack.DefaultFileMode = "04755"
+	_, err = parseFileModes(&ctx)
+	assert.NotNil(err)
+
+	ctx = context.Ctx{}
+	ctx.Pack.DefaultFileMode = "0200"
+	_, err = parseFileModes(&ctx)
+	assert.EqualError(err, "Invalid --default-file-mode value: Mode 0200 doesn't allow owner to read")
+
+	ctx = context.Ctx{}
+	ctx.Pack.DefaultDirMode = "0644"
+	_, err = parseFileModes(&ctx)
+	assert.EqualError(err, "Invalid --default-dir-mode value: Mode 0644 doesn't allow owner to search directory")
+
+	ctx = context.Ctx{}
+	ctx.Pack.DefaultDirMode = "0555"
+	_, err = parseFileModes(&ctx)
+	assert.EqualError(err, "Invalid --default-dir-mode value: Mode 0555 doesn't allow owner to write to directory")
+
+	ctx = context.Ctx{}
+	ctx.Pack.FileModes = []string{"0644"}
+	_, err = parseFileModes(&ctx)
+	assert.EqualError(err, `Invalid --file-mode value "0644": should be GLOB=MODE`)
+
+	ctx = context.Ctx{}
+	ctx.Pack.FileModes = []string{"*.lua=abc"}
+	_, err = parseFileModes(&ctx)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), `Invalid --file-mode value "*.lua=abc"`)
+}
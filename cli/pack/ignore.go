@@ -0,0 +1,188 @@
+package pack
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	cartridgeIgnoreFileName = ".cartridgeignore"
+)
+
+// ignoreRule is the .cartridgeignore pattern (gitignore syntax)
+type ignoreRule struct {
+	Pattern string
+	Line    int
+
+	Negate  bool
+	DirOnly bool
+
+	rgx *regexp.Regexp
+}
+
+// ignoreRules describes which project files shouldn't be packed.
+// The last matching rule wins
+type ignoreRules struct {
+	FileName string
+	Rules    []ignoreRule
+}
+
+// getIgnorePatternRgx converts the gitignore-style pattern to the regular expression.
+// Pattern that contains `/` (except the trailing one) is matched against the path
+// relative to the project root, otherwise it's matched against the base name
+func getIgnorePatternRgx(pattern string) (*regexp.Regexp, error) {
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var rgxStr strings.Builder
+	if anchored {
+		rgxStr.WriteString("^")
+	} else {
+		rgxStr.WriteString("^(?:.*/)?")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/") && (i == 0 || pattern[i-1] == '/'):
+			// leading `**/` and `/**/` match zero or more directories
+			rgxStr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**") && (i == 0 || pattern[i-1] == '/') && i+2 == len(pattern):
+			// trailing `/**` matches everything inside
+			rgxStr.WriteString(".*")
+			i++
+		case c == '*':
+			rgxStr.WriteString("[^/]*")
+		case c == '?':
+			rgxStr.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("Unclosed character class")
+			}
+
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+
+			rgxStr.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(pattern):
+			rgxStr.WriteString(regexp.QuoteMeta(string(pattern[i+1])))
+			i++
+		default:
+			rgxStr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	rgxStr.WriteString("$")
+
+	return regexp.Compile(rgxStr.String())
+}
+
+// parseIgnoreRule parses .cartridgeignore line.
+// Nil rule is returned for blank lines and comments
+func parseIgnoreRule(line string, lineNum int) (*ignoreRule, error) {
+	pattern := strings.TrimRight(line, " \t")
+	if strings.HasSuffix(pattern, "\\") && strings.HasSuffix(line, " ") {
+		// escaped trailing space
+		pattern += " "
+	}
+
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return nil, nil
+	}
+
+	rule := ignoreRule{
+		Pattern: pattern,
+		Line:    lineNum,
+	}
+
+	if strings.HasPrefix(pattern, "!") {
+		rule.Negate = true
+		pattern = pattern[1:]
+	} else if strings.HasPrefix(pattern, "\\!") || strings.HasPrefix(pattern, "\\#") {
+		pattern = pattern[1:]
+	}
+
+	if strings.HasSuffix(pattern, "/") {
+		rule.DirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+
+	if pattern == "" {
+		return nil, fmt.Errorf("Invalid pattern %q", rule.Pattern)
+	}
+
+	rgx, err := getIgnorePatternRgx(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid pattern %q: %s", rule.Pattern, err)
+	}
+	rule.rgx = rgx
+
+	return &rule, nil
+}
+
+// getIgnoreRules reads .cartridgeignore file from the project root.
+// Nil is returned if the file doesn't exist
+func getIgnoreRules(projectPath string) (*ignoreRules, error) {
+	ignoreFilePath := filepath.Join(projectPath, cartridgeIgnoreFileName)
+
+	ignoreFile, err := os.Open(ignoreFilePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed to open %s: %s", cartridgeIgnoreFileName, err)
+	}
+	defer ignoreFile.Close()
+
+	rules := ignoreRules{
+		FileName: cartridgeIgnoreFileName,
+	}
+
+	scanner := bufio.NewScanner(ignoreFile)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		rule, err := parseIgnoreRule(scanner.Text(), lineNum)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid %s line %d: %s", cartridgeIgnoreFileName, lineNum, err)
+		}
+
+		if rule != nil {
+			rules.Rules = append(rules.Rules, *rule)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to read %s: %s", cartridgeIgnoreFileName, err)
+	}
+
+	return &rules, nil
+}
+
+// Match returns true if the path (relative to the project root) should be ignored.
+// The rule that made the decision is returned (nil if no rules match).
+// Files of the ignored directory aren't checked since the directory is skipped
+func (rules *ignoreRules) Match(relPath string, isDir bool) (bool, *ignoreRule) {
+	relPath = filepath.ToSlash(relPath)
+
+	for i := len(rules.Rules) - 1; i >= 0; i-- {
+		rule := &rules.Rules[i]
+
+		if rule.DirOnly && !isDir {
+			continue
+		}
+
+		if rule.rgx.MatchString(relPath) {
+			return !rule.Negate, rule
+		}
+	}
+
+	return false, nil
+}
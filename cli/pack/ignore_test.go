@@ -0,0 +1,148 @@
+package pack
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIgnoreRule(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	// blank lines and comments
+	for _, line := range []string{"", "   ", "# comment", "#*.lua"} {
+		rule, err := parseIgnoreRule(line, 1)
+		assert.Nil(err, line)
+		assert.Nil(rule, line)
+	}
+
+	rule, err := parseIgnoreRule("!keep.me  ", 3)
+	assert.Nil(err)
+	assert.Equal("!keep.me", rule.Pattern)
+	assert.Equal(3, rule.Line)
+	assert.True(rule.Negate)
+	assert.False(rule.DirOnly)
+
+	rule, err = parseIgnoreRule("tmp/", 1)
+	assert.Nil(err)
+	assert.False(rule.Negate)
+	assert.True(rule.DirOnly)
+
+	rule, err = parseIgnoreRule(`\#file`, 1)
+	assert.Nil(err)
+	assert.True(rule.rgx.MatchString("#file"))
+
+	rule, err = parseIgnoreRule(`\!file`, 1)
+	assert.Nil(err)
+	assert.False(rule.Negate)
+	assert.True(rule.rgx.MatchString("!file"))
+
+	_, err = parseIgnoreRule("[abc", 1)
+	assert.EqualError(err, `Invalid pattern "[abc": Unclosed character class`)
+
+	_, err = parseIgnoreRule("!", 1)
+	assert.EqualError(err, `Invalid pattern "!"`)
+}
+
+func TestIgnoreRulesMatch(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "project")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// no .cartridgeignore
+	ignore, err := getIgnoreRules(tmpDir)
+	assert.Nil(err)
+	assert.Nil(ignore)
+
+	ignoreContent := `# local files
+.env
+*.tmp
+*~
+!keep.tmp
+
+# directories
+scratch/
+/build
+docs/**/*.draft
+logs/**
+!logs/README.md
+file?.bak
+[ab].log
+`
+	assert.Nil(ioutil.WriteFile(filepath.Join(tmpDir, cartridgeIgnoreFileName), []byte(ignoreContent), 0644))
+
+	ignore, err = getIgnoreRules(tmpDir)
+	assert.Nil(err)
+	assert.Len(ignore.Rules, 11)
+
+	type matchCase struct {
+		Path    string
+		IsDir   bool
+		Ignored bool
+		Line    int
+	}
+
+	cases := []matchCase{
+		{".env", false, true, 2},
+		{"app/.env", false, true, 2},
+		{"init.lua", false, false, 0},
+		{"app/roles/custom.lua", false, false, 0},
+		{"data.tmp", false, true, 3},
+		{"app/data.tmp", false, true, 3},
+		{"init.lua~", false, true, 4},
+		{"keep.tmp", false, false, 5},
+		{"app/keep.tmp", false, false, 5},
+
+		// directory patterns
+		{"scratch", true, true, 8},
+		{"app/scratch", true, true, 8},
+		{"scratch", false, false, 0},
+
+		// anchored patterns
+		{"build", true, true, 9},
+		{"build", false, true, 9},
+		{"app/build", true, false, 0},
+
+		{"docs/a.draft", false, true, 10},
+		{"docs/api/v1/a.draft", false, true, 10},
+		{"app/docs/a.draft", false, false, 0},
+
+		{"logs/x.log", false, true, 11},
+		{"logs/1/x.log", false, true, 11},
+		{"logs/README.md", false, false, 12},
+		{"logs", true, false, 0},
+
+		{"file1.bak", false, true, 13},
+		{"file12.bak", false, false, 0},
+		{"a.log", false, true, 14},
+		{"c.log", false, false, 0},
+	}
+
+	for _, c := range cases {
+		ignored, rule := ignore.Match(c.Path, c.IsDir)
+		assert.Equal(c.Ignored, ignored, c.Path)
+
+		if c.Line == 0 {
+			assert.Nil(rule, c.Path)
+		} else if assert.NotNil(rule, c.Path) {
+			assert.Equal(c.Line, rule.Line, c.Path)
+		}
+	}
+
+	// invalid pattern
+	assert.Nil(ioutil.WriteFile(filepath.Join(tmpDir, cartridgeIgnoreFileName), []byte("*.tmp\n[abc\n"), 0644))
+
+	_, err = getIgnoreRules(tmpDir)
+	assert.EqualError(err, `Invalid .cartridgeignore line 2: Invalid pattern "[abc": Unclosed character class`)
+}
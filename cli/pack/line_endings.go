@@ -0,0 +1,109 @@
+package pack
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/apex/log"
+)
+
+const (
+	// binaryCheckSize is the size of the file beginning
+	// that is checked for NUL bytes (the same as git uses)
+	binaryCheckSize = 8000
+)
+
+var (
+	crlf = []byte("\r\n")
+	lf   = []byte("\n")
+)
+
+// isBinaryContent returns true if the content beginning contains NUL byte
+func isBinaryContent(content []byte) bool {
+	if len(content) > binaryCheckSize {
+		content = content[:binaryCheckSize]
+	}
+
+	return bytes.IndexByte(content, 0) != -1
+}
+
+// normalizeFileLineEndings replaces CRLF with LF in the text file.
+// The file is replaced with the new one, so read-only files can be normalized too
+func normalizeFileLineEndings(filePath string, fileInfo os.FileInfo) (bool, error) {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return false, fmt.Errorf("Failed to read file: %s", err)
+	}
+
+	if isBinaryContent(content) || !bytes.Contains(content, crlf) {
+		return false, nil
+	}
+
+	normalizedFile, err := ioutil.TempFile(filepath.Dir(filePath), ".normalized-*")
+	if err != nil {
+		return false, fmt.Errorf("Failed to create temporary file: %s", err)
+	}
+	defer os.Remove(normalizedFile.Name())
+
+	if _, err := normalizedFile.Write(bytes.ReplaceAll(content, crlf, lf)); err != nil {
+		normalizedFile.Close()
+		return false, fmt.Errorf("Failed to write file: %s", err)
+	}
+
+	if err := normalizedFile.Chmod(fileInfo.Mode()); err != nil {
+		normalizedFile.Close()
+		return false, fmt.Errorf("Failed to set file mode: %s", err)
+	}
+
+	if err := normalizedFile.Close(); err != nil {
+		return false, fmt.Errorf("Failed to write file: %s", err)
+	}
+
+	if err := os.Rename(normalizedFile.Name(), filePath); err != nil {
+		return false, fmt.Errorf("Failed to replace file: %s", err)
+	}
+
+	return true, nil
+}
+
+// normalizeLineEndings converts CRLF line endings to LF
+// in all text files in the specified directory.
+// Binary files (detected by content) and symlinks are left untouched
+func normalizeLineEndings(dirPath string) error {
+	// files are collected first since they are replaced on normalizing
+	filesInfo := make(map[string]os.FileInfo)
+	var filePaths []string
+
+	err := filepath.Walk(dirPath, func(filePath string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if fileInfo.Mode().IsRegular() {
+			filePaths = append(filePaths, filePath)
+			filesInfo[filePath] = fileInfo
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("Failed to collect files: %s", err)
+	}
+
+	for _, filePath := range filePaths {
+		normalized, err := normalizeFileLineEndings(filePath, filesInfo[filePath])
+		if err != nil {
+			return fmt.Errorf("Failed to normalize %s line endings: %s", filePath, err)
+		}
+
+		if normalized {
+			log.Debugf("Line endings are normalized: %s", filePath)
+		}
+	}
+
+	return nil
+}
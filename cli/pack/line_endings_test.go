@@ -0,0 +1,97 @@
+package pack
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+)
+
+func TestIsBinaryContent(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	assert.False(isBinaryContent([]byte("")))
+	assert.False(isBinaryContent([]byte("#!/bin/sh\r\necho ok\r\n")))
+	assert.True(isBinaryContent([]byte("\x7fELF\x00\r\n")))
+
+	// NUL byte after the checked part
+	content := append(bytes.Repeat([]byte("a"), binaryCheckSize), 0)
+	assert.False(isBinaryContent(content))
+}
+
+func TestNormalizeLineEndings(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	dirPath, err := ioutil.TempDir("", "app")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(dirPath)
+
+	files := map[string][]byte{
+		"cartridge.post-build": []byte("#!/bin/sh\r\necho ok\r\n"),
+		"init.lua":             []byte("print('unix')\n"),
+		"bin/data.bin":         []byte("\x00\x01\r\n\x02\r\n"),
+	}
+
+	for filePath, content := range files {
+		fullPath := filepath.Join(dirPath, filePath)
+		assert.Nil(os.MkdirAll(filepath.Dir(fullPath), 0755))
+		assert.Nil(ioutil.WriteFile(fullPath, content, 0755))
+	}
+
+	// read-only file
+	readOnlyPath := filepath.Join(dirPath, "README.md")
+	assert.Nil(ioutil.WriteFile(readOnlyPath, []byte("line\r\nline\r\n"), 0444))
+
+	assert.Nil(normalizeLineEndings(dirPath))
+
+	var buf bytes.Buffer
+	assert.Nil(common.WriteTarArchive(dirPath, &buf))
+
+	contents := make(map[string][]byte)
+	modes := make(map[string]int64)
+
+	tarReader := tar.NewReader(&buf)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(err)
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		content, err := ioutil.ReadAll(tarReader)
+		assert.Nil(err)
+
+		contents[header.Name] = content
+		modes[header.Name] = header.Mode
+	}
+
+	assert.Len(contents, 4)
+
+	assert.Equal([]byte("#!/bin/sh\necho ok\n"), contents["cartridge.post-build"])
+	assert.Equal([]byte("print('unix')\n"), contents["init.lua"])
+	assert.Equal([]byte("line\nline\n"), contents["README.md"])
+
+	// binary file is unchanged
+	assert.Equal(files["bin/data.bin"], contents["bin/data.bin"])
+
+	// modes are kept
+	assert.EqualValues(0755, modes["cartridge.post-build"])
+	assert.EqualValues(0444, modes["README.md"])
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 
 	"github.com/apex/log"
 
@@ -17,6 +18,7 @@ var (
 	packers = map[string]func(*context.Ctx) error{
 		TgzType:    packTgz,
 		DebType:    packDeb,
+		ApkType:    packApk,
 		RpmType:    packRpm,
 		DockerType: packDocker,
 	}
@@ -26,9 +28,64 @@ const (
 	TgzType    = "tgz"
 	RpmType    = "rpm"
 	DebType    = "deb"
+	ApkType    = "apk"
 	DockerType = "docker"
 )
 
+const (
+	GzipCompression = "gzip"
+	ZstdCompression = "zstd"
+)
+
+// RunTypes packs application into all specified distributable types.
+// Each type is validated and packed using its own copy of the context.
+// If ctx.Pack.KeepGoing is set, failure of one type doesn't abort packing
+// into the rest types, all failures are reported at the end
+func RunTypes(ctx *context.Ctx, packTypes []string) error {
+	return runTypes(ctx, packTypes, runType)
+}
+
+func runType(ctx *context.Ctx) error {
+	if err := Validate(ctx); err != nil {
+		return err
+	}
+
+	if err := FillCtx(ctx); err != nil {
+		return err
+	}
+
+	return Run(ctx)
+}
+
+func runTypes(ctx *context.Ctx, packTypes []string, runTypeFunc func(*context.Ctx) error) error {
+	if len(packTypes) == 1 {
+		ctx.Pack.Type = packTypes[0]
+		return runTypeFunc(ctx)
+	}
+
+	var failedTypes []string
+
+	for _, packType := range packTypes {
+		typeCtx := *ctx
+		typeCtx.Pack.Type = packType
+
+		if err := runTypeFunc(&typeCtx); err != nil {
+			if !ctx.Pack.KeepGoing {
+				return fmt.Errorf("Failed to pack application into %s: %s", packType, err)
+			}
+
+			log.Errorf("Failed to pack application into %s: %s", packType, err)
+			failedTypes = append(failedTypes, packType)
+		}
+	}
+
+	if len(failedTypes) > 0 {
+		return fmt.Errorf("Failed to pack application into some types: %s", strings.Join(failedTypes, ", "))
+	}
+
+	return nil
+}
+
 // Run packs application into project.PackType distributable
 func Run(ctx *context.Ctx) error {
 	if err := checkCtx(ctx); err != nil {
@@ -51,6 +108,12 @@ func Run(ctx *context.Ctx) error {
 		return fmt.Errorf("Unsupported distribution type: %s", ctx.Pack.Type)
 	}
 
+	// DEB package is built for the only platform
+	// that is used for building the application in docker
+	if ctx.Pack.Type == DebType && len(ctx.Docker.Platforms) == 1 {
+		ctx.Docker.Platform = ctx.Docker.Platforms[0]
+	}
+
 	log.Infof("Packing %s into %s", ctx.Project.Name, ctx.Pack.Type)
 
 	// All types except TGZ pack require init.lua in the project root
@@ -66,6 +129,12 @@ func Run(ctx *context.Ctx) error {
 		}
 	}
 
+	if ctx.Pack.NoBuild {
+		if err := checkPrebuiltApp(ctx.Project.Path); err != nil {
+			return err
+		}
+	}
+
 	ctx.Pack.ID = common.RandomString(10)
 	ctx.Build.ID = ctx.Pack.ID
 
@@ -95,7 +164,7 @@ func Run(ctx *context.Ctx) error {
 				return fmt.Errorf("Failed to use default build Dockerfile: %s", err)
 			}
 		}
-		if ctx.Pack.DockerFrom == "" {
+		if ctx.Pack.DockerFrom == "" && ctx.Pack.RuntimeDockerfile == "" {
 			// runtime Dockerfile
 			defaultBaseRuntimeDockerfilePath := filepath.Join(ctx.Project.Path, project.DefaultBaseRuntimeDockerfile)
 			if _, err := os.Stat(defaultBaseRuntimeDockerfilePath); err == nil {
@@ -118,7 +187,7 @@ func Run(ctx *context.Ctx) error {
 	}
 
 	// get and normalize version
-	if ctx.Pack.Type != DockerType || len(ctx.Pack.ImageTags) == 0 {
+	if ctx.Pack.Type != DockerType || len(ctx.Pack.ImageTags) == 0 || imageTagsUseVersion(ctx.Pack.ImageTags) {
 		if err := detectVersion(ctx); err != nil {
 			return err
 		}
@@ -136,14 +205,18 @@ func Run(ctx *context.Ctx) error {
 
 	if ctx.Pack.Type != DockerType {
 		// set result package path
-		curDir, err := os.Getwd()
+		resDir, err := getResultDir(ctx)
 		if err != nil {
 			return err
 		}
-		ctx.Pack.ResPackagePath = filepath.Join(curDir, getPackageFullname(ctx))
+		ctx.Pack.ResPackagePath = filepath.Join(resDir, getPackageFullname(ctx))
 	} else {
 		// set result image fullname
-		ctx.Pack.ResImageTags = getImageTags(ctx)
+		imageTags, err := getImageTags(ctx)
+		if err != nil {
+			return err
+		}
+		ctx.Pack.ResImageTags = imageTags
 	}
 
 	// tmp directory
@@ -151,6 +224,10 @@ func Run(ctx *context.Ctx) error {
 		return err
 	}
 
+	if ctx.Pack.DryRun {
+		return runDryRun(ctx, os.Stdout)
+	}
+
 	log.Infof("Temporary directory is set to %s", ctx.Cli.TmpDir)
 	if err := initTmpDir(ctx); err != nil {
 		return err
@@ -161,6 +238,15 @@ func Run(ctx *context.Ctx) error {
 		return err
 	}
 
+	if ctx.Pack.Checksum {
+		checksumPath, err := writeChecksumFile(ctx.Pack.ResPackagePath)
+		if err != nil {
+			return fmt.Errorf("Failed to write result package checksum: %s", err)
+		}
+
+		log.Infof("Created result package checksum file: %s", checksumPath)
+	}
+
 	log.Infof("Application was successfully packed")
 
 	return nil
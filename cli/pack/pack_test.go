@@ -0,0 +1,71 @@
+package pack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func getRunTypeFunc(packedTypes *[]string, failedType string) func(*context.Ctx) error {
+	return func(ctx *context.Ctx) error {
+		if ctx.Pack.Type == failedType {
+			return fmt.Errorf("Some error")
+		}
+
+		*packedTypes = append(*packedTypes, ctx.Pack.Type)
+		return nil
+	}
+}
+
+func TestRunTypes(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var err error
+	var ctx context.Ctx
+	var packedTypes []string
+
+	packTypes := []string{TgzType, RpmType, DebType}
+
+	// all types are packed
+	packedTypes = nil
+	ctx = context.Ctx{}
+	err = runTypes(&ctx, packTypes, getRunTypeFunc(&packedTypes, ""))
+	assert.Nil(err)
+	assert.Equal(packTypes, packedTypes)
+
+	// rpm fails, packing is aborted
+	packedTypes = nil
+	ctx = context.Ctx{}
+	err = runTypes(&ctx, packTypes, getRunTypeFunc(&packedTypes, RpmType))
+	assert.EqualError(err, "Failed to pack application into rpm: Some error")
+	assert.Equal([]string{TgzType}, packedTypes)
+
+	// rpm fails, --keep-going
+	packedTypes = nil
+	ctx = context.Ctx{}
+	ctx.Pack.KeepGoing = true
+	err = runTypes(&ctx, packTypes, getRunTypeFunc(&packedTypes, RpmType))
+	assert.EqualError(err, "Failed to pack application into some types: rpm")
+	assert.Equal([]string{TgzType, DebType}, packedTypes)
+
+	// single type fails, error is returned as is
+	packedTypes = nil
+	ctx = context.Ctx{}
+	ctx.Pack.KeepGoing = true
+	err = runTypes(&ctx, []string{TgzType}, getRunTypeFunc(&packedTypes, TgzType))
+	assert.EqualError(err, "Some error")
+	assert.Equal(0, len(packedTypes))
+
+	// single type is packed using the passed context
+	packedTypes = nil
+	ctx = context.Ctx{}
+	err = runTypes(&ctx, []string{DockerType}, getRunTypeFunc(&packedTypes, ""))
+	assert.Nil(err)
+	assert.Equal(DockerType, ctx.Pack.Type)
+	assert.Equal([]string{DockerType}, packedTypes)
+}
@@ -14,10 +14,6 @@ import (
 func packRpm(ctx *context.Ctx) error {
 	var err error
 
-	if err := common.CheckRequiredBinaries("cpio"); err != nil {
-		return err
-	}
-
 	appDirPath := filepath.Join(ctx.Pack.PackageFilesDir, ctx.Running.AppDir)
 	if err := initAppDir(appDirPath, ctx); err != nil {
 		return err
@@ -31,6 +27,35 @@ func packRpm(ctx *context.Ctx) error {
 		return err
 	}
 
+	if err := initSysusersDir(ctx.Pack.PackageFilesDir, ctx); err != nil {
+		return err
+	}
+
+	if ctx.Pack.RpmRelocateDocsDir != "" {
+		relocatedPaths, err := relocateDocs(ctx.Pack.PackageFilesDir, ctx.Running.AppDir,
+			ctx.Pack.RpmRelocateDocsDir, ctx.Pack.RpmDocPatterns)
+		if err != nil {
+			return err
+		}
+
+		if len(relocatedPaths) == 0 {
+			log.Warnf("No documentation files found to relocate")
+		}
+	}
+
+	if len(ctx.Pack.Transforms) > 0 {
+		if err := applyTransforms(ctx.Pack.PackageFilesDir, true, ctx.Pack.Transforms,
+			getEnsuredDirsPaths(appDirPath, ctx.Pack.EnsureDirs)); err != nil {
+			return err
+		}
+	}
+
+	if fileModesAreSpecified(ctx) {
+		if err := normalizeFileModes(ctx.Pack.PackageFilesDir, ctx); err != nil {
+			return err
+		}
+	}
+
 	err = common.RunFunctionWithSpinner(func() error {
 		return rpm.Pack(ctx)
 	}, "Creating result RPM package...")
@@ -38,6 +63,15 @@ func packRpm(ctx *context.Ctx) error {
 		return fmt.Errorf("Failed to create RPM package: %s", err)
 	}
 
+	if ctx.Pack.RpmVerify {
+		err = common.RunFunctionWithSpinner(func() error {
+			return rpm.Verify(ctx)
+		}, "Verifying result RPM package...")
+		if err != nil {
+			return fmt.Errorf("Failed to verify RPM package: %s", err)
+		}
+	}
+
 	log.Infof("Created result RPM package: %s", ctx.Pack.ResPackagePath)
 
 	return nil
@@ -0,0 +1,104 @@
+package pack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/apex/log"
+)
+
+var (
+	defaultDocPatterns = []string{
+		"README*",
+		"LICENSE*",
+		"docs",
+	}
+)
+
+// relocateDocs moves application files (and directories) which paths relative
+// to the application directory match one of the patterns to the docs directory.
+// docsDir is an absolute path in the package, relative paths of matched files
+// are kept (e.g. <appDir>/docs/index.md -> <docsDir>/docs/index.md)
+func relocateDocs(packageFilesDir, appDir, docsDir string, patterns []string) ([]string, error) {
+	if len(patterns) == 0 {
+		patterns = defaultDocPatterns
+	}
+
+	appDirPath := filepath.Join(packageFilesDir, appDir)
+	docsDirPath := filepath.Join(packageFilesDir, docsDir)
+
+	var relocatedPaths []string
+
+	err := filepath.Walk(appDirPath, func(filePath string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if filePath == appDirPath {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(appDirPath, filePath)
+		if err != nil {
+			return fmt.Errorf("Failed to get file rel path: %s", err)
+		}
+
+		matched, err := matchesSomePattern(filepath.ToSlash(relPath), patterns)
+		if err != nil {
+			return err
+		}
+
+		if !matched {
+			return nil
+		}
+
+		relocatedPaths = append(relocatedPaths, relPath)
+
+		if fileInfo.IsDir() {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to collect documentation files: %s", err)
+	}
+
+	for _, relPath := range relocatedPaths {
+		newPath := filepath.Join(docsDirPath, relPath)
+		log.Debugf("Relocate %s to %s", relPath, filepath.Join(docsDir, relPath))
+
+		if _, err := os.Stat(newPath); err == nil {
+			return nil, fmt.Errorf("Failed to relocate %s: %s already exists", relPath, newPath)
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("Failed to relocate %s: %s", relPath, err)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+			return nil, fmt.Errorf("Failed to create docs directory: %s", err)
+		}
+
+		if err := os.Rename(filepath.Join(appDirPath, relPath), newPath); err != nil {
+			return nil, fmt.Errorf("Failed to relocate %s: %s", relPath, err)
+		}
+	}
+
+	return relocatedPaths, nil
+}
+
+func matchesSomePattern(path string, patterns []string) (bool, error) {
+	for _, pattern := range patterns {
+		matched, err := filepath.Match(pattern, path)
+		if err != nil {
+			return false, fmt.Errorf("Invalid documentation pattern %q: %s", pattern, err)
+		}
+
+		if matched {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
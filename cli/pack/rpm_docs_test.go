@@ -0,0 +1,86 @@
+package pack
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelocateDocs(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	appDir := "/usr/share/tarantool/myapp"
+	docsDir := "/usr/share/doc/myapp"
+
+	createPackageFiles := func() string {
+		packageFilesDir, err := ioutil.TempDir("", "package-files")
+		assert.Nil(err)
+
+		appDirPath := filepath.Join(packageFilesDir, appDir)
+		for _, relPath := range []string{
+			"init.lua",
+			"README.md",
+			"LICENSE",
+			"docs/index.md",
+			"docs/api/roles.md",
+			"app/roles/README.md",
+			"app/roles/api.lua",
+		} {
+			filePath := filepath.Join(appDirPath, relPath)
+			assert.Nil(os.MkdirAll(filepath.Dir(filePath), 0755))
+			assert.Nil(ioutil.WriteFile(filePath, []byte(relPath), 0644))
+		}
+
+		return packageFilesDir
+	}
+
+	// default patterns
+	packageFilesDir := createPackageFiles()
+	defer os.RemoveAll(packageFilesDir)
+
+	relocatedPaths, err := relocateDocs(packageFilesDir, appDir, docsDir, nil)
+	assert.Nil(err)
+	sort.Strings(relocatedPaths)
+	assert.Equal([]string{"LICENSE", "README.md", "docs"}, relocatedPaths)
+
+	// docs are relocated
+	for _, relPath := range []string{"README.md", "LICENSE", "docs/index.md", "docs/api/roles.md"} {
+		assert.FileExists(filepath.Join(packageFilesDir, docsDir, relPath))
+		assert.NoFileExists(filepath.Join(packageFilesDir, appDir, relPath))
+	}
+	assert.NoDirExists(filepath.Join(packageFilesDir, appDir, "docs"))
+
+	// code stays put
+	for _, relPath := range []string{"init.lua", "app/roles/api.lua", "app/roles/README.md"} {
+		assert.FileExists(filepath.Join(packageFilesDir, appDir, relPath))
+		assert.NoFileExists(filepath.Join(packageFilesDir, docsDir, relPath))
+	}
+
+	// custom patterns
+	packageFilesDir = createPackageFiles()
+	defer os.RemoveAll(packageFilesDir)
+
+	relocatedPaths, err = relocateDocs(packageFilesDir, appDir, docsDir, []string{"*.md", "app/roles/*.md"})
+	assert.Nil(err)
+	sort.Strings(relocatedPaths)
+	assert.Equal([]string{"README.md", "app/roles/README.md"}, relocatedPaths)
+
+	assert.FileExists(filepath.Join(packageFilesDir, docsDir, "README.md"))
+	assert.FileExists(filepath.Join(packageFilesDir, docsDir, "app/roles/README.md"))
+	assert.FileExists(filepath.Join(packageFilesDir, appDir, "LICENSE"))
+	assert.FileExists(filepath.Join(packageFilesDir, appDir, "docs/index.md"))
+
+	// bad pattern
+	packageFilesDir = createPackageFiles()
+	defer os.RemoveAll(packageFilesDir)
+
+	_, err = relocateDocs(packageFilesDir, appDir, docsDir, []string{"[README"})
+	assert.NotNil(err)
+	assert.Contains(err.Error(), `Invalid documentation pattern "[README"`)
+}
@@ -3,6 +3,7 @@ package pack
 import (
 	"fmt"
 	"path/filepath"
+	"text/template"
 
 	"github.com/apex/log"
 	"github.com/tarantool/cartridge-cli/cli/common"
@@ -110,6 +111,36 @@ func getSystemdTemplate(ctx *context.Ctx) (templates.Template, error) {
 	return &systemdFilesTemplate, nil
 }
 
+// checkUnitTemplates checks that specified unit templates can be read and parsed,
+// so invalid template is reported before packing
+func checkUnitTemplates(ctx *context.Ctx) error {
+	unitTemplates := []struct {
+		Flag string
+		Path string
+	}{
+		{"--unit-template", ctx.Pack.UnitTemplatePath},
+		{"--instantiated-unit-template", ctx.Pack.InstUnitTemplatePath},
+		{"--stateboard-unit-template", ctx.Pack.StatboardUnitTemplatePath},
+	}
+
+	for _, unitTemplate := range unitTemplates {
+		if unitTemplate.Path == "" {
+			continue
+		}
+
+		content, err := common.GetFileContent(unitTemplate.Path)
+		if err != nil {
+			return fmt.Errorf("Failed to read %s file: %s", unitTemplate.Flag, err)
+		}
+
+		if _, err := template.New("content").Parse(content); err != nil {
+			return fmt.Errorf("Invalid %s file %s: %s", unitTemplate.Flag, unitTemplate.Path, err)
+		}
+	}
+
+	return nil
+}
+
 func getSystemdCtx(ctx *context.Ctx) *map[string]interface{} {
 	systemdCtx := make(map[string]interface{})
 
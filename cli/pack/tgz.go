@@ -2,14 +2,26 @@ package pack
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 
 	"github.com/apex/log"
+	"gopkg.in/yaml.v2"
 
 	"github.com/tarantool/cartridge-cli/cli/common"
 	"github.com/tarantool/cartridge-cli/cli/context"
 )
 
+// splitManifest describes parts of the split archive.
+// Archive can be reassembled by concatenating parts in the specified order
+type splitManifest struct {
+	Archive string   `yaml:"archive"`
+	Size    int64    `yaml:"size"`
+	SHA256  string   `yaml:"sha256"`
+	Parts   []string `yaml:"parts"`
+}
+
 func packTgz(ctx *context.Ctx) error {
 	var err error
 
@@ -18,14 +30,87 @@ func packTgz(ctx *context.Ctx) error {
 		return err
 	}
 
+	if len(ctx.Pack.Transforms) > 0 {
+		if err := applyTransforms(ctx.Pack.PackageFilesDir, false, ctx.Pack.Transforms,
+			getEnsuredDirsPaths(appDirPath, ctx.Pack.EnsureDirs)); err != nil {
+			return err
+		}
+	}
+
+	archiveKind := "TGZ"
+	writeArchive := common.WriteTgzArchive
+
+	if ctx.Pack.Compression == ZstdCompression {
+		archiveKind = "TAR.ZST"
+		writeArchive = common.WriteTarZstArchive
+	}
+
 	err = common.RunFunctionWithSpinner(func() error {
-		return common.WriteTgzArchive(ctx.Pack.PackageFilesDir, ctx.Pack.ResPackagePath)
-	}, "Creating result TGZ archive...")
+		return writeArchive(ctx.Pack.PackageFilesDir, ctx.Pack.ResPackagePath, ctx.Pack.CompressionLevel)
+	}, fmt.Sprintf("Creating result %s archive...", archiveKind))
 	if err != nil {
-		return fmt.Errorf("Failed to create TGZ archive: %s", err)
+		return fmt.Errorf("Failed to create %s archive: %s", archiveKind, err)
 	}
 
-	log.Infof("Created result TGZ archive: %s", ctx.Pack.ResPackagePath)
+	if ctx.Pack.SplitSize > 0 {
+		manifestPath, err := splitArchive(ctx.Pack.ResPackagePath, ctx.Pack.SplitSize)
+		if err != nil {
+			return fmt.Errorf("Failed to split %s archive: %s", archiveKind, err)
+		}
+
+		log.Infof("Created result %s archive parts described in %s", archiveKind, manifestPath)
+
+		return nil
+	}
+
+	log.Infof("Created result %s archive: %s", archiveKind, ctx.Pack.ResPackagePath)
 
 	return nil
 }
+
+// splitArchive splits archive into <archive>.partNN files
+// and writes <archive>.manifest file that lists parts and archive SHA256.
+// Source archive is removed. Manifest path is returned
+func splitArchive(archivePath string, partSize int64) (string, error) {
+	archiveInfo, err := os.Stat(archivePath)
+	if err != nil {
+		return "", fmt.Errorf("Failed to use archive: %s", err)
+	}
+
+	archiveSHA256, err := common.FileSHA256Hex(archivePath)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get archive SHA256: %s", err)
+	}
+
+	partPaths, err := common.SplitFile(archivePath, partSize)
+	if err != nil {
+		return "", err
+	}
+
+	manifest := splitManifest{
+		Archive: filepath.Base(archivePath),
+		Size:    archiveInfo.Size(),
+		SHA256:  archiveSHA256,
+		Parts:   make([]string, len(partPaths)),
+	}
+
+	for i, partPath := range partPaths {
+		manifest.Parts[i] = filepath.Base(partPath)
+	}
+
+	manifestContent, err := yaml.Marshal(manifest)
+	if err != nil {
+		return "", fmt.Errorf("Failed to encode manifest: %s", err)
+	}
+
+	manifestPath := fmt.Sprintf("%s.manifest", archivePath)
+	if err := ioutil.WriteFile(manifestPath, manifestContent, 0644); err != nil {
+		return "", fmt.Errorf("Failed to write manifest: %s", err)
+	}
+
+	if err := os.Remove(archivePath); err != nil {
+		return "", fmt.Errorf("Failed to remove source archive: %s", err)
+	}
+
+	return manifestPath, nil
+}
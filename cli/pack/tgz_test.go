@@ -0,0 +1,81 @@
+package pack
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+)
+
+func TestSplitArchive(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	dirPath, err := ioutil.TempDir("", "split")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(dirPath)
+
+	archiveContent := make([]byte, 2500)
+	rand.Read(archiveContent)
+
+	archivePath := filepath.Join(dirPath, "myapp-1.0.0-0.tar.gz")
+	assert.Nil(ioutil.WriteFile(archivePath, archiveContent, 0644))
+
+	archiveSHA256, err := common.FileSHA256Hex(archivePath)
+	assert.Nil(err)
+
+	manifestPath, err := splitArchive(archivePath, 1000)
+	assert.Nil(err)
+	assert.Equal(archivePath+".manifest", manifestPath)
+
+	// source archive is removed
+	_, err = os.Stat(archivePath)
+	assert.True(os.IsNotExist(err))
+
+	// check manifest
+	manifestContent, err := ioutil.ReadFile(manifestPath)
+	assert.Nil(err)
+
+	var manifest splitManifest
+	assert.Nil(yaml.Unmarshal(manifestContent, &manifest))
+
+	assert.Equal("myapp-1.0.0-0.tar.gz", manifest.Archive)
+	assert.EqualValues(2500, manifest.Size)
+	assert.Equal(archiveSHA256, manifest.SHA256)
+	assert.Equal([]string{
+		"myapp-1.0.0-0.tar.gz.part00",
+		"myapp-1.0.0-0.tar.gz.part01",
+		"myapp-1.0.0-0.tar.gz.part02",
+	}, manifest.Parts)
+
+	// concatenated parts reproduce the original archive
+	var partPaths []string
+	for _, part := range manifest.Parts {
+		partPaths = append(partPaths, filepath.Join(dirPath, part))
+	}
+
+	partInfo, err := os.Stat(partPaths[2])
+	assert.Nil(err)
+	assert.EqualValues(500, partInfo.Size())
+
+	reassembledPath := filepath.Join(dirPath, "reassembled.tar.gz")
+	assert.Nil(common.MergeFiles(reassembledPath, partPaths...))
+
+	reassembledContent, err := ioutil.ReadFile(reassembledPath)
+	assert.Nil(err)
+	assert.True(bytes.Equal(archiveContent, reassembledContent))
+
+	reassembledSHA256, err := common.FileSHA256Hex(reassembledPath)
+	assert.Nil(err)
+	assert.Equal(manifest.SHA256, reassembledSHA256)
+}
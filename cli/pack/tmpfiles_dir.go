@@ -2,12 +2,25 @@ package pack
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/apex/log"
+	"gopkg.in/yaml.v2"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
 	"github.com/tarantool/cartridge-cli/cli/context"
+	"github.com/tarantool/cartridge-cli/cli/project"
 	"github.com/tarantool/cartridge-cli/cli/templates"
 )
 
+const (
+	serviceUser = "tarantool"
+
+	sysusersEntryTypes = "ugmr"
+)
+
 var (
 	tmpFilesTemplate = templates.FileTreeTemplate{
 		Dirs: []templates.DirTemplate{
@@ -20,22 +33,216 @@ var (
 			{
 				Path:    "/usr/lib/tmpfiles.d/{{ .Name }}.conf",
 				Mode:    0644,
-				Content: tmpFilesConfContent,
+				Content: confEntriesContent,
+			},
+		},
+	}
+
+	sysusersTemplate = templates.FileTreeTemplate{
+		Dirs: []templates.DirTemplate{
+			{
+				Path: "/usr/lib/sysusers.d",
+				Mode: 0755,
+			},
+		},
+		Files: []templates.FileTemplate{
+			{
+				Path:    "/usr/lib/sysusers.d/{{ .Name }}.conf",
+				Mode:    0644,
+				Content: confEntriesContent,
 			},
 		},
 	}
 )
 
+// packConf is the `pack` section of the .cartridge.yml file
+type packConf struct {
+	Tmpfiles []string `yaml:"tmpfiles"`
+	Sysusers []string `yaml:"sysusers"`
+}
+
 func initTmpfilesDir(baseDirPath string, ctx *context.Ctx) error {
 	log.Infof("Initialize tmpfiles dir")
 
-	if err := tmpFilesTemplate.Instantiate(baseDirPath, ctx.Project); err != nil {
+	entries, err := getTmpfilesEntries(ctx)
+	if err != nil {
+		return err
+	}
+
+	tmpfilesCtx := map[string]interface{}{
+		"Name":    ctx.Project.Name,
+		"Entries": entries,
+	}
+
+	if err := tmpFilesTemplate.Instantiate(baseDirPath, tmpfilesCtx); err != nil {
 		return fmt.Errorf("Failed to instantiate tmpfiles dir: %s", err)
 	}
 
 	return nil
 }
 
+func initSysusersDir(baseDirPath string, ctx *context.Ctx) error {
+	log.Infof("Initialize sysusers dir")
+
+	entries, err := getSysusersEntries(ctx)
+	if err != nil {
+		return err
+	}
+
+	sysusersCtx := map[string]interface{}{
+		"Name":    ctx.Project.Name,
+		"Entries": entries,
+	}
+
+	if err := sysusersTemplate.Instantiate(baseDirPath, sysusersCtx); err != nil {
+		return fmt.Errorf("Failed to instantiate sysusers dir: %s", err)
+	}
+
+	return nil
+}
+
+// getDefaultTmpfilesEntries returns tmpfiles.d entries that create
+// the run dir used in the generated unit files
+func getDefaultTmpfilesEntries(ctx *context.Ctx) []string {
+	return []string{
+		fmt.Sprintf("d %s 0755 %s %s", ctx.Running.RunDir, serviceUser, serviceUser),
+	}
+}
+
+// getDefaultSysusersEntries returns sysusers.d entries that create
+// the user the generated unit files are run by
+func getDefaultSysusersEntries(ctx *context.Ctx) []string {
+	return []string{
+		fmt.Sprintf(`u %s - "Tarantool Server" %s /sbin/nologin`, serviceUser, ctx.Running.DataDir),
+	}
+}
+
+// getTmpfilesEntries returns default tmpfiles.d entries
+// overridden by the .cartridge.yml entries and --tmpfiles-entry values
+func getTmpfilesEntries(ctx *context.Ctx) ([]string, error) {
+	conf, err := getPackConf(ctx.Project.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := mergeConfEntries(getTmpfilesEntryKey,
+		getDefaultTmpfilesEntries(ctx), conf.Tmpfiles, ctx.Pack.TmpfilesEntries,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid tmpfiles.d entry: %s", err)
+	}
+
+	return entries, nil
+}
+
+// getSysusersEntries returns default sysusers.d entries
+// overridden by the .cartridge.yml entries and --sysusers-entry values
+func getSysusersEntries(ctx *context.Ctx) ([]string, error) {
+	conf, err := getPackConf(ctx.Project.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := mergeConfEntries(getSysusersEntryKey,
+		getDefaultSysusersEntries(ctx), conf.Sysusers, ctx.Pack.SysusersEntries,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid sysusers.d entry: %s", err)
+	}
+
+	return entries, nil
+}
+
+// getPackConf reads the `pack` section of the .cartridge.yml file
+// placed in the application directory
+func getPackConf(projectPath string) (*packConf, error) {
+	confPath := filepath.Join(projectPath, project.CartridgeLocalConf)
+
+	if _, err := os.Stat(confPath); os.IsNotExist(err) {
+		return &packConf{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed to use %s: %s", project.CartridgeLocalConf, err)
+	}
+
+	confContent, err := common.GetFileContentBytes(confPath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read %s: %s", project.CartridgeLocalConf, err)
+	}
+
+	var conf struct {
+		Pack packConf `yaml:"pack"`
+	}
+
+	if err := yaml.Unmarshal(confContent, &conf); err != nil {
+		return nil, fmt.Errorf("Failed to parse %s pack section: %s", project.CartridgeLocalConf, err)
+	}
+
+	return &conf.Pack, nil
+}
+
+// mergeConfEntries merges the lists of the drop-in file entries.
+// Entry replaces the entry with the same key from the previous lists,
+// entries with the new keys are appended
+func mergeConfEntries(getKey func(entry string) (string, error), entriesLists ...[]string) ([]string, error) {
+	var merged []string
+	indexes := make(map[string]int)
+
+	for _, entries := range entriesLists {
+		for _, entry := range entries {
+			entry = strings.TrimSpace(entry)
+
+			key, err := getKey(entry)
+			if err != nil {
+				return nil, err
+			}
+
+			if index, found := indexes[key]; found {
+				merged[index] = entry
+				continue
+			}
+
+			indexes[key] = len(merged)
+			merged = append(merged, entry)
+		}
+	}
+
+	return merged, nil
+}
+
+// getTmpfilesEntryKey returns the path of the tmpfiles.d entry
+func getTmpfilesEntryKey(entry string) (string, error) {
+	fields := strings.Fields(entry)
+	if len(fields) < 2 {
+		return "", fmt.Errorf("%q should contain at least type and path", entry)
+	}
+
+	return fields[1], nil
+}
+
+// getSysusersEntryKey returns the type and the name of the sysusers.d entry.
+// Group is added for the `m` entry, since one user can be added to many groups
+func getSysusersEntryKey(entry string) (string, error) {
+	fields := strings.Fields(entry)
+	if len(fields) < 2 {
+		return "", fmt.Errorf("%q should contain at least type and name", entry)
+	}
+
+	if len(fields[0]) != 1 || !strings.Contains(sysusersEntryTypes, fields[0]) {
+		return "", fmt.Errorf("%q type should be one of u, g, m, r", entry)
+	}
+
+	if fields[0] == "m" {
+		if len(fields) < 3 {
+			return "", fmt.Errorf("%q should contain the group", entry)
+		}
+
+		return strings.Join(fields[:3], " "), nil
+	}
+
+	return strings.Join(fields[:2], " "), nil
+}
+
 const (
-	tmpFilesConfContent = `d /var/run/tarantool 0755 tarantool tarantool`
+	confEntriesContent = `{{ range .Entries }}{{ . }}
+{{ end }}`
 )
@@ -0,0 +1,120 @@
+package pack
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+	"github.com/tarantool/cartridge-cli/cli/project"
+)
+
+func TestMergeConfEntries(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	entries, err := mergeConfEntries(getTmpfilesEntryKey,
+		[]string{"d /var/run/tarantool 0755 tarantool tarantool"},
+		nil,
+		[]string{"  d /var/run/tarantool 0750 tarantool tarantool  ", "d /var/run/myapp 0755 tarantool tarantool"},
+	)
+	assert.Nil(err)
+	assert.Equal([]string{
+		"d /var/run/tarantool 0750 tarantool tarantool",
+		"d /var/run/myapp 0755 tarantool tarantool",
+	}, entries)
+
+	_, err = mergeConfEntries(getTmpfilesEntryKey, []string{"d"})
+	assert.EqualError(err, `"d" should contain at least type and path`)
+
+	entries, err = mergeConfEntries(getSysusersEntryKey,
+		[]string{`u tarantool - "Tarantool Server" /var/lib/tarantool /sbin/nologin`},
+		[]string{`u tarantool 1500 "Tarantool Server" /var/lib/tarantool /sbin/nologin`},
+		[]string{"m tarantool adm", "m tarantool systemd-journal", "g tarantool 1500"},
+	)
+	assert.Nil(err)
+	assert.Equal([]string{
+		`u tarantool 1500 "Tarantool Server" /var/lib/tarantool /sbin/nologin`,
+		"m tarantool adm",
+		"m tarantool systemd-journal",
+		"g tarantool 1500",
+	}, entries)
+
+	_, err = mergeConfEntries(getSysusersEntryKey, []string{"x tarantool"})
+	assert.EqualError(err, `"x tarantool" type should be one of u, g, m, r`)
+
+	_, err = mergeConfEntries(getSysusersEntryKey, []string{"m tarantool"})
+	assert.EqualError(err, `"m tarantool" should contain the group`)
+}
+
+func TestGetDropInEntries(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	projectPath, err := ioutil.TempDir("", "project")
+	assert.Nil(err)
+	defer os.RemoveAll(projectPath)
+
+	var ctx context.Ctx
+	ctx.Project.Path = projectPath
+	ctx.Running.RunDir = "/var/run/tarantool"
+	ctx.Running.DataDir = "/var/lib/tarantool"
+
+	// defaults
+	tmpfilesEntries, err := getTmpfilesEntries(&ctx)
+	assert.Nil(err)
+	assert.Equal([]string{"d /var/run/tarantool 0755 tarantool tarantool"}, tmpfilesEntries)
+
+	sysusersEntries, err := getSysusersEntries(&ctx)
+	assert.Nil(err)
+	assert.Equal([]string{`u tarantool - "Tarantool Server" /var/lib/tarantool /sbin/nologin`}, sysusersEntries)
+
+	// .cartridge.yml pack section
+	confContent := `run-dir: tmp/run
+pack:
+  tmpfiles:
+    - d /var/run/tarantool 0750 tarantool tarantool
+    - d /var/run/tarantool/myapp 0750 tarantool tarantool
+  sysusers:
+    - m tarantool adm
+`
+	confPath := filepath.Join(projectPath, project.CartridgeLocalConf)
+	assert.Nil(ioutil.WriteFile(confPath, []byte(confContent), 0644))
+
+	tmpfilesEntries, err = getTmpfilesEntries(&ctx)
+	assert.Nil(err)
+	assert.Equal([]string{
+		"d /var/run/tarantool 0750 tarantool tarantool",
+		"d /var/run/tarantool/myapp 0750 tarantool tarantool",
+	}, tmpfilesEntries)
+
+	sysusersEntries, err = getSysusersEntries(&ctx)
+	assert.Nil(err)
+	assert.Equal([]string{
+		`u tarantool - "Tarantool Server" /var/lib/tarantool /sbin/nologin`,
+		"m tarantool adm",
+	}, sysusersEntries)
+
+	// flags override .cartridge.yml
+	ctx.Pack.TmpfilesEntries = []string{"d /var/run/tarantool/myapp 0700 tarantool tarantool"}
+	tmpfilesEntries, err = getTmpfilesEntries(&ctx)
+	assert.Nil(err)
+	assert.Equal([]string{
+		"d /var/run/tarantool 0750 tarantool tarantool",
+		"d /var/run/tarantool/myapp 0700 tarantool tarantool",
+	}, tmpfilesEntries)
+
+	// invalid .cartridge.yml pack section
+	assert.Nil(ioutil.WriteFile(confPath, []byte("pack:\n  sysusers:\n    - tarantool\n"), 0644))
+	_, err = getSysusersEntries(&ctx)
+	assert.EqualError(err, `Invalid sysusers.d entry: "tarantool" should contain at least type and name`)
+
+	assert.Nil(ioutil.WriteFile(confPath, []byte("pack: []\n"), 0644))
+	_, err = getTmpfilesEntries(&ctx)
+	assert.NotNil(err)
+}
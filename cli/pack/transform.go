@@ -0,0 +1,308 @@
+package pack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/tarantool/cartridge-cli/cli/common"
+)
+
+// pathTransform is a sed-style `s|FROM|TO|[g]` expression
+// applied to the package entries paths
+type pathTransform struct {
+	Expr string
+
+	From   *regexp.Regexp
+	To     string
+	Global bool
+}
+
+// parseTransform parses sed-style `s<delim>FROM<delim>TO<delim>[g]` expression.
+// FROM is a regular expression, TO can contain `\1`..`\9` and `&` references
+func parseTransform(expr string) (*pathTransform, error) {
+	if len(expr) < 2 || expr[0] != 's' {
+		return nil, fmt.Errorf("Expression should have format s|FROM|TO|")
+	}
+
+	delim := expr[1]
+	if delim == '\\' || delim == '\n' {
+		return nil, fmt.Errorf("Invalid delimiter %q", delim)
+	}
+
+	parts := splitByUnescaped(expr[2:], delim)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("Expression should have format s%cFROM%cTO%c", delim, delim, delim)
+	}
+
+	from, to, flags := parts[0], parts[1], parts[2]
+
+	if from == "" {
+		return nil, fmt.Errorf("FROM shouldn't be empty")
+	}
+
+	transform := pathTransform{
+		Expr: expr,
+	}
+
+	for _, flag := range flags {
+		switch flag {
+		case 'g':
+			transform.Global = true
+		default:
+			return nil, fmt.Errorf("Unknown flag %q", flag)
+		}
+	}
+
+	var err error
+	if transform.From, err = regexp.Compile(from); err != nil {
+		return nil, fmt.Errorf("Invalid regular expression %q: %s", from, err)
+	}
+
+	if transform.To, err = getReplacementTemplate(to, transform.From.NumSubexp()); err != nil {
+		return nil, err
+	}
+
+	return &transform, nil
+}
+
+func parseTransforms(exprs []string) ([]*pathTransform, error) {
+	transforms := make([]*pathTransform, len(exprs))
+
+	for i, expr := range exprs {
+		transform, err := parseTransform(expr)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid transform expression %q: %s", expr, err)
+		}
+
+		transforms[i] = transform
+	}
+
+	return transforms, nil
+}
+
+// splitByUnescaped splits string by the delimiter that isn't escaped by `\`.
+// Escaped delimiter is replaced by the delimiter itself
+func splitByUnescaped(str string, delim byte) []string {
+	var parts []string
+	var current strings.Builder
+
+	for i := 0; i < len(str); i++ {
+		switch {
+		case str[i] == '\\' && i+1 < len(str) && str[i+1] == delim:
+			current.WriteByte(delim)
+			i++
+		case str[i] == '\\' && i+1 < len(str):
+			current.WriteByte(str[i])
+			current.WriteByte(str[i+1])
+			i++
+		case str[i] == delim:
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(str[i])
+		}
+	}
+
+	return append(parts, current.String())
+}
+
+// getReplacementTemplate converts sed replacement to the regexp.Expand template
+func getReplacementTemplate(replacement string, subexpNum int) (string, error) {
+	var template strings.Builder
+
+	for i := 0; i < len(replacement); i++ {
+		c := replacement[i]
+
+		switch {
+		case c == '\\' && i+1 < len(replacement):
+			next := replacement[i+1]
+			i++
+
+			if next >= '0' && next <= '9' {
+				groupNum := int(next - '0')
+				if groupNum > subexpNum {
+					return "", fmt.Errorf("Invalid reference \\%d in TO", groupNum)
+				}
+
+				template.WriteString(fmt.Sprintf("${%d}", groupNum))
+			} else if next == '$' {
+				template.WriteString("$$")
+			} else {
+				template.WriteByte(next)
+			}
+		case c == '&':
+			template.WriteString("${0}")
+		case c == '$':
+			template.WriteString("$$")
+		default:
+			template.WriteByte(c)
+		}
+	}
+
+	return template.String(), nil
+}
+
+func (transform *pathTransform) Apply(path string) string {
+	if transform.Global {
+		return transform.From.ReplaceAllString(path, transform.To)
+	}
+
+	match := transform.From.FindStringSubmatchIndex(path)
+	if match == nil {
+		return path
+	}
+
+	var res []byte
+	res = append(res, path[:match[0]]...)
+	res = transform.From.ExpandString(res, transform.To, path, match)
+	res = append(res, path[match[1]:]...)
+
+	return string(res)
+}
+
+// applyTransforms moves files in the package root directory according to the transforms.
+// Transforms are applied to the entries paths relative to the package root.
+// If absPaths is set, entries paths are absolute (starts with `/`),
+// it's used for packages that are installed to the system root (RPM and DEB).
+// Directories that became empty are removed, except the keepDirs ones (see --ensure-dir).
+func applyTransforms(rootDir string, absPaths bool, exprs []string, keepDirs []string) error {
+	transforms, err := parseTransforms(exprs)
+	if err != nil {
+		return err
+	}
+
+	var relPaths []string
+
+	err = filepath.Walk(rootDir, func(filePath string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if fileInfo.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(rootDir, filePath)
+		if err != nil {
+			return fmt.Errorf("Failed to get file rel path: %s", err)
+		}
+
+		relPaths = append(relPaths, filepath.ToSlash(relPath))
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("Failed to collect package files: %s", err)
+	}
+
+	changedDirs := make(map[string]bool)
+
+	for _, relPath := range relPaths {
+		entryPath := relPath
+		if absPaths {
+			entryPath = "/" + relPath
+		}
+
+		newEntryPath := entryPath
+		for _, transform := range transforms {
+			newEntryPath = transform.Apply(newEntryPath)
+		}
+
+		if newEntryPath == entryPath {
+			continue
+		}
+
+		newRelPath, err := getTransformedRelPath(newEntryPath, absPaths)
+		if err != nil {
+			return fmt.Errorf("Failed to transform %s: %s", entryPath, err)
+		}
+
+		if newRelPath == relPath {
+			continue
+		}
+
+		srcPath := filepath.Join(rootDir, relPath)
+		dstPath := filepath.Join(rootDir, newRelPath)
+
+		if _, err := os.Lstat(dstPath); err == nil {
+			return fmt.Errorf("Failed to transform %s: %s already exists", entryPath, newEntryPath)
+		}
+
+		log.Debugf("Transform %s to %s", entryPath, newEntryPath)
+
+		if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+			return fmt.Errorf("Failed to create directory for %s: %s", newEntryPath, err)
+		}
+
+		if err := os.Rename(srcPath, dstPath); err != nil {
+			return fmt.Errorf("Failed to move %s to %s: %s", entryPath, newEntryPath, err)
+		}
+
+		changedDirs[filepath.Dir(srcPath)] = true
+	}
+
+	keepDirsSet := make(map[string]bool, len(keepDirs))
+	for _, dir := range keepDirs {
+		keepDirsSet[filepath.Clean(dir)] = true
+	}
+
+	return removeEmptyParentDirs(rootDir, changedDirs, keepDirsSet)
+}
+
+func getTransformedRelPath(entryPath string, absPaths bool) (string, error) {
+	if absPaths && !strings.HasPrefix(entryPath, "/") {
+		return "", fmt.Errorf("Result path %s should be absolute", entryPath)
+	}
+
+	relPath := filepath.Clean(strings.TrimPrefix(entryPath, "/"))
+
+	if relPath == "." || relPath == ".." || strings.HasPrefix(relPath, "../") {
+		return "", fmt.Errorf("Result path %s should be placed inside the package", entryPath)
+	}
+
+	return relPath, nil
+}
+
+// removeEmptyParentDirs removes specified directories and their parents
+// (down to the root directory) if they became empty after files were moved.
+// Removing stops at the keepDirs directories
+func removeEmptyParentDirs(rootDir string, dirs map[string]bool, keepDirs map[string]bool) error {
+	dirsList := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		dirsList = append(dirsList, dir)
+	}
+
+	// the deepest directories go first
+	sort.Slice(dirsList, func(i, j int) bool {
+		return len(dirsList[i]) > len(dirsList[j])
+	})
+
+	for _, dir := range dirsList {
+		for dir != rootDir && !keepDirs[dir] && strings.HasPrefix(dir, rootDir) {
+			if _, err := os.Stat(dir); os.IsNotExist(err) {
+				dir = filepath.Dir(dir)
+				continue
+			}
+
+			if isEmpty, err := common.IsDirEmpty(dir); err != nil {
+				return fmt.Errorf("Failed to read directory %s: %s", dir, err)
+			} else if !isEmpty {
+				break
+			}
+
+			if err := os.Remove(dir); err != nil {
+				return fmt.Errorf("Failed to remove empty directory %s: %s", dir, err)
+			}
+
+			dir = filepath.Dir(dir)
+		}
+	}
+
+	return nil
+}
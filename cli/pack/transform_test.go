@@ -0,0 +1,167 @@
+package pack
+
+import (
+	"archive/tar"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTransform(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var err error
+	var transform *pathTransform
+
+	// valid
+	transform, err = parseTransform("s|^myapp/conf/|etc/myapp/|")
+	assert.Nil(err)
+	assert.False(transform.Global)
+	assert.Equal("etc/myapp/conf.yml", transform.Apply("myapp/conf/conf.yml"))
+	assert.Equal("myapp/init.lua", transform.Apply("myapp/init.lua"))
+
+	transform, err = parseTransform(`s#(.*)\.lua$#\1.lua.bak#`)
+	assert.Nil(err)
+	assert.Equal("myapp/init.lua.bak", transform.Apply("myapp/init.lua"))
+
+	transform, err = parseTransform(`s,o,0,g`)
+	assert.Nil(err)
+	assert.True(transform.Global)
+	assert.Equal("f00/b00", transform.Apply("foo/boo"))
+
+	transform, err = parseTransform(`s,o,0,`)
+	assert.Nil(err)
+	assert.Equal("f0o/boo", transform.Apply("foo/boo"))
+
+	transform, err = parseTransform(`s|lib/|&old-|`)
+	assert.Nil(err)
+	assert.Equal("myapp/lib/old-module.lua", transform.Apply("myapp/lib/module.lua"))
+
+	// escaped delimiter
+	transform, err = parseTransform(`s/myapp\/conf/conf/`)
+	assert.Nil(err)
+	assert.Equal("conf/a.yml", transform.Apply("myapp/conf/a.yml"))
+
+	// invalid
+	invalidExprs := map[string]string{
+		"":                "Expression should have format s|FROM|TO|",
+		"y|a|b|":          "Expression should have format s|FROM|TO|",
+		"s|a|b":           "Expression should have format s|FROM|TO|",
+		"s|a|b|c|":        "Expression should have format s|FROM|TO|",
+		"s||b|":           "FROM shouldn't be empty",
+		"s|a|b|x":         `Unknown flag 'x'`,
+		"s|a(|b|":         `Invalid regular expression "a("`,
+		`s|(a)|\2|`:       `Invalid reference \2 in TO`,
+		"s\\a\\b\\":       "Invalid delimiter",
+		"s|conf/|etc/|gg": "",
+	}
+
+	for expr, expErr := range invalidExprs {
+		_, err = parseTransform(expr)
+		if expErr == "" {
+			assert.Nil(err, expr)
+			continue
+		}
+
+		assert.NotNil(err, expr)
+		if err != nil {
+			assert.Contains(err.Error(), expErr, expr)
+		}
+	}
+
+	_, err = parseTransforms([]string{"s|a|b|", "s|a|b"})
+	assert.NotNil(err)
+	assert.Contains(err.Error(), `Invalid transform expression "s|a|b"`)
+}
+
+func TestApplyTransforms(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	rootDir, err := ioutil.TempDir("", "package")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(rootDir)
+
+	files := []string{
+		"usr/share/tarantool/myapp/init.lua",
+		"usr/share/tarantool/myapp/conf/app.yml",
+		"usr/share/tarantool/myapp/conf/roles/roles.yml",
+		"usr/lib/systemd/system/myapp.service",
+	}
+
+	for _, file := range files {
+		filePath := filepath.Join(rootDir, file)
+		assert.Nil(os.MkdirAll(filepath.Dir(filePath), 0755))
+		assert.Nil(ioutil.WriteFile(filePath, []byte(file), 0644))
+	}
+
+	transforms := []string{
+		"s|^/usr/share/tarantool/myapp/conf/|/etc/myapp/|",
+		"s|\\.service$|@.service|",
+	}
+
+	assert.Nil(applyTransforms(rootDir, true, transforms, nil))
+
+	entries := getTarEntries(t, rootDir)
+
+	// rewritten
+	assert.Equal(byte(tar.TypeReg), entries["etc/myapp/app.yml"])
+	assert.Equal(byte(tar.TypeReg), entries["etc/myapp/roles/roles.yml"])
+	assert.Equal(byte(tar.TypeReg), entries["usr/lib/systemd/system/myapp@.service"])
+
+	content, err := ioutil.ReadFile(filepath.Join(rootDir, "etc/myapp/app.yml"))
+	assert.Nil(err)
+	assert.Equal("usr/share/tarantool/myapp/conf/app.yml", string(content))
+
+	// untouched
+	assert.Equal(byte(tar.TypeReg), entries["usr/share/tarantool/myapp/init.lua"])
+
+	// source paths and empty directories are removed
+	assert.NotContains(entries, "usr/share/tarantool/myapp/conf/app.yml")
+	assert.NotContains(entries, "usr/share/tarantool/myapp/conf")
+	assert.NotContains(entries, "usr/lib/systemd/system/myapp.service")
+
+	// result path is outside of the package
+	err = applyTransforms(rootDir, true, []string{"s|^/etc/myapp/|/../|"}, nil)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "should be placed inside the package")
+
+	// result path isn't absolute
+	err = applyTransforms(rootDir, true, []string{"s|^/etc/myapp/|etc/|"}, nil)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "should be absolute")
+
+	// result path already exists
+	err = applyTransforms(rootDir, true, []string{"s|app\\.yml$|roles/roles.yml|"}, nil)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "/etc/myapp/roles/roles.yml already exists")
+
+	// relative paths
+	assert.Nil(applyTransforms(rootDir, false, []string{"s|^etc/myapp/|conf/|"}, nil))
+
+	entries = getTarEntries(t, rootDir)
+	assert.Equal(byte(tar.TypeReg), entries["conf/app.yml"])
+	assert.Equal(byte(tar.TypeReg), entries["conf/roles/roles.yml"])
+	assert.NotContains(entries, "etc")
+
+	// ensured directories aren't removed
+	appDir := "usr/share/tarantool/myapp"
+	assert.Nil(os.MkdirAll(filepath.Join(rootDir, appDir, "var", "data"), 0755))
+	assert.Nil(ioutil.WriteFile(filepath.Join(rootDir, appDir, "var", "data", "seed.json"), []byte("{}"), 0644))
+
+	ensuredDirs := getEnsuredDirsPaths(filepath.Join(rootDir, appDir), []string{"var/data"})
+	assert.Nil(applyTransforms(rootDir, true, []string{"s|/var/data/seed\\.json$|/seed.json|"}, ensuredDirs))
+
+	entries = getTarEntries(t, rootDir)
+	assert.Equal(byte(tar.TypeReg), entries[appDir+"/seed.json"])
+	assert.Equal(byte(tar.TypeDir), entries[appDir+"/var/data"])
+	assert.NotContains(entries, appDir+"/var/data/seed.json")
+}
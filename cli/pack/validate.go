@@ -2,22 +2,220 @@ package pack
 
 import (
 	"fmt"
+	"path/filepath"
 
+	"github.com/tarantool/cartridge-cli/cli/common"
 	"github.com/tarantool/cartridge-cli/cli/context"
+	"github.com/tarantool/cartridge-cli/cli/docker"
+	"github.com/tarantool/cartridge-cli/cli/project"
+	"github.com/tarantool/cartridge-cli/cli/rpm"
 )
 
 func Validate(ctx *context.Ctx) error {
-	if ctx.Pack.Type != RpmType && ctx.Pack.Type != DebType {
+	if ctx.Pack.Type != RpmType && ctx.Pack.Type != DebType && ctx.Pack.Type != ApkType {
 		if ctx.Pack.UnitTemplatePath != "" {
-			return fmt.Errorf("--unit-template option can be used only with rpm and deb types")
+			return fmt.Errorf("--unit-template option can be used only with rpm, deb and apk types")
 		}
 
 		if ctx.Pack.InstUnitTemplatePath != "" {
-			return fmt.Errorf("--instantiated-unit-template option can be used only with rpm and deb types")
+			return fmt.Errorf("--instantiated-unit-template option can be used only with rpm, deb and apk types")
 		}
 
 		if ctx.Pack.StatboardUnitTemplatePath != "" {
-			return fmt.Errorf("--statboard-unit-template option can be used only with rpm and deb types")
+			return fmt.Errorf("--statboard-unit-template option can be used only with rpm, deb and apk types")
+		}
+	}
+
+	if err := checkUnitTemplates(ctx); err != nil {
+		return err
+	}
+
+	if ctx.Pack.Type != RpmType && ctx.Pack.Type != DebType && ctx.Pack.Type != ApkType {
+		if len(ctx.Pack.TmpfilesEntries) > 0 {
+			return fmt.Errorf("--tmpfiles-entry option can be used only with rpm, deb and apk types")
+		}
+	}
+
+	if ctx.Pack.Type != RpmType && ctx.Pack.Type != DebType {
+		if len(ctx.Pack.SysusersEntries) > 0 {
+			return fmt.Errorf("--sysusers-entry option can be used only with rpm and deb types")
+		}
+	}
+
+	if _, err := mergeConfEntries(getTmpfilesEntryKey, ctx.Pack.TmpfilesEntries); err != nil {
+		return fmt.Errorf("Invalid --tmpfiles-entry value: %s", err)
+	}
+
+	if _, err := mergeConfEntries(getSysusersEntryKey, ctx.Pack.SysusersEntries); err != nil {
+		return fmt.Errorf("Invalid --sysusers-entry value: %s", err)
+	}
+
+	if ctx.Pack.Type != RpmType && ctx.Pack.Type != DebType && ctx.Pack.Type != ApkType {
+		if ctx.Pack.DefaultFileMode != "" {
+			return fmt.Errorf("--default-file-mode option can be used only with rpm, deb and apk types")
+		}
+
+		if ctx.Pack.DefaultDirMode != "" {
+			return fmt.Errorf("--default-dir-mode option can be used only with rpm, deb and apk types")
+		}
+
+		if len(ctx.Pack.FileModes) > 0 {
+			return fmt.Errorf("--file-mode option can be used only with rpm, deb and apk types")
+		}
+	}
+
+	if fileModesAreSpecified(ctx) {
+		if _, err := parseFileModes(ctx); err != nil {
+			return err
+		}
+	}
+
+	if ctx.Pack.Type != TgzType {
+		if ctx.Pack.SplitSize > 0 {
+			return fmt.Errorf("--split-size option can be used only with tgz type")
+		}
+
+		if ctx.Pack.Compression != "" && ctx.Pack.Compression != GzipCompression {
+			return fmt.Errorf("--compression option can be used only with tgz type")
+		}
+	}
+
+	switch ctx.Pack.Compression {
+	case "", GzipCompression, ZstdCompression:
+	default:
+		return fmt.Errorf("Unknown compression %q. Supported compressions are %s and %s",
+			ctx.Pack.Compression, GzipCompression, ZstdCompression)
+	}
+
+	if err := checkCompressionLevel(ctx); err != nil {
+		return err
+	}
+
+	if ctx.Pack.Type != RpmType {
+		if len(ctx.Pack.RpmRecommends) > 0 {
+			return fmt.Errorf("--recommends option can be used only with rpm type")
+		}
+
+		if len(ctx.Pack.RpmSupplements) > 0 {
+			return fmt.Errorf("--supplements option can be used only with rpm type")
+		}
+
+		if len(ctx.Pack.RpmEnhances) > 0 {
+			return fmt.Errorf("--enhances option can be used only with rpm type")
+		}
+
+		if ctx.Pack.RpmMinVersion != "" {
+			return fmt.Errorf("--rpm-min-version option can be used only with rpm type")
+		}
+
+		if ctx.Pack.RpmBuildHost != "" {
+			return fmt.Errorf("--rpm-build-host option can be used only with rpm type")
+		}
+
+		if ctx.Pack.RpmPayloadDigestAlgo != "" {
+			return fmt.Errorf("--payload-digest-algo option can be used only with rpm type")
+		}
+
+		if ctx.Pack.RpmRelocateDocsDir != "" {
+			return fmt.Errorf("--relocate-docs option can be used only with rpm type")
+		}
+
+		if ctx.Pack.RpmSignKey != "" {
+			return fmt.Errorf("--sign-key option can be used only with rpm type")
+		}
+
+		if ctx.Pack.Reproducible {
+			return fmt.Errorf("--reproducible option can be used only with rpm type")
+		}
+
+		if ctx.Pack.RpmVerify {
+			return fmt.Errorf("--verify option can be used only with rpm type")
+		}
+	}
+
+	if ctx.Pack.RpmSignHeader && ctx.Pack.RpmSignKey == "" {
+		return fmt.Errorf("--sign-header option can be used only with --sign-key option")
+	}
+
+	if ctx.Pack.RpmRelocateDocsDir != "" && !filepath.IsAbs(ctx.Pack.RpmRelocateDocsDir) {
+		return fmt.Errorf("--relocate-docs option value should be an absolute path")
+	}
+
+	if len(ctx.Pack.RpmDocPatterns) > 0 && ctx.Pack.RpmRelocateDocsDir == "" {
+		return fmt.Errorf("--doc-pattern option can be used only with --relocate-docs option")
+	}
+
+	if ctx.Pack.RpmMinVersion != "" {
+		if err := rpm.CheckMinVersion(ctx); err != nil {
+			return err
+		}
+	}
+
+	if ctx.Pack.RpmPayloadDigestAlgo != "" {
+		if err := rpm.CheckPayloadDigestAlgo(ctx.Pack.RpmPayloadDigestAlgo); err != nil {
+			return fmt.Errorf("Invalid --payload-digest-algo value: %s", err)
+		}
+	}
+
+	if ctx.Pack.Type != RpmType && ctx.Pack.Type != DebType {
+		for _, kind := range project.InstallScripts {
+			if project.GetUserScriptPath(ctx, kind) != "" {
+				return fmt.Errorf("--%s option can be used only with rpm and deb types", kind)
+			}
+		}
+
+		if len(ctx.Pack.Deps) > 0 {
+			return fmt.Errorf("--deps option can be used only with rpm and deb types")
+		}
+
+		if ctx.Pack.DepsFile != "" {
+			return fmt.Errorf("--deps-file option can be used only with rpm and deb types")
+		}
+	}
+
+	if err := project.CheckUserScripts(ctx); err != nil {
+		return err
+	}
+
+	if _, err := project.GetUserDependencies(ctx); err != nil {
+		return err
+	}
+
+	if ctx.Pack.Type != DebType {
+		if ctx.Pack.DebConffilesFrom != "" {
+			return fmt.Errorf("--deb-conffiles-from option can be used only with deb type")
+		}
+
+		if len(ctx.Pack.DebConffiles) > 0 {
+			return fmt.Errorf("--deb-conffile option can be used only with deb type")
+		}
+
+		if ctx.Pack.DebArch != "" {
+			return fmt.Errorf("--deb-arch option can be used only with deb type")
+		}
+	}
+
+	if len(ctx.Pack.Transforms) > 0 {
+		if ctx.Pack.Type == DockerType {
+			return fmt.Errorf("--transform option can't be used with docker type")
+		}
+
+		if _, err := parseTransforms(ctx.Pack.Transforms); err != nil {
+			return err
+		}
+	}
+
+	if ctx.Pack.Type == DockerType && ctx.Pack.OutputDir != "" {
+		return fmt.Errorf("--output-dir option can't be used with docker type")
+	}
+
+	if ctx.Pack.Checksum {
+		if ctx.Pack.Type == DockerType {
+			return fmt.Errorf("--checksum option can't be used with docker type")
+		}
+
+		if ctx.Pack.SplitSize > 0 {
+			return fmt.Errorf("--checksum and --split-size options can't be used together")
 		}
 	}
 
@@ -25,6 +223,59 @@ func Validate(ctx *context.Ctx) error {
 		if len(ctx.Pack.ImageTags) > 0 {
 			return fmt.Errorf("--tag option can be used only with docker type")
 		}
+
+		if ctx.Pack.RuntimeDockerfile != "" {
+			return fmt.Errorf("--dockerfile option can be used only with docker type")
+		}
+
+		if ctx.Pack.BaseImage != "" {
+			return fmt.Errorf("--base-image option can be used only with docker type")
+		}
+
+		if ctx.Pack.Push {
+			return fmt.Errorf("--push option can be used only with docker type")
+		}
+
+	}
+
+	if ctx.Pack.Type != DockerType && len(ctx.Docker.Platforms) > 0 {
+		if ctx.Pack.Type != DebType {
+			return fmt.Errorf("--platform option can be used only with docker and deb types")
+		}
+
+		if !ctx.Build.InDocker {
+			return fmt.Errorf("--platform option can be used for deb type only with --use-docker flag")
+		}
+
+		if len(ctx.Docker.Platforms) > 1 {
+			return fmt.Errorf("--platform option accepts only one platform for deb type")
+		}
+	}
+
+	for _, platform := range ctx.Docker.Platforms {
+		if err := checkPlatform(platform); err != nil {
+			return fmt.Errorf("Invalid --platform value: %s", err)
+		}
+	}
+
+	if ctx.Pack.RuntimeDockerfile != "" && ctx.Pack.DockerFrom != "" {
+		return fmt.Errorf("--dockerfile and --from options can't be used together")
+	}
+
+	if ctx.Pack.RuntimeDockerfile != "" && ctx.Pack.BaseImage != "" {
+		return fmt.Errorf("--dockerfile and --base-image options can't be used together")
+	}
+
+	if ctx.Build.BaseImage != "" {
+		if err := docker.CheckImageReference(ctx.Build.BaseImage); err != nil {
+			return fmt.Errorf("Invalid --build-base-image value: %s", err)
+		}
+	}
+
+	if ctx.Pack.BaseImage != "" {
+		if err := docker.CheckImageReference(ctx.Pack.BaseImage); err != nil {
+			return fmt.Errorf("Invalid --base-image value: %s", err)
+		}
 	}
 
 	if !ctx.Build.InDocker && ctx.Pack.Type != DockerType {
@@ -40,10 +291,22 @@ func Validate(ctx *context.Ctx) error {
 			return fmt.Errorf("--from option can be used only with --use-docker flag or docker type")
 		}
 
+		if ctx.Build.BaseImage != "" {
+			return fmt.Errorf("--build-base-image option can be used only with --use-docker flag or docker type")
+		}
+
 		if ctx.Docker.NoCache {
 			return fmt.Errorf("--no-cache option can be used only with --use-docker flag or docker type")
 		}
 
+		if len(ctx.Docker.BuildArgs) > 0 {
+			return fmt.Errorf("--build-arg option can be used only with --use-docker flag or docker type")
+		}
+
+		if len(ctx.Docker.Secrets) > 0 {
+			return fmt.Errorf("--secret option can be used only with --use-docker flag or docker type")
+		}
+
 		if ctx.Build.SDKLocal {
 			return fmt.Errorf("--sdk-local option can be used only with --use-docker flag or docker type")
 		}
@@ -55,3 +318,30 @@ func Validate(ctx *context.Ctx) error {
 
 	return nil
 }
+
+// checkCompressionLevel checks that the compression level is in range
+// of the compressor used for the package type
+func checkCompressionLevel(ctx *context.Ctx) error {
+	if ctx.Pack.CompressionLevel == common.DefaultCompressionLevel {
+		return nil
+	}
+
+	if ctx.Pack.Type != TgzType && ctx.Pack.Type != RpmType && ctx.Pack.Type != DebType {
+		return fmt.Errorf("--compression-level option can be used only with tgz, rpm and deb types")
+	}
+
+	compression := GzipCompression
+	minLevel, maxLevel := common.GzipMinCompressionLevel, common.GzipMaxCompressionLevel
+
+	if ctx.Pack.Compression == ZstdCompression {
+		compression = ZstdCompression
+		minLevel, maxLevel = common.ZstdMinCompressionLevel, common.ZstdMaxCompressionLevel
+	}
+
+	if ctx.Pack.CompressionLevel < minLevel || ctx.Pack.CompressionLevel > maxLevel {
+		return fmt.Errorf("Compression level %d is out of %s levels range. Please, specify level from %d to %d",
+			ctx.Pack.CompressionLevel, compression, minLevel, maxLevel)
+	}
+
+	return nil
+}
@@ -0,0 +1,270 @@
+package pack
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func TestValidateCompression(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var ctx context.Ctx
+
+	ctx.Pack.Type = TgzType
+	for _, compression := range []string{"", GzipCompression, ZstdCompression} {
+		ctx.Pack.Compression = compression
+		assert.Nil(Validate(&ctx), compression)
+	}
+
+	ctx.Pack.Compression = "bzip2"
+	assert.EqualError(Validate(&ctx), `Unknown compression "bzip2". Supported compressions are gzip and zstd`)
+
+	ctx.Pack.Type = RpmType
+	ctx.Pack.Compression = ZstdCompression
+	assert.EqualError(Validate(&ctx), "--compression option can be used only with tgz type")
+}
+
+func TestValidateCompressionLevel(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var ctx context.Ctx
+
+	ctx.Pack.Type = TgzType
+	for _, level := range []int{0, 1, 9} {
+		ctx.Pack.CompressionLevel = level
+		assert.Nil(Validate(&ctx), level)
+	}
+
+	ctx.Pack.CompressionLevel = 10
+	assert.EqualError(Validate(&ctx),
+		"Compression level 10 is out of gzip levels range. Please, specify level from 1 to 9")
+
+	ctx.Pack.CompressionLevel = -1
+	assert.EqualError(Validate(&ctx),
+		"Compression level -1 is out of gzip levels range. Please, specify level from 1 to 9")
+
+	ctx.Pack.Compression = ZstdCompression
+	for _, level := range []int{1, 10, 22} {
+		ctx.Pack.CompressionLevel = level
+		assert.Nil(Validate(&ctx), level)
+	}
+
+	ctx.Pack.CompressionLevel = 23
+	assert.EqualError(Validate(&ctx),
+		"Compression level 23 is out of zstd levels range. Please, specify level from 1 to 22")
+
+	ctx.Pack.Compression = ""
+	ctx.Pack.CompressionLevel = 9
+	for _, packType := range []string{RpmType, DebType} {
+		ctx.Pack.Type = packType
+		assert.Nil(Validate(&ctx), packType)
+	}
+
+	ctx.Pack.Type = DockerType
+	assert.EqualError(Validate(&ctx), "--compression-level option can be used only with tgz, rpm and deb types")
+}
+
+func TestValidatePlatform(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var ctx context.Ctx
+
+	ctx.Pack.Type = DockerType
+	ctx.Docker.Platforms = []string{"linux/amd64", "linux/arm64"}
+	assert.Nil(Validate(&ctx))
+
+	ctx.Docker.Platforms = []string{"linux/amd64", "arm64"}
+	assert.EqualError(Validate(&ctx),
+		`Invalid --platform value: Invalid platform "arm64": should be OS/ARCH[/VARIANT] (e.g. linux/arm64)`)
+
+	ctx.Pack.Type = RpmType
+	ctx.Docker.Platforms = []string{"linux/arm64"}
+	assert.EqualError(Validate(&ctx), "--platform option can be used only with docker and deb types")
+
+	ctx.Pack.Type = DebType
+	assert.EqualError(Validate(&ctx), "--platform option can be used for deb type only with --use-docker flag")
+
+	ctx.Build.InDocker = true
+	assert.Nil(Validate(&ctx))
+
+	ctx.Docker.Platforms = []string{"linux/amd64", "linux/arm64"}
+	assert.EqualError(Validate(&ctx), "--platform option accepts only one platform for deb type")
+
+	ctx.Docker.Platforms = nil
+	ctx.Pack.DebArch = "arm64"
+	assert.Nil(Validate(&ctx))
+
+	ctx.Pack.Type = RpmType
+	assert.EqualError(Validate(&ctx), "--deb-arch option can be used only with deb type")
+}
+
+func TestValidateBuildArgs(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var ctx context.Ctx
+
+	ctx.Pack.Type = DockerType
+	ctx.Docker.BuildArgs = map[string]string{"HTTP_PROXY": "http://proxy:3128"}
+	ctx.Docker.Secrets = []string{"id=token,src=token.txt"}
+	assert.Nil(Validate(&ctx))
+
+	ctx.Pack.Type = RpmType
+	assert.EqualError(Validate(&ctx), "--build-arg option can be used only with --use-docker flag or docker type")
+
+	ctx.Build.InDocker = true
+	assert.Nil(Validate(&ctx))
+
+	ctx.Build.InDocker = false
+	ctx.Docker.BuildArgs = nil
+	assert.EqualError(Validate(&ctx), "--secret option can be used only with --use-docker flag or docker type")
+}
+
+func TestValidateBaseImage(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var ctx context.Ctx
+
+	ctx.Pack.Type = DockerType
+	ctx.Pack.BaseImage = "registry.local/mirror/centos:8"
+	ctx.Build.BaseImage = "registry.local/mirror/centos:8-build"
+	assert.Nil(Validate(&ctx))
+
+	ctx.Pack.BaseImage = "registry.local/mirror/CentOS"
+	assert.EqualError(Validate(&ctx),
+		`Invalid --base-image value: Invalid image reference "registry.local/mirror/CentOS"`)
+
+	ctx.Pack.BaseImage = "centos:8"
+	ctx.Build.BaseImage = "centos:"
+	assert.EqualError(Validate(&ctx), `Invalid --build-base-image value: Invalid image reference "centos:"`)
+
+	ctx.Build.BaseImage = "centos:8"
+	ctx.Pack.RuntimeDockerfile = "Dockerfile.runtime"
+	assert.EqualError(Validate(&ctx), "--dockerfile and --base-image options can't be used together")
+
+	ctx.Pack.RuntimeDockerfile = ""
+	ctx.Pack.Type = RpmType
+	assert.EqualError(Validate(&ctx), "--base-image option can be used only with docker type")
+
+	ctx.Pack.BaseImage = ""
+	assert.EqualError(Validate(&ctx), "--build-base-image option can be used only with --use-docker flag or docker type")
+
+	ctx.Build.InDocker = true
+	assert.Nil(Validate(&ctx))
+}
+
+func TestValidatePush(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var ctx context.Ctx
+
+	ctx.Pack.Type = DockerType
+	ctx.Pack.Push = true
+	assert.Nil(Validate(&ctx))
+
+	for _, packType := range []string{TgzType, RpmType, DebType, ApkType} {
+		ctx.Pack.Type = packType
+		assert.EqualError(Validate(&ctx), "--push option can be used only with docker type", packType)
+	}
+}
+
+func TestValidateDeps(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var ctx context.Ctx
+
+	ctx.Pack.Type = DebType
+	ctx.Pack.Deps = []string{"tarantool >= 2.5", "tarantool < 3"}
+	assert.Nil(Validate(&ctx))
+
+	ctx.Pack.Deps = []string{"tarantool >= 2.5", "tarantool >= 2.6"}
+	assert.EqualError(Validate(&ctx), `Dependencies "tarantool >= 2.5" and "tarantool >= 2.6" conflict`)
+
+	ctx.Pack.Type = TgzType
+	ctx.Pack.Deps = []string{"logrotate"}
+	assert.EqualError(Validate(&ctx), "--deps option can be used only with rpm and deb types")
+
+	ctx.Pack.Deps = nil
+	ctx.Pack.DepsFile = "deps.txt"
+	assert.EqualError(Validate(&ctx), "--deps-file option can be used only with rpm and deb types")
+}
+
+func TestValidateUnitTemplates(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "units")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	validTemplatePath := filepath.Join(tmpDir, "valid.service")
+	validTemplate := "[Service]\nExecStart={{ .Tarantool }} {{ .AppEntrypointPath }}\nMemoryMax=1G\n"
+	assert.Nil(ioutil.WriteFile(validTemplatePath, []byte(validTemplate), 0644))
+
+	invalidTemplatePath := filepath.Join(tmpDir, "invalid.service")
+	invalidTemplate := "[Service]\nExecStart={{ .Tarantool }\n"
+	assert.Nil(ioutil.WriteFile(invalidTemplatePath, []byte(invalidTemplate), 0644))
+
+	var ctx context.Ctx
+	ctx.Pack.Type = RpmType
+
+	ctx.Pack.UnitTemplatePath = validTemplatePath
+	ctx.Pack.InstUnitTemplatePath = validTemplatePath
+	assert.Nil(Validate(&ctx))
+
+	ctx.Pack.InstUnitTemplatePath = invalidTemplatePath
+	err = Validate(&ctx)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Invalid --instantiated-unit-template file "+invalidTemplatePath)
+
+	ctx.Pack.InstUnitTemplatePath = ""
+	ctx.Pack.StatboardUnitTemplatePath = filepath.Join(tmpDir, "missing.service")
+	err = Validate(&ctx)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Failed to read --stateboard-unit-template file")
+}
+
+func TestValidateDropInEntries(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var ctx context.Ctx
+
+	ctx.Pack.Type = RpmType
+	ctx.Pack.TmpfilesEntries = []string{"d /var/run/tarantool/myapp 0755 tarantool tarantool"}
+	ctx.Pack.SysusersEntries = []string{"m tarantool adm"}
+	assert.Nil(Validate(&ctx))
+
+	ctx.Pack.SysusersEntries = []string{"m tarantool"}
+	assert.EqualError(Validate(&ctx), `Invalid --sysusers-entry value: "m tarantool" should contain the group`)
+
+	ctx.Pack.Type = ApkType
+	ctx.Pack.SysusersEntries = []string{"m tarantool adm"}
+	assert.EqualError(Validate(&ctx), "--sysusers-entry option can be used only with rpm and deb types")
+
+	ctx.Pack.Type = TgzType
+	assert.EqualError(Validate(&ctx), "--tmpfiles-entry option can be used only with rpm, deb and apk types")
+}
@@ -0,0 +1,136 @@
+package project
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+var (
+	depRgx = regexp.MustCompile(`^\s*([^\s<>=]+)\s*(?:(<=|>=|<|>|=)\s*([^\s<>=]\S*))?\s*$`)
+)
+
+// Dependency is the package dependency `<name> [<operator> <version>]`
+type Dependency struct {
+	Name     string
+	Operator string
+	Version  string
+}
+
+func (dep Dependency) String() string {
+	if dep.Operator == "" {
+		return dep.Name
+	}
+
+	return fmt.Sprintf("%s %s %s", dep.Name, dep.Operator, dep.Version)
+}
+
+// bound returns the kind of the version constraint.
+// Package can have one lower and one upper bound,
+// exact version or unversioned dependency can't be combined with others
+func (dep Dependency) bound() string {
+	switch dep.Operator {
+	case "<", "<=":
+		return "upper"
+	case ">", ">=":
+		return "lower"
+	}
+
+	return "exact"
+}
+
+// ParseDependency parses dependency string like
+// "name", "name >= 1.2.3" or "name<2"
+func ParseDependency(depStr string) (Dependency, error) {
+	matches := depRgx.FindStringSubmatch(depStr)
+	if matches == nil {
+		return Dependency{}, fmt.Errorf("Invalid dependency format: %q. "+
+			"Should be `<name> [<operator> <version>]`", depStr)
+	}
+
+	return Dependency{
+		Name:     matches[1],
+		Operator: matches[2],
+		Version:  matches[3],
+	}, nil
+}
+
+// GetUserDependencies returns package dependencies specified by
+// --deps-file and --deps flags (in this order).
+// Dependency specified several times is added once,
+// conflicting dependencies of the same package cause an error
+func GetUserDependencies(ctx *context.Ctx) ([]Dependency, error) {
+	var depsStrs []string
+
+	if ctx.Pack.DepsFile != "" {
+		content, err := common.GetFileContent(ctx.Pack.DepsFile)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read dependencies file: %s", err)
+		}
+
+		for _, line := range strings.Split(content, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			depsStrs = append(depsStrs, line)
+		}
+	}
+
+	depsStrs = append(depsStrs, ctx.Pack.Deps...)
+
+	var deps []Dependency
+
+	for _, depStr := range depsStrs {
+		dep, err := ParseDependency(depStr)
+		if err != nil {
+			return nil, err
+		}
+
+		added, err := checkDependencyConflicts(deps, dep)
+		if err != nil {
+			return nil, err
+		}
+
+		if !added {
+			deps = append(deps, dep)
+		}
+	}
+
+	return deps, nil
+}
+
+// checkDependencyConflicts returns true if the same dependency is already added
+// and an error if the dependency conflicts with one of the added ones
+func checkDependencyConflicts(deps []Dependency, dep Dependency) (bool, error) {
+	for _, addedDep := range deps {
+		if addedDep.Name != dep.Name {
+			continue
+		}
+
+		if addedDep == dep {
+			return true, nil
+		}
+
+		if addedDep.bound() == dep.bound() || addedDep.bound() == "exact" || dep.bound() == "exact" {
+			return false, fmt.Errorf("Dependencies %q and %q conflict", addedDep, dep)
+		}
+	}
+
+	return false, nil
+}
+
+// DependsOn checks if the dependencies list contains the specified package
+func DependsOn(deps []Dependency, name string) bool {
+	for _, dep := range deps {
+		if dep.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
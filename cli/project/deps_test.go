@@ -0,0 +1,84 @@
+package project
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func TestGetUserDependencies(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "deps")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var ctx context.Ctx
+
+	// no deps
+	deps, err := GetUserDependencies(&ctx)
+	assert.Nil(err)
+	assert.Len(deps, 0)
+
+	depsFilePath := filepath.Join(tmpDir, "deps.txt")
+	depsFileContent := `# runtime deps
+tarantool >= 2.5.1
+
+tarantool<3
+  logrotate
+`
+	assert.Nil(ioutil.WriteFile(depsFilePath, []byte(depsFileContent), 0644))
+
+	ctx.Pack.DepsFile = depsFilePath
+	ctx.Pack.Deps = []string{"openssl = 1.1.1", "logrotate"}
+
+	deps, err = GetUserDependencies(&ctx)
+	assert.Nil(err)
+	assert.Equal([]Dependency{
+		{Name: "tarantool", Operator: ">=", Version: "2.5.1"},
+		{Name: "tarantool", Operator: "<", Version: "3"},
+		{Name: "logrotate"},
+		{Name: "openssl", Operator: "=", Version: "1.1.1"},
+	}, deps)
+
+	assert.Equal("tarantool >= 2.5.1", deps[0].String())
+	assert.Equal("logrotate", deps[2].String())
+
+	// conflicts
+	conflicts := [][]string{
+		{"tarantool >= 2.5", "tarantool > 2.6"},
+		{"tarantool < 3", "tarantool <= 2.8"},
+		{"tarantool = 2.5", "tarantool < 3"},
+		{"tarantool", "tarantool >= 2.5"},
+	}
+
+	ctx.Pack.DepsFile = ""
+	for _, depsStrs := range conflicts {
+		ctx.Pack.Deps = depsStrs
+
+		_, err = GetUserDependencies(&ctx)
+		assert.EqualError(err, `Dependencies "`+depsStrs[0]+`" and "`+depsStrs[1]+`" conflict`)
+	}
+
+	// bad format
+	ctx.Pack.Deps = []string{"tarantool 2.5"}
+
+	_, err = GetUserDependencies(&ctx)
+	assert.EqualError(err, "Invalid dependency format: \"tarantool 2.5\". Should be `<name> [<operator> <version>]`")
+
+	// file doesn't exist
+	ctx.Pack.Deps = nil
+	ctx.Pack.DepsFile = filepath.Join(tmpDir, "unknown.txt")
+
+	_, err = GetUserDependencies(&ctx)
+	assert.NotNil(err)
+}
@@ -1,8 +1,10 @@
 package project
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -16,7 +18,7 @@ var (
 )
 
 func init() {
-	fromLayerRegexp = regexp.MustCompile(`^from\s+centos:[78]$`)
+	fromLayerRegexp = regexp.MustCompile(`^from\s+(--platform=\S+\s+)?centos:[78]$`)
 }
 
 type opensourseCtx struct {
@@ -40,6 +42,12 @@ func GetBuildImageDockerfileTemplate(ctx *context.Ctx) (*templates.FileTemplate,
 		return nil, fmt.Errorf("Failed to get base build Dockerfile %s: %s", ctx.Build.DockerFrom, err)
 	}
 
+	if ctx.Build.BaseImage != "" {
+		if baseLayers, err = replaceBaseImage(baseLayers, ctx.Build.BaseImage); err != nil {
+			return nil, fmt.Errorf("Failed to set build image base image: %s", err)
+		}
+	}
+
 	installTarantoolLayers, err := getInstallTarantoolLayers(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to get install Tarantool Dockerfile layers: %s", err)
@@ -70,6 +78,12 @@ func GetRuntimeImageDockerfileTemplate(ctx *context.Ctx) (*templates.FileTemplat
 		return nil, fmt.Errorf("Failed to get base runtime Dockerfile %s: %s", ctx.Build.DockerFrom, err)
 	}
 
+	if ctx.Pack.BaseImage != "" {
+		if baseLayers, err = replaceBaseImage(baseLayers, ctx.Pack.BaseImage); err != nil {
+			return nil, fmt.Errorf("Failed to set runtime image base image: %s", err)
+		}
+	}
+
 	dockerfileParts = append(dockerfileParts, baseLayers)
 
 	// Install Tarantool Opensource or create tarantool user for Enterprise
@@ -119,6 +133,41 @@ func getBaseLayers(specifiedDockerfile, defaultLayers string) (string, error) {
 	return baseLayers, nil
 }
 
+// replaceBaseImage replaces the image of the first FROM instruction.
+// FROM flags (e.g. --platform) and stage name are kept
+func replaceBaseImage(layers string, baseImage string) (string, error) {
+	lines := strings.Split(layers, "\n")
+
+	for i, line := range lines {
+		trimmedLine := strings.TrimSpace(common.TrimSince(line, "#"))
+		if trimmedLine == "" {
+			continue
+		}
+
+		parts := strings.Fields(trimmedLine)
+		if strings.ToLower(parts[0]) != "from" {
+			// ARG instructions can precede FROM
+			continue
+		}
+
+		imageIndex := 1
+		for imageIndex < len(parts) && strings.HasPrefix(parts[imageIndex], "--") {
+			imageIndex++
+		}
+
+		if imageIndex == len(parts) {
+			return "", fmt.Errorf("FROM instruction doesn't contain image: %s", trimmedLine)
+		}
+
+		parts[imageIndex] = baseImage
+		lines[i] = strings.Join(parts, " ")
+
+		return strings.Join(lines, "\n"), nil
+	}
+
+	return "", fmt.Errorf("Base Dockerfile doesn't contain FROM instruction")
+}
+
 func CheckBaseDockerfile(dockerfilePath string) error {
 	file, err := os.Open(dockerfilePath)
 	if err != nil {
@@ -138,6 +187,11 @@ func CheckBaseDockerfile(dockerfilePath string) error {
 			continue
 		}
 
+		// ARG instructions can precede FROM
+		if strings.HasPrefix(strings.ToLower(line), "arg ") {
+			continue
+		}
+
 		fromLine = line
 		break
 
@@ -151,6 +205,88 @@ func CheckBaseDockerfile(dockerfilePath string) error {
 	return nil
 }
 
+// CheckRuntimeDockerfile checks that custom runtime image Dockerfile
+// copies application files to the application directory
+// (COPY or ADD instruction with the destination equal to appDir)
+func CheckRuntimeDockerfile(dockerfilePath string, appDir string) error {
+	file, err := os.Open(dockerfilePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := common.FileLinesScanner(file)
+
+	var instruction string
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if instruction == "" && strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// join continuation lines
+		if strings.HasSuffix(line, "\\") {
+			instruction += strings.TrimSuffix(line, "\\") + " "
+			continue
+		}
+
+		instruction += line
+
+		if copiesToDir(instruction, appDir) {
+			return nil
+		}
+
+		instruction = ""
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("Application files should be copied to %s via COPY or ADD instruction", appDir)
+}
+
+func copiesToDir(instruction string, dir string) bool {
+	parts := strings.Fields(instruction)
+	if len(parts) < 3 {
+		return false
+	}
+
+	command := strings.ToLower(parts[0])
+	if command != "copy" && command != "add" {
+		return false
+	}
+
+	var args []string
+
+	argsStr := strings.TrimSpace(strings.TrimPrefix(instruction, parts[0]))
+	for strings.HasPrefix(argsStr, "--") {
+		// skip flags like --chown=tarantool:tarantool
+		flagEnd := strings.IndexAny(argsStr, " \t")
+		if flagEnd == -1 {
+			return false
+		}
+
+		argsStr = strings.TrimSpace(argsStr[flagEnd:])
+	}
+
+	if strings.HasPrefix(argsStr, "[") {
+		if err := json.Unmarshal([]byte(argsStr), &args); err != nil {
+			return false
+		}
+	} else {
+		args = strings.Fields(argsStr)
+	}
+
+	if len(args) < 2 {
+		return false
+	}
+
+	return filepath.Clean(args[len(args)-1]) == filepath.Clean(dir)
+}
+
 func getInstallTarantoolLayers(ctx *context.Ctx) (string, error) {
 	var installTarantoolLayers string
 	var err error
@@ -64,6 +64,11 @@ FROM centos:8`)
 	err = CheckBaseDockerfile(f.Name())
 	assert.Nil(err)
 
+	writeDockerfile(f, `ARG TARGETPLATFORM
+FROM --platform=$TARGETPLATFORM centos:8`)
+	err = CheckBaseDockerfile(f.Name())
+	assert.Nil(err)
+
 	// Error
 
 	writeDockerfile(f, ``)
@@ -81,6 +86,66 @@ FROM ubuntu:eoan`)
 	assert.EqualError(err, baseImageError)
 }
 
+func TestCheckRuntimeDockerfile(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	var err error
+	appDir := "/usr/share/tarantool/myapp"
+	copyError := "Application files should be copied to /usr/share/tarantool/myapp via COPY or ADD instruction"
+
+	// create tmp Dockerfile
+	f, err := ioutil.TempFile("", "Dockerfile")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	// non existing file
+	err = CheckRuntimeDockerfile("bad-path", appDir)
+	assert.EqualError(err, "open bad-path: no such file or directory")
+
+	// OK
+	writeDockerfile(f, `FROM centos:8
+COPY . /usr/share/tarantool/myapp`)
+	err = CheckRuntimeDockerfile(f.Name(), appDir)
+	assert.Nil(err)
+
+	writeDockerfile(f, `FROM centos:8
+copy --chown=tarantool:tarantool . /usr/share/tarantool/myapp/`)
+	err = CheckRuntimeDockerfile(f.Name(), appDir)
+	assert.Nil(err)
+
+	writeDockerfile(f, `FROM centos:8
+ADD ["init.lua", "app", "/usr/share/tarantool/myapp"]`)
+	err = CheckRuntimeDockerfile(f.Name(), appDir)
+	assert.Nil(err)
+
+	writeDockerfile(f, `FROM centos:8
+COPY --chown=tarantool:tarantool \
+    . \
+    /usr/share/tarantool/myapp`)
+	err = CheckRuntimeDockerfile(f.Name(), appDir)
+	assert.Nil(err)
+
+	// Error
+
+	writeDockerfile(f, `FROM centos:8`)
+	err = CheckRuntimeDockerfile(f.Name(), appDir)
+	assert.EqualError(err, copyError)
+
+	writeDockerfile(f, `FROM centos:8
+# COPY . /usr/share/tarantool/myapp`)
+	err = CheckRuntimeDockerfile(f.Name(), appDir)
+	assert.EqualError(err, copyError)
+
+	writeDockerfile(f, `FROM centos:8
+COPY . /usr/share/tarantool/other-app
+RUN echo /usr/share/tarantool/myapp`)
+	err = CheckRuntimeDockerfile(f.Name(), appDir)
+	assert.EqualError(err, copyError)
+}
+
 func TestGetBaseLayers(t *testing.T) {
 	assert := assert.New(t)
 
@@ -114,6 +179,39 @@ func TestGetBaseLayers(t *testing.T) {
 	assert.Equal(baseDockerfileContent, layers)
 }
 
+func TestReplaceBaseImage(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var err error
+	var layers string
+
+	baseImage := "registry.local/mirror/centos:8"
+
+	layers, err = replaceBaseImage(defaultBaseLayers, baseImage)
+	assert.Nil(err)
+	assert.Equal("FROM registry.local/mirror/centos:8\n", layers)
+
+	layers, err = replaceBaseImage(`# FROM centos:7
+ARG CENTOS_VERSION=8
+from --platform=linux/amd64 centos:${CENTOS_VERSION} AS base # comment
+RUN yum install -y zip
+FROM base`, baseImage)
+	assert.Nil(err)
+	assert.Equal(`# FROM centos:7
+ARG CENTOS_VERSION=8
+from --platform=linux/amd64 registry.local/mirror/centos:8 AS base
+RUN yum install -y zip
+FROM base`, layers)
+
+	_, err = replaceBaseImage("# FROM centos:8\n", baseImage)
+	assert.EqualError(err, "Base Dockerfile doesn't contain FROM instruction")
+
+	_, err = replaceBaseImage("FROM --platform=linux/amd64\n", baseImage)
+	assert.EqualError(err, "FROM instruction doesn't contain image: FROM --platform=linux/amd64")
+}
+
 func TestGetInstallTarantoolLayers(t *testing.T) {
 	assert := assert.New(t)
 
@@ -10,7 +10,9 @@ import (
 )
 
 const (
-	cartridgeLocalConf = ".cartridge.yml"
+	// CartridgeLocalConf is the application configuration file.
+	// It contains running paths and the pack section
+	CartridgeLocalConf = ".cartridge.yml"
 
 	defaultEntrypoint           = "init.lua"
 	defaultStateboardEntrypoint = "stateboard.init.lua"
@@ -172,7 +174,7 @@ func SetLocalRunningPaths(ctx *context.Ctx) error {
 	}
 
 	conf := make(map[string]interface{})
-	cartridgeConfPath := filepath.Join(curDir, cartridgeLocalConf)
+	cartridgeConfPath := filepath.Join(curDir, CartridgeLocalConf)
 
 	if _, err := os.Stat(cartridgeConfPath); err == nil {
 		if conf, err = common.ParseYmlFile(cartridgeConfPath); err != nil {
@@ -337,5 +339,6 @@ const (
 /bin/sh -c 'chown root:root /etc/systemd/system/{{ .Name }}.service'
 /bin/sh -c 'chown root:root /etc/systemd/system/{{ .Name }}@.service'
 /bin/sh -c 'chown root:root /usr/lib/tmpfiles.d/{{ .Name }}.conf'
+/bin/sh -c 'chown root:root /usr/lib/sysusers.d/{{ .Name }}.conf'
 `
 )
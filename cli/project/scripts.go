@@ -0,0 +1,92 @@
+package project
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+// Install scripts kinds (the same as DEB maintainer scripts names)
+const (
+	PreInstScript  = "preinst"
+	PostInstScript = "postinst"
+	PreRmScript    = "prerm"
+	PostRmScript   = "postrm"
+)
+
+// InstallScripts are the install scripts kinds in the order they are run
+var InstallScripts = []string{PreInstScript, PostInstScript, PreRmScript, PostRmScript}
+
+// GetUserScriptPath returns the path to the user-provided install script
+// specified by --preinst, --postinst, --prerm or --postrm flag
+func GetUserScriptPath(ctx *context.Ctx, kind string) string {
+	switch kind {
+	case PreInstScript:
+		return ctx.Pack.PreInstScriptPath
+	case PostInstScript:
+		return ctx.Pack.PostInstScriptPath
+	case PreRmScript:
+		return ctx.Pack.PreRmScriptPath
+	case PostRmScript:
+		return ctx.Pack.PostRmScriptPath
+	}
+
+	return ""
+}
+
+func readUserScript(ctx *context.Ctx, kind string) (string, error) {
+	scriptPath := GetUserScriptPath(ctx, kind)
+
+	content, err := common.GetFileContent(scriptPath)
+	if err != nil {
+		return "", fmt.Errorf(
+			"Failed to read --%s script: %s. It's run after the cartridge-generated %s script, "+
+				"so the file should exist",
+			kind, err, kind,
+		)
+	}
+
+	return content, nil
+}
+
+// CheckUserScripts checks that specified user-provided install scripts can be read
+func CheckUserScripts(ctx *context.Ctx) error {
+	for _, kind := range InstallScripts {
+		if GetUserScriptPath(ctx, kind) == "" {
+			continue
+		}
+
+		if _, err := readUserScript(ctx, kind); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetUserScriptSection returns the user-provided install script content
+// delimited by comments. It should be appended to the generated script.
+// Empty string is returned if the script isn't specified
+func GetUserScriptSection(ctx *context.Ctx, kind string) (string, error) {
+	scriptPath := GetUserScriptPath(ctx, kind)
+	if scriptPath == "" {
+		return "", nil
+	}
+
+	content, err := readUserScript(ctx, kind)
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+
+	return fmt.Sprintf(
+		"\n# ---- BEGIN user-provided %s script (%s) ----\n%s# ---- END user-provided %s script ----\n",
+		kind, filepath.Base(scriptPath), content, kind,
+	), nil
+}
@@ -2,6 +2,8 @@ package repair
 
 import (
 	"fmt"
+	"net"
+	"strconv"
 
 	"github.com/tarantool/cartridge-cli/cli/common"
 	"github.com/tarantool/cartridge-cli/cli/context"
@@ -29,3 +31,22 @@ func patchInstanceURI(topologyConf *TopologyConfType, ctx *context.Ctx) error {
 
 	return nil
 }
+
+// checkAdvertiseURI checks that URI is HOST:PORT,
+// since it's the format cartridge uses for advertise URI
+func checkAdvertiseURI(uri string) error {
+	host, portStr, err := net.SplitHostPort(uri)
+	if err != nil {
+		return fmt.Errorf("Invalid advertise URI %q: should be HOST:PORT", uri)
+	}
+
+	if host == "" {
+		return fmt.Errorf("Invalid advertise URI %q: host is empty", uri)
+	}
+
+	if port, err := strconv.Atoi(portStr); err != nil || port <= 0 || port > 65535 {
+		return fmt.Errorf("Invalid advertise URI %q: port should be a number from 1 to 65535", uri)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package repair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckAdvertiseURI(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	for _, uri := range []string{"localhost:3301", "10.0.0.2:3301", "[::1]:3301", "my-host.local:65535"} {
+		assert.Nil(checkAdvertiseURI(uri), uri)
+	}
+
+	assert.EqualError(checkAdvertiseURI("localhost"), `Invalid advertise URI "localhost": should be HOST:PORT`)
+	assert.EqualError(checkAdvertiseURI(":3301"), `Invalid advertise URI ":3301": host is empty`)
+
+	for _, uri := range []string{"localhost:port", "localhost:0", "localhost:65536"} {
+		assert.EqualError(checkAdvertiseURI(uri),
+			`Invalid advertise URI "`+uri+`": port should be a number from 1 to 65535`)
+	}
+}
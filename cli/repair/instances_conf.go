@@ -0,0 +1,352 @@
+package repair
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apex/log"
+	"gopkg.in/yaml.v2"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+	"github.com/tarantool/cartridge-cli/cli/context"
+	"github.com/tarantool/cartridge-cli/cli/project"
+)
+
+const (
+	instanceProbeTimeout = 3 * time.Second
+
+	// values are taken from the instance runtime state
+	// since the instances config can be already lost or corrupted
+	probeInstanceFuncBody = `
+local myself = require('membership').myself()
+if myself == nil then
+	return nil, 'Membership is not initialized'
+end
+
+local httpd = require('cartridge').service_get('httpd')
+
+return {
+	instance_name = os.getenv('TARANTOOL_INSTANCE_NAME'),
+	advertise_uri = myself.uri,
+	http_port = httpd ~= nil and httpd.port or nil,
+}
+`
+)
+
+var (
+	runDirFilesExts = []string{".pid", ".control"}
+)
+
+// instanceConf is the instance section of the instances config
+type instanceConf struct {
+	AdvertiseURI string `yaml:"advertise_uri,omitempty"`
+	HTTPPort     int    `yaml:"http_port,omitempty"`
+}
+
+// ReloadInstancesConf regenerates the instances config by the running instances.
+// Instances are found by the PID files and console sockets in the run dir
+// and probed over the console for their names and advertise URIs
+func ReloadInstancesConf(ctx *context.Ctx) error {
+	if err := project.SetSystemRunningPaths(ctx); err != nil {
+		return fmt.Errorf("Failed to get default paths: %s", err)
+	}
+
+	confPath, err := getInstancesConfPath(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Restore %s instances configuration by the running instances", ctx.Project.Name)
+	log.Debugf("Run directory is set to: %s", ctx.Running.RunDir)
+
+	instanceNames, err := getRunDirInstanceNames(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to find application instances in the run directory: %s", err)
+	}
+
+	instancesConf := make(map[string]instanceConf)
+	for _, instanceName := range instanceNames {
+		probedName, conf, err := probeInstance(project.GetInstanceConsoleSock(ctx, instanceName))
+		if err != nil {
+			log.Warnf("Instance %s is skipped: %s", instanceName, err)
+			continue
+		}
+
+		if probedName != "" && probedName != instanceName {
+			log.Warnf("Instance %s is configured as %s, this name is used", instanceName, probedName)
+			instanceName = probedName
+		}
+
+		instanceID := project.GetInstanceID(ctx, instanceName)
+		if _, found := instancesConf[instanceID]; found {
+			return fmt.Errorf("Instance %s is found more than once", instanceName)
+		}
+
+		instancesConf[instanceID] = *conf
+	}
+
+	if len(instancesConf) == 0 {
+		return fmt.Errorf("No running instances found in %s", ctx.Running.RunDir)
+	}
+
+	return writeInstancesConf(confPath, instancesConf, ctx)
+}
+
+// getInstancesConfPath returns the path of the instances config to be written.
+// If the config path is a directory, <app-name>.yml file in it is used
+func getInstancesConfPath(ctx *context.Ctx) (string, error) {
+	fileInfo, err := os.Stat(ctx.Running.ConfPath)
+	if os.IsNotExist(err) {
+		return ctx.Running.ConfPath, nil
+	} else if err != nil {
+		return "", fmt.Errorf("Failed to use instances config path: %s", err)
+	}
+
+	if fileInfo.IsDir() {
+		return filepath.Join(ctx.Running.ConfPath, fmt.Sprintf("%s.yml", ctx.Project.Name)), nil
+	}
+
+	return ctx.Running.ConfPath, nil
+}
+
+// getRunDirInstanceNames returns sorted names of the application instances
+// that have PID files or console sockets in the run dir
+func getRunDirInstanceNames(ctx *context.Ctx) ([]string, error) {
+	runDirFiles, err := ioutil.ReadDir(ctx.Running.RunDir)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list the run directory: %s", err)
+	}
+
+	appFilesPrefix := fmt.Sprintf("%s.", ctx.Project.Name)
+	foundNames := make(map[string]struct{})
+
+	for _, runDirFile := range runDirFiles {
+		fileName := runDirFile.Name()
+		if !strings.HasPrefix(fileName, appFilesPrefix) {
+			continue
+		}
+
+		for _, ext := range runDirFilesExts {
+			if strings.HasSuffix(fileName, ext) {
+				instanceName := strings.TrimSuffix(strings.TrimPrefix(fileName, appFilesPrefix), ext)
+				if instanceName != "" {
+					foundNames[instanceName] = struct{}{}
+				}
+			}
+		}
+	}
+
+	if len(foundNames) == 0 {
+		return nil, fmt.Errorf("No instance PID files or console sockets found in %s", ctx.Running.RunDir)
+	}
+
+	instanceNames := make([]string, 0, len(foundNames))
+	for instanceName := range foundNames {
+		instanceNames = append(instanceNames, instanceName)
+	}
+
+	sort.Strings(instanceNames)
+
+	return instanceNames, nil
+}
+
+// probeInstance returns the configured instance name and options
+// evaluated over the instance console socket
+func probeInstance(consoleSock string) (string, *instanceConf, error) {
+	if _, err := os.Stat(consoleSock); err != nil {
+		return "", nil, fmt.Errorf("Failed to use console socket: %s", err)
+	}
+
+	conn, err := common.ConnectToTarantoolSocket(consoleSock)
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed to connect to console socket: %s", err)
+	}
+	defer conn.Close()
+
+	resRaw, err := common.EvalTarantoolConn(conn, probeInstanceFuncBody, common.ConnOpts{
+		ReadTimeout: instanceProbeTimeout,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed to get instance options: %s", err)
+	}
+
+	resMap, ok := resRaw.(map[interface{}]interface{})
+	if !ok {
+		return "", nil, project.InternalError("Instance options isn't a map: %#v", resRaw)
+	}
+
+	return parseProbedInstance(resMap)
+}
+
+func parseProbedInstance(resMap map[interface{}]interface{}) (string, *instanceConf, error) {
+	var instanceName string
+	var conf instanceConf
+
+	if nameRaw, found := resMap["instance_name"]; found {
+		var ok bool
+		if instanceName, ok = nameRaw.(string); !ok {
+			return "", nil, fmt.Errorf("Instance name isn't a string: %#v", nameRaw)
+		}
+	}
+
+	uriRaw, found := resMap["advertise_uri"]
+	if !found {
+		return "", nil, fmt.Errorf("Advertise URI isn't configured")
+	}
+
+	var ok bool
+	if conf.AdvertiseURI, ok = uriRaw.(string); !ok {
+		return "", nil, fmt.Errorf("Advertise URI isn't a string: %#v", uriRaw)
+	}
+
+	if portRaw, found := resMap["http_port"]; found {
+		if conf.HTTPPort, ok = portRaw.(int); !ok {
+			return "", nil, fmt.Errorf("HTTP port isn't a number: %#v", portRaw)
+		}
+	}
+
+	return instanceName, &conf, nil
+}
+
+// writeInstancesConf writes the probed instances options to the config file.
+// If the existing config can be parsed, probed options are set in the existing
+// instances sections, other options and sections are kept.
+// Existing config is backed up before overwriting
+func writeInstancesConf(confPath string, instancesConf map[string]instanceConf, ctx *context.Ctx) error {
+	var oldConfContent []byte
+
+	if _, err := os.Stat(confPath); err == nil {
+		if oldConfContent, err = common.GetFileContentBytes(confPath); err != nil {
+			return fmt.Errorf("Failed to read instances config: %s", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("Failed to use instances config: %s", err)
+	}
+
+	newConfContent, err := getNewInstancesConfContent(oldConfContent, instancesConf, ctx)
+	if err != nil {
+		return err
+	}
+
+	diffLines, err := getDiffLines(oldConfContent, newConfContent, confPath, confPath)
+	if err != nil {
+		log.Warnf("Failed to get instances config difference: %s", err)
+	} else if len(diffLines) == 0 {
+		log.Infof("Instances config %s is up to date", confPath)
+		return nil
+	} else {
+		fmt.Printf("%s\n", strings.Join(diffLines, "\n"))
+	}
+
+	if ctx.Repair.DryRun {
+		return nil
+	}
+
+	if oldConfContent != nil {
+		backupPath, err := createFileBackup(confPath)
+		if err != nil {
+			return fmt.Errorf("Failed to create instances config backup: %s", err)
+		}
+
+		log.Infof("Created backup file: %s", backupPath)
+	}
+
+	if err := ioutil.WriteFile(confPath, newConfContent, 0644); err != nil {
+		return fmt.Errorf("Failed to write instances config: %s", err)
+	}
+
+	log.Infof("Instances config is written to %s", confPath)
+
+	return nil
+}
+
+// getNewInstancesConfContent returns the config content with the probed instances options.
+// Sections of the instances that weren't probed are left unchanged,
+// sections of the new instances are appended in the sorted order
+func getNewInstancesConfContent(oldConfContent []byte, instancesConf map[string]instanceConf,
+	ctx *context.Ctx) ([]byte, error) {
+	newConf := make(yaml.MapSlice, 0)
+
+	oldConf := make(yaml.MapSlice, 0)
+	if err := yaml.Unmarshal(oldConfContent, &oldConf); err != nil {
+		log.Warnf("Failed to parse existing instances config, it's overwritten: %s", err)
+		oldConf = nil
+	}
+
+	mergedIDs := make(map[string]bool)
+	for _, item := range oldConf {
+		if instanceID, ok := item.Key.(string); ok {
+			if conf, found := instancesConf[instanceID]; found && !mergedIDs[instanceID] {
+				item.Value = mergeInstanceConf(item.Value, conf)
+				mergedIDs[instanceID] = true
+			}
+		}
+
+		newConf = append(newConf, item)
+	}
+
+	instanceIDs := make([]string, 0, len(instancesConf))
+	for instanceID := range instancesConf {
+		if !mergedIDs[instanceID] {
+			instanceIDs = append(instanceIDs, instanceID)
+		}
+	}
+
+	sort.Strings(instanceIDs)
+
+	for _, instanceID := range instanceIDs {
+		newConf = append(newConf, yaml.MapItem{
+			Key:   instanceID,
+			Value: mergeInstanceConf(nil, instancesConf[instanceID]),
+		})
+	}
+
+	newConfContent, err := yaml.Marshal(newConf)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to encode instances config: %s", err)
+	}
+
+	return newConfContent, nil
+}
+
+// mergeInstanceConf sets the probed options in the existing instance section.
+// Other options and the options order are kept.
+// If the section isn't a map, it's replaced with the probed options
+func mergeInstanceConf(sectionRaw interface{}, conf instanceConf) yaml.MapSlice {
+	section, ok := sectionRaw.(yaml.MapSlice)
+	if !ok {
+		section = make(yaml.MapSlice, 0)
+	}
+
+	probedOpts := yaml.MapSlice{
+		{Key: "advertise_uri", Value: conf.AdvertiseURI},
+	}
+
+	if conf.HTTPPort != 0 {
+		probedOpts = append(probedOpts, yaml.MapItem{Key: "http_port", Value: conf.HTTPPort})
+	}
+
+	merged := make(yaml.MapSlice, len(section))
+	copy(merged, section)
+
+	for _, opt := range probedOpts {
+		found := false
+		for i := range merged {
+			if merged[i].Key == opt.Key {
+				merged[i].Value = opt.Value
+				found = true
+			}
+		}
+
+		if !found {
+			merged = append(merged, opt)
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,164 @@
+package repair
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func TestGetRunDirInstanceNames(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	runDir, err := ioutil.TempDir("", "run-dir")
+	assert.Nil(err)
+	defer os.RemoveAll(runDir)
+
+	ctx := &context.Ctx{}
+	ctx.Project.Name = "myapp"
+	ctx.Running.RunDir = runDir
+
+	_, err = getRunDirInstanceNames(ctx)
+	assert.EqualError(err, "No instance PID files or console sockets found in "+runDir)
+
+	for _, fileName := range []string{
+		"myapp.router.pid",
+		"myapp.router.control",
+		"myapp.router.notify",
+		"myapp.storage.control",
+		"myapp.s3.pid",
+		"myapp-stateboard.pid",
+		"otherapp.router.pid",
+	} {
+		assert.Nil(ioutil.WriteFile(filepath.Join(runDir, fileName), nil, 0644))
+	}
+
+	instanceNames, err := getRunDirInstanceNames(ctx)
+	assert.Nil(err)
+	assert.Equal([]string{"router", "s3", "storage"}, instanceNames)
+}
+
+func TestParseProbedInstance(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	instanceName, conf, err := parseProbedInstance(map[interface{}]interface{}{
+		"instance_name": "router",
+		"advertise_uri": "localhost:3301",
+		"http_port":     8081,
+	})
+	assert.Nil(err)
+	assert.Equal("router", instanceName)
+	assert.Equal(instanceConf{AdvertiseURI: "localhost:3301", HTTPPort: 8081}, *conf)
+
+	instanceName, conf, err = parseProbedInstance(map[interface{}]interface{}{
+		"advertise_uri": "localhost:3302",
+	})
+	assert.Nil(err)
+	assert.Equal("", instanceName)
+	assert.Equal(instanceConf{AdvertiseURI: "localhost:3302"}, *conf)
+
+	_, _, err = parseProbedInstance(map[interface{}]interface{}{
+		"instance_name": "router",
+	})
+	assert.EqualError(err, "Advertise URI isn't configured")
+
+	_, _, err = parseProbedInstance(map[interface{}]interface{}{
+		"advertise_uri": "localhost:3301",
+		"http_port":     "8081",
+	})
+	assert.EqualError(err, `HTTP port isn't a number: "8081"`)
+}
+
+func TestWriteInstancesConf(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	confDir, err := ioutil.TempDir("", "conf")
+	assert.Nil(err)
+	defer os.RemoveAll(confDir)
+
+	ctx := &context.Ctx{}
+	ctx.Project.Name = "myapp"
+	ctx.Running.ConfPath = confDir
+
+	// config directory
+	confPath, err := getInstancesConfPath(ctx)
+	assert.Nil(err)
+	assert.Equal(filepath.Join(confDir, "myapp.yml"), confPath)
+
+	// config file
+	confPath = filepath.Join(confDir, "instances.yml")
+	ctx.Running.ConfPath = confPath
+
+	confPath, err = getInstancesConfPath(ctx)
+	assert.Nil(err)
+	assert.Equal(ctx.Running.ConfPath, confPath)
+
+	instancesConf := map[string]instanceConf{
+		"myapp.storage": {AdvertiseURI: "localhost:3302"},
+		"myapp.router":  {AdvertiseURI: "localhost:3301", HTTPPort: 8081},
+	}
+
+	// new config
+	assert.Nil(writeInstancesConf(confPath, instancesConf, ctx))
+
+	confContent, err := ioutil.ReadFile(confPath)
+	assert.Nil(err)
+	assert.Equal(`myapp.router:
+  advertise_uri: localhost:3301
+  http_port: 8081
+myapp.storage:
+  advertise_uri: localhost:3302
+`, string(confContent))
+
+	// probed options are merged into the existing sections,
+	// other sections are kept, existing config is backed up
+	oldConfContent := `otherapp.router:
+  advertise_uri: localhost:3401
+myapp.router:
+  advertise_uri: localhost:3501
+  workdir: /var/lib/tarantool/myapp.router
+myapp.lost:
+  advertise_uri: localhost:3502
+  http_port: 8082
+`
+	assert.Nil(ioutil.WriteFile(confPath, []byte(oldConfContent), 0644))
+	assert.Nil(writeInstancesConf(confPath, instancesConf, ctx))
+
+	confContent, err = ioutil.ReadFile(confPath)
+	assert.Nil(err)
+	assert.Equal(`otherapp.router:
+  advertise_uri: localhost:3401
+myapp.router:
+  advertise_uri: localhost:3301
+  workdir: /var/lib/tarantool/myapp.router
+  http_port: 8081
+myapp.lost:
+  advertise_uri: localhost:3502
+  http_port: 8082
+myapp.storage:
+  advertise_uri: localhost:3302
+`, string(confContent))
+
+	backupContent, err := ioutil.ReadFile(getBackupPath(confPath))
+	assert.Nil(err)
+	assert.Equal(oldConfContent, string(backupContent))
+
+	// dry run doesn't change the config
+	ctx.Repair.DryRun = true
+	assert.Nil(ioutil.WriteFile(confPath, []byte("corrupted: [\n"), 0644))
+	assert.Nil(writeInstancesConf(confPath, instancesConf, ctx))
+
+	confContent, err = ioutil.ReadFile(confPath)
+	assert.Nil(err)
+	assert.Equal("corrupted: [\n", string(confContent))
+}
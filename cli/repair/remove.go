@@ -25,6 +25,14 @@ func removeInstance(topologyConf *TopologyConfType, ctx *context.Ctx) error {
 
 		if ok {
 			leaderIndex := common.StringsSliceElemIndex(replicasetConf.Leaders, instanceUUID)
+			if leaderIndex == 0 && !ctx.Repair.RemoveLeader {
+				return fmt.Errorf(
+					"Instance %s is the leader of replicaset %s. "+
+						"Change the leader using set-leader command or use --remove-leader option to remove it anyway",
+					instanceUUID, replicasetUUID,
+				)
+			}
+
 			if leaderIndex != -1 {
 				replicasetConf.SetLeaders(common.RemoveFromStringSlice(replicasetConf.Leaders, leaderIndex))
 			}
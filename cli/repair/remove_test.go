@@ -0,0 +1,73 @@
+package repair
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func TestRemoveInstanceLeader(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	workDir, err := ioutil.TempDir("", "work-dir")
+	assert.Nil(err)
+	defer os.RemoveAll(workDir)
+
+	topologyConfPath := writeTopologyConfig(workDir, `---
+replicasets:
+  rpl-1:
+    alias: replicaset-1
+    master:
+    - srv-1
+    - srv-2
+    roles:
+      vshard-storage: true
+servers:
+  srv-1:
+    disabled: false
+    replicaset_uuid: rpl-1
+    uri: localhost:3301
+  srv-2:
+    disabled: false
+    replicaset_uuid: rpl-1
+    uri: localhost:3302
+...
+`)
+
+	ctx := &context.Ctx{}
+
+	// replica is removed
+	topologyConf, err := getTopologyConf(topologyConfPath)
+	assert.Nil(err)
+
+	ctx.Repair.RemoveInstanceUUID = "srv-2"
+	assert.Nil(removeInstance(topologyConf, ctx))
+	assert.Equal([]string{"srv-1"}, topologyConf.Replicasets["rpl-1"].Leaders)
+
+	// leader isn't removed without --remove-leader
+	topologyConf, err = getTopologyConf(topologyConfPath)
+	assert.Nil(err)
+
+	ctx.Repair.RemoveInstanceUUID = "srv-1"
+	assert.EqualError(removeInstance(topologyConf, ctx),
+		"Instance srv-1 is the leader of replicaset rpl-1. "+
+			"Change the leader using set-leader command or use --remove-leader option to remove it anyway")
+	assert.Contains(topologyConf.Instances, "srv-1")
+
+	// --force doesn't allow to remove the leader
+	ctx.Repair.Force = true
+	assert.NotNil(removeInstance(topologyConf, ctx))
+	assert.Contains(topologyConf.Instances, "srv-1")
+
+	// leader is removed with --remove-leader
+	ctx.Repair.RemoveLeader = true
+	assert.Nil(removeInstance(topologyConf, ctx))
+	assert.NotContains(topologyConf.Instances, "srv-1")
+	assert.Equal([]string{"srv-2"}, topologyConf.Replicasets["rpl-1"].Leaders)
+}
@@ -25,6 +25,10 @@ func List(ctx *context.Ctx) error {
 }
 
 func PatchURI(ctx *context.Ctx) error {
+	if err := checkAdvertiseURI(ctx.Repair.NewURI); err != nil {
+		return err
+	}
+
 	log.Infof("Set %s advertise URI to %s", ctx.Repair.SetURIInstanceUUID, ctx.Repair.NewURI)
 	return Run(patchConfAdvertiseURI, ctx, true)
 }
@@ -0,0 +1,262 @@
+package replicasets
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apex/log"
+	"gopkg.in/yaml.v2"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+	"github.com/tarantool/cartridge-cli/cli/context"
+	"github.com/tarantool/cartridge-cli/cli/project"
+)
+
+type configChange struct {
+	ReplicasetAlias string
+	Description     string
+	Destructive     bool
+}
+
+// ConfigBackup saves current topology configuration to the specified file
+func ConfigBackup(ctx *context.Ctx, args []string) error {
+	backupPath, err := filepath.Abs(args[0])
+	if err != nil {
+		return fmt.Errorf("Failed to get backup file absolute path: %s", err)
+	}
+
+	conn, err := connectToSomeJoinedInstance(ctx)
+	if err != nil {
+		return err
+	}
+
+	topologyReplicasets, err := getTopologyReplicasets(conn)
+	if err != nil {
+		return fmt.Errorf("Failed to get current topology replica sets: %s", err)
+	}
+
+	if err := writeReplicasetsConf(getReplicasetsConf(topologyReplicasets), backupPath); err != nil {
+		return err
+	}
+
+	log.Infof("Topology configuration is saved to %s", backupPath)
+
+	return nil
+}
+
+// ConfigRestore applies topology configuration from the specified backup file.
+// Destructive changes are applied only if ctx.Replicasets.Yes is set
+func ConfigRestore(ctx *context.Ctx, args []string) error {
+	var err error
+
+	if ctx.Replicasets.File, err = filepath.Abs(args[0]); err != nil {
+		return fmt.Errorf("Failed to get backup file absolute path: %s", err)
+	}
+
+	log.Infof("Restore topology configuration from %s", ctx.Replicasets.File)
+
+	replicasetsList, err := getReplicasetsList(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to get topology configuration backup: %s", err)
+	}
+
+	instancesConf, err := getInstancesConf(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to get instances configuration: %s", err)
+	}
+
+	conn, err := getConnToSetupReplicasets(replicasetsList, instancesConf, ctx)
+	if err != nil {
+		return err
+	}
+
+	topologyReplicasets, err := getTopologyReplicasets(conn)
+	if err != nil {
+		return fmt.Errorf("Failed to get current topology replica sets: %s", err)
+	}
+
+	changes := getConfigChanges(getReplicasetsConf(topologyReplicasets), replicasetsList)
+	if len(changes) == 0 {
+		log.Infof("Current topology configuration is the same as in backup")
+		return nil
+	}
+
+	hasDestructiveChanges := false
+	for _, change := range changes {
+		if change.Destructive {
+			hasDestructiveChanges = true
+			log.Warnf("%s: %s", change.ReplicasetAlias, change.Description)
+		} else {
+			log.Infof("%s: %s", change.ReplicasetAlias, change.Description)
+		}
+	}
+
+	if hasDestructiveChanges && !ctx.Replicasets.Yes {
+		return fmt.Errorf("Restoring the backup leads to destructive changes. Use --yes flag to apply them")
+	}
+
+	newTopologyReplicasets, err := setupReplicasets(conn, replicasetsList, instancesConf, topologyReplicasets)
+	if err != nil {
+		return err
+	}
+
+	logSetupSummary(topologyReplicasets, newTopologyReplicasets)
+
+	log.Infof("Topology configuration is restored successfully")
+
+	return nil
+}
+
+func writeReplicasetsConf(replicasetsConf *ReplicasetsConf, path string) error {
+	confContent, err := yaml.Marshal(*replicasetsConf)
+	if err != nil {
+		return project.InternalError("Failed to marshal replica sets configuration: %s", err)
+	}
+
+	if err := ioutil.WriteFile(path, confContent, 0644); err != nil {
+		return fmt.Errorf("Failed to write replica sets configuration: %s", err)
+	}
+
+	return nil
+}
+
+// getConfigChanges returns the list of changes that would be made
+// on applying the backup to the current topology configuration.
+// Changes that can lead to data loss or unavailability are marked as destructive
+func getConfigChanges(currentConf *ReplicasetsConf, replicasetsList *ReplicasetsList) []configChange {
+	var changes []configChange
+
+	backupAliases := make(map[string]bool)
+
+	sortedList := make(ReplicasetsList, len(*replicasetsList))
+	copy(sortedList, *replicasetsList)
+	sort.Slice(sortedList, func(i, j int) bool {
+		return sortedList[i].Alias < sortedList[j].Alias
+	})
+
+	for _, backupConf := range sortedList {
+		alias := backupConf.Alias
+		backupAliases[alias] = true
+
+		addChange := func(destructive bool, format string, a ...interface{}) {
+			changes = append(changes, configChange{
+				ReplicasetAlias: alias,
+				Description:     fmt.Sprintf(format, a...),
+				Destructive:     destructive,
+			})
+		}
+
+		currentReplicasetConf, found := (*currentConf)[alias]
+		if !found {
+			addChange(false, "Replica set will be created")
+			continue
+		}
+
+		// roles
+		if removedRoles := common.GetStringSlicesDifference(currentReplicasetConf.Roles, backupConf.Roles); len(removedRoles) > 0 {
+			addChange(true, "Roles will be removed: %s", strings.Join(removedRoles, ", "))
+		}
+		if addedRoles := common.GetStringSlicesDifference(backupConf.Roles, currentReplicasetConf.Roles); len(addedRoles) > 0 {
+			addChange(false, "Roles will be added: %s", strings.Join(addedRoles, ", "))
+		}
+
+		// instances
+		if newInstances := common.GetStringSlicesDifference(backupConf.InstanceNames, currentReplicasetConf.InstanceNames); len(newInstances) > 0 {
+			addChange(false, "Instances will be joined: %s", strings.Join(newInstances, ", "))
+		}
+		if extraInstances := common.GetStringSlicesDifference(currentReplicasetConf.InstanceNames, backupConf.InstanceNames); len(extraInstances) > 0 {
+			addChange(false, "Instances aren't described in backup and won't be expelled: %s", strings.Join(extraInstances, ", "))
+		}
+
+		currentLeader := getFirstString(currentReplicasetConf.InstanceNames)
+		backupLeader := getFirstString(backupConf.InstanceNames)
+		if currentLeader != backupLeader {
+			addChange(true, "Leader will be changed: %s -> %s", currentLeader, backupLeader)
+		} else if !reflect.DeepEqual(currentReplicasetConf.InstanceNames, backupConf.InstanceNames) {
+			addChange(false, "Failover priority will be changed: %s", strings.Join(backupConf.InstanceNames, ", "))
+		}
+
+		// parameters
+		if backupConf.Weight != nil && !floatPtrsEqual(currentReplicasetConf.Weight, backupConf.Weight) {
+			addChange(true, "Weight will be changed: %s -> %s",
+				formatFloatPtr(currentReplicasetConf.Weight), formatFloatPtr(backupConf.Weight))
+		}
+
+		if backupConf.VshardGroup != nil && !stringPtrsEqual(currentReplicasetConf.VshardGroup, backupConf.VshardGroup) {
+			addChange(true, "Vshard group will be changed: %s -> %s",
+				formatStringPtr(currentReplicasetConf.VshardGroup), formatStringPtr(backupConf.VshardGroup))
+		}
+
+		if backupConf.AllRW != nil && !boolPtrsEqual(currentReplicasetConf.AllRW, backupConf.AllRW) {
+			addChange(false, "All RW will be changed: %s -> %s",
+				formatBoolPtr(currentReplicasetConf.AllRW), formatBoolPtr(backupConf.AllRW))
+		}
+	}
+
+	// replicasets that aren't described in backup
+	var extraAliases []string
+	for alias := range *currentConf {
+		if !backupAliases[alias] {
+			extraAliases = append(extraAliases, alias)
+		}
+	}
+	sort.Strings(extraAliases)
+
+	for _, alias := range extraAliases {
+		changes = append(changes, configChange{
+			ReplicasetAlias: alias,
+			Description:     "Replica set isn't described in backup and won't be changed",
+		})
+	}
+
+	return changes
+}
+
+func getFirstString(strs []string) string {
+	if len(strs) == 0 {
+		return ""
+	}
+
+	return strs[0]
+}
+
+func floatPtrsEqual(a, b *float64) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func stringPtrsEqual(a, b *string) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func boolPtrsEqual(a, b *bool) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func formatFloatPtr(value *float64) string {
+	if value == nil {
+		return "<none>"
+	}
+
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+func formatStringPtr(value *string) string {
+	if value == nil {
+		return "<none>"
+	}
+
+	return *value
+}
+
+func formatBoolPtr(value *bool) string {
+	if value == nil {
+		return "<none>"
+	}
+
+	return strconv.FormatBool(*value)
+}
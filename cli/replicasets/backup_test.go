@@ -0,0 +1,164 @@
+package replicasets
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func getBackupMockTopology() *TopologyReplicasets {
+	weight := 1.0
+	vshardGroup := "default"
+
+	return &TopologyReplicasets{
+		"router-uuid": &TopologyReplicaset{
+			UUID:  "router-uuid",
+			Alias: "router",
+			Roles: []string{"vshard-router", "app.roles.api"},
+			Instances: TopologyInstances{
+				&TopologyInstance{Alias: "router", UUID: "router-1", URI: "localhost:3301"},
+			},
+			LeaderUUID: "router-1",
+		},
+		"s-1-uuid": &TopologyReplicaset{
+			UUID:        "s-1-uuid",
+			Alias:       "s-1",
+			Roles:       []string{"vshard-storage"},
+			Weight:      &weight,
+			VshardGroup: &vshardGroup,
+			Instances: TopologyInstances{
+				&TopologyInstance{Alias: "s1-master", UUID: "s1-1", URI: "localhost:3302"},
+				&TopologyInstance{Alias: "s1-replica", UUID: "s1-2", URI: "localhost:3303"},
+			},
+			LeaderUUID: "s1-1",
+		},
+	}
+}
+
+// applyEditReplicasetOpts applies edit_topology options to the mock topology
+// the same way Cartridge does it
+func applyEditReplicasetOpts(topologyReplicaset *TopologyReplicaset, opts *EditReplicasetOpts,
+	instancesConf *InstancesConf) {
+
+	if opts.Roles != nil {
+		topologyReplicaset.Roles = opts.Roles
+	}
+	if opts.Weight != nil {
+		topologyReplicaset.Weight = opts.Weight
+	}
+	if opts.AllRW != nil {
+		topologyReplicaset.AllRW = opts.AllRW
+	}
+	if opts.VshardGroup != nil {
+		topologyReplicaset.VshardGroup = opts.VshardGroup
+	}
+
+	for _, joinURI := range opts.JoinInstancesURIs {
+		for instanceName, instanceConf := range *instancesConf {
+			if instanceConf.URI == joinURI {
+				topologyReplicaset.Instances = append(topologyReplicaset.Instances, &TopologyInstance{
+					Alias: instanceName,
+					UUID:  instanceName + "-uuid",
+					URI:   joinURI,
+				})
+			}
+		}
+	}
+
+	if len(opts.FailoverPriorityUUIDs) > 0 {
+		instancesByUUID := make(map[string]*TopologyInstance)
+		for _, instance := range topologyReplicaset.Instances {
+			instancesByUUID[instance.UUID] = instance
+		}
+
+		instances := TopologyInstances{}
+		for _, instanceUUID := range opts.FailoverPriorityUUIDs {
+			instances = append(instances, instancesByUUID[instanceUUID])
+		}
+
+		topologyReplicaset.Instances = instances
+		topologyReplicaset.LeaderUUID = opts.FailoverPriorityUUIDs[0]
+	}
+}
+
+func TestConfigBackupRestore(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "backup")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	instancesConf := &InstancesConf{
+		"router":     &InstanceConf{URI: "localhost:3301"},
+		"s1-master":  &InstanceConf{URI: "localhost:3302"},
+		"s1-replica": &InstanceConf{URI: "localhost:3303"},
+	}
+
+	// backup
+	backupPath := filepath.Join(tmpDir, "backup.yml")
+	originalConf := getReplicasetsConf(getBackupMockTopology())
+	assert.Nil(writeReplicasetsConf(originalConf, backupPath))
+
+	var ctx context.Ctx
+	ctx.Replicasets.File = backupPath
+
+	replicasetsList, err := getReplicasetsList(&ctx)
+	assert.Nil(err)
+	assert.Len(*replicasetsList, 2)
+
+	// change topology
+	changedTopology := getBackupMockTopology()
+	newWeight := 0.0
+
+	router := changedTopology.GetByAlias("router")
+	router.Roles = []string{"vshard-router"}
+
+	s1 := changedTopology.GetByAlias("s-1")
+	s1.Weight = &newWeight
+	s1.Instances = TopologyInstances{s1.Instances[1]}
+	s1.LeaderUUID = "s1-2"
+
+	// check changes
+	changes := getConfigChanges(getReplicasetsConf(changedTopology), replicasetsList)
+	assert.Equal([]configChange{
+		{"router", "Roles will be added: app.roles.api", false},
+		{"s-1", "Instances will be joined: s1-master", false},
+		{"s-1", "Leader will be changed: s1-replica -> s1-master", true},
+		{"s-1", "Weight will be changed: 0 -> 1", true},
+	}, changes)
+
+	// restore
+	for _, replicasetConf := range *replicasetsList {
+		topologyReplicaset := changedTopology.GetByAlias(replicasetConf.Alias)
+
+		opts, err := getUpdateReplicasetEditReplicasetsOpts(topologyReplicaset, replicasetConf, instancesConf)
+		assert.Nil(err)
+		applyEditReplicasetOpts(topologyReplicaset, opts, instancesConf)
+
+		opts, err = getSetFailoverPriorityEditReplicasetOpts(replicasetConf.InstanceNames, topologyReplicaset)
+		assert.Nil(err)
+		applyEditReplicasetOpts(topologyReplicaset, opts, instancesConf)
+	}
+
+	assert.Equal(originalConf, getReplicasetsConf(changedTopology))
+	assert.Len(getConfigChanges(getReplicasetsConf(changedTopology), replicasetsList), 0)
+
+	// changes that are destructive
+	changedTopology = getBackupMockTopology()
+	changedTopology.GetByAlias("router").Roles = []string{"vshard-router", "app.roles.api", "metrics"}
+	delete(*changedTopology, "s-1-uuid")
+	(*changedTopology)["s-2-uuid"] = &TopologyReplicaset{UUID: "s-2-uuid", Alias: "s-2"}
+
+	changes = getConfigChanges(getReplicasetsConf(changedTopology), replicasetsList)
+	assert.Equal([]configChange{
+		{"router", "Roles will be removed: metrics", true},
+		{"s-1", "Replica set will be created", false},
+		{"s-2", "Replica set isn't described in backup and won't be changed", false},
+	}, changes)
+}
@@ -161,8 +161,7 @@ func getMembershipInstances(instancesConf *InstancesConf, ctx *context.Ctx) (*Me
 func getRunningInstances(instancesConf *InstancesConf, ctx *context.Ctx) []string {
 	var runningInstancesNames []string
 	for instanceName := range *instancesConf {
-		process := running.NewInstanceProcess(ctx, instanceName)
-		if process.IsRunning() {
+		if running.IsInstanceRunning(ctx, instanceName) {
 			runningInstancesNames = append(runningInstancesNames, instanceName)
 		}
 	}
@@ -225,7 +225,7 @@ func appendStringOpt(optsStrings *[]string, optName string, optValue *string) {
 		return
 	}
 
-	optString := fmt.Sprintf("%s = '%s'", optName, *optValue)
+	optString := fmt.Sprintf("%s = %s", optName, common.QuoteLua(*optValue, '\''))
 	*optsStrings = append(*optsStrings, optString)
 }
 
@@ -265,7 +265,7 @@ func appendJoinServersOpt(optsStrings *[]string, optName string, instancesURIs [
 
 	joinServerStrings := make([]string, len(instancesURIs))
 	for i, instancesURI := range instancesURIs {
-		joinServerStrings[i] = fmt.Sprintf("{ uri = '%s' }", instancesURI)
+		joinServerStrings[i] = fmt.Sprintf("{ uri = %s }", common.QuoteLua(instancesURI, '\''))
 	}
 
 	optString := fmt.Sprintf("%s = { %s }", optName, strings.Join(joinServerStrings, ", "))
@@ -275,7 +275,7 @@ func appendJoinServersOpt(optsStrings *[]string, optName string, instancesURIs [
 func serializeStringsSlice(stringsSlice []string) string {
 	elemStrings := make([]string, len(stringsSlice))
 	for i, elem := range stringsSlice {
-		elemStrings[i] = fmt.Sprintf("'%s'", elem)
+		elemStrings[i] = common.QuoteLua(elem, '\'')
 	}
 
 	return fmt.Sprintf("{ %s }", strings.Join(elemStrings, ", "))
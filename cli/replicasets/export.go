@@ -0,0 +1,98 @@
+package replicasets
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/apex/log"
+	"gopkg.in/yaml.v2"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+	"github.com/tarantool/cartridge-cli/cli/project"
+)
+
+const (
+	exportToStdout = "-"
+)
+
+// Export writes current topology and failover configuration
+// in the format that is consumed by Setup.
+// If ctx.Replicasets.File is "-", configuration is written to stdout
+func Export(ctx *context.Ctx, args []string) error {
+	var err error
+
+	if ctx.Replicasets.File == "" {
+		ctx.Replicasets.File = defaultReplicasetsFile
+	}
+	if ctx.Replicasets.File != exportToStdout {
+		if ctx.Replicasets.File, err = filepath.Abs(ctx.Replicasets.File); err != nil {
+			return fmt.Errorf("Failed to get replicasets configuration file absolute path: %s", err)
+		}
+	}
+
+	conn, err := connectToSomeJoinedInstance(ctx)
+	if err != nil {
+		return err
+	}
+
+	topologyReplicasets, err := getTopologyReplicasets(conn)
+	if err != nil {
+		return fmt.Errorf("Failed to get current topology replica sets: %s", err)
+	}
+
+	failoverConf, err := getFailoverConf(conn)
+	if err != nil {
+		return err
+	}
+
+	confContent, err := getExportedConfContent(getReplicasetsConf(topologyReplicasets), failoverConf)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Replicasets.File == exportToStdout {
+		if _, err := os.Stdout.Write(confContent); err != nil {
+			return fmt.Errorf("Failed to write topology configuration: %s", err)
+		}
+
+		return nil
+	}
+
+	// configuration contains state provider passwords
+	if err := ioutil.WriteFile(ctx.Replicasets.File, confContent, 0600); err != nil {
+		return fmt.Errorf("Failed to write topology configuration: %s", err)
+	}
+
+	// mode of the existing file isn't changed by WriteFile
+	if err := os.Chmod(ctx.Replicasets.File, 0600); err != nil {
+		return fmt.Errorf("Failed to set topology configuration file mode: %s", err)
+	}
+
+	log.Infof("Topology configuration is exported to %s", ctx.Replicasets.File)
+
+	return nil
+}
+
+func getExportedConfContent(replicasetsConf *ReplicasetsConf, failoverConf *FailoverConf) ([]byte, error) {
+	if _, found := (*replicasetsConf)[failoverConfKey]; found {
+		return nil, fmt.Errorf("Replica set alias %q clashes with the failover configuration key", failoverConfKey)
+	}
+
+	exportedConf := make(map[string]interface{}, len(*replicasetsConf)+1)
+	for alias, replicasetConf := range *replicasetsConf {
+		exportedConf[alias] = replicasetConf
+	}
+
+	if failoverConf != nil {
+		exportedConf[failoverConfKey] = failoverConf
+	}
+
+	confContent, err := yaml.Marshal(exportedConf)
+	if err != nil {
+		return nil, project.InternalError("Failed to marshal topology configuration: %s", err)
+	}
+
+	return confContent, nil
+}
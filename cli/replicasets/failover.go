@@ -0,0 +1,445 @@
+package replicasets
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+
+	"github.com/apex/log"
+	"gopkg.in/yaml.v2"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+	"github.com/tarantool/cartridge-cli/cli/context"
+	"github.com/tarantool/cartridge-cli/cli/project"
+	"github.com/tarantool/cartridge-cli/cli/templates"
+)
+
+const (
+	// failoverConfKey is the replicasets configuration file key of the failover configuration.
+	// It's namespaced, so it doesn't clash with the replica sets aliases
+	failoverConfKey = "cartridge.failover"
+
+	FailoverModeDisabled = "disabled"
+	FailoverModeEventual = "eventual"
+	FailoverModeStateful = "stateful"
+
+	StateProviderStateboard = "stateboard"
+	StateProviderEtcd2      = "etcd2"
+)
+
+var (
+	FailoverModes  = []string{FailoverModeDisabled, FailoverModeEventual, FailoverModeStateful}
+	stateProviders = []string{StateProviderStateboard, StateProviderEtcd2}
+)
+
+type StateboardParams struct {
+	URI      string `yaml:"uri"`
+	Password string `yaml:"password"`
+}
+
+type Etcd2Params struct {
+	Prefix    *string  `yaml:"prefix,omitempty"`
+	LockDelay *float64 `yaml:"lock_delay,omitempty"`
+	Endpoints []string `yaml:"endpoints,omitempty"`
+	Username  *string  `yaml:"username,omitempty"`
+	Password  *string  `yaml:"password,omitempty"`
+}
+
+// FailoverConf describes cluster failover configuration.
+// It's stored under the failoverConfKey key of the replica sets configuration file
+type FailoverConf struct {
+	Mode          string  `yaml:"mode"`
+	StateProvider *string `yaml:"state_provider,omitempty"`
+
+	FailoverTimeout *float64 `yaml:"failover_timeout,omitempty"`
+	FencingEnabled  *bool    `yaml:"fencing_enabled,omitempty"`
+
+	StateboardParams *StateboardParams `yaml:"stateboard_params,omitempty"`
+	Etcd2Params      *Etcd2Params      `yaml:"etcd2_params,omitempty"`
+}
+
+func getFailoverConf(conn net.Conn) (*FailoverConf, error) {
+	failoverConfRaw, err := common.EvalTarantoolConn(conn, getFailoverConfBody, common.ConnOpts{
+		ReadTimeout: SimpleOperationTimeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get failover configuration: %s", err)
+	}
+
+	failoverConf, err := parseFailoverConf(failoverConfRaw)
+	if err != nil {
+		return nil, project.InternalError("Failover configuration received in bad format: %s", err)
+	}
+
+	return failoverConf, nil
+}
+
+func setFailoverConf(conn net.Conn, failoverConf *FailoverConf) error {
+	setFailoverConfBody, err := templates.GetTemplatedStr(&setFailoverConfBodyTemplate, map[string]string{
+		"FailoverConf": serializeFailoverConf(failoverConf),
+	})
+	if err != nil {
+		return project.InternalError("Failed to compute set failover configuration function body: %s", err)
+	}
+
+	if _, err := common.EvalTarantoolConn(conn, setFailoverConfBody, common.ConnOpts{}); err != nil {
+		return fmt.Errorf("Failed to set failover configuration: %s", err)
+	}
+
+	return nil
+}
+
+// SetFailover sets failover mode specified in args[0]
+// and prints the resulting failover configuration
+func SetFailover(ctx *context.Ctx, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("Should be specified one argument - failover mode")
+	}
+
+	failoverConf := getCtxFailoverConf(ctx, args[0])
+	if err := checkFailoverConf(failoverConf); err != nil {
+		return fmt.Errorf("Invalid failover configuration: %s", err)
+	}
+
+	conn, err := connectToSomeJoinedInstance(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := setFailoverConf(conn, failoverConf); err != nil {
+		return err
+	}
+
+	newFailoverConf, err := getFailoverConf(conn)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Failover configuration is set:")
+	for _, line := range formatFailoverConf(newFailoverConf) {
+		log.Infof("  %s", line)
+	}
+
+	return nil
+}
+
+func getCtxFailoverConf(ctx *context.Ctx, mode string) *FailoverConf {
+	failoverConf := &FailoverConf{
+		Mode: mode,
+	}
+
+	if ctx.Replicasets.StateProvider != "" {
+		failoverConf.StateProvider = &ctx.Replicasets.StateProvider
+	}
+
+	if ctx.Replicasets.FailoverTimeout != 0 {
+		failoverConf.FailoverTimeout = &ctx.Replicasets.FailoverTimeout
+	}
+
+	if ctx.Replicasets.FencingEnabledSet {
+		failoverConf.FencingEnabled = &ctx.Replicasets.FencingEnabled
+	}
+
+	if ctx.Replicasets.StateboardURI != "" || ctx.Replicasets.StateboardPassword != "" {
+		failoverConf.StateboardParams = &StateboardParams{
+			URI:      ctx.Replicasets.StateboardURI,
+			Password: ctx.Replicasets.StateboardPassword,
+		}
+	}
+
+	etcd2Params := Etcd2Params{}
+
+	if len(ctx.Replicasets.Etcd2Endpoints) > 0 {
+		etcd2Params.Endpoints = ctx.Replicasets.Etcd2Endpoints
+	}
+
+	if ctx.Replicasets.Etcd2Prefix != "" {
+		etcd2Params.Prefix = &ctx.Replicasets.Etcd2Prefix
+	}
+
+	if ctx.Replicasets.Etcd2LockDelay != 0 {
+		etcd2Params.LockDelay = &ctx.Replicasets.Etcd2LockDelay
+	}
+
+	if ctx.Replicasets.Etcd2Username != "" {
+		etcd2Params.Username = &ctx.Replicasets.Etcd2Username
+	}
+
+	if ctx.Replicasets.Etcd2Password != "" {
+		etcd2Params.Password = &ctx.Replicasets.Etcd2Password
+	}
+
+	if !reflect.DeepEqual(etcd2Params, Etcd2Params{}) {
+		failoverConf.Etcd2Params = &etcd2Params
+	}
+
+	return failoverConf
+}
+
+// checkFailoverConf checks that failover parameters are consistent,
+// e.g. state provider parameters are specified only for stateful failover
+func checkFailoverConf(failoverConf *FailoverConf) error {
+	if !common.StringSliceContains(FailoverModes, failoverConf.Mode) {
+		return fmt.Errorf("Unknown failover mode %q, should be one of: %s",
+			failoverConf.Mode, strings.Join(FailoverModes, ", "))
+	}
+
+	if failoverConf.Mode != FailoverModeStateful {
+		if failoverConf.StateProvider != nil || failoverConf.StateboardParams != nil || failoverConf.Etcd2Params != nil {
+			return fmt.Errorf("State provider and its parameters can be specified only for %s failover",
+				FailoverModeStateful)
+		}
+
+		if failoverConf.FencingEnabled != nil {
+			return fmt.Errorf("Fencing can be configured only for %s failover", FailoverModeStateful)
+		}
+
+		return nil
+	}
+
+	if failoverConf.StateProvider == nil {
+		return fmt.Errorf("State provider should be specified for %s failover", FailoverModeStateful)
+	}
+
+	switch stateProvider := *failoverConf.StateProvider; stateProvider {
+	case StateProviderStateboard:
+		if failoverConf.Etcd2Params != nil {
+			return fmt.Errorf("%s parameters can't be used with %s state provider", StateProviderEtcd2, stateProvider)
+		}
+
+		params := failoverConf.StateboardParams
+		if params == nil || params.URI == "" || params.Password == "" {
+			return fmt.Errorf("Stateboard URI and password should be specified for %s state provider", stateProvider)
+		}
+	case StateProviderEtcd2:
+		if failoverConf.StateboardParams != nil {
+			return fmt.Errorf("%s parameters can't be used with %s state provider", StateProviderStateboard, stateProvider)
+		}
+	default:
+		return fmt.Errorf("Unknown state provider %q, should be one of: %s",
+			stateProvider, strings.Join(stateProviders, ", "))
+	}
+
+	return nil
+}
+
+// formatFailoverConf returns failover configuration lines to print.
+// Passwords are hidden
+func formatFailoverConf(failoverConf *FailoverConf) []string {
+	lines := []string{
+		fmt.Sprintf("Mode: %s", failoverConf.Mode),
+	}
+
+	if failoverConf.StateProvider != nil {
+		lines = append(lines, fmt.Sprintf("State provider: %s", *failoverConf.StateProvider))
+	}
+
+	if failoverConf.FailoverTimeout != nil {
+		lines = append(lines, fmt.Sprintf("Failover timeout: %s", formatFloatPtr(failoverConf.FailoverTimeout)))
+	}
+
+	if failoverConf.FencingEnabled != nil {
+		lines = append(lines, fmt.Sprintf("Fencing enabled: %t", *failoverConf.FencingEnabled))
+	}
+
+	if params := failoverConf.StateboardParams; params != nil {
+		lines = append(lines, fmt.Sprintf("Stateboard URI: %s", params.URI))
+	}
+
+	if params := failoverConf.Etcd2Params; params != nil {
+		if len(params.Endpoints) > 0 {
+			lines = append(lines, fmt.Sprintf("etcd2 endpoints: %s", strings.Join(params.Endpoints, ", ")))
+		}
+
+		if params.Prefix != nil {
+			lines = append(lines, fmt.Sprintf("etcd2 prefix: %s", *params.Prefix))
+		}
+
+		if params.LockDelay != nil {
+			lines = append(lines, fmt.Sprintf("etcd2 lock delay: %s", formatFloatPtr(params.LockDelay)))
+		}
+
+		if params.Username != nil {
+			lines = append(lines, fmt.Sprintf("etcd2 username: %s", *params.Username))
+		}
+	}
+
+	return lines
+}
+
+func parseFailoverConf(failoverConfRaw interface{}) (*FailoverConf, error) {
+	failoverConfContent, err := yaml.Marshal(failoverConfRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	var failoverConf FailoverConf
+	if err := yaml.Unmarshal(failoverConfContent, &failoverConf); err != nil {
+		return nil, err
+	}
+
+	if failoverConf.Mode == "" {
+		return nil, fmt.Errorf("Failover mode isn't specified")
+	}
+
+	return &failoverConf, nil
+}
+
+// failoverConfIsApplied checks if the current failover configuration
+// already has all the specified parameters
+func failoverConfIsApplied(failoverConf, currentFailoverConf *FailoverConf) bool {
+	if failoverConf.Mode != currentFailoverConf.Mode {
+		return false
+	}
+
+	if failoverConf.StateProvider != nil && !stringPtrsEqual(failoverConf.StateProvider, currentFailoverConf.StateProvider) {
+		return false
+	}
+
+	if failoverConf.FailoverTimeout != nil && !floatPtrsEqual(failoverConf.FailoverTimeout, currentFailoverConf.FailoverTimeout) {
+		return false
+	}
+
+	if failoverConf.FencingEnabled != nil && !boolPtrsEqual(failoverConf.FencingEnabled, currentFailoverConf.FencingEnabled) {
+		return false
+	}
+
+	if failoverConf.StateboardParams != nil && (currentFailoverConf.StateboardParams == nil ||
+		*failoverConf.StateboardParams != *currentFailoverConf.StateboardParams) {
+		return false
+	}
+
+	if failoverConf.Etcd2Params != nil && !etcd2ParamsAreApplied(failoverConf.Etcd2Params, currentFailoverConf.Etcd2Params) {
+		return false
+	}
+
+	return true
+}
+
+func etcd2ParamsAreApplied(etcd2Params, currentEtcd2Params *Etcd2Params) bool {
+	if currentEtcd2Params == nil {
+		return false
+	}
+
+	if etcd2Params.Prefix != nil && !stringPtrsEqual(etcd2Params.Prefix, currentEtcd2Params.Prefix) {
+		return false
+	}
+
+	if etcd2Params.LockDelay != nil && !floatPtrsEqual(etcd2Params.LockDelay, currentEtcd2Params.LockDelay) {
+		return false
+	}
+
+	if etcd2Params.Endpoints != nil && strings.Join(etcd2Params.Endpoints, ",") != strings.Join(currentEtcd2Params.Endpoints, ",") {
+		return false
+	}
+
+	if etcd2Params.Username != nil && !stringPtrsEqual(etcd2Params.Username, currentEtcd2Params.Username) {
+		return false
+	}
+
+	if etcd2Params.Password != nil && !stringPtrsEqual(etcd2Params.Password, currentEtcd2Params.Password) {
+		return false
+	}
+
+	return true
+}
+
+func serializeFailoverConf(failoverConf *FailoverConf) string {
+	var optsStrings []string
+
+	appendStringOpt(&optsStrings, "mode", &failoverConf.Mode)
+	appendStringOpt(&optsStrings, "state_provider", failoverConf.StateProvider)
+	appendFloatOpt(&optsStrings, "failover_timeout", failoverConf.FailoverTimeout)
+	appendBoolOpt(&optsStrings, "fencing_enabled", failoverConf.FencingEnabled)
+
+	if params := failoverConf.StateboardParams; params != nil {
+		var paramsStrings []string
+
+		appendStringOpt(&paramsStrings, "uri", &params.URI)
+		appendStringOpt(&paramsStrings, "password", &params.Password)
+
+		optsStrings = append(optsStrings, fmt.Sprintf("stateboard_params = { %s }", strings.Join(paramsStrings, ", ")))
+	}
+
+	if params := failoverConf.Etcd2Params; params != nil {
+		var paramsStrings []string
+
+		appendStringOpt(&paramsStrings, "prefix", params.Prefix)
+		appendFloatOpt(&paramsStrings, "lock_delay", params.LockDelay)
+		appendStringsSliceOpt(&paramsStrings, "endpoints", params.Endpoints)
+		appendStringOpt(&paramsStrings, "username", params.Username)
+		appendStringOpt(&paramsStrings, "password", params.Password)
+
+		optsStrings = append(optsStrings, fmt.Sprintf("etcd2_params = { %s }", strings.Join(paramsStrings, ", ")))
+	}
+
+	return fmt.Sprintf("{ %s }", strings.Join(optsStrings, ", "))
+}
+
+var (
+	getFailoverConfBody = `
+local cartridge = require('cartridge')
+
+if cartridge.failover_get_params == nil then
+	local enabled = cartridge.admin_get_failover()
+	return { mode = enabled and 'eventual' or 'disabled' }
+end
+
+local params = cartridge.failover_get_params()
+
+local failover_conf = {
+	mode = params.mode,
+	failover_timeout = params.failover_timeout,
+}
+
+if params.mode == 'stateful' then
+	failover_conf.fencing_enabled = params.fencing_enabled
+
+	if params.state_provider == 'tarantool' then
+		failover_conf.state_provider = 'stateboard'
+		failover_conf.stateboard_params = params.tarantool_params
+	elseif params.state_provider == 'etcd2' then
+		failover_conf.state_provider = 'etcd2'
+		failover_conf.etcd2_params = params.etcd2_params
+	end
+end
+
+return failover_conf
+`
+
+	setFailoverConfBodyTemplate = `
+local cartridge = require('cartridge')
+
+local failover_conf = {{ .FailoverConf }}
+
+if cartridge.failover_set_params == nil then
+	if failover_conf.mode == 'stateful' then
+		return nil, 'Stateful failover is supported only since Cartridge 2.0'
+	end
+
+	if failover_conf.mode == 'eventual' then
+		return cartridge.admin_enable_failover()
+	end
+
+	return cartridge.admin_disable_failover()
+end
+
+local params = {
+	mode = failover_conf.mode,
+	failover_timeout = failover_conf.failover_timeout,
+	fencing_enabled = failover_conf.fencing_enabled,
+}
+
+if failover_conf.state_provider == 'stateboard' then
+	params.state_provider = 'tarantool'
+	params.tarantool_params = failover_conf.stateboard_params
+elseif failover_conf.state_provider == 'etcd2' then
+	params.state_provider = 'etcd2'
+	params.etcd2_params = failover_conf.etcd2_params
+end
+
+local ok, err = cartridge.failover_set_params(params)
+return ok, err
+`
+)
@@ -0,0 +1,316 @@
+package replicasets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func TestSerializeFailoverConf(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	stateboard := StateProviderStateboard
+	etcd2 := StateProviderEtcd2
+	failoverTimeout := 20.5
+	fencingEnabled := true
+	prefix := "/myapp"
+
+	assert.Equal(
+		"{ mode = 'eventual' }",
+		serializeFailoverConf(&FailoverConf{Mode: FailoverModeEventual}),
+	)
+
+	assert.Equal(
+		"{ mode = 'stateful', state_provider = 'stateboard', failover_timeout = 20.5, fencing_enabled = true, "+
+			"stateboard_params = { uri = 'localhost:4401', password = 'passwd' } }",
+		serializeFailoverConf(&FailoverConf{
+			Mode:            FailoverModeStateful,
+			StateProvider:   &stateboard,
+			FailoverTimeout: &failoverTimeout,
+			FencingEnabled:  &fencingEnabled,
+			StateboardParams: &StateboardParams{
+				URI:      "localhost:4401",
+				Password: "passwd",
+			},
+		}),
+	)
+
+	assert.Equal(
+		"{ mode = 'stateful', state_provider = 'etcd2', "+
+			"etcd2_params = { prefix = '/myapp', endpoints = { 'http://etcd-1:2379', 'http://etcd-2:2379' } } }",
+		serializeFailoverConf(&FailoverConf{
+			Mode:          FailoverModeStateful,
+			StateProvider: &etcd2,
+			Etcd2Params: &Etcd2Params{
+				Prefix:    &prefix,
+				Endpoints: []string{"http://etcd-1:2379", "http://etcd-2:2379"},
+			},
+		}),
+	)
+
+	// strings are escaped
+	assert.Equal(
+		`{ mode = 'stateful', state_provider = 'stateboard', `+
+			`stateboard_params = { uri = 'localhost:4401', password = 'pa\'ss\\wd\n' } }`,
+		serializeFailoverConf(&FailoverConf{
+			Mode:          FailoverModeStateful,
+			StateProvider: &stateboard,
+			StateboardParams: &StateboardParams{
+				URI:      "localhost:4401",
+				Password: "pa'ss\\wd\n",
+			},
+		}),
+	)
+}
+
+func TestParseFailoverConf(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	failoverConf, err := parseFailoverConf(map[interface{}]interface{}{
+		"mode":             "stateful",
+		"state_provider":   "stateboard",
+		"failover_timeout": 20,
+		"fencing_enabled":  false,
+		"stateboard_params": map[interface{}]interface{}{
+			"uri":      "localhost:4401",
+			"password": "passwd",
+		},
+	})
+	assert.Nil(err)
+
+	assert.Equal(FailoverModeStateful, failoverConf.Mode)
+	assert.Equal(StateProviderStateboard, *failoverConf.StateProvider)
+	assert.Equal(20.0, *failoverConf.FailoverTimeout)
+	assert.False(*failoverConf.FencingEnabled)
+	assert.Equal(&StateboardParams{URI: "localhost:4401", Password: "passwd"}, failoverConf.StateboardParams)
+	assert.Nil(failoverConf.Etcd2Params)
+
+	_, err = parseFailoverConf(map[interface{}]interface{}{"failover_timeout": 20})
+	assert.EqualError(err, "Failover mode isn't specified")
+}
+
+func TestFailoverConfIsApplied(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	stateboard := StateProviderStateboard
+	etcd2 := StateProviderEtcd2
+	failoverTimeout := 20.0
+	prefix := "/myapp"
+
+	currentFailoverConf := &FailoverConf{
+		Mode:            FailoverModeStateful,
+		StateProvider:   &etcd2,
+		FailoverTimeout: &failoverTimeout,
+		Etcd2Params: &Etcd2Params{
+			Prefix:    &prefix,
+			Endpoints: []string{"http://etcd-1:2379"},
+		},
+	}
+
+	// unspecified parameters don't matter
+	assert.True(failoverConfIsApplied(&FailoverConf{Mode: FailoverModeStateful}, currentFailoverConf))
+	assert.True(failoverConfIsApplied(&FailoverConf{
+		Mode:          FailoverModeStateful,
+		StateProvider: &etcd2,
+		Etcd2Params:   &Etcd2Params{Endpoints: []string{"http://etcd-1:2379"}},
+	}, currentFailoverConf))
+
+	assert.False(failoverConfIsApplied(&FailoverConf{Mode: FailoverModeEventual}, currentFailoverConf))
+	assert.False(failoverConfIsApplied(&FailoverConf{
+		Mode:          FailoverModeStateful,
+		StateProvider: &stateboard,
+	}, currentFailoverConf))
+	assert.False(failoverConfIsApplied(&FailoverConf{
+		Mode:        FailoverModeStateful,
+		Etcd2Params: &Etcd2Params{Endpoints: []string{"http://etcd-2:2379"}},
+	}, currentFailoverConf))
+	assert.False(failoverConfIsApplied(&FailoverConf{
+		Mode:             FailoverModeStateful,
+		StateboardParams: &StateboardParams{URI: "localhost:4401", Password: "passwd"},
+	}, currentFailoverConf))
+}
+
+func TestGetExportedConfContent(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	weight := 1.0
+
+	replicasetsConf := &ReplicasetsConf{
+		"router": &ReplicasetConf{
+			InstanceNames: []string{"router"},
+			Roles:         []string{"vshard-router"},
+		},
+		"s-1": &ReplicasetConf{
+			InstanceNames: []string{"s1-master", "s1-replica"},
+			Roles:         []string{"vshard-storage"},
+			Weight:        &weight,
+		},
+	}
+
+	confContent, err := getExportedConfContent(replicasetsConf, &FailoverConf{Mode: FailoverModeEventual})
+	assert.Nil(err)
+	assert.Equal(`cartridge.failover:
+  mode: eventual
+router:
+  instances:
+  - router
+  roles:
+  - vshard-router
+s-1:
+  instances:
+  - s1-master
+  - s1-replica
+  roles:
+  - vshard-storage
+  weight: 1
+`, string(confContent))
+
+	// replica set can be named failover
+	(*replicasetsConf)["failover"] = &ReplicasetConf{InstanceNames: []string{"failover"}}
+	confContent, err = getExportedConfContent(replicasetsConf, &FailoverConf{Mode: FailoverModeEventual})
+	assert.Nil(err)
+	assert.Contains(string(confContent), "\nfailover:\n  instances:\n  - failover\n")
+
+	(*replicasetsConf)[failoverConfKey] = &ReplicasetConf{InstanceNames: []string{"failover"}}
+	_, err = getExportedConfContent(replicasetsConf, nil)
+	assert.EqualError(err, `Replica set alias "cartridge.failover" clashes with the failover configuration key`)
+}
+
+func TestCheckFailoverConf(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	stateboard := StateProviderStateboard
+	etcd2 := StateProviderEtcd2
+	consul := "consul"
+	fencingEnabled := true
+	stateboardParams := &StateboardParams{URI: "localhost:4401", Password: "passwd"}
+	etcd2Params := &Etcd2Params{Endpoints: []string{"http://etcd-1:2379"}}
+
+	assert.Nil(checkFailoverConf(&FailoverConf{Mode: FailoverModeDisabled}))
+	assert.Nil(checkFailoverConf(&FailoverConf{Mode: FailoverModeEventual}))
+	assert.Nil(checkFailoverConf(&FailoverConf{
+		Mode:             FailoverModeStateful,
+		StateProvider:    &stateboard,
+		FencingEnabled:   &fencingEnabled,
+		StateboardParams: stateboardParams,
+	}))
+	assert.Nil(checkFailoverConf(&FailoverConf{Mode: FailoverModeStateful, StateProvider: &etcd2}))
+	assert.Nil(checkFailoverConf(&FailoverConf{
+		Mode:          FailoverModeStateful,
+		StateProvider: &etcd2,
+		Etcd2Params:   etcd2Params,
+	}))
+
+	assert.EqualError(
+		checkFailoverConf(&FailoverConf{Mode: "manual"}),
+		`Unknown failover mode "manual", should be one of: disabled, eventual, stateful`,
+	)
+	assert.EqualError(
+		checkFailoverConf(&FailoverConf{Mode: FailoverModeEventual, StateProvider: &etcd2}),
+		"State provider and its parameters can be specified only for stateful failover",
+	)
+	assert.EqualError(
+		checkFailoverConf(&FailoverConf{Mode: FailoverModeDisabled, StateboardParams: stateboardParams}),
+		"State provider and its parameters can be specified only for stateful failover",
+	)
+	assert.EqualError(
+		checkFailoverConf(&FailoverConf{Mode: FailoverModeEventual, FencingEnabled: &fencingEnabled}),
+		"Fencing can be configured only for stateful failover",
+	)
+	assert.EqualError(
+		checkFailoverConf(&FailoverConf{Mode: FailoverModeStateful}),
+		"State provider should be specified for stateful failover",
+	)
+	assert.EqualError(
+		checkFailoverConf(&FailoverConf{Mode: FailoverModeStateful, StateProvider: &stateboard}),
+		"Stateboard URI and password should be specified for stateboard state provider",
+	)
+	assert.EqualError(
+		checkFailoverConf(&FailoverConf{
+			Mode:             FailoverModeStateful,
+			StateProvider:    &stateboard,
+			StateboardParams: stateboardParams,
+			Etcd2Params:      etcd2Params,
+		}),
+		"etcd2 parameters can't be used with stateboard state provider",
+	)
+	assert.EqualError(
+		checkFailoverConf(&FailoverConf{
+			Mode:             FailoverModeStateful,
+			StateProvider:    &etcd2,
+			StateboardParams: stateboardParams,
+		}),
+		"stateboard parameters can't be used with etcd2 state provider",
+	)
+	assert.EqualError(
+		checkFailoverConf(&FailoverConf{Mode: FailoverModeStateful, StateProvider: &consul}),
+		`Unknown state provider "consul", should be one of: stateboard, etcd2`,
+	)
+}
+
+func TestGetCtxFailoverConf(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var ctx context.Ctx
+
+	ctx.Replicasets.Etcd2Endpoints = []string{}
+	assert.Equal(&FailoverConf{Mode: FailoverModeEventual}, getCtxFailoverConf(&ctx, FailoverModeEventual))
+
+	ctx.Replicasets.StateProvider = StateProviderEtcd2
+	ctx.Replicasets.Etcd2Endpoints = []string{"http://etcd-1:2379"}
+	ctx.Replicasets.Etcd2LockDelay = 10
+	ctx.Replicasets.FencingEnabledSet = true
+
+	failoverConf := getCtxFailoverConf(&ctx, FailoverModeStateful)
+	assert.Equal(StateProviderEtcd2, *failoverConf.StateProvider)
+	assert.False(*failoverConf.FencingEnabled)
+	assert.Nil(failoverConf.FailoverTimeout)
+	assert.Nil(failoverConf.StateboardParams)
+	assert.Equal([]string{"http://etcd-1:2379"}, failoverConf.Etcd2Params.Endpoints)
+	assert.Equal(10.0, *failoverConf.Etcd2Params.LockDelay)
+	assert.Nil(failoverConf.Etcd2Params.Prefix)
+}
+
+func TestFormatFailoverConf(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	stateboard := StateProviderStateboard
+	failoverTimeout := 20.0
+	fencingEnabled := false
+
+	assert.Equal(
+		[]string{"Mode: eventual", "Failover timeout: 20"},
+		formatFailoverConf(&FailoverConf{Mode: FailoverModeEventual, FailoverTimeout: &failoverTimeout}),
+	)
+
+	// password isn't shown
+	assert.Equal(
+		[]string{
+			"Mode: stateful",
+			"State provider: stateboard",
+			"Fencing enabled: false",
+			"Stateboard URI: localhost:4401",
+		},
+		formatFailoverConf(&FailoverConf{
+			Mode:             FailoverModeStateful,
+			StateProvider:    &stateboard,
+			FencingEnabled:   &fencingEnabled,
+			StateboardParams: &StateboardParams{URI: "localhost:4401", Password: "passwd"},
+		}),
+	)
+}
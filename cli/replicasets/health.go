@@ -0,0 +1,144 @@
+package replicasets
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/tarantool/cartridge-cli/cli/common"
+	"github.com/tarantool/cartridge-cli/cli/context"
+	"github.com/tarantool/cartridge-cli/cli/running"
+)
+
+type isInstanceUpFunc func(instanceName string) bool
+
+type ReplicasetHealth struct {
+	Alias string
+
+	LeaderAlias string
+	LeaderUp    bool
+
+	InstancesCount   int
+	InstancesUpCount int
+}
+
+// HasQuorum returns true if the majority of replicaset instances are up
+func (health *ReplicasetHealth) HasQuorum() bool {
+	return health.InstancesUpCount > health.InstancesCount/2
+}
+
+// IsHealthy returns true if replicaset leader is up and quorum is present
+func (health *ReplicasetHealth) IsHealthy() bool {
+	return health.LeaderUp && health.HasQuorum()
+}
+
+// ReplicasetsHealth checks health of all cluster replicasets.
+// Replicaset is healthy if its leader is running and
+// the majority of replicaset instances are running.
+func ReplicasetsHealth(ctx *context.Ctx) error {
+	conn, err := connectToSomeJoinedInstance(ctx)
+	if err != nil {
+		return err
+	}
+
+	topologyReplicasets, err := getTopologyReplicasets(conn)
+	if err != nil {
+		return fmt.Errorf("Failed to get current topology replica sets: %s", err)
+	}
+
+	isInstanceUp := func(instanceName string) bool {
+		return running.IsInstanceRunning(ctx, instanceName)
+	}
+
+	replicasetsHealth := getReplicasetsHealth(topologyReplicasets, isInstanceUp)
+
+	log.Infof("Replica sets health:\n%s", getReplicasetsHealthSummary(replicasetsHealth))
+
+	unhealthyReplicasets := []string{}
+	for _, health := range replicasetsHealth {
+		if !health.IsHealthy() {
+			unhealthyReplicasets = append(unhealthyReplicasets, health.Alias)
+		}
+	}
+
+	if len(unhealthyReplicasets) > 0 {
+		return fmt.Errorf("Some replica sets are unhealthy: %s", strings.Join(unhealthyReplicasets, ", "))
+	}
+
+	return nil
+}
+
+func getReplicasetsHealth(topologyReplicasets *TopologyReplicasets, isInstanceUp isInstanceUpFunc) []*ReplicasetHealth {
+	replicasetsHealth := make([]*ReplicasetHealth, 0, len(*topologyReplicasets))
+
+	for _, topologyReplicaset := range *topologyReplicasets {
+		replicasetsHealth = append(replicasetsHealth, getReplicasetHealth(topologyReplicaset, isInstanceUp))
+	}
+
+	sort.Slice(replicasetsHealth, func(i, j int) bool {
+		return replicasetsHealth[i].Alias < replicasetsHealth[j].Alias
+	})
+
+	return replicasetsHealth
+}
+
+func getReplicasetHealth(topologyReplicaset *TopologyReplicaset, isInstanceUp isInstanceUpFunc) *ReplicasetHealth {
+	health := ReplicasetHealth{
+		Alias: topologyReplicaset.Alias,
+	}
+
+	for _, topologyInstance := range topologyReplicaset.Instances {
+		if topologyInstance.Expelled {
+			continue
+		}
+
+		instanceUp := isInstanceUp(topologyInstance.Alias)
+
+		health.InstancesCount++
+		if instanceUp {
+			health.InstancesUpCount++
+		}
+
+		if topologyInstance.UUID == topologyReplicaset.LeaderUUID {
+			health.LeaderAlias = topologyInstance.Alias
+			health.LeaderUp = instanceUp
+		}
+	}
+
+	return &health
+}
+
+func getReplicasetsHealthSummary(replicasetsHealth []*ReplicasetHealth) string {
+	// example:
+	//
+	// • s-1                 HEALTHY      leader s1-master is up, 2/2 instances are up
+	// • s-2                 UNHEALTHY    leader s2-master is down, 1/2 instances are up
+	summaryLines := make([]string, len(replicasetsHealth))
+
+	for i, health := range replicasetsHealth {
+		var statusStr string
+		if health.IsHealthy() {
+			statusStr = common.ColorOk.Sprintf("%-12s", "HEALTHY")
+		} else {
+			statusStr = common.ColorErr.Sprintf("%-12s", "UNHEALTHY")
+		}
+
+		var leaderStr string
+		if health.LeaderAlias == "" {
+			leaderStr = "no leader"
+		} else if health.LeaderUp {
+			leaderStr = fmt.Sprintf("leader %s is up", health.LeaderAlias)
+		} else {
+			leaderStr = fmt.Sprintf("leader %s is down", health.LeaderAlias)
+		}
+
+		summaryLines[i] = fmt.Sprintf(
+			"%s %-20s %s %s, %d/%d instances are up",
+			instanceMarker, health.Alias, statusStr, leaderStr,
+			health.InstancesUpCount, health.InstancesCount,
+		)
+	}
+
+	return strings.Join(summaryLines, "\n")
+}
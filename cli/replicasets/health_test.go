@@ -0,0 +1,134 @@
+package replicasets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getMockIsInstanceUp(runningInstances ...string) isInstanceUpFunc {
+	return func(instanceName string) bool {
+		for _, runningInstance := range runningInstances {
+			if runningInstance == instanceName {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+func getMockTopologyReplicasets() *TopologyReplicasets {
+	return &TopologyReplicasets{
+		"router-uuid": &TopologyReplicaset{
+			UUID:  "router-uuid",
+			Alias: "router",
+			Instances: TopologyInstances{
+				&TopologyInstance{Alias: "router", UUID: "router-1"},
+			},
+			LeaderUUID: "router-1",
+		},
+		"s-1-uuid": &TopologyReplicaset{
+			UUID:  "s-1-uuid",
+			Alias: "s-1",
+			Instances: TopologyInstances{
+				&TopologyInstance{Alias: "s1-master", UUID: "s1-1"},
+				&TopologyInstance{Alias: "s1-replica", UUID: "s1-2"},
+				&TopologyInstance{Alias: "s1-replica-2", UUID: "s1-3"},
+			},
+			LeaderUUID: "s1-1",
+		},
+		"s-2-uuid": &TopologyReplicaset{
+			UUID:  "s-2-uuid",
+			Alias: "s-2",
+			Instances: TopologyInstances{
+				&TopologyInstance{Alias: "s2-master", UUID: "s2-1"},
+				&TopologyInstance{Alias: "s2-replica", UUID: "s2-2"},
+				&TopologyInstance{Alias: "s2-expelled", UUID: "s2-3", Expelled: true},
+			},
+			LeaderUUID: "s2-1",
+		},
+	}
+}
+
+func TestGetReplicasetsHealthHealthy(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	isInstanceUp := getMockIsInstanceUp(
+		"router", "s1-master", "s1-replica", "s1-replica-2", "s2-master", "s2-replica",
+	)
+
+	replicasetsHealth := getReplicasetsHealth(getMockTopologyReplicasets(), isInstanceUp)
+	assert.Len(replicasetsHealth, 3)
+
+	assert.Equal(&ReplicasetHealth{
+		Alias:            "router",
+		LeaderAlias:      "router",
+		LeaderUp:         true,
+		InstancesCount:   1,
+		InstancesUpCount: 1,
+	}, replicasetsHealth[0])
+
+	assert.Equal(&ReplicasetHealth{
+		Alias:            "s-1",
+		LeaderAlias:      "s1-master",
+		LeaderUp:         true,
+		InstancesCount:   3,
+		InstancesUpCount: 3,
+	}, replicasetsHealth[1])
+
+	// expelled instance isn't counted
+	assert.Equal(&ReplicasetHealth{
+		Alias:            "s-2",
+		LeaderAlias:      "s2-master",
+		LeaderUp:         true,
+		InstancesCount:   2,
+		InstancesUpCount: 2,
+	}, replicasetsHealth[2])
+
+	for _, health := range replicasetsHealth {
+		assert.True(health.IsHealthy())
+	}
+}
+
+func TestGetReplicasetsHealthDegraded(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	// s-1: leader is up, but there is no quorum
+	// s-2: quorum is present, but leader is down
+	isInstanceUp := getMockIsInstanceUp("router", "s1-master", "s2-replica")
+
+	replicasetsHealth := getReplicasetsHealth(getMockTopologyReplicasets(), isInstanceUp)
+	assert.Len(replicasetsHealth, 3)
+
+	routerHealth := replicasetsHealth[0]
+	assert.True(routerHealth.IsHealthy())
+
+	s1Health := replicasetsHealth[1]
+	assert.Equal("s-1", s1Health.Alias)
+	assert.True(s1Health.LeaderUp)
+	assert.Equal(1, s1Health.InstancesUpCount)
+	assert.False(s1Health.HasQuorum())
+	assert.False(s1Health.IsHealthy())
+
+	s2Health := replicasetsHealth[2]
+	assert.Equal("s-2", s2Health.Alias)
+	assert.False(s2Health.LeaderUp)
+	assert.Equal(1, s2Health.InstancesUpCount)
+	assert.False(s2Health.HasQuorum())
+	assert.False(s2Health.IsHealthy())
+
+	// s-1 with two of three instances up has quorum
+	isInstanceUp = getMockIsInstanceUp("s1-master", "s1-replica-2")
+	s1Health = getReplicasetHealth((*getMockTopologyReplicasets())["s-1-uuid"], isInstanceUp)
+	assert.True(s1Health.HasQuorum())
+	assert.True(s1Health.IsHealthy())
+
+	summary := getReplicasetsHealthSummary(replicasetsHealth)
+	assert.Contains(summary, "leader s1-master is up, 1/3 instances are up")
+	assert.Contains(summary, "leader s2-master is down, 1/2 instances are up")
+}
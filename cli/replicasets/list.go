@@ -2,7 +2,7 @@ package replicasets
 
 import (
 	"fmt"
-	"sort"
+	"os"
 	"strconv"
 	"strings"
 
@@ -23,46 +23,77 @@ func List(ctx *context.Ctx, args []string) error {
 		return err
 	}
 
-	conn, err := connectToSomeJoinedInstance(ctx)
+	if ctx.Replicasets.ListFormat == "" {
+		ctx.Replicasets.ListFormat = ListFormatText
+	}
+
+	if err := CheckListFormat(ctx.Replicasets.ListFormat); err != nil {
+		return fmt.Errorf(`Invalid argument %q for "--%s" flag: %s`, ctx.Replicasets.ListFormat, "format", err)
+	}
+
+	topologyReplicasets, err := getCurrentTopologyReplicasets(ctx)
 	if err != nil {
 		return err
 	}
 
-	topologyReplicasets, err := getTopologyReplicasets(conn)
-	if err != nil {
-		return fmt.Errorf("Failed to get current topology replica sets: %s", err)
+	// both text and structured outputs are rendered from the same items
+	items := getReplicasetsListItems(topologyReplicasets)
+
+	if ctx.Replicasets.ListFormat != ListFormatText {
+		return writeReplicasetsList(os.Stdout, items, ctx.Replicasets.ListFormat)
 	}
 
-	replicasetsSummary := getTopologyReplicasetsSummary(topologyReplicasets)
+	if topologyReplicasets == nil {
+		return fmt.Errorf("Failed to find some instance joined to cluster")
+	}
 
-	log.Infof("Current replica sets:\n%s", replicasetsSummary)
+	log.Infof("Current replica sets:\n%s", getReplicasetsSummary(items))
 
 	return nil
 }
 
-func getTopologyReplicasetsSummary(topologyReplicasets *TopologyReplicasets) string {
-	// sort replicasets by aliases
-	replicasetsList := make([]*TopologyReplicaset, len(*topologyReplicasets))
-	i := 0
-	for _, topologyReplicaset := range *topologyReplicasets {
-		replicasetsList[i] = topologyReplicaset
-		i++
+// getCurrentTopologyReplicasets returns the cluster replica sets.
+// Nil is returned if no instances are joined to the cluster yet
+func getCurrentTopologyReplicasets(ctx *context.Ctx) (*TopologyReplicasets, error) {
+	instancesConf, err := getInstancesConf(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get instances configuration: %s", err)
+	}
+
+	joinedInstanceName, err := getJoinedInstanceName(instancesConf, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to find some instance joined to cluster: %s", err)
+	}
+
+	if joinedInstanceName == "" {
+		return nil, nil
+	}
+
+	conn, err := connectToInstance(joinedInstanceName, ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	sort.Slice(replicasetsList, func(i, j int) bool {
-		return replicasetsList[i].Alias < replicasetsList[j].Alias
-	})
+	topologyReplicasets, err := getTopologyReplicasets(conn)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get current topology replica sets: %s", err)
+	}
+
+	return topologyReplicasets, nil
+}
 
-	// get replicasets summaries in sorted aliases order
-	replicasetsSummary := make([]string, len(*topologyReplicasets))
-	for i, topologyReplicaset := range replicasetsList {
-		replicasetsSummary[i] = getTopologyReplicasetSummary(topologyReplicaset)
+// getReplicasetsSummary returns the text list of replica sets
+// (items are sorted by aliases)
+func getReplicasetsSummary(items []replicasetListItem) string {
+	replicasetsSummary := make([]string, len(items))
+	for i, item := range items {
+		replicasetsSummary[i] = getReplicasetSummary(item)
 	}
 
 	return strings.Join(replicasetsSummary, "\n")
 }
 
-func getTopologyReplicasetSummary(topologyReplicaset *TopologyReplicaset) string {
+func getReplicasetSummary(item replicasetListItem) string {
 	replicasetSummary := []string{}
 
 	// example replicaset summary:
@@ -74,7 +105,7 @@ func getTopologyReplicasetSummary(topologyReplicaset *TopologyReplicaset) string
 
 	replicasetTitle := fmt.Sprintf(
 		"• %s",
-		common.ColorHiMagenta.Sprintf(topologyReplicaset.Alias),
+		common.ColorHiMagenta.Sprintf(item.Alias),
 	)
 
 	// additionalInfo is vshard group, weight, all rw
@@ -83,14 +114,14 @@ func getTopologyReplicasetSummary(topologyReplicaset *TopologyReplicaset) string
 	// example:
 	// default | 123.4 | ALL RW
 	additionalInfo := []string{}
-	if topologyReplicaset.VshardGroup != nil {
-		additionalInfo = append(additionalInfo, *topologyReplicaset.VshardGroup)
+	if item.VshardGroup != nil {
+		additionalInfo = append(additionalInfo, *item.VshardGroup)
 	}
-	if topologyReplicaset.Weight != nil {
-		formattedWeight := strconv.FormatFloat(*topologyReplicaset.Weight, 'f', -1, 64)
+	if item.Weight != nil {
+		formattedWeight := strconv.FormatFloat(*item.Weight, 'f', -1, 64)
 		additionalInfo = append(additionalInfo, formattedWeight)
 	}
-	if topologyReplicaset.AllRW != nil && *(topologyReplicaset.AllRW) {
+	if item.AllRW != nil && *(item.AllRW) {
 		additionalInfo = append(additionalInfo, "ALL RW")
 	}
 
@@ -108,10 +139,10 @@ func getTopologyReplicasetSummary(topologyReplicaset *TopologyReplicaset) string
 	// Role: failover-coordinator | vshard-storage | metrics
 	// No roles (if no roles specified)
 	var rolesSummary string
-	if len(topologyReplicaset.Roles) > 0 {
+	if len(item.Roles) > 0 {
 		rolesSummary = fmt.Sprintf(
 			"  Role: %s",
-			strings.Join(topologyReplicaset.Roles, " | "),
+			strings.Join(item.Roles, " | "),
 		)
 	} else {
 		rolesSummary = "  No roles"
@@ -125,23 +156,23 @@ func getTopologyReplicasetSummary(topologyReplicaset *TopologyReplicaset) string
 	// ★ s2-master localhost:3304        msk
 	// • s2-replica localhost:3305       spb
 	instancesSummary := []string{}
-	for _, topologyInstance := range topologyReplicaset.Instances {
+	for _, instanceItem := range item.Instances {
 		instanceMarker := instanceMarker
-		if topologyInstance.UUID == topologyReplicaset.LeaderUUID {
+		if instanceItem.Alias == item.Leader {
 			instanceMarker = leaderInstanceMarker
 		}
 
 		instanceTitle := fmt.Sprintf(
 			"%s %s",
-			common.ColorHiCyan.Sprint(topologyInstance.Alias),
-			topologyInstance.URI,
+			common.ColorHiCyan.Sprint(instanceItem.Alias),
+			instanceItem.URI,
 		)
 
-		if topologyInstance.Zone != "" {
+		if instanceItem.Zone != "" {
 			instanceTitle = fmt.Sprintf(
 				"%-40s %s",
 				instanceTitle,
-				common.ColorCyan.Sprint(topologyInstance.Zone),
+				common.ColorCyan.Sprint(instanceItem.Zone),
 			)
 		}
 
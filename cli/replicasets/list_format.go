@@ -0,0 +1,138 @@
+package replicasets
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	ListFormatText = "text"
+	ListFormatJSON = "json"
+	ListFormatYAML = "yaml"
+)
+
+// instanceListItem is the replica set instance in the structured list output
+type instanceListItem struct {
+	Alias string `json:"alias" yaml:"alias"`
+	UUID  string `json:"uuid" yaml:"uuid"`
+	URI   string `json:"uri" yaml:"uri"`
+	Zone  string `json:"zone,omitempty" yaml:"zone,omitempty"`
+}
+
+// replicasetListItem is the replica set in the structured list output.
+// Fields are encoded in the declaration order, so the output is stable
+type replicasetListItem struct {
+	Alias       string             `json:"alias" yaml:"alias"`
+	UUID        string             `json:"uuid" yaml:"uuid"`
+	Status      string             `json:"status" yaml:"status"`
+	Roles       []string           `json:"roles" yaml:"roles"`
+	Leader      string             `json:"leader,omitempty" yaml:"leader,omitempty"`
+	VshardGroup *string            `json:"vshard_group,omitempty" yaml:"vshard_group,omitempty"`
+	Weight      *float64           `json:"weight,omitempty" yaml:"weight,omitempty"`
+	AllRW       *bool              `json:"all_rw,omitempty" yaml:"all_rw,omitempty"`
+	Instances   []instanceListItem `json:"instances" yaml:"instances"`
+}
+
+// CheckListFormat checks that the list output format is supported
+func CheckListFormat(format string) error {
+	switch format {
+	case ListFormatText, ListFormatJSON, ListFormatYAML:
+		return nil
+	default:
+		return fmt.Errorf("Unknown format %q. Supported formats are %s, %s and %s",
+			format, ListFormatText, ListFormatJSON, ListFormatYAML)
+	}
+}
+
+// getSortedTopologyReplicasets returns replica sets sorted by aliases
+func getSortedTopologyReplicasets(topologyReplicasets *TopologyReplicasets) []*TopologyReplicaset {
+	replicasetsList := make([]*TopologyReplicaset, 0, len(*topologyReplicasets))
+	for _, topologyReplicaset := range *topologyReplicasets {
+		replicasetsList = append(replicasetsList, topologyReplicaset)
+	}
+
+	sort.Slice(replicasetsList, func(i, j int) bool {
+		return replicasetsList[i].Alias < replicasetsList[j].Alias
+	})
+
+	return replicasetsList
+}
+
+func getReplicasetListItem(topologyReplicaset *TopologyReplicaset) replicasetListItem {
+	item := replicasetListItem{
+		Alias:       topologyReplicaset.Alias,
+		UUID:        topologyReplicaset.UUID,
+		Status:      topologyReplicaset.Status,
+		Roles:       topologyReplicaset.Roles,
+		VshardGroup: topologyReplicaset.VshardGroup,
+		Weight:      topologyReplicaset.Weight,
+		AllRW:       topologyReplicaset.AllRW,
+		Instances:   make([]instanceListItem, 0, len(topologyReplicaset.Instances)),
+	}
+
+	if item.Roles == nil {
+		item.Roles = []string{}
+	}
+
+	for _, topologyInstance := range topologyReplicaset.Instances {
+		if topologyInstance.UUID == topologyReplicaset.LeaderUUID {
+			item.Leader = topologyInstance.Alias
+		}
+
+		item.Instances = append(item.Instances, instanceListItem{
+			Alias: topologyInstance.Alias,
+			UUID:  topologyInstance.UUID,
+			URI:   topologyInstance.URI,
+			Zone:  topologyInstance.Zone,
+		})
+	}
+
+	return item
+}
+
+// getReplicasetsListItems returns replica sets sorted by aliases.
+// Nil topology is the empty one
+func getReplicasetsListItems(topologyReplicasets *TopologyReplicasets) []replicasetListItem {
+	items := []replicasetListItem{}
+
+	if topologyReplicasets == nil {
+		return items
+	}
+
+	for _, topologyReplicaset := range getSortedTopologyReplicasets(topologyReplicasets) {
+		items = append(items, getReplicasetListItem(topologyReplicaset))
+	}
+
+	return items
+}
+
+// writeReplicasetsList writes the replica sets list in JSON or YAML format to w
+func writeReplicasetsList(w io.Writer, items []replicasetListItem, format string) error {
+	var data []byte
+	var err error
+
+	switch format {
+	case ListFormatJSON:
+		if data, err = json.MarshalIndent(items, "", "  "); err == nil {
+			data = append(data, '\n')
+		}
+	case ListFormatYAML:
+		data, err = yaml.Marshal(items)
+	default:
+		return fmt.Errorf("Unknown format %q", format)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed to encode replica sets list: %s", err)
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("Failed to write replica sets list: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package replicasets
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestWriteReplicasetsList(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	weight := 1.5
+	vshardGroup := "hot"
+
+	topologyReplicasets := &TopologyReplicasets{
+		"rpl-2": &TopologyReplicaset{
+			UUID:       "rpl-2",
+			Alias:      "s-2",
+			Status:     "unhealthy",
+			LeaderUUID: "uuid-3",
+			Instances: TopologyInstances{
+				&TopologyInstance{Alias: "s2-master", UUID: "uuid-3", URI: "localhost:3304"},
+			},
+		},
+		"rpl-1": &TopologyReplicaset{
+			UUID:        "rpl-1",
+			Alias:       "s-1",
+			Status:      "healthy",
+			Roles:       []string{"vshard-storage"},
+			Weight:      &weight,
+			VshardGroup: &vshardGroup,
+			LeaderUUID:  "uuid-2",
+			Instances: TopologyInstances{
+				&TopologyInstance{Alias: "s1-replica", UUID: "uuid-1", URI: "localhost:3303"},
+				&TopologyInstance{Alias: "s1-master", UUID: "uuid-2", URI: "localhost:3302", Zone: "msk"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.Nil(writeReplicasetsList(&buf, getReplicasetsListItems(topologyReplicasets), ListFormatJSON))
+	assert.Equal(`[
+  {
+    "alias": "s-1",
+    "uuid": "rpl-1",
+    "status": "healthy",
+    "roles": [
+      "vshard-storage"
+    ],
+    "leader": "s1-master",
+    "vshard_group": "hot",
+    "weight": 1.5,
+    "instances": [
+      {
+        "alias": "s1-replica",
+        "uuid": "uuid-1",
+        "uri": "localhost:3303"
+      },
+      {
+        "alias": "s1-master",
+        "uuid": "uuid-2",
+        "uri": "localhost:3302",
+        "zone": "msk"
+      }
+    ]
+  },
+  {
+    "alias": "s-2",
+    "uuid": "rpl-2",
+    "status": "unhealthy",
+    "roles": [],
+    "leader": "s2-master",
+    "instances": [
+      {
+        "alias": "s2-master",
+        "uuid": "uuid-3",
+        "uri": "localhost:3304"
+      }
+    ]
+  }
+]
+`, buf.String())
+
+	buf.Reset()
+	assert.Nil(writeReplicasetsList(&buf, getReplicasetsListItems(topologyReplicasets), ListFormatYAML))
+
+	var items []replicasetListItem
+	assert.Nil(yaml.Unmarshal(buf.Bytes(), &items))
+	assert.Equal(getReplicasetsListItems(topologyReplicasets), items)
+
+	// empty topology
+	for _, topology := range []*TopologyReplicasets{nil, {}} {
+		buf.Reset()
+		assert.Nil(writeReplicasetsList(&buf, getReplicasetsListItems(topology), ListFormatJSON))
+		assert.Equal("[]\n", buf.String())
+
+		buf.Reset()
+		assert.Nil(writeReplicasetsList(&buf, getReplicasetsListItems(topology), ListFormatYAML))
+		assert.Equal("[]\n", buf.String())
+	}
+
+	assert.EqualError(CheckListFormat("xml"), `Unknown format "xml". Supported formats are text, json and yaml`)
+}
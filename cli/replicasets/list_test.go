@@ -33,7 +33,7 @@ func TestGetReplicasetSummary(t *testing.T) {
 		},
 	}
 
-	summary = getTopologyReplicasetSummary(topologyReplicaset)
+	summary = getReplicasetSummary(getReplicasetListItem(topologyReplicaset))
 	expSummary = `• rpl-alias
   No roles
     • instance-1 uri-1`
@@ -55,7 +55,7 @@ func TestGetReplicasetSummary(t *testing.T) {
 		},
 	}
 
-	summary = getTopologyReplicasetSummary(topologyReplicaset)
+	summary = getReplicasetSummary(getReplicasetListItem(topologyReplicaset))
 	expSummary = `• rpl-alias
   Role: role-1 | role-2 | role-3
     • instance-1 uri-1`
@@ -80,7 +80,7 @@ func TestGetReplicasetSummary(t *testing.T) {
 		},
 	}
 
-	summary = getTopologyReplicasetSummary(topologyReplicaset)
+	summary = getReplicasetSummary(getReplicasetListItem(topologyReplicaset))
 	expSummary = `• rpl-alias                       hot | 123.4 | ALL RW
   No roles
     • instance-1 uri-1`
@@ -105,7 +105,7 @@ func TestGetReplicasetSummary(t *testing.T) {
 		},
 	}
 
-	summary = getTopologyReplicasetSummary(topologyReplicaset)
+	summary = getReplicasetSummary(getReplicasetListItem(topologyReplicaset))
 	expSummary = `• rpl-alias                       hot | 123.4
   No roles
     • instance-1 uri-1`
@@ -138,7 +138,7 @@ func TestGetReplicasetSummary(t *testing.T) {
 		},
 	}
 
-	summary = getTopologyReplicasetSummary(topologyReplicaset)
+	summary = getReplicasetSummary(getReplicasetListItem(topologyReplicaset))
 	expSummary = `• rpl-alias
   No roles
     • instance-1 uri-1
@@ -174,7 +174,7 @@ func TestGetReplicasetSummary(t *testing.T) {
 		},
 	}
 
-	summary = getTopologyReplicasetSummary(topologyReplicaset)
+	summary = getReplicasetSummary(getReplicasetListItem(topologyReplicaset))
 	expSummary = `• rpl-alias
   No roles
     • instance-1 uri-1                         msk
@@ -214,7 +214,7 @@ func TestGetReplicasetSummary(t *testing.T) {
 		},
 	}
 
-	summary = getTopologyReplicasetSummary(topologyReplicaset)
+	summary = getReplicasetSummary(getReplicasetListItem(topologyReplicaset))
 	expSummary = `• rpl-alias                       hot | 123.4 | ALL RW
   Role: role-1 | role-2 | role-3
     • instance-1 uri-1                         msk
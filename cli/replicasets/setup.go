@@ -54,11 +54,26 @@ func Setup(ctx *context.Ctx, args []string) error {
 		return fmt.Errorf("Failed to get replicasets configuration: %s", err)
 	}
 
+	failoverConf, err := getFileFailoverConf(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to get failover configuration: %s", err)
+	}
+
+	if failoverConf != nil {
+		if err := checkFailoverConf(failoverConf); err != nil {
+			return fmt.Errorf("Invalid failover configuration in %s: %s", ctx.Replicasets.File, err)
+		}
+	}
+
 	instancesConf, err := getInstancesConf(ctx)
 	if err != nil {
 		return fmt.Errorf("Failed to get instances configuration: %s", err)
 	}
 
+	if err := checkReplicasetInstancesConf(replicasetsList, instancesConf); err != nil {
+		return fmt.Errorf("Invalid replicasets configuration file %s: %s", ctx.Replicasets.File, err)
+	}
+
 	conn, err := getConnToSetupReplicasets(replicasetsList, instancesConf, ctx)
 	if err != nil {
 		return err
@@ -80,6 +95,12 @@ func Setup(ctx *context.Ctx, args []string) error {
 
 	log.Infof("Replicasets are set up successfully")
 
+	if failoverConf != nil {
+		if err := setupFailover(conn, failoverConf); err != nil {
+			return err
+		}
+	}
+
 	if ctx.Replicasets.BootstrapVshard {
 		if err := bootstrapVshard(conn); err != nil {
 			return fmt.Errorf("Failed to bootstrap vshard: %s", err)
@@ -151,7 +172,7 @@ func createAndUpdateReplicasets(conn net.Conn, replicasetsList *ReplicasetsList,
 				return nil, fmt.Errorf("Failed to get edit_topology options for creating replicaset: %s", err)
 			}
 			*editReplicasetsOpts = append(*editReplicasetsOpts, editReplicasetOpts)
-		} else {
+		} else if !replicasetConfIsApplied(replicasetConf, topologyReplicaset) {
 			editReplicasetOpts, err := getUpdateReplicasetEditReplicasetsOpts(topologyReplicaset, replicasetConf, instancesConf)
 			if err != nil {
 				return nil, fmt.Errorf("Failed to get edit_topology options for updating replicaset: %s", err)
@@ -160,12 +181,7 @@ func createAndUpdateReplicasets(conn net.Conn, replicasetsList *ReplicasetsList,
 		}
 	}
 
-	newTopologyReplicasets, err := editReplicasetsList(conn, editReplicasetsOpts)
-	if err != nil {
-		return nil, err
-	}
-
-	return newTopologyReplicasets, nil
+	return editChangedReplicasets(conn, editReplicasetsOpts, topologyReplicasets)
 }
 
 func createFirstReplicasetInOldCartridge(conn net.Conn, replicasetsList *ReplicasetsList, instancesConf *InstancesConf) (*TopologyReplicaset, error) {
@@ -195,6 +211,10 @@ func setFailoverPriority(conn net.Conn, replicasetsList *ReplicasetsList, topolo
 	for _, replicasetConf := range *replicasetsList {
 		newTopologyReplicaset := topologyReplicasets.GetByAlias(replicasetConf.Alias)
 
+		if failoverPriorityIsApplied(replicasetConf.InstanceNames, newTopologyReplicaset) {
+			continue
+		}
+
 		// set failover priority
 		editReplicasetOpts, err := getSetFailoverPriorityEditReplicasetOpts(replicasetConf.InstanceNames, newTopologyReplicaset)
 		if err != nil {
@@ -204,14 +224,85 @@ func setFailoverPriority(conn net.Conn, replicasetsList *ReplicasetsList, topolo
 		editReplicasetsOpts = append(editReplicasetsOpts, editReplicasetOpts)
 	}
 
-	newTopologyReplicasets, err := editReplicasetsList(conn, &editReplicasetsOpts)
+	return editChangedReplicasets(conn, &editReplicasetsOpts, topologyReplicasets)
+}
+
+// editChangedReplicasets applies edit_topology options and returns
+// the topology replicasets with edited ones replaced.
+// If there is nothing to change, edit_topology isn't called at all,
+// so applying the same configuration is a no-op
+func editChangedReplicasets(conn net.Conn, editReplicasetsOpts *EditReplicasetsListOpts,
+	topologyReplicasets *TopologyReplicasets) (*TopologyReplicasets, error) {
+
+	newTopologyReplicasets := &TopologyReplicasets{}
+	for replicasetUUID, topologyReplicaset := range *topologyReplicasets {
+		(*newTopologyReplicasets)[replicasetUUID] = topologyReplicaset
+	}
+
+	if len(*editReplicasetsOpts) == 0 {
+		return newTopologyReplicasets, nil
+	}
+
+	editedTopologyReplicasets, err := editReplicasetsList(conn, editReplicasetsOpts)
 	if err != nil {
 		return nil, err
 	}
 
+	for replicasetUUID, editedTopologyReplicaset := range *editedTopologyReplicasets {
+		(*newTopologyReplicasets)[replicasetUUID] = editedTopologyReplicaset
+	}
+
 	return newTopologyReplicasets, nil
 }
 
+// replicasetConfIsApplied checks if the existing replicaset already has
+// the configured roles, instances and parameters
+func replicasetConfIsApplied(replicasetConf *ReplicasetConf, topologyReplicaset *TopologyReplicaset) bool {
+	if len(common.GetStringSlicesDifference(replicasetConf.Roles, topologyReplicaset.Roles)) > 0 ||
+		len(common.GetStringSlicesDifference(topologyReplicaset.Roles, replicasetConf.Roles)) > 0 {
+		return false
+	}
+
+	topologyInstancesAliases := make([]string, len(topologyReplicaset.Instances))
+	for i, instance := range topologyReplicaset.Instances {
+		topologyInstancesAliases[i] = instance.Alias
+	}
+
+	if len(common.GetStringSlicesDifference(replicasetConf.InstanceNames, topologyInstancesAliases)) > 0 {
+		return false
+	}
+
+	if replicasetConf.Weight != nil && !floatPtrsEqual(replicasetConf.Weight, topologyReplicaset.Weight) {
+		return false
+	}
+
+	if replicasetConf.AllRW != nil && !boolPtrsEqual(replicasetConf.AllRW, topologyReplicaset.AllRW) {
+		return false
+	}
+
+	if replicasetConf.VshardGroup != nil && !stringPtrsEqual(replicasetConf.VshardGroup, topologyReplicaset.VshardGroup) {
+		return false
+	}
+
+	return true
+}
+
+// failoverPriorityIsApplied checks if the replicaset instances
+// are already ordered as specified
+func failoverPriorityIsApplied(instanceNames []string, topologyReplicaset *TopologyReplicaset) bool {
+	if topologyReplicaset == nil || len(topologyReplicaset.Instances) < len(instanceNames) {
+		return false
+	}
+
+	for i, instanceName := range instanceNames {
+		if topologyReplicaset.Instances[i].Alias != instanceName {
+			return false
+		}
+	}
+
+	return true
+}
+
 func logSetupSummary(topologyReplicasets, newTopologyReplicasets *TopologyReplicasets) {
 	for replicasetUUID, newTopologyReplicaset := range *newTopologyReplicasets {
 		replicasetID := newTopologyReplicaset.Alias
@@ -247,11 +338,25 @@ func getReplicasetsList(ctx *context.Ctx) (*ReplicasetsList, error) {
 		return nil, fmt.Errorf("Failed to read replicasets configuration file: %s", err)
 	}
 
+	var rawReplicasetsConf interface{}
+	if err := yaml.Unmarshal([]byte(fileContentBytes), &rawReplicasetsConf); err != nil {
+		return nil, fmt.Errorf("Failed to parse replicasets configuration file %s: %s", ctx.Replicasets.File, err)
+	}
+
+	if rawReplicasetsConf != nil {
+		if err := validateReplicasetsConf(rawReplicasetsConf); err != nil {
+			return nil, fmt.Errorf("Invalid replicasets configuration file %s: %s", ctx.Replicasets.File, err)
+		}
+	}
+
 	var replicasetsConf ReplicasetsConf
 	if err := yaml.Unmarshal([]byte(fileContentBytes), &replicasetsConf); err != nil {
 		return nil, fmt.Errorf("Failed to parse replicasets configuration file %s: %s", ctx.Replicasets.File, err)
 	}
 
+	// failover configuration is read by getFileFailoverConf
+	delete(replicasetsConf, failoverConfKey)
+
 	if len(replicasetsConf) == 0 {
 		return nil, fmt.Errorf("No replicasets specified in %s", ctx.Replicasets.File)
 	}
@@ -269,6 +374,70 @@ func getReplicasetsList(ctx *context.Ctx) (*ReplicasetsList, error) {
 	return &replicasetsList, nil
 }
 
+// getFileFailoverConf returns the failover configuration described
+// in the replicasets configuration file or nil if it isn't specified.
+// The file is expected to be already validated by getReplicasetsList
+func getFileFailoverConf(ctx *context.Ctx) (*FailoverConf, error) {
+	fileContentBytes, err := common.GetFileContentBytes(ctx.Replicasets.File)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read replicasets configuration file: %s", err)
+	}
+
+	failoverConf, err := parseFileFailoverConf(fileContentBytes)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse replicasets configuration file %s: %s", ctx.Replicasets.File, err)
+	}
+
+	return failoverConf, nil
+}
+
+// parseFileFailoverConf parses the failover configuration
+// specified under failoverConfKey in the replicasets configuration file.
+// Other keys are replica sets (one of them can be named `failover`)
+func parseFileFailoverConf(fileContent []byte) (*FailoverConf, error) {
+	var fileConf map[string]interface{}
+	if err := yaml.Unmarshal(fileContent, &fileConf); err != nil {
+		return nil, err
+	}
+
+	failoverConfRaw, found := fileConf[failoverConfKey]
+	if !found {
+		return nil, nil
+	}
+
+	failoverConfContent, err := yaml.Marshal(failoverConfRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	var failoverConf FailoverConf
+	if err := yaml.Unmarshal(failoverConfContent, &failoverConf); err != nil {
+		return nil, err
+	}
+
+	return &failoverConf, nil
+}
+
+func setupFailover(conn net.Conn, failoverConf *FailoverConf) error {
+	currentFailoverConf, err := getFailoverConf(conn)
+	if err != nil {
+		return err
+	}
+
+	if failoverConfIsApplied(failoverConf, currentFailoverConf) {
+		log.Infof("Failover configuration is already applied")
+		return nil
+	}
+
+	if err := setFailoverConf(conn, failoverConf); err != nil {
+		return err
+	}
+
+	log.Infof("Failover configuration is applied: %s mode", failoverConf.Mode)
+
+	return nil
+}
+
 func getConnToSetupReplicasets(replicasetsList *ReplicasetsList, instancesConf *InstancesConf, ctx *context.Ctx) (net.Conn, error) {
 	controlInstanceName, err := getJoinedInstanceName(instancesConf, ctx)
 	if err != nil {
@@ -1,9 +1,16 @@
 package replicasets
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
 )
 
 func TestGetCreateReplicasetEditReplicasetsOpts(t *testing.T) {
@@ -202,3 +209,251 @@ func TestGetUpdateReplicasetEditReplicasetsOpts(t *testing.T) {
 		serializedOpts,
 	)
 }
+
+func TestValidateReplicasetsConf(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	validate := func(content string) error {
+		var rawConf interface{}
+		if err := yaml.Unmarshal([]byte(content), &rawConf); err != nil {
+			t.Fatalf("Failed to parse configuration: %s", err)
+		}
+
+		return validateReplicasetsConf(rawConf)
+	}
+
+	assert.Nil(validate(`
+router:
+  instances: [router]
+  roles: [vshard-router, app.roles.custom]
+s-1:
+  instances: [s1-master, s1-replica]
+  roles: [vshard-storage]
+  weight: 1
+  all_rw: false
+  vshard_group: hot
+s-2:
+  instances: [s2-master]
+  weight: 0.5
+`))
+
+	assert.Nil(validate(`
+s-1:
+  instances: [s1-master]
+cartridge.failover:
+  mode: stateful
+  state_provider: etcd2
+  failover_timeout: 20
+  fencing_enabled: true
+  etcd2_params:
+    prefix: /myapp
+    lock_delay: 10
+    endpoints: [http://etcd-1:2379]
+`))
+
+	assert.Nil(validate(`
+failover:
+  instances: [s1-master]
+`))
+
+	cases := map[string]string{
+		`[s-1]`:            `Configuration should be a map of replica sets`,
+		`s-1: [s1-master]`: `s-1: should be a map`,
+		`s-1: {instances: [s1-master], weigth: 1}`: `s-1.weigth: unknown field, ` +
+			`supported fields are: instances, roles, weight, all_rw, vshard_group`,
+		`s-1: {roles: [vshard-storage]}`:                 `s-1.instances: field is required`,
+		`s-1: {instances: s1-master}`:                    `s-1.instances: should be a list of strings`,
+		`s-1: {instances: []}`:                           `s-1.instances: at least one instance should be specified`,
+		`s-1: {instances: [s1-master, 1]}`:               `s-1.instances[1]: should be a non-empty string`,
+		`s-1: {instances: [s1-master, s1-master]}`:       `s-1.instances[1]: instance s1-master is specified twice`,
+		`s-1: {instances: [s1-master], roles: [true]}`:   `s-1.roles[0]: should be a non-empty string`,
+		`s-1: {instances: [s1-master], weight: -1}`:      `s-1.weight: should be a non-negative number`,
+		`s-1: {instances: [s1-master], weight: high}`:    `s-1.weight: should be a non-negative number`,
+		`s-1: {instances: [s1-master], all_rw: yes!}`:    `s-1.all_rw: should be a boolean`,
+		`s-1: {instances: [s1-master], vshard_group: 1}`: `s-1.vshard_group: should be a non-empty string`,
+		"s-1: {instances: [s1-master]}\ns-2: {instances: [s1-master]}": `s-2.instances[0]: ` +
+			`instance s1-master is already specified in replica set s-1`,
+		`cartridge.failover: [eventual]`:              `cartridge.failover: should be a map`,
+		`cartridge.failover: {state_provider: etcd2}`: `cartridge.failover.mode: field is required`,
+		`cartridge.failover: {mode: manual}`:          `cartridge.failover.mode: should be one of: disabled, eventual, stateful`,
+		`cartridge.failover: {mode: stateful, state_provider: consul}`: `cartridge.failover.state_provider: ` +
+			`should be one of: stateboard, etcd2`,
+		`cartridge.failover: {mode: eventual, failover_timeout: -1}`: `cartridge.failover.failover_timeout: should be a non-negative number`,
+		`cartridge.failover: {mode: stateful, stateboard_params: {uri: localhost:4401}}`: `cartridge.failover.stateboard_params.password: ` +
+			`should be a non-empty string`,
+		`cartridge.failover: {mode: stateful, etcd2_params: {endpoint: http://etcd-1:2379}}`: `cartridge.failover.etcd2_params.endpoint: ` +
+			`unknown field, supported fields are: prefix, lock_delay, endpoints, username, password`,
+		// replica set can be named failover
+		`failover: {mode: eventual}`: `failover.mode: unknown field, ` +
+			`supported fields are: instances, roles, weight, all_rw, vshard_group`,
+		// the first offending replica set is reported
+		"s-2: {instances: []}\ns-1: {instances: [s1-master], weight: -1}": `s-1.weight: should be a non-negative number`,
+	}
+
+	for content, expErr := range cases {
+		assert.EqualError(validate(content), expErr, content)
+	}
+}
+
+func TestCheckReplicasetInstancesConf(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	instancesConf := &InstancesConf{
+		"router":    &InstanceConf{URI: "uri-1"},
+		"s1-master": &InstanceConf{URI: "uri-2"},
+	}
+
+	replicasetsList := &ReplicasetsList{
+		&ReplicasetConf{Alias: "s-1", InstanceNames: []string{"s1-master", "s1-replica"}},
+		&ReplicasetConf{Alias: "router", InstanceNames: []string{"router"}},
+	}
+
+	assert.EqualError(
+		checkReplicasetInstancesConf(replicasetsList, instancesConf),
+		"s-1.instances[1]: instance s1-replica isn't described in instances configuration",
+	)
+
+	(*instancesConf)["s1-replica"] = &InstanceConf{URI: "uri-3"}
+	assert.Nil(checkReplicasetInstancesConf(replicasetsList, instancesConf))
+}
+
+func TestReplicasetConfIsApplied(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	weight := 1.0
+	otherWeight := 2.0
+	allRW := true
+	vshardGroup := "hot"
+
+	topologyReplicaset := &TopologyReplicaset{
+		UUID:        "rpl-uuid",
+		Alias:       "s-1",
+		Roles:       []string{"vshard-storage", "metrics"},
+		Weight:      &weight,
+		AllRW:       &allRW,
+		VshardGroup: &vshardGroup,
+		Instances: TopologyInstances{
+			&TopologyInstance{Alias: "s1-master", UUID: "uuid-1"},
+			&TopologyInstance{Alias: "s1-replica", UUID: "uuid-2"},
+		},
+	}
+
+	replicasetConf := &ReplicasetConf{
+		Alias:         "s-1",
+		Roles:         []string{"metrics", "vshard-storage"},
+		InstanceNames: []string{"s1-replica", "s1-master"},
+	}
+
+	// roles order and unspecified parameters don't matter
+	assert.True(replicasetConfIsApplied(replicasetConf, topologyReplicaset))
+
+	replicasetConf.Weight = &weight
+	replicasetConf.AllRW = &allRW
+	replicasetConf.VshardGroup = &vshardGroup
+	assert.True(replicasetConfIsApplied(replicasetConf, topologyReplicaset))
+
+	replicasetConf.Weight = &otherWeight
+	assert.False(replicasetConfIsApplied(replicasetConf, topologyReplicaset))
+	replicasetConf.Weight = &weight
+
+	replicasetConf.Roles = []string{"vshard-storage"}
+	assert.False(replicasetConfIsApplied(replicasetConf, topologyReplicaset))
+	replicasetConf.Roles = []string{"vshard-storage", "metrics", "app.roles.custom"}
+	assert.False(replicasetConfIsApplied(replicasetConf, topologyReplicaset))
+	replicasetConf.Roles = []string{"vshard-storage", "metrics"}
+
+	replicasetConf.InstanceNames = []string{"s1-master", "s1-replica", "s1-replica-2"}
+	assert.False(replicasetConfIsApplied(replicasetConf, topologyReplicaset))
+
+	// failover priority
+	assert.True(failoverPriorityIsApplied([]string{"s1-master"}, topologyReplicaset))
+	assert.True(failoverPriorityIsApplied([]string{"s1-master", "s1-replica"}, topologyReplicaset))
+	assert.False(failoverPriorityIsApplied([]string{"s1-replica", "s1-master"}, topologyReplicaset))
+	assert.False(failoverPriorityIsApplied([]string{"s1-master", "s1-replica", "s1-replica-2"}, topologyReplicaset))
+	assert.False(failoverPriorityIsApplied([]string{"s1-master"}, nil))
+}
+
+func TestGetFileFailoverConf(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "replicasets")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	ctx := &context.Ctx{}
+	ctx.Replicasets.File = filepath.Join(tmpDir, "replicasets.yml")
+
+	getAliases := func(replicasetsList *ReplicasetsList) []string {
+		aliases := make([]string, 0, len(*replicasetsList))
+		for _, replicasetConf := range *replicasetsList {
+			aliases = append(aliases, replicasetConf.Alias)
+		}
+
+		sort.Strings(aliases)
+		return aliases
+	}
+
+	// exported configuration is set up as is
+	stateProvider := StateProviderEtcd2
+	failoverTimeout := 20.0
+	fencingEnabled := true
+	prefix := "/myapp"
+
+	failoverConf := &FailoverConf{
+		Mode:            FailoverModeStateful,
+		StateProvider:   &stateProvider,
+		FailoverTimeout: &failoverTimeout,
+		FencingEnabled:  &fencingEnabled,
+		Etcd2Params: &Etcd2Params{
+			Prefix:    &prefix,
+			Endpoints: []string{"http://etcd-1:2379"},
+		},
+	}
+
+	replicasetsConf := &ReplicasetsConf{
+		"router": &ReplicasetConf{
+			InstanceNames: []string{"router"},
+			Roles:         []string{"vshard-router"},
+		},
+		"failover": &ReplicasetConf{
+			InstanceNames: []string{"failover"},
+			Roles:         []string{"app.roles.custom"},
+		},
+	}
+
+	confContent, err := getExportedConfContent(replicasetsConf, failoverConf)
+	assert.Nil(err)
+	assert.Nil(ioutil.WriteFile(ctx.Replicasets.File, confContent, 0600))
+
+	replicasetsList, err := getReplicasetsList(ctx)
+	assert.Nil(err)
+	assert.Equal([]string{"failover", "router"}, getAliases(replicasetsList))
+
+	fileFailoverConf, err := getFileFailoverConf(ctx)
+	assert.Nil(err)
+	assert.Equal(failoverConf, fileFailoverConf)
+
+	// replica set named failover isn't a failover configuration
+	assert.Nil(ioutil.WriteFile(ctx.Replicasets.File, []byte(`
+router:
+  instances: [router]
+failover:
+  instances: [failover]
+`), 0600))
+
+	replicasetsList, err = getReplicasetsList(ctx)
+	assert.Nil(err)
+	assert.Equal([]string{"failover", "router"}, getAliases(replicasetsList))
+
+	fileFailoverConf, err = getFileFailoverConf(ctx)
+	assert.Nil(err)
+	assert.Nil(fileFailoverConf)
+}
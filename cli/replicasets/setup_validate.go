@@ -0,0 +1,254 @@
+package replicasets
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+)
+
+var (
+	replicasetConfFields = []string{"instances", "roles", "weight", "all_rw", "vshard_group"}
+
+	failoverConfFields = []string{
+		"mode", "state_provider", "failover_timeout", "fencing_enabled", "stateboard_params", "etcd2_params",
+	}
+	stateboardParamsFields = []string{"uri", "password"}
+	etcd2ParamsFields      = []string{"prefix", "lock_delay", "endpoints", "username", "password"}
+)
+
+// validateReplicasetsConf checks the raw replicasets configuration
+// (parsed to the generic YAML value) before it's applied.
+// The first offending field is reported with its path, e.g. `s-1.instances[0]`.
+// Replicasets are checked in the aliases order, fields - in replicasetConfFields order.
+// The `failover` key describes the failover configuration instead of a replica set
+func validateReplicasetsConf(rawConf interface{}) error {
+	replicasetsMap, ok := rawConf.(map[interface{}]interface{})
+	if !ok {
+		return fmt.Errorf("Configuration should be a map of replica sets")
+	}
+
+	aliases := make([]string, 0, len(replicasetsMap))
+	for rawAlias := range replicasetsMap {
+		alias, ok := rawAlias.(string)
+		if !ok || alias == "" {
+			return fmt.Errorf("%v: replica set alias should be a non-empty string", rawAlias)
+		}
+
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	instancesReplicasets := make(map[string]string)
+
+	for _, alias := range aliases {
+		if alias == failoverConfKey {
+			if err := validateFailoverConf(replicasetsMap[alias]); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		replicasetMap, ok := replicasetsMap[alias].(map[interface{}]interface{})
+		if !ok {
+			return fmt.Errorf("%s: should be a map", alias)
+		}
+
+		if err := checkReplicasetConfFields(alias, replicasetMap); err != nil {
+			return err
+		}
+
+		instancesPath := fmt.Sprintf("%s.instances", alias)
+		rawInstances, found := replicasetMap["instances"]
+		if !found {
+			return fmt.Errorf("%s: field is required", instancesPath)
+		}
+
+		instanceNames, err := getStringsListField(instancesPath, rawInstances)
+		if err != nil {
+			return err
+		}
+
+		if len(instanceNames) == 0 {
+			return fmt.Errorf("%s: at least one instance should be specified", instancesPath)
+		}
+
+		for i, instanceName := range instanceNames {
+			if otherAlias, found := instancesReplicasets[instanceName]; found {
+				if otherAlias == alias {
+					return fmt.Errorf("%s[%d]: instance %s is specified twice", instancesPath, i, instanceName)
+				}
+
+				return fmt.Errorf("%s[%d]: instance %s is already specified in replica set %s",
+					instancesPath, i, instanceName, otherAlias)
+			}
+
+			instancesReplicasets[instanceName] = alias
+		}
+
+		if rawRoles, found := replicasetMap["roles"]; found {
+			if _, err := getStringsListField(fmt.Sprintf("%s.roles", alias), rawRoles); err != nil {
+				return err
+			}
+		}
+
+		if rawWeight, found := replicasetMap["weight"]; found {
+			if !isNonNegativeNumber(rawWeight) {
+				return fmt.Errorf("%s.weight: should be a non-negative number", alias)
+			}
+		}
+
+		if rawAllRW, found := replicasetMap["all_rw"]; found {
+			if _, ok := rawAllRW.(bool); !ok {
+				return fmt.Errorf("%s.all_rw: should be a boolean", alias)
+			}
+		}
+
+		if rawVshardGroup, found := replicasetMap["vshard_group"]; found {
+			if vshardGroup, ok := rawVshardGroup.(string); !ok || vshardGroup == "" {
+				return fmt.Errorf("%s.vshard_group: should be a non-empty string", alias)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkReplicasetInstancesConf checks that all instances of the replicasets
+// are described in the instances configuration
+func checkReplicasetInstancesConf(replicasetsList *ReplicasetsList, instancesConf *InstancesConf) error {
+	sortedList := make(ReplicasetsList, len(*replicasetsList))
+	copy(sortedList, *replicasetsList)
+	sort.Slice(sortedList, func(i, j int) bool {
+		return sortedList[i].Alias < sortedList[j].Alias
+	})
+
+	for _, replicasetConf := range sortedList {
+		for i, instanceName := range replicasetConf.InstanceNames {
+			if _, found := (*instancesConf)[instanceName]; !found {
+				return fmt.Errorf("%s.instances[%d]: instance %s isn't described in instances configuration",
+					replicasetConf.Alias, i, instanceName)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateFailoverConf(rawFailoverConf interface{}) error {
+	failoverMap, ok := rawFailoverConf.(map[interface{}]interface{})
+	if !ok {
+		return fmt.Errorf("%s: should be a map", failoverConfKey)
+	}
+
+	if err := checkConfFields(failoverConfKey, failoverMap, failoverConfFields); err != nil {
+		return err
+	}
+
+	modePath := fmt.Sprintf("%s.mode", failoverConfKey)
+	rawMode, found := failoverMap["mode"]
+	if !found {
+		return fmt.Errorf("%s: field is required", modePath)
+	}
+
+	if mode, ok := rawMode.(string); !ok || !common.StringSliceContains(FailoverModes, mode) {
+		return fmt.Errorf("%s: should be one of: %s", modePath, strings.Join(FailoverModes, ", "))
+	}
+
+	if rawStateProvider, found := failoverMap["state_provider"]; found {
+		if stateProvider, ok := rawStateProvider.(string); !ok || !common.StringSliceContains(stateProviders, stateProvider) {
+			return fmt.Errorf("%s.state_provider: should be one of: %s",
+				failoverConfKey, strings.Join(stateProviders, ", "))
+		}
+	}
+
+	if rawFailoverTimeout, found := failoverMap["failover_timeout"]; found {
+		if !isNonNegativeNumber(rawFailoverTimeout) {
+			return fmt.Errorf("%s.failover_timeout: should be a non-negative number", failoverConfKey)
+		}
+	}
+
+	if rawFencingEnabled, found := failoverMap["fencing_enabled"]; found {
+		if _, ok := rawFencingEnabled.(bool); !ok {
+			return fmt.Errorf("%s.fencing_enabled: should be a boolean", failoverConfKey)
+		}
+	}
+
+	if rawParams, found := failoverMap["stateboard_params"]; found {
+		paramsPath := fmt.Sprintf("%s.stateboard_params", failoverConfKey)
+		paramsMap, ok := rawParams.(map[interface{}]interface{})
+		if !ok {
+			return fmt.Errorf("%s: should be a map", paramsPath)
+		}
+
+		if err := checkConfFields(paramsPath, paramsMap, stateboardParamsFields); err != nil {
+			return err
+		}
+
+		for _, field := range stateboardParamsFields {
+			if value, ok := paramsMap[field].(string); !ok || value == "" {
+				return fmt.Errorf("%s.%s: should be a non-empty string", paramsPath, field)
+			}
+		}
+	}
+
+	if rawParams, found := failoverMap["etcd2_params"]; found {
+		paramsPath := fmt.Sprintf("%s.etcd2_params", failoverConfKey)
+		paramsMap, ok := rawParams.(map[interface{}]interface{})
+		if !ok {
+			return fmt.Errorf("%s: should be a map", paramsPath)
+		}
+
+		if err := checkConfFields(paramsPath, paramsMap, etcd2ParamsFields); err != nil {
+			return err
+		}
+
+		for _, field := range []string{"prefix", "username", "password"} {
+			if rawValue, found := paramsMap[field]; found {
+				if _, ok := rawValue.(string); !ok {
+					return fmt.Errorf("%s.%s: should be a string", paramsPath, field)
+				}
+			}
+		}
+
+		if rawLockDelay, found := paramsMap["lock_delay"]; found {
+			if !isNonNegativeNumber(rawLockDelay) {
+				return fmt.Errorf("%s.lock_delay: should be a non-negative number", paramsPath)
+			}
+		}
+
+		if rawEndpoints, found := paramsMap["endpoints"]; found {
+			if _, err := getStringsListField(fmt.Sprintf("%s.endpoints", paramsPath), rawEndpoints); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkReplicasetConfFields(alias string, replicasetMap map[interface{}]interface{}) error {
+	return checkConfFields(alias, replicasetMap, replicasetConfFields)
+}
+
+func checkConfFields(path string, confMap map[interface{}]interface{}, confFields []string) error {
+	var unknownFields []string
+
+	for rawField := range confMap {
+		field := fmt.Sprintf("%v", rawField)
+
+		if !common.StringSliceContains(confFields, field) {
+			unknownFields = append(unknownFields, field)
+		}
+	}
+
+	if len(unknownFields) > 0 {
+		sort.Strings(unknownFields)
+		return fmt.Errorf("%s.%s: unknown field, supported fields are: %s",
+			path, unknownFields[0], strings.Join(confFields, ", "))
+	}
+
+	return nil
+}
@@ -1,20 +1,30 @@
 package rpm
 
+import (
+	"github.com/tarantool/cartridge-cli/cli/common"
+)
+
 const (
 	defaultFileUser   = "root"
 	defaultFileGroup  = "root"
 	defaultFileLang   = ""
 	defaultFileLinkTo = ""
 	emptyDigest       = ""
+	defaultBuildHost  = "localhost"
 
 	headerSignatures = 62
 	headerImmutable  = 63
 
+	hashAlgoSHA1   = 2
 	hashAlgoSHA256 = 8
+	hashAlgoSHA512 = 10
+
+	defaultPayloadDigestAlgo = "sha256"
 
 	// XXX
-	fileFlag = 1 << 4
-	dirFlag  = 0
+	fileFlag    = 1 << 4
+	docFileFlag = 1 << 1
+	dirFlag     = 0
 
 	rpmTypeNull        = 0
 	rpmTypeChar        = 1
@@ -31,6 +41,10 @@ const (
 	signatureTagMD5         = 1004
 	signatureTagPayloadSize = 1007
 	signatureTagSHA1        = 269
+	signatureTagDSA         = 267
+	signatureTagRSA         = 268
+	signatureTagPGP         = 1002
+	signatureTagGPG         = 1005
 
 	tagName              = 1000
 	tagVersion           = 1001
@@ -39,6 +53,7 @@ const (
 	tagSummary           = 1004
 	tagDescription       = 1005
 	tagBuildtime         = 1006
+	tagBuildHost         = 1007
 	tagSize              = 1009
 	tagOs                = 1021
 	tagArch              = 1022
@@ -48,7 +63,13 @@ const (
 	tagPayloadCompressor = 1125
 	tagPayloadFlags      = 1126
 	tagPrein             = 1023
+	tagPostin            = 1024
+	tagPreun             = 1025
+	tagPostun            = 1026
 	tagPreinProg         = 1085
+	tagPostinProg        = 1086
+	tagPreunProg         = 1087
+	tagPostunProg        = 1088
 	tagDirNames          = 1118
 	tagBaseNames         = 1117
 	tagDirIndexes        = 1116
@@ -68,6 +89,15 @@ const (
 	tagRequireFlags      = 1048
 	tagRequireName       = 1049
 	tagRequireVersion    = 1050
+	tagRecommendName     = 5046
+	tagRecommendVersion  = 5047
+	tagRecommendFlags    = 5048
+	tagSupplementName    = 5052
+	tagSupplementVersion = 5053
+	tagSupplementFlags   = 5054
+	tagEnhanceName       = 5055
+	tagEnhanceVersion    = 5056
+	tagEnhanceFlags      = 5057
 	tagPayloadDigest     = 5092
 	tagPayloadDigestAlgo = 5093
 
@@ -99,6 +129,7 @@ var (
 		"usr/share/tarantool":  struct{}{},
 		"usr/lib":              struct{}{},
 		"usr/lib/tmpfiles.d":   struct{}{},
+		"usr/lib/sysusers.d":   struct{}{},
 		"var":                  struct{}{},
 		"var/lib":              struct{}{},
 		"var/lib/tarantool":    struct{}{},
@@ -111,6 +142,13 @@ var (
 		"etc/systemd/system":   struct{}{},
 	}
 
+	// payload digest algorithms by names
+	payloadDigestAlgos = map[string]payloadDigestAlgo{
+		"sha1":   {ID: hashAlgoSHA1, GetFileDigest: common.FileSHA1Hex},
+		"sha256": {ID: hashAlgoSHA256, GetFileDigest: common.FileSHA256Hex},
+		"sha512": {ID: hashAlgoSHA512, GetFileDigest: common.FileSHA512Hex},
+	}
+
 	boundariesByType = map[rpmValueType]int{
 		rpmTypeNull:        1,
 		rpmTypeBin:         1,
@@ -2,18 +2,220 @@ package rpm
 
 import (
 	"bufio"
-	"bytes"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
+	"path/filepath"
 	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
 
 	"github.com/tarantool/cartridge-cli/cli/context"
 )
 
+const (
+	cpioNewcMagic   = "070701"
+	cpioTrailerName = "TRAILER!!!"
+
+	// cpioBlockSize is the size of the block the archive is padded to
+	// (default `cpio -o` I/O block size)
+	cpioBlockSize = 512
+
+	// cpioNewcMaxFieldValue is the max value of the newc header field
+	// (8 hex digits)
+	cpioNewcMaxFieldValue = 1<<32 - 1
+)
+
+// cpioNewcEntry describes the newc CPIO archive entry
+type cpioNewcEntry struct {
+	Ino   int
+	Mode  uint32
+	Uid   uint32
+	Gid   uint32
+	Nlink int
+	Mtime int64
+	Name  string
+
+	DevMajor  uint32
+	DevMinor  uint32
+	RdevMajor uint32
+	RdevMinor uint32
+
+	Size int64
+	Data io.Reader
+}
+
+// cpioNewcEntryNormalizer modifies the entry before writing it to the archive.
+// It's used to make the archive independent on the build
+type cpioNewcEntryNormalizer func(index int, entry *cpioNewcEntry)
+
+func getCpioPadding(size int64, blockSize int64) []byte {
+	return make([]byte, (blockSize-size%blockSize)%blockSize)
+}
+
+// countingWriter counts bytes written to the archive to pad it to the block size
+type countingWriter struct {
+	w       io.Writer
+	written int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.written += int64(n)
+	return n, err
+}
+
+func writeCpioNewcEntry(w io.Writer, entry cpioNewcEntry) error {
+	if entry.Size > cpioNewcMaxFieldValue {
+		return fmt.Errorf("%s size is %d bytes, files of 4 GiB or more aren't supported by CPIO newc format",
+			entry.Name, entry.Size)
+	}
+
+	header := fmt.Sprintf("%s%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x",
+		cpioNewcMagic,
+		entry.Ino, entry.Mode, entry.Uid, entry.Gid, entry.Nlink, entry.Mtime, entry.Size,
+		entry.DevMajor, entry.DevMinor, entry.RdevMajor, entry.RdevMinor,
+		len(entry.Name)+1, 0,
+	)
+
+	// header with name and data are padded to the multiple of 4
+	headerWithName := append([]byte(header), entry.Name...)
+	headerWithName = append(headerWithName, 0)
+	headerWithName = append(headerWithName, getCpioPadding(int64(len(headerWithName)), 4)...)
+
+	if _, err := w.Write(headerWithName); err != nil {
+		return err
+	}
+
+	if entry.Data != nil {
+		if written, err := io.Copy(w, entry.Data); err != nil {
+			return err
+		} else if written != entry.Size {
+			return fmt.Errorf("%s size has changed while writing", entry.Name)
+		}
+	}
+
+	if _, err := w.Write(getCpioPadding(entry.Size, 4)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func writeCpioNewcFile(w io.Writer, entry cpioNewcEntry, filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	entry.Data = file
+
+	return writeCpioNewcEntry(w, entry)
+}
+
+// getCpioNewcEntry returns the entry filled with the file info
+// the same way as `cpio -o -H newc` does, except the inode number:
+// it's the entry index + 1, since the file system inode may not fit the header
+func getCpioNewcEntry(index int, relPath string, fileInfo os.FileInfo) (cpioNewcEntry, error) {
+	sysFileInfo, ok := fileInfo.Sys().(*syscall.Stat_t)
+	if !ok {
+		return cpioNewcEntry{}, fmt.Errorf("Failed to get %s file info", relPath)
+	}
+
+	entry := cpioNewcEntry{
+		Ino:   index + 1,
+		Mode:  uint32(sysFileInfo.Mode),
+		Uid:   sysFileInfo.Uid,
+		Gid:   sysFileInfo.Gid,
+		Nlink: int(sysFileInfo.Nlink),
+		Mtime: fileInfo.ModTime().Unix(),
+		Name:  relPath,
+
+		DevMajor:  unix.Major(uint64(sysFileInfo.Dev)),
+		DevMinor:  unix.Minor(uint64(sysFileInfo.Dev)),
+		RdevMajor: unix.Major(uint64(sysFileInfo.Rdev)),
+		RdevMinor: unix.Minor(uint64(sysFileInfo.Rdev)),
+	}
+
+	// hard links are written as separate files
+	// (cpio writes the data only for the last link)
+	if fileInfo.Mode().IsRegular() {
+		entry.Nlink = 1
+	}
+
+	return entry, nil
+}
+
+// writeCpioNewcArchive writes files to the CPIO archive in the newc format.
+// The result is equal to the `cpio -o -H newc` output for the same files list
+// with inodes renumbered in the files order.
+// If normalize is specified, it's called for each entry before writing
+func writeCpioNewcArchive(relPaths []string, srcDir string, w io.Writer, normalize cpioNewcEntryNormalizer) error {
+	archiveWriter := &countingWriter{w: w}
+
+	for i, relPath := range relPaths {
+		filePath := filepath.Join(srcDir, relPath)
+
+		fileInfo, err := os.Lstat(filePath)
+		if err != nil {
+			return err
+		}
+
+		entry, err := getCpioNewcEntry(i, relPath, fileInfo)
+		if err != nil {
+			return err
+		}
+
+		if normalize != nil {
+			normalize(i, &entry)
+		}
+
+		switch {
+		case fileInfo.IsDir():
+			err = writeCpioNewcEntry(archiveWriter, entry)
+		case fileInfo.Mode()&os.ModeSymlink != 0:
+			var target string
+			if target, err = os.Readlink(filePath); err != nil {
+				return fmt.Errorf("Failed to read symlink %s: %s", relPath, err)
+			}
+
+			entry.Size = int64(len(target))
+			entry.Data = strings.NewReader(target)
+			err = writeCpioNewcEntry(archiveWriter, entry)
+		case fileInfo.Mode().IsRegular():
+			entry.Size = fileInfo.Size()
+			err = writeCpioNewcFile(archiveWriter, entry, filePath)
+		default:
+			return fmt.Errorf("File %s has unsupported type", relPath)
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed to write %s: %s", relPath, err)
+		}
+	}
+
+	trailer := cpioNewcEntry{Nlink: 1, Name: cpioTrailerName}
+	if err := writeCpioNewcEntry(archiveWriter, trailer); err != nil {
+		return fmt.Errorf("Failed to write trailer: %s", err)
+	}
+
+	// archive is padded to the block size
+	if _, err := archiveWriter.Write(getCpioPadding(archiveWriter.written, cpioBlockSize)); err != nil {
+		return fmt.Errorf("Failed to write trailer: %s", err)
+	}
+
+	return nil
+}
+
+// packCpio writes files from ctx.Pack.PackageFilesDir to the CPIO archive.
+// In reproducible mode entries are normalized
 func packCpio(relPaths []string, resFileName string, ctx *context.Ctx) error {
-	filesBuffer := bytes.Buffer{}
-	filesBuffer.WriteString(strings.Join(relPaths, "\n"))
+	var normalize cpioNewcEntryNormalizer
+	if reproducibleTime, reproducible := getReproducibleTime(ctx); reproducible {
+		normalize = getReproducibleCpioNormalizer(reproducibleTime)
+	}
 
 	cpioFile, err := os.Create(resFileName)
 	if err != nil {
@@ -22,18 +224,13 @@ func packCpio(relPaths []string, resFileName string, ctx *context.Ctx) error {
 	defer cpioFile.Close()
 
 	cpioFileWriter := bufio.NewWriter(cpioFile)
-	defer cpioFileWriter.Flush()
 
-	var stderrBuf bytes.Buffer
-
-	cmd := exec.Command("cpio", "-o", "-H", "newc")
-	cmd.Stdin = &filesBuffer
-	cmd.Stdout = cpioFileWriter
-	cmd.Stderr = &stderrBuf
-	cmd.Dir = ctx.Pack.PackageFilesDir
+	if err := writeCpioNewcArchive(relPaths, ctx.Pack.PackageFilesDir, cpioFileWriter, normalize); err != nil {
+		return err
+	}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("Failed to run \n%s\n\nStderr: %s", cmd.String(), stderrBuf.String())
+	if err := cpioFileWriter.Flush(); err != nil {
+		return err
 	}
 
 	return nil
@@ -0,0 +1,136 @@
+package rpm
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func TestWriteCpioNewcArchive(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "cpio")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	relPaths := createReproducibleTestTree(t, tmpDir)
+
+	var buf bytes.Buffer
+	assert.Nil(writeCpioNewcArchive(relPaths, tmpDir, &buf, nil))
+	assert.Equal(0, buf.Len()%cpioBlockSize)
+
+	entries := parseCpioNewc(t, buf.Bytes())
+	assert.Len(entries, len(relPaths)+1)
+
+	for i, relPath := range relPaths {
+		fileInfo, err := os.Lstat(filepath.Join(tmpDir, relPath))
+		assert.Nil(err)
+
+		sysFileInfo := fileInfo.Sys().(*syscall.Stat_t)
+
+		assert.Equal(relPath, entries[i].Name)
+		assert.EqualValues(i+1, entries[i].Ino)
+		assert.EqualValues(sysFileInfo.Mode, entries[i].Mode)
+		assert.EqualValues(fileInfo.ModTime().Unix(), entries[i].Mtime)
+	}
+
+	assert.Equal("print('hello')\n", entries[1].Data)
+	assert.Equal("init.lua", entries[2].Data)
+
+	trailer := entries[len(entries)-1]
+	assert.Equal(cpioTrailerName, trailer.Name)
+	assert.EqualValues(0, trailer.Ino)
+	assert.EqualValues(0, trailer.Mode)
+
+	// unsupported file type
+	fifoPath := filepath.Join(tmpDir, "fifo")
+	assert.Nil(syscall.Mkfifo(fifoPath, 0644))
+
+	err = writeCpioNewcArchive([]string{"fifo"}, tmpDir, &buf, nil)
+	assert.EqualError(err, "File fifo has unsupported type")
+
+	// file doesn't exist
+	err = writeCpioNewcArchive([]string{"unknown"}, tmpDir, &buf, nil)
+	assert.NotNil(err)
+	assert.True(strings.Contains(err.Error(), "no such file or directory"), err.Error())
+}
+
+func TestWriteCpioNewcEntryTooBig(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+
+	entry := cpioNewcEntry{Ino: 1, Nlink: 1, Name: "big", Size: cpioNewcMaxFieldValue + 1}
+	err := writeCpioNewcEntry(&buf, entry)
+	assert.EqualError(err, "big size is 4294967296 bytes, files of 4 GiB or more aren't supported by CPIO newc format")
+	assert.Equal(0, buf.Len())
+
+	entry = cpioNewcEntry{Ino: 1, Nlink: 1, Name: "empty"}
+	assert.Nil(writeCpioNewcEntry(&buf, entry))
+}
+
+func TestPackCpioMatchesCpioTool(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("cpio"); err != nil {
+		t.Skip("cpio isn't installed")
+	}
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "cpio")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	var ctx context.Ctx
+	ctx.Pack.PackageFilesDir = filepath.Join(tmpDir, "package-files")
+	relPaths := createReproducibleTestTree(t, ctx.Pack.PackageFilesDir)
+
+	cpioPath := filepath.Join(tmpDir, "cpio")
+	assert.Nil(packCpio(relPaths, cpioPath, &ctx))
+
+	cpioContent, err := ioutil.ReadFile(cpioPath)
+	assert.Nil(err)
+
+	var expCpioContent bytes.Buffer
+	cmd := exec.Command("cpio", "-o", "-H", "newc", "--quiet")
+	cmd.Stdin = strings.NewReader(strings.Join(relPaths, "\n"))
+	cmd.Stdout = &expCpioContent
+	cmd.Dir = ctx.Pack.PackageFilesDir
+	assert.Nil(cmd.Run())
+
+	expCpioContentRenumbered := renumberCpioNewcInodes(t, expCpioContent.Bytes())
+	assert.True(bytes.Equal(expCpioContentRenumbered, cpioContent), "CPIO archives differ")
+}
+
+// renumberCpioNewcInodes sets entries inodes to the entry index + 1
+// (trailer inode is kept)
+func renumberCpioNewcInodes(t *testing.T, data []byte) []byte {
+	res := append([]byte{}, data...)
+
+	offset := 0
+	for i, entry := range parseCpioNewc(t, data) {
+		if entry.Name != cpioTrailerName {
+			copy(res[offset+6:offset+14], fmt.Sprintf("%08x", i+1))
+		}
+
+		nameEnd := (offset + 110 + len(entry.Name) + 1 + 3) &^ 3
+		offset = (nameEnd + len(entry.Data) + 3) &^ 3
+	}
+
+	return res
+}
@@ -0,0 +1,200 @@
+package rpm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apex/log"
+	goVersion "github.com/hashicorp/go-version"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+	"github.com/tarantool/cartridge-cli/cli/context"
+	"github.com/tarantool/cartridge-cli/cli/project"
+)
+
+const (
+	// weak dependencies are supported since RPM 4.12
+	minWeakDepsRpmVersion = "4.12"
+
+	tarantoolDepName = "tarantool"
+)
+
+var (
+	senseByOperator = map[string]int32{
+		"<":  rpmSenseLess,
+		">":  rpmSenseGreater,
+		"=":  rpmSenseEqual,
+		"<=": rpmSenseLess | rpmSenseEqual,
+		">=": rpmSenseGreater | rpmSenseEqual,
+	}
+)
+
+type depType struct {
+	Name    string
+	Version string
+	Flags   int32
+}
+
+type depsType []depType
+
+type weakDepsTagsType struct {
+	NameTag    int
+	VersionTag int
+	FlagsTag   int
+
+	Deps []string
+}
+
+// parseDependency parses dependency string like
+// "name", "name >= 1.2.3" or "name<2"
+func parseDependency(depStr string) (depType, error) {
+	projectDep, err := project.ParseDependency(depStr)
+	if err != nil {
+		return depType{}, err
+	}
+
+	return getDepType(projectDep), nil
+}
+
+func getDepType(projectDep project.Dependency) depType {
+	dep := depType{
+		Name:    projectDep.Name,
+		Version: projectDep.Version,
+	}
+
+	if projectDep.Operator != "" {
+		dep.Flags = senseByOperator[projectDep.Operator]
+	}
+
+	return dep
+}
+
+func parseDependencies(depsStrs []string) (depsType, error) {
+	deps := make(depsType, len(depsStrs))
+
+	for i, depStr := range depsStrs {
+		dep, err := parseDependency(depStr)
+		if err != nil {
+			return nil, err
+		}
+
+		deps[i] = dep
+	}
+
+	return deps, nil
+}
+
+func (deps depsType) getTags(nameTag, versionTag, flagsTag int) []rpmTagType {
+	names := make([]string, len(deps))
+	versions := make([]string, len(deps))
+	flags := make([]int32, len(deps))
+
+	for i, dep := range deps {
+		names[i] = dep.Name
+		versions[i] = dep.Version
+		flags[i] = dep.Flags
+	}
+
+	return []rpmTagType{
+		{ID: nameTag, Type: rpmTypeStringArray, Value: names},
+		{ID: versionTag, Type: rpmTypeStringArray, Value: versions},
+		{ID: flagsTag, Type: rpmTypeInt32, Value: flags},
+	}
+}
+
+// genRequiresTags generates Requires tags.
+// Tarantool dependency is added if it isn't specified by --deps or --deps-file
+func genRequiresTags(ctx *context.Ctx) ([]rpmTagType, error) {
+	userDeps, err := project.GetUserDependencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var deps depsType
+
+	if !ctx.Tarantool.TarantoolIsEnterprise && !project.DependsOn(userDeps, tarantoolDepName) {
+		minVersion := strings.SplitN(ctx.Tarantool.TarantoolVersion, "-", 2)[0]
+		maxVersion, err := common.GetNextMajorVersion(minVersion)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get next major version of Tarantool %s", err)
+		}
+
+		deps = append(deps,
+			depType{Name: tarantoolDepName, Version: minVersion, Flags: rpmSenseGreater | rpmSenseEqual},
+			depType{Name: tarantoolDepName, Version: maxVersion, Flags: rpmSenseLess},
+		)
+	}
+
+	for _, userDep := range userDeps {
+		deps = append(deps, getDepType(userDep))
+	}
+
+	if len(deps) == 0 {
+		return nil, nil
+	}
+
+	return deps.getTags(tagRequireName, tagRequireVersion, tagRequireFlags), nil
+}
+
+func weakDepsAreSpecified(ctx *context.Ctx) bool {
+	return len(ctx.Pack.RpmRecommends) > 0 ||
+		len(ctx.Pack.RpmSupplements) > 0 ||
+		len(ctx.Pack.RpmEnhances) > 0
+}
+
+// genWeakDepsTags generates Recommends, Supplements and Enhances tags
+func genWeakDepsTags(ctx *context.Ctx) ([]rpmTagType, error) {
+	var tags []rpmTagType
+
+	weakDepsTags := []weakDepsTagsType{
+		{tagRecommendName, tagRecommendVersion, tagRecommendFlags, ctx.Pack.RpmRecommends},
+		{tagSupplementName, tagSupplementVersion, tagSupplementFlags, ctx.Pack.RpmSupplements},
+		{tagEnhanceName, tagEnhanceVersion, tagEnhanceFlags, ctx.Pack.RpmEnhances},
+	}
+
+	for _, weakDepsTag := range weakDepsTags {
+		if len(weakDepsTag.Deps) == 0 {
+			continue
+		}
+
+		deps, err := parseDependencies(weakDepsTag.Deps)
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, deps.getTags(
+			weakDepsTag.NameTag, weakDepsTag.VersionTag, weakDepsTag.FlagsTag,
+		)...)
+	}
+
+	return tags, nil
+}
+
+// CheckMinVersion checks that ctx.Pack.RpmMinVersion is a valid version
+// and that the specified weak dependencies are supported by it.
+// Headers are written by cartridge, so the build host RPM version doesn't matter
+func CheckMinVersion(ctx *context.Ctx) error {
+	minVersion, err := goVersion.NewVersion(ctx.Pack.RpmMinVersion)
+	if err != nil {
+		return fmt.Errorf("Invalid --rpm-min-version value %q: %s", ctx.Pack.RpmMinVersion, err)
+	}
+
+	if weakDepsAreSpecified(ctx) {
+		minWeakDepsVersion := goVersion.Must(goVersion.NewVersion(minWeakDepsRpmVersion))
+		if minVersion.LessThan(minWeakDepsVersion) {
+			return fmt.Errorf("Weak dependencies (--recommends, --supplements and --enhances) "+
+				"require RPM >= %s, but --rpm-min-version is %s", minWeakDepsRpmVersion, ctx.Pack.RpmMinVersion)
+		}
+	}
+
+	return nil
+}
+
+// warnWeakDepsCompatibility warns that weak dependencies
+// are ignored by old RPM versions on the target system.
+// It's used if the target RPM version isn't specified by --rpm-min-version
+func warnWeakDepsCompatibility() {
+	log.Warnf("Weak dependencies are supported by RPM >= %s. They are ignored "+
+		"on the target systems with older RPM versions. Use --rpm-min-version "+
+		"to fail on unsupported target RPM version", minWeakDepsRpmVersion)
+}
@@ -0,0 +1,210 @@
+package rpm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func TestParseDependency(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var err error
+	var dep depType
+
+	dep, err = parseDependency("tarantool-metrics")
+	assert.Nil(err)
+	assert.Equal(depType{Name: "tarantool-metrics"}, dep)
+
+	dep, err = parseDependency("tarantool-metrics >= 0.6.0")
+	assert.Nil(err)
+	assert.Equal(depType{
+		Name:    "tarantool-metrics",
+		Version: "0.6.0",
+		Flags:   rpmSenseGreater | rpmSenseEqual,
+	}, dep)
+
+	dep, err = parseDependency("tarantool<2")
+	assert.Nil(err)
+	assert.Equal(depType{Name: "tarantool", Version: "2", Flags: rpmSenseLess}, dep)
+
+	dep, err = parseDependency(" tarantool = 2.5.1 ")
+	assert.Nil(err)
+	assert.Equal(depType{Name: "tarantool", Version: "2.5.1", Flags: rpmSenseEqual}, dep)
+
+	dep, err = parseDependency("tarantool <= 2.5")
+	assert.Nil(err)
+	assert.Equal(depType{Name: "tarantool", Version: "2.5", Flags: rpmSenseLess | rpmSenseEqual}, dep)
+
+	dep, err = parseDependency("tarantool > 1.10")
+	assert.Nil(err)
+	assert.Equal(depType{Name: "tarantool", Version: "1.10", Flags: rpmSenseGreater}, dep)
+
+	// bad formats
+	_, err = parseDependency("")
+	assert.NotNil(err)
+
+	_, err = parseDependency("tarantool >=")
+	assert.NotNil(err)
+
+	_, err = parseDependency("tarantool 2.5")
+	assert.NotNil(err)
+}
+
+func TestGenWeakDepsTags(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var err error
+	var tags []rpmTagType
+
+	ctx := &context.Ctx{}
+
+	// no weak deps
+	tags, err = genWeakDepsTags(ctx)
+	assert.Nil(err)
+	assert.Len(tags, 0)
+
+	// all weak deps
+	ctx.Pack.RpmRecommends = []string{"tarantool-metrics >= 0.6.0", "tarantool-http"}
+	ctx.Pack.RpmSupplements = []string{"tarantool < 3"}
+	ctx.Pack.RpmEnhances = []string{"tarantool = 2.5"}
+
+	tags, err = genWeakDepsTags(ctx)
+	assert.Nil(err)
+	assert.Equal([]rpmTagType{
+		{ID: tagRecommendName, Type: rpmTypeStringArray,
+			Value: []string{"tarantool-metrics", "tarantool-http"}},
+		{ID: tagRecommendVersion, Type: rpmTypeStringArray,
+			Value: []string{"0.6.0", ""}},
+		{ID: tagRecommendFlags, Type: rpmTypeInt32,
+			Value: []int32{rpmSenseGreater | rpmSenseEqual, 0}},
+
+		{ID: tagSupplementName, Type: rpmTypeStringArray, Value: []string{"tarantool"}},
+		{ID: tagSupplementVersion, Type: rpmTypeStringArray, Value: []string{"3"}},
+		{ID: tagSupplementFlags, Type: rpmTypeInt32, Value: []int32{rpmSenseLess}},
+
+		{ID: tagEnhanceName, Type: rpmTypeStringArray, Value: []string{"tarantool"}},
+		{ID: tagEnhanceVersion, Type: rpmTypeStringArray, Value: []string{"2.5"}},
+		{ID: tagEnhanceFlags, Type: rpmTypeInt32, Value: []int32{rpmSenseEqual}},
+	}, tags)
+
+	// check encoding
+	packed, err := packTag(tags[0])
+	assert.Nil(err)
+	assert.Equal(2, packed.Count)
+	assert.Equal("746172616e746f6f6c2d6d65747269637300746172616e746f6f6c2d6874747000", hex(packed.Data))
+
+	packed, err = packTag(tags[2])
+	assert.Nil(err)
+	assert.Equal(2, packed.Count)
+	assert.Equal("0000000c00000000", hex(packed.Data))
+
+	packed, err = packTag(tags[5])
+	assert.Nil(err)
+	assert.Equal(1, packed.Count)
+	assert.Equal("00000002", hex(packed.Data))
+
+	packed, err = packTag(tags[8])
+	assert.Nil(err)
+	assert.Equal(1, packed.Count)
+	assert.Equal("00000008", hex(packed.Data))
+
+	// bad dependency
+	ctx.Pack.RpmEnhances = []string{"tarantool >="}
+
+	_, err = genWeakDepsTags(ctx)
+	assert.NotNil(err)
+}
+
+func TestCheckMinVersion(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	ctx := &context.Ctx{}
+
+	// no weak deps
+	for _, version := range []string{"4.11", "4.12", "4.16.1"} {
+		ctx.Pack.RpmMinVersion = version
+		assert.Nil(CheckMinVersion(ctx), version)
+	}
+
+	// weak deps
+	ctx.Pack.RpmRecommends = []string{"tarantool-metrics"}
+
+	for _, version := range []string{"4.12", "4.12.0", "4.16.1", "5"} {
+		ctx.Pack.RpmMinVersion = version
+		assert.Nil(CheckMinVersion(ctx), version)
+	}
+
+	ctx.Pack.RpmMinVersion = "4.11.3"
+	assert.EqualError(CheckMinVersion(ctx), "Weak dependencies (--recommends, --supplements and --enhances) "+
+		"require RPM >= 4.12, but --rpm-min-version is 4.11.3")
+
+	// bad version
+	ctx.Pack.RpmMinVersion = "four"
+	assert.NotNil(CheckMinVersion(ctx))
+}
+
+func TestGenRequiresTags(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	ctx := &context.Ctx{}
+	ctx.Tarantool.TarantoolVersion = "2.4.2-1-g7f8c5e6"
+
+	// auto-detected Tarantool dependency
+	tags, err := genRequiresTags(ctx)
+	assert.Nil(err)
+	assert.Equal([]rpmTagType{
+		{ID: tagRequireName, Type: rpmTypeStringArray, Value: []string{"tarantool", "tarantool"}},
+		{ID: tagRequireVersion, Type: rpmTypeStringArray, Value: []string{"2.4.2", "3"}},
+		{ID: tagRequireFlags, Type: rpmTypeInt32,
+			Value: []int32{rpmSenseGreater | rpmSenseEqual, rpmSenseLess}},
+	}, tags)
+
+	// user dependencies are added
+	ctx.Pack.Deps = []string{"logrotate", "openssl >= 1.1"}
+
+	tags, err = genRequiresTags(ctx)
+	assert.Nil(err)
+	assert.Equal([]rpmTagType{
+		{ID: tagRequireName, Type: rpmTypeStringArray,
+			Value: []string{"tarantool", "tarantool", "logrotate", "openssl"}},
+		{ID: tagRequireVersion, Type: rpmTypeStringArray, Value: []string{"2.4.2", "3", "", "1.1"}},
+		{ID: tagRequireFlags, Type: rpmTypeInt32,
+			Value: []int32{rpmSenseGreater | rpmSenseEqual, rpmSenseLess, 0, rpmSenseGreater | rpmSenseEqual}},
+	}, tags)
+
+	// Tarantool dependency is overridden
+	ctx.Pack.Deps = []string{"tarantool >= 2.5"}
+
+	tags, err = genRequiresTags(ctx)
+	assert.Nil(err)
+	assert.Equal([]rpmTagType{
+		{ID: tagRequireName, Type: rpmTypeStringArray, Value: []string{"tarantool"}},
+		{ID: tagRequireVersion, Type: rpmTypeStringArray, Value: []string{"2.5"}},
+		{ID: tagRequireFlags, Type: rpmTypeInt32, Value: []int32{rpmSenseGreater | rpmSenseEqual}},
+	}, tags)
+
+	// no dependencies for Tarantool Enterprise
+	ctx.Pack.Deps = nil
+	ctx.Tarantool.TarantoolIsEnterprise = true
+
+	tags, err = genRequiresTags(ctx)
+	assert.Nil(err)
+	assert.Len(tags, 0)
+
+	// conflicting dependencies
+	ctx.Pack.Deps = []string{"tarantool >= 2.5", "tarantool >= 2.6"}
+
+	_, err = genRequiresTags(ctx)
+	assert.EqualError(err, `Dependencies "tarantool >= 2.5" and "tarantool >= 2.6" conflict`)
+}
@@ -0,0 +1,67 @@
+package rpm
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+// getDigestWorkersNum returns the number of workers that compute files digests.
+// Defaults to GOMAXPROCS
+func getDigestWorkersNum(ctx *context.Ctx) int {
+	if ctx.Pack.RpmDigestWorkers > 0 {
+		return ctx.Pack.RpmDigestWorkers
+	}
+
+	return runtime.GOMAXPROCS(0)
+}
+
+// getFilesDigests computes MD5 digests of the specified files using workersNum workers.
+// Digests are returned in the order of the files paths,
+// empty digest is returned for the empty path
+func getFilesDigests(filePaths []string, workersNum int) ([]string, error) {
+	if workersNum < 1 {
+		workersNum = 1
+	}
+
+	digests := make([]string, len(filePaths))
+	errs := make([]error, len(filePaths))
+
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workersNum; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				if filePaths[i] == "" {
+					digests[i] = emptyDigest
+					continue
+				}
+
+				digests[i], errs[i] = common.FileMD5Hex(filePaths[i])
+			}
+		}()
+	}
+
+	for i := range filePaths {
+		indexes <- i
+	}
+	close(indexes)
+
+	wg.Wait()
+
+	// the first error is returned to keep it the same as on serial computation
+	for _, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get file MD5 hex: %s", err)
+		}
+	}
+
+	return digests, nil
+}
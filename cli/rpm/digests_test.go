@@ -0,0 +1,102 @@
+package rpm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+)
+
+func writeDigestsTestFiles(dir string, filesNum int, fileSize int) ([]string, error) {
+	filePaths := make([]string, filesNum)
+	content := make([]byte, fileSize)
+
+	for i := range filePaths {
+		filePaths[i] = filepath.Join(dir, fmt.Sprintf("file-%d.lua", i))
+
+		content[0] = byte(i)
+		if err := ioutil.WriteFile(filePaths[i], content, 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	return filePaths, nil
+}
+
+func TestGetFilesDigests(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "digests")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	filePaths, err := writeDigestsTestFiles(tmpDir, 100, 1024)
+	assert.Nil(err)
+
+	// directories have empty paths
+	filePaths[10] = ""
+	filePaths[42] = ""
+
+	var expDigests []string
+	for _, filePath := range filePaths {
+		if filePath == "" {
+			expDigests = append(expDigests, emptyDigest)
+			continue
+		}
+
+		digest, err := common.FileMD5Hex(filePath)
+		assert.Nil(err)
+		expDigests = append(expDigests, digest)
+	}
+
+	// digests order doesn't depend on workers number
+	for _, workersNum := range []int{0, 1, 3, 16, 200} {
+		digests, err := getFilesDigests(filePaths, workersNum)
+		assert.Nil(err)
+		assert.Equal(expDigests, digests, workersNum)
+	}
+
+	// no files
+	digests, err := getFilesDigests(nil, 4)
+	assert.Nil(err)
+	assert.Len(digests, 0)
+
+	// missing file
+	filePaths[50] = filepath.Join(tmpDir, "missing.lua")
+	_, err = getFilesDigests(filePaths, 4)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Failed to get file MD5 hex")
+	assert.Contains(err.Error(), "missing.lua")
+}
+
+func BenchmarkGetFilesDigests(b *testing.B) {
+	tmpDir, err := ioutil.TempDir("", "digests")
+	if err != nil {
+		b.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	filePaths, err := writeDigestsTestFiles(tmpDir, 5000, 64*1024)
+	if err != nil {
+		b.Fatalf("Failed to create files: %s", err)
+	}
+
+	for _, workersNum := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers-%d", workersNum), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := getFilesDigests(filePaths, workersNum); err != nil {
+					b.Fatalf("Failed to get files digests: %s", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package rpm
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/apex/log"
+)
+
+// gpgKey describes the key used to sign the package.
+// HomeDir is set if the key is imported from the file
+// to the temporary keyring.
+// Algo is the OpenPGP public key algorithm ID (RFC 4880, 9.1)
+type gpgKey struct {
+	ID      string
+	HomeDir string
+	Algo    int
+}
+
+// isRSA checks if the key algorithm is RSA.
+// RPM stores RSA signatures and DSA (ECDSA, EdDSA) ones in different tags
+func (key *gpgKey) isRSA() bool {
+	return isRSAKeyAlgo(key.Algo)
+}
+
+func isRSAKeyAlgo(algo int) bool {
+	// RSA, RSA Encrypt-Only, RSA Sign-Only
+	return algo >= 1 && algo <= 3
+}
+
+func runGPG(homeDir string, args ...string) (string, error) {
+	if homeDir != "" {
+		args = append([]string{"--homedir", homeDir}, args...)
+	}
+
+	return runWithStderr(exec.Command("gpg", append([]string{"--batch"}, args...)...))
+}
+
+// runWithStderr runs the command and returns its stdout.
+// The command stderr is returned in the error
+func runWithStderr(cmd *exec.Cmd) (string, error) {
+	var stdoutBuf bytes.Buffer
+	var stderrBuf bytes.Buffer
+
+	cmd.Stdout = &stdoutBuf
+	cmd.Stderr = &stderrBuf
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("Failed to run \n%s\n\n%s\nStderr: %s",
+			cmd.String(), err, strings.TrimSpace(stderrBuf.String()))
+	}
+
+	return stdoutBuf.String(), nil
+}
+
+// parseSecretKey returns the fingerprint and the public key algorithm
+// of the first secret key from the `gpg --with-colons --list-secret-keys` output
+func parseSecretKey(listOutput string) (string, int, error) {
+	secFound := false
+	algo := 0
+
+	for _, line := range strings.Split(listOutput, "\n") {
+		fields := strings.Split(line, ":")
+
+		switch fields[0] {
+		case "sec":
+			if secFound {
+				continue
+			}
+
+			secFound = true
+
+			if len(fields) > 3 {
+				var err error
+				if algo, err = strconv.Atoi(fields[3]); err != nil {
+					return "", 0, fmt.Errorf("Failed to parse key algorithm %q", fields[3])
+				}
+			}
+		case "fpr":
+			if secFound && len(fields) > 9 && fields[9] != "" {
+				return fields[9], algo, nil
+			}
+		}
+	}
+
+	return "", 0, fmt.Errorf("No secret key found")
+}
+
+// getGPGKey returns the key specified by ID (it should be in the user keyring)
+// or by the path to the exported secret key file.
+// The key file is imported to the temporary keyring that should be removed by calling Cleanup
+func getGPGKey(signKey string) (*gpgKey, error) {
+	if fileInfo, err := os.Stat(signKey); err != nil || fileInfo.IsDir() {
+		listOutput, err := runGPG("", "--with-colons", "--list-secret-keys", signKey)
+		if err != nil {
+			return nil, fmt.Errorf("GPG secret key %q isn't found: %s", signKey, err)
+		}
+
+		key := &gpgKey{ID: signKey}
+		if _, key.Algo, err = parseSecretKey(listOutput); err != nil {
+			return nil, fmt.Errorf("Failed to get GPG secret key %q: %s", signKey, err)
+		}
+
+		return key, nil
+	}
+
+	homeDir, err := ioutil.TempDir("", "gnupg")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create temporary GPG home directory: %s", err)
+	}
+
+	key := &gpgKey{HomeDir: homeDir}
+
+	if _, err := runGPG(homeDir, "--import", signKey); err != nil {
+		key.Cleanup()
+		return nil, fmt.Errorf("Failed to import GPG key from %s: %s", signKey, err)
+	}
+
+	listOutput, err := runGPG(homeDir, "--with-colons", "--list-secret-keys")
+	if err != nil {
+		key.Cleanup()
+		return nil, fmt.Errorf("Failed to list imported GPG keys: %s", err)
+	}
+
+	if key.ID, key.Algo, err = parseSecretKey(listOutput); err != nil {
+		key.Cleanup()
+		return nil, fmt.Errorf("File %s doesn't contain GPG secret key", signKey)
+	}
+
+	log.Debugf("GPG key %s is imported from %s", key.ID, signKey)
+
+	return key, nil
+}
+
+// Cleanup removes the temporary keyring
+func (key *gpgKey) Cleanup() {
+	if key.HomeDir == "" {
+		return
+	}
+
+	// stop the agent started for the temporary keyring
+	exec.Command("gpgconf", "--homedir", key.HomeDir, "--kill", "gpg-agent").Run()
+
+	if err := os.RemoveAll(key.HomeDir); err != nil {
+		log.Warnf("Failed to remove temporary GPG home directory %s: %s", key.HomeDir, err)
+	}
+}
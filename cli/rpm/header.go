@@ -4,12 +4,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"syscall"
+	"time"
 
-	"github.com/tarantool/cartridge-cli/cli/common"
 	"github.com/tarantool/cartridge-cli/cli/context"
-	"github.com/tarantool/cartridge-cli/cli/project"
 )
 
 type filesInfoType struct {
@@ -34,8 +34,12 @@ func genRpmHeader(relPaths []string, cpioPath, compresedCpioPath string, ctx *co
 	rmpHeader := rpmTagSetType{}
 
 	// compute payload digest
-	payloadDigestAlgo := hashAlgoSHA256
-	payloadDigest, err := common.FileSHA256Hex(compresedCpioPath)
+	payloadDigestAlgo, err := getPayloadDigestAlgo(ctx.Pack.RpmPayloadDigestAlgo)
+	if err != nil {
+		return nil, err
+	}
+
+	payloadDigest, err := payloadDigestAlgo.GetFileDigest(compresedCpioPath)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to get payload digest: %s", err)
 	}
@@ -47,11 +51,25 @@ func genRpmHeader(relPaths []string, cpioPath, compresedCpioPath string, ctx *co
 	payloadSize := cpioFileInfo.Size()
 
 	// gen fileinfo
-	filesInfo, err := getFilesInfo(relPaths, ctx.Pack.PackageFilesDir)
+	filesInfo, err := getFilesInfo(
+		relPaths, ctx.Pack.PackageFilesDir, ctx.Pack.RpmRelocateDocsDir, getDigestWorkersNum(ctx),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to get files info: %s", err)
 	}
 
+	// install scripts
+	preInstScript, scriptsTags, err := genScriptsTags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gen install scripts tags: %s", err)
+	}
+
+	buildTime := time.Now()
+	if reproducibleTime, reproducible := getReproducibleTime(ctx); reproducible {
+		buildTime = reproducibleTime
+		normalizeFilesInfo(&filesInfo, reproducibleTime)
+	}
+
 	rmpHeader.addTags([]rpmTagType{
 		{ID: tagName, Type: rpmTypeString, Value: ctx.Project.Name},
 		{ID: tagVersion, Type: rpmTypeString, Value: ctx.Pack.Version},
@@ -61,6 +79,8 @@ func genRpmHeader(relPaths []string, cpioPath, compresedCpioPath string, ctx *co
 
 		{ID: tagLicense, Type: rpmTypeString, Value: "N/A"},
 		{ID: tagGroup, Type: rpmTypeString, Value: "None"},
+		{ID: tagBuildtime, Type: rpmTypeInt32, Value: []int32{int32(buildTime.Unix())}},
+		{ID: tagBuildHost, Type: rpmTypeString, Value: getBuildHost(ctx)},
 		{ID: tagOs, Type: rpmTypeString, Value: "linux"},
 		{ID: tagArch, Type: rpmTypeString, Value: "x86_64"},
 
@@ -68,7 +88,7 @@ func genRpmHeader(relPaths []string, cpioPath, compresedCpioPath string, ctx *co
 		{ID: tagPayloadCompressor, Type: rpmTypeString, Value: "gzip"},
 		{ID: tagPayloadFlags, Type: rpmTypeString, Value: "5"},
 
-		{ID: tagPrein, Type: rpmTypeString, Value: project.PreInstScriptContent},
+		{ID: tagPrein, Type: rpmTypeString, Value: preInstScript},
 		{ID: tagPreinProg, Type: rpmTypeString, Value: "/bin/sh"},
 
 		{ID: tagDirNames, Type: rpmTypeStringArray, Value: filesInfo.DirNames},
@@ -90,37 +110,88 @@ func genRpmHeader(relPaths []string, cpioPath, compresedCpioPath string, ctx *co
 
 		{ID: tagSize, Type: rpmTypeInt32, Value: []int32{int32(payloadSize)}},
 		{ID: tagPayloadDigest, Type: rpmTypeStringArray, Value: []string{payloadDigest}},
-		{ID: tagPayloadDigestAlgo, Type: rpmTypeInt32, Value: []int32{int32(payloadDigestAlgo)}},
+		{ID: tagPayloadDigestAlgo, Type: rpmTypeInt32, Value: []int32{int32(payloadDigestAlgo.ID)}},
 	}...)
 
-	if !ctx.Tarantool.TarantoolIsEnterprise {
-		// add Tarantool dependency
-		tarantoolDepName := "tarantool"
-		flagGreaterOrEqual := int32(rpmSenseGreater | rpmSenseEqual)
+	requiresTags, err := genRequiresTags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gen dependencies tags: %s", err)
+	}
 
-		minVersion := strings.SplitN(ctx.Tarantool.TarantoolVersion, "-", 2)[0]
-		maxVersion, err := common.GetNextMajorVersion(minVersion)
+	rmpHeader.addTags(requiresTags...)
+
+	rmpHeader.addTags(scriptsTags...)
+
+	if weakDepsAreSpecified(ctx) {
+		weakDepsTags, err := genWeakDepsTags(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("Failed to get next major version of Tarantool %s", err)
+			return nil, fmt.Errorf("Failed to gen weak dependencies tags: %s", err)
 		}
 
-		rmpHeader.addTags([]rpmTagType{
-			{ID: tagRequireName, Type: rpmTypeStringArray,
-				Value: []string{tarantoolDepName, tarantoolDepName}},
-			{ID: tagRequireFlags, Type: rpmTypeInt32,
-				Value: []int32{flagGreaterOrEqual, int32(rpmSenseLess)}},
-			{ID: tagRequireVersion, Type: rpmTypeStringArray,
-				Value: []string{minVersion, maxVersion}},
-		}...)
+		rmpHeader.addTags(weakDepsTags...)
 	}
 
 	return rmpHeader, nil
 }
 
-func getFilesInfo(relPaths []string, dirPath string) (filesInfoType, error) {
+// payloadDigestAlgo describes the RPM payload digest algorithm
+type payloadDigestAlgo struct {
+	ID            int
+	GetFileDigest func(path string) (string, error)
+}
+
+// CheckPayloadDigestAlgo checks that payload digest algorithm is supported
+func CheckPayloadDigestAlgo(algoName string) error {
+	_, err := getPayloadDigestAlgo(algoName)
+	return err
+}
+
+func getPayloadDigestAlgo(algoName string) (*payloadDigestAlgo, error) {
+	if algoName == "" {
+		algoName = defaultPayloadDigestAlgo
+	}
+
+	algo, found := payloadDigestAlgos[strings.ToLower(algoName)]
+	if !found {
+		return nil, fmt.Errorf("Unsupported payload digest algorithm %s. Supported algorithms are: %s",
+			algoName, strings.Join(getPayloadDigestAlgosNames(), ", "))
+	}
+
+	return &algo, nil
+}
+
+func getPayloadDigestAlgosNames() []string {
+	algosNames := make([]string, 0, len(payloadDigestAlgos))
+	for algoName := range payloadDigestAlgos {
+		algosNames = append(algosNames, algoName)
+	}
+
+	sort.Strings(algosNames)
+
+	return algosNames
+}
+
+// getBuildHost returns the value of the BUILDHOST tag.
+// The real hostname isn't used to avoid leaking it to the package
+func getBuildHost(ctx *context.Ctx) string {
+	if ctx.Pack.RpmBuildHost != "" {
+		return ctx.Pack.RpmBuildHost
+	}
+
+	return defaultBuildHost
+}
+
+// getFilesInfo collects files info for the RPM header.
+// Regular files placed in docsDir (if specified) are marked with doc flag.
+// Files digests are computed by digestWorkersNum workers
+func getFilesInfo(relPaths []string, dirPath string, docsDir string, digestWorkersNum int) (filesInfoType, error) {
 	filesInfo := filesInfoType{}
+	docsRelDir := strings.TrimPrefix(filepath.Clean(docsDir), "/")
+
+	// paths of the files to compute digests, empty for non-regular files
+	digestFilePaths := make([]string, len(relPaths))
 
-	for _, relPath := range relPaths {
+	for i, relPath := range relPaths {
 		fullFilePath := filepath.Join(dirPath, relPath)
 		fileInfo, err := os.Stat(fullFilePath)
 		if err != nil {
@@ -128,17 +199,15 @@ func getFilesInfo(relPaths []string, dirPath string) (filesInfoType, error) {
 		}
 
 		if fileInfo.Mode().IsRegular() {
-			filesInfo.FileFlags = append(filesInfo.FileFlags, fileFlag) // XXX
-
-			fileDigest, err := common.FileMD5Hex(fullFilePath)
-			if err != nil {
-				return filesInfo, fmt.Errorf("Failed to get file MD5 hex: %s", err)
+			flags := fileFlag // XXX
+			if docsDir != "" && isInDir(relPath, docsRelDir) {
+				flags |= docFileFlag
 			}
 
-			filesInfo.FileDigests = append(filesInfo.FileDigests, fileDigest)
+			filesInfo.FileFlags = append(filesInfo.FileFlags, int32(flags))
+			digestFilePaths[i] = fullFilePath
 		} else {
 			filesInfo.FileFlags = append(filesInfo.FileFlags, dirFlag) // XXX
-			filesInfo.FileDigests = append(filesInfo.FileDigests, emptyDigest)
 		}
 
 		fileDir := filepath.Dir(relPath)
@@ -161,14 +230,25 @@ func getFilesInfo(relPaths []string, dirPath string) (filesInfoType, error) {
 
 		filesInfo.FileSizes = append(filesInfo.FileSizes, int32(sysFileInfo.Size))
 		filesInfo.FileModes = append(filesInfo.FileModes, int16(sysFileInfo.Mode))
-		filesInfo.FileInodes = append(filesInfo.FileInodes, int32(sysFileInfo.Ino))
+		// inodes are renumbered the same way as in the CPIO archive
+		filesInfo.FileInodes = append(filesInfo.FileInodes, int32(i+1))
 		filesInfo.FileDevices = append(filesInfo.FileDevices, int32(sysFileInfo.Dev))
 		filesInfo.FileRdevs = append(filesInfo.FileRdevs, int16(sysFileInfo.Rdev))
 	}
 
+	fileDigests, err := getFilesDigests(digestFilePaths, digestWorkersNum)
+	if err != nil {
+		return filesInfo, err
+	}
+	filesInfo.FileDigests = fileDigests
+
 	return filesInfo, nil
 }
 
+func isInDir(path string, dir string) bool {
+	return strings.HasPrefix(filepath.Clean(path), dir+string(filepath.Separator))
+}
+
 func addDirAndGetIndex(dirNames *[]string, fileDir string) int {
 	for i, dirName := range *dirNames {
 		if dirName == fileDir {
@@ -0,0 +1,176 @@
+package rpm
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func getTagValue(header rpmTagSetType, tagID int) (interface{}, bool) {
+	for _, tag := range header {
+		if tag.ID == tagID {
+			return tag.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+func TestGenRpmHeaderBuildHost(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "rpm-header")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	cpioPath := filepath.Join(tmpDir, "payload.cpio")
+	compressedCpioPath := filepath.Join(tmpDir, "payload.cpio.gz")
+	packageFilesDir := filepath.Join(tmpDir, "package-files")
+
+	assert.Nil(ioutil.WriteFile(cpioPath, []byte("cpio"), 0644))
+	assert.Nil(ioutil.WriteFile(compressedCpioPath, []byte("gzip"), 0644))
+	assert.Nil(os.Mkdir(packageFilesDir, 0755))
+
+	hostname, err := os.Hostname()
+	assert.Nil(err)
+
+	var ctx context.Ctx
+	ctx.Project.Name = "myapp"
+	ctx.Pack.PackageFilesDir = packageFilesDir
+	ctx.Tarantool.TarantoolIsEnterprise = true
+
+	// default
+	header, err := genRpmHeader(nil, cpioPath, compressedCpioPath, &ctx)
+	assert.Nil(err)
+
+	buildHost, found := getTagValue(header, tagBuildHost)
+	assert.True(found)
+	assert.Equal(defaultBuildHost, buildHost)
+	if hostname != defaultBuildHost {
+		assert.NotEqual(hostname, buildHost)
+	}
+
+	// specified
+	ctx.Pack.RpmBuildHost = "build.example.com"
+
+	header, err = genRpmHeader(nil, cpioPath, compressedCpioPath, &ctx)
+	assert.Nil(err)
+
+	buildHost, found = getTagValue(header, tagBuildHost)
+	assert.True(found)
+	assert.Equal("build.example.com", buildHost)
+}
+
+func TestGenRpmHeaderPayloadDigest(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "rpm-header")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	cpioPath := filepath.Join(tmpDir, "payload.cpio")
+	compressedCpioPath := filepath.Join(tmpDir, "payload.cpio.gz")
+	packageFilesDir := filepath.Join(tmpDir, "package-files")
+
+	compressedPayload := []byte("compressed payload")
+
+	assert.Nil(ioutil.WriteFile(cpioPath, []byte("payload"), 0644))
+	assert.Nil(ioutil.WriteFile(compressedCpioPath, compressedPayload, 0644))
+	assert.Nil(os.Mkdir(packageFilesDir, 0755))
+
+	var ctx context.Ctx
+	ctx.Project.Name = "myapp"
+	ctx.Pack.PackageFilesDir = packageFilesDir
+	ctx.Tarantool.TarantoolIsEnterprise = true
+
+	testCases := []struct {
+		algoName  string
+		expAlgoID int32
+		expDigest string
+	}{
+		{"", hashAlgoSHA256, fmt.Sprintf("%x", sha256.Sum256(compressedPayload))},
+		{"sha256", hashAlgoSHA256, fmt.Sprintf("%x", sha256.Sum256(compressedPayload))},
+		{"SHA512", hashAlgoSHA512, fmt.Sprintf("%x", sha512.Sum512(compressedPayload))},
+		{"sha1", hashAlgoSHA1, fmt.Sprintf("%x", sha1.Sum(compressedPayload))},
+	}
+
+	for _, tc := range testCases {
+		ctx.Pack.RpmPayloadDigestAlgo = tc.algoName
+
+		header, err := genRpmHeader(nil, cpioPath, compressedCpioPath, &ctx)
+		assert.Nil(err)
+
+		payloadDigest, found := getTagValue(header, tagPayloadDigest)
+		assert.True(found)
+		assert.Equal([]string{tc.expDigest}, payloadDigest)
+
+		payloadDigestAlgo, found := getTagValue(header, tagPayloadDigestAlgo)
+		assert.True(found)
+		assert.Equal([]int32{tc.expAlgoID}, payloadDigestAlgo)
+	}
+
+	// unsupported algorithm
+	ctx.Pack.RpmPayloadDigestAlgo = "md4"
+
+	_, err = genRpmHeader(nil, cpioPath, compressedCpioPath, &ctx)
+	assert.EqualError(err, "Unsupported payload digest algorithm md4. Supported algorithms are: sha1, sha256, sha512")
+}
+
+func TestGetFilesInfoDocFlag(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	packageFilesDir, err := ioutil.TempDir("", "package-files")
+	assert.Nil(err)
+	defer os.RemoveAll(packageFilesDir)
+
+	relPaths := []string{
+		"usr/share/doc/myapp",
+		"usr/share/doc/myapp/README.md",
+		"usr/share/doc/myapp/docs",
+		"usr/share/doc/myapp/docs/index.md",
+		"usr/share/doc/myapp-other/README.md",
+		"usr/share/tarantool/myapp/init.lua",
+	}
+
+	for _, relPath := range []string{
+		"usr/share/doc/myapp/README.md",
+		"usr/share/doc/myapp/docs/index.md",
+		"usr/share/doc/myapp-other/README.md",
+		"usr/share/tarantool/myapp/init.lua",
+	} {
+		filePath := filepath.Join(packageFilesDir, relPath)
+		assert.Nil(os.MkdirAll(filepath.Dir(filePath), 0755))
+		assert.Nil(ioutil.WriteFile(filePath, []byte(relPath), 0644))
+	}
+
+	// docs dir isn't specified
+	filesInfo, err := getFilesInfo(relPaths, packageFilesDir, "", 1)
+	assert.Nil(err)
+	assert.Equal([]int32{dirFlag, fileFlag, dirFlag, fileFlag, fileFlag, fileFlag}, filesInfo.FileFlags)
+
+	// docs dir is specified
+	filesInfo, err = getFilesInfo(relPaths, packageFilesDir, "/usr/share/doc/myapp", 2)
+	assert.Nil(err)
+	assert.Equal([]int32{
+		dirFlag,
+		fileFlag | docFileFlag,
+		dirFlag,
+		fileFlag | docFileFlag,
+		fileFlag,
+		fileFlag,
+	}, filesInfo.FileFlags)
+}
@@ -0,0 +1,85 @@
+package rpm
+
+import (
+	"io"
+	"syscall"
+	"time"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+const (
+	// reproducibleFileDevice is set as a device of all package files
+	// in reproducible mode (like rpmbuild does)
+	reproducibleFileDevice = 1
+)
+
+// getReproducibleTime returns the time all package mtimes should be clamped to
+// and true if the package should be built reproducibly.
+// SOURCE_DATE_EPOCH is used if it's set, otherwise the zero Unix time is used
+func getReproducibleTime(ctx *context.Ctx) (time.Time, bool) {
+	if ctx.Pack.SourceDateEpochIsSet {
+		return time.Unix(ctx.Pack.SourceDateEpoch, 0), true
+	}
+
+	if ctx.Pack.Reproducible {
+		return time.Unix(0, 0), true
+	}
+
+	return time.Time{}, false
+}
+
+func clampTime(t time.Time, maxTime time.Time) time.Time {
+	if t.After(maxTime) {
+		return maxTime
+	}
+
+	return t
+}
+
+// normalizeFilesInfo makes files info independent on the build.
+// Inodes are renumbered in the files order (the same way as in the reproducible CPIO),
+// devices are set to the constant and mtimes are clamped to maxMtime
+func normalizeFilesInfo(filesInfo *filesInfoType, maxMtime time.Time) {
+	for i := range filesInfo.FileMtimes {
+		if int64(filesInfo.FileMtimes[i]) > maxMtime.Unix() {
+			filesInfo.FileMtimes[i] = int32(maxMtime.Unix())
+		}
+	}
+
+	for i := range filesInfo.FileInodes {
+		filesInfo.FileInodes[i] = int32(i + 1)
+	}
+
+	for i := range filesInfo.FileDevices {
+		filesInfo.FileDevices[i] = reproducibleFileDevice
+	}
+}
+
+// getReproducibleCpioNormalizer returns the CPIO entries normalizer that
+// makes the archive independent on the build: inodes are renumbered in the files order,
+// owners and devices are zeroed and mtimes are clamped to maxMtime
+func getReproducibleCpioNormalizer(maxMtime time.Time) cpioNewcEntryNormalizer {
+	return func(index int, entry *cpioNewcEntry) {
+		entry.Ino = index + 1
+		entry.Uid = 0
+		entry.Gid = 0
+		entry.Mtime = clampTime(time.Unix(entry.Mtime, 0), maxMtime).Unix()
+
+		entry.DevMajor = 0
+		entry.DevMinor = 0
+		entry.RdevMajor = 0
+		entry.RdevMinor = 0
+
+		entry.Nlink = 1
+		if entry.Mode&syscall.S_IFMT == syscall.S_IFDIR {
+			entry.Nlink = 2
+		}
+	}
+}
+
+// writeCpioNewc writes files to the CPIO archive in the newc format
+// the same way as `cpio -o -H newc` does, but the result doesn't depend on the build
+func writeCpioNewc(relPaths []string, srcDir string, w io.Writer, maxMtime time.Time) error {
+	return writeCpioNewcArchive(relPaths, srcDir, w, getReproducibleCpioNormalizer(maxMtime))
+}
@@ -0,0 +1,216 @@
+package rpm
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+type parsedCpioEntry struct {
+	Ino   int64
+	Mode  int64
+	Mtime int64
+	Name  string
+	Data  string
+}
+
+func parseCpioNewc(t *testing.T, data []byte) []parsedCpioEntry {
+	var entries []parsedCpioEntry
+
+	parseField := func(header []byte, index int) int64 {
+		value, err := strconv.ParseInt(string(header[6+index*8:6+(index+1)*8]), 16, 64)
+		if err != nil {
+			t.Fatalf("Failed to parse CPIO header field: %s", err)
+		}
+		return value
+	}
+
+	align := func(offset int) int {
+		return (offset + 3) &^ 3
+	}
+
+	for offset := 0; offset < len(data); {
+		header := data[offset : offset+110]
+		if string(header[:6]) != cpioNewcMagic {
+			t.Fatalf("Invalid CPIO magic at offset %d", offset)
+		}
+
+		fileSize := int(parseField(header, 6))
+		nameSize := int(parseField(header, 11))
+
+		nameOffset := offset + 110
+		name := string(data[nameOffset : nameOffset+nameSize-1])
+
+		dataOffset := align(nameOffset + nameSize)
+		entries = append(entries, parsedCpioEntry{
+			Ino:   parseField(header, 0),
+			Mode:  parseField(header, 1),
+			Mtime: parseField(header, 5),
+			Name:  name,
+			Data:  string(data[dataOffset : dataOffset+fileSize]),
+		})
+
+		offset = align(dataOffset + fileSize)
+		if name == cpioTrailerName {
+			break
+		}
+	}
+
+	return entries
+}
+
+func createReproducibleTestTree(t *testing.T, dirPath string) []string {
+	assert := assert.New(t)
+
+	assert.Nil(os.MkdirAll(filepath.Join(dirPath, "usr", "share", "tarantool", "myapp"), 0755))
+	assert.Nil(ioutil.WriteFile(filepath.Join(dirPath, "usr", "share", "tarantool", "myapp", "init.lua"),
+		[]byte("print('hello')\n"), 0644))
+	assert.Nil(os.Symlink("init.lua", filepath.Join(dirPath, "usr", "share", "tarantool", "myapp", "link.lua")))
+
+	relPaths, err := getSortedRelPaths(dirPath)
+	assert.Nil(err)
+
+	return relPaths
+}
+
+func TestWriteCpioNewc(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "cpio")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	maxMtime := time.Unix(1600000000, 0)
+
+	var archives [][]byte
+	for _, name := range []string{"first", "second"} {
+		srcDir := filepath.Join(tmpDir, name)
+		relPaths := createReproducibleTestTree(t, srcDir)
+
+		var buf bytes.Buffer
+		assert.Nil(writeCpioNewc(relPaths, srcDir, &buf, maxMtime))
+		assert.Equal(0, buf.Len()%4)
+
+		archives = append(archives, buf.Bytes())
+	}
+
+	// the result doesn't depend on the inodes and mtimes
+	assert.Equal(archives[0], archives[1])
+
+	entries := parseCpioNewc(t, archives[0])
+
+	// system directories are skipped
+	expNames := []string{
+		"usr/share/tarantool/myapp",
+		"usr/share/tarantool/myapp/init.lua",
+		"usr/share/tarantool/myapp/link.lua",
+		cpioTrailerName,
+	}
+
+	assert.Len(entries, len(expNames))
+
+	for i, entry := range entries {
+		assert.Equal(expNames[i], entry.Name)
+
+		if entry.Name != cpioTrailerName {
+			assert.EqualValues(i+1, entry.Ino)
+			assert.EqualValues(maxMtime.Unix(), entry.Mtime)
+		}
+	}
+
+	assert.EqualValues(040755, entries[0].Mode)
+	assert.Equal("print('hello')\n", entries[1].Data)
+	assert.EqualValues(0100644, entries[1].Mode)
+	assert.Equal("init.lua", entries[2].Data)
+	assert.EqualValues(0120000, entries[2].Mode&0170000)
+}
+
+func TestNormalizeFilesInfo(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	filesInfo := filesInfoType{
+		FileMtimes:  []int32{100, 300},
+		FileInodes:  []int32{123456, 654321},
+		FileDevices: []int32{2049, 2049},
+	}
+
+	normalizeFilesInfo(&filesInfo, time.Unix(200, 0))
+
+	assert.Equal([]int32{100, 200}, filesInfo.FileMtimes)
+	assert.Equal([]int32{1, 2}, filesInfo.FileInodes)
+	assert.Equal([]int32{1, 1}, filesInfo.FileDevices)
+}
+
+func TestGetReproducibleTime(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	var ctx context.Ctx
+
+	_, reproducible := getReproducibleTime(&ctx)
+	assert.False(reproducible)
+
+	ctx.Pack.Reproducible = true
+	reproducibleTime, reproducible := getReproducibleTime(&ctx)
+	assert.True(reproducible)
+	assert.EqualValues(0, reproducibleTime.Unix())
+
+	ctx.Pack.Reproducible = false
+	ctx.Pack.SourceDateEpoch = 1600000000
+	ctx.Pack.SourceDateEpochIsSet = true
+	reproducibleTime, reproducible = getReproducibleTime(&ctx)
+	assert.True(reproducible)
+	assert.EqualValues(1600000000, reproducibleTime.Unix())
+}
+
+func TestPackReproducible(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "rpm")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	var packages [][]byte
+	for _, name := range []string{"first", "second"} {
+		var ctx context.Ctx
+		ctx.Project.Name = "myapp"
+		ctx.Pack.Version = "1.0.0"
+		ctx.Pack.Release = "0"
+		ctx.Tarantool.TarantoolIsEnterprise = true
+		ctx.Pack.Reproducible = true
+
+		ctx.Cli.TmpDir = filepath.Join(tmpDir, name, "tmp")
+		ctx.Pack.PackageFilesDir = filepath.Join(tmpDir, name, "package-files")
+		ctx.Pack.ResPackagePath = filepath.Join(tmpDir, name, "myapp-1.0.0-0.rpm")
+
+		assert.Nil(os.MkdirAll(ctx.Cli.TmpDir, 0755))
+		createReproducibleTestTree(t, ctx.Pack.PackageFilesDir)
+
+		assert.Nil(Pack(&ctx))
+
+		rpmContent, err := ioutil.ReadFile(ctx.Pack.ResPackagePath)
+		assert.Nil(err)
+
+		packages = append(packages, rpmContent)
+
+		// mtimes of the next tree differ
+		time.Sleep(1100 * time.Millisecond)
+	}
+
+	assert.True(bytes.Equal(packages[0], packages[1]), "RPM packages differ")
+}
@@ -61,6 +61,22 @@ import (
 func Pack(ctx *context.Ctx) error {
 	var err error
 
+	if weakDepsAreSpecified(ctx) && ctx.Pack.RpmMinVersion == "" {
+		warnWeakDepsCompatibility()
+	}
+
+	var signKey *gpgKey
+	if ctx.Pack.RpmSignKey != "" {
+		if err := common.CheckRequiredBinaries("gpg"); err != nil {
+			return err
+		}
+
+		if signKey, err = getGPGKey(ctx.Pack.RpmSignKey); err != nil {
+			return err
+		}
+		defer signKey.Cleanup()
+	}
+
 	relPaths, err := getSortedRelPaths(ctx.Pack.PackageFilesDir)
 	if err != nil {
 		return fmt.Errorf("Failed to get sorted package files list: %s", err)
@@ -72,7 +88,7 @@ func Pack(ctx *context.Ctx) error {
 	}
 
 	compresedCpioPath := filepath.Join(ctx.Cli.TmpDir, "cpio.gz")
-	if err := common.CompressGzip(cpioPath, compresedCpioPath); err != nil {
+	if err := common.CompressGzip(cpioPath, compresedCpioPath, ctx.Pack.CompressionLevel); err != nil {
 		return fmt.Errorf("Failed to compress CPIO: %s", err)
 	}
 
@@ -110,6 +126,16 @@ func Pack(ctx *context.Ctx) error {
 		return fmt.Errorf("Failed to gen RPM signature: %s", err)
 	}
 
+	if ctx.Pack.RpmSignKey != "" {
+		pgpSignatureTags, err := genPGPSignatureTags(rpmBodyFilePath, rpmHeaderFilePath,
+			ctx.Pack.RpmSignHeader, signKey.isRSA(), getGPGSignFunc(signKey))
+		if err != nil {
+			return fmt.Errorf("Failed to sign RPM package: %s", err)
+		}
+
+		signature.addTags(pgpSignatureTags...)
+	}
+
 	packedSignature, err := packTagSet(*signature, headerSignatures)
 	if err != nil {
 		return fmt.Errorf("Failed to pack RPM header: %s", err)
@@ -0,0 +1,54 @@
+package rpm
+
+import (
+	"github.com/tarantool/cartridge-cli/cli/context"
+	"github.com/tarantool/cartridge-cli/cli/project"
+)
+
+type scriptTagsType struct {
+	Kind    string
+	TagID   int
+	ProgTag int
+}
+
+var (
+	// scriptsTags are the tags of the scripts that are added
+	// only if user-provided script is specified
+	scriptsTags = []scriptTagsType{
+		{Kind: project.PostInstScript, TagID: tagPostin, ProgTag: tagPostinProg},
+		{Kind: project.PreRmScript, TagID: tagPreun, ProgTag: tagPreunProg},
+		{Kind: project.PostRmScript, TagID: tagPostun, ProgTag: tagPostunProg},
+	}
+)
+
+// genScriptsTags returns the pre-install script (it's always delivered)
+// and the tags of the other install scripts.
+// User-provided scripts are appended after the generated ones
+func genScriptsTags(ctx *context.Ctx) (string, []rpmTagType, error) {
+	preInstSection, err := project.GetUserScriptSection(ctx, project.PreInstScript)
+	if err != nil {
+		return "", nil, err
+	}
+
+	preInstScript := project.PreInstScriptContent + preInstSection
+
+	var tags []rpmTagType
+
+	for _, scriptTags := range scriptsTags {
+		section, err := project.GetUserScriptSection(ctx, scriptTags.Kind)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if section == "" {
+			continue
+		}
+
+		tags = append(tags, []rpmTagType{
+			{ID: scriptTags.TagID, Type: rpmTypeString, Value: section},
+			{ID: scriptTags.ProgTag, Type: rpmTypeString, Value: "/bin/sh"},
+		}...)
+	}
+
+	return preInstScript, tags, nil
+}
@@ -0,0 +1,57 @@
+package rpm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+	"github.com/tarantool/cartridge-cli/cli/project"
+)
+
+func TestGenScriptsTags(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "scripts")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var ctx context.Ctx
+
+	// no user scripts
+	preInstScript, tags, err := genScriptsTags(&ctx)
+	assert.Nil(err)
+	assert.Equal(project.PreInstScriptContent, preInstScript)
+	assert.Len(tags, 0)
+
+	// user scripts are specified
+	ctx.Pack.PreInstScriptPath = filepath.Join(tmpDir, "preinst.sh")
+	assert.Nil(ioutil.WriteFile(ctx.Pack.PreInstScriptPath, []byte("useradd myapp\n"), 0644))
+
+	ctx.Pack.PreRmScriptPath = filepath.Join(tmpDir, "prerm.sh")
+	assert.Nil(ioutil.WriteFile(ctx.Pack.PreRmScriptPath, []byte("systemctl stop myapp\n"), 0644))
+
+	preInstScript, tags, err = genScriptsTags(&ctx)
+	assert.Nil(err)
+
+	assert.True(strings.HasPrefix(preInstScript, project.PreInstScriptContent))
+	assert.True(strings.HasSuffix(preInstScript, `
+# ---- BEGIN user-provided preinst script (preinst.sh) ----
+useradd myapp
+# ---- END user-provided preinst script ----
+`))
+
+	assert.Len(tags, 2)
+	assert.Equal(tagPreun, tags[0].ID)
+	assert.Contains(tags[0].Value, "systemctl stop myapp\n")
+	assert.Equal(tagPreunProg, tags[1].ID)
+	assert.Equal("/bin/sh", tags[1].Value)
+}
@@ -43,3 +43,56 @@ func genSignature(rpmBodyFilePath, rpmHeaderFilePath, cpioPath string) (*rpmTagS
 
 	return &signature, nil
 }
+
+// signFunc returns the binary OpenPGP signature of the file
+type signFunc func(filePath string) ([]byte, error)
+
+func getGPGSignFunc(key *gpgKey) signFunc {
+	return func(filePath string) ([]byte, error) {
+		signature, err := runGPG(key.HomeDir,
+			"--no-armor", "--digest-algo", "sha256",
+			"--local-user", key.ID,
+			"--detach-sign", "--output", "-",
+			filePath,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return []byte(signature), nil
+	}
+}
+
+// genPGPSignatureTags returns the signature tags that contain OpenPGP signatures.
+// PGP (GPG for non-RSA keys) tag contains the legacy signature of the header and the payload,
+// RSAHEADER (DSAHEADER for non-RSA keys) tag contains the v4 header-only signature
+// (added if signHeader is set).
+// The header file contains exactly the immutable header region (header magic included),
+// the body file contains the header region followed by the compressed payload
+func genPGPSignatureTags(rpmBodyFilePath, rpmHeaderFilePath string, signHeader bool, isRSA bool,
+	sign signFunc) ([]rpmTagType, error) {
+	var tags []rpmTagType
+
+	headerTagID, bodyTagID := signatureTagRSA, signatureTagPGP
+	if !isRSA {
+		headerTagID, bodyTagID = signatureTagDSA, signatureTagGPG
+	}
+
+	if signHeader {
+		headerSignature, err := sign(rpmHeaderFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign RPM header: %s", err)
+		}
+
+		tags = append(tags, rpmTagType{ID: headerTagID, Type: rpmTypeBin, Value: headerSignature})
+	}
+
+	bodySignature, err := sign(rpmBodyFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to sign RPM header and payload: %s", err)
+	}
+
+	tags = append(tags, rpmTagType{ID: bodyTagID, Type: rpmTypeBin, Value: bodySignature})
+
+	return tags, nil
+}
@@ -0,0 +1,155 @@
+package rpm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenPGPSignatureTags(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "rpm")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	header := rpmTagSetType{
+		{ID: tagName, Type: rpmTypeString, Value: "myapp"},
+		{ID: tagVersion, Type: rpmTypeString, Value: "1.0.0"},
+		{ID: tagSize, Type: rpmTypeInt32, Value: []int32{42}},
+	}
+
+	packedHeader, err := packTagSet(header, headerImmutable)
+	assert.Nil(err)
+	headerBytes := packedHeader.Bytes()
+
+	payloadBytes := []byte("compressed payload")
+	bodyBytes := append(append([]byte{}, headerBytes...), payloadBytes...)
+
+	headerFilePath := filepath.Join(tmpDir, "header")
+	bodyFilePath := filepath.Join(tmpDir, "body")
+	assert.Nil(ioutil.WriteFile(headerFilePath, headerBytes, 0644))
+	assert.Nil(ioutil.WriteFile(bodyFilePath, bodyBytes, 0644))
+
+	// mocked sign function returns signed data prefixed with "SIG:"
+	signedData := make(map[string][]byte)
+	sign := func(filePath string) ([]byte, error) {
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		signedData[filePath] = data
+		return append([]byte("SIG:"), data...), nil
+	}
+
+	// header and payload signature only
+	tags, err := genPGPSignatureTags(bodyFilePath, headerFilePath, false, true, sign)
+	assert.Nil(err)
+	assert.Len(tags, 1)
+	assert.Equal(signatureTagPGP, tags[0].ID)
+	assert.EqualValues(rpmTypeBin, tags[0].Type)
+	assert.Equal(append([]byte("SIG:"), bodyBytes...), tags[0].Value)
+
+	// with header-only signature
+	tags, err = genPGPSignatureTags(bodyFilePath, headerFilePath, true, true, sign)
+	assert.Nil(err)
+	assert.Len(tags, 2)
+	assert.Equal(signatureTagRSA, tags[0].ID)
+	assert.EqualValues(rpmTypeBin, tags[0].Type)
+	assert.Equal(append([]byte("SIG:"), headerBytes...), tags[0].Value)
+	assert.Equal(signatureTagPGP, tags[1].ID)
+
+	// header signature covers exactly the immutable header region:
+	// it starts with the header magic and ends with the region data
+	signedHeader := signedData[headerFilePath]
+	assert.True(bytes.HasPrefix(signedHeader, append(headerMagic, byte(versionByte))))
+
+	tagsNum := int(binary.BigEndian.Uint32(signedHeader[8:12]))
+	dataLen := int(binary.BigEndian.Uint32(signedHeader[12:16]))
+	assert.Equal(len(header)+1, tagsNum)
+	assert.Equal(16+tagsNum*16+dataLen, len(signedHeader))
+
+	// the first index entry is the immutable region tag
+	assert.Equal(uint32(headerImmutable), binary.BigEndian.Uint32(signedHeader[16:20]))
+
+	// payload isn't covered by the header signature
+	assert.False(bytes.Contains(signedHeader, payloadBytes))
+
+	// body signature covers the header region followed by the payload
+	signedBody := signedData[bodyFilePath]
+	assert.Equal(signedHeader, signedBody[:len(signedHeader)])
+	assert.Equal(payloadBytes, signedBody[len(signedHeader):])
+
+	// non-RSA key
+	tags, err = genPGPSignatureTags(bodyFilePath, headerFilePath, true, false, sign)
+	assert.Nil(err)
+	assert.Len(tags, 2)
+	assert.Equal(signatureTagDSA, tags[0].ID)
+	assert.Equal(append([]byte("SIG:"), headerBytes...), tags[0].Value)
+	assert.Equal(signatureTagGPG, tags[1].ID)
+	assert.Equal(append([]byte("SIG:"), bodyBytes...), tags[1].Value)
+}
+
+func TestParseSecretKey(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	listOutput := `sec:u:4096:1:0123456789ABCDEF:1610000000:::u:::scESC:::+:::23::0:
+fpr:::::::::AAAABBBBCCCCDDDDEEEEFFFF0123456789ABCDEF:
+grp:::::::::1111222233334444555566667777888899990000:
+uid:u::::1610000000::HASH::Packager <packager@example.com>::::::::::0:
+ssb:u:4096:1:FEDCBA9876543210:1610000000::::::e:::+:::23:
+fpr:::::::::99998888777766665555444433332222FEDCBA98:
+`
+
+	fingerprint, algo, err := parseSecretKey(listOutput)
+	assert.Nil(err)
+	assert.Equal("AAAABBBBCCCCDDDDEEEEFFFF0123456789ABCDEF", fingerprint)
+	assert.Equal(1, algo)
+	assert.True(isRSAKeyAlgo(algo))
+
+	// EdDSA key
+	fingerprint, algo, err = parseSecretKey(`sec:u:255:22:0123456789ABCDEF:1610000000:::u:::scESC:::+::ed25519:::0:
+fpr:::::::::1111222233334444555566667777888899990000:
+ssb:u:255:18:FEDCBA9876543210:1610000000::::::e:::+::cv25519::
+fpr:::::::::99998888777766665555444433332222FEDCBA98:
+`)
+	assert.Nil(err)
+	assert.Equal("1111222233334444555566667777888899990000", fingerprint)
+	assert.Equal(22, algo)
+	assert.False(isRSAKeyAlgo(algo))
+
+	// public key only
+	_, _, err = parseSecretKey("pub:u:4096:1:0123456789ABCDEF:1610000000:::u:::scESC:\n" +
+		"fpr:::::::::AAAABBBBCCCCDDDDEEEEFFFF0123456789ABCDEF:\n")
+	assert.EqualError(err, "No secret key found")
+
+	_, _, err = parseSecretKey("")
+	assert.EqualError(err, "No secret key found")
+}
+
+func TestRunWithStderr(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	output, err := runWithStderr(exec.Command("sh", "-c", "echo signed; echo warning >&2"))
+	assert.Nil(err)
+	assert.Equal("signed\n", output)
+
+	_, err = runWithStderr(exec.Command("sh", "-c", "echo signed; echo 'gpg: signing failed: No secret key' >&2; exit 2"))
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "exit status 2")
+	assert.Contains(err.Error(), "Stderr: gpg: signing failed: No secret key")
+	assert.NotContains(err.Error(), "signed\n")
+}
@@ -0,0 +1,457 @@
+package rpm
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+const (
+	leadSize           = 96
+	tagSetHeaderSize   = 16
+	tagSetIndexSize    = 16
+	cpioNewcHeaderSize = 110
+
+	fileTypeMask    = 0170000
+	regularFileType = 0100000
+)
+
+var (
+	leadMagic = []byte{0xed, 0xab, 0xee, 0xdb}
+)
+
+// rpmFileInfo describes the file stored in the RPM package.
+// Digest is set only for regular files
+type rpmFileInfo struct {
+	Size      int64
+	Digest    string
+	IsRegular bool
+}
+
+// Verify reads the RPM package ctx.Pack.ResPackagePath and checks that
+// all files from ctx.Pack.PackageFilesDir are present in the header and in the payload.
+// Sizes and digests of the regular files should be the same as the files ones
+func Verify(ctx *context.Ctx) error {
+	relPaths, err := getSortedRelPaths(ctx.Pack.PackageFilesDir)
+	if err != nil {
+		return fmt.Errorf("Failed to get sorted package files list: %s", err)
+	}
+
+	headerFiles, payloadFiles, err := readRpmFiles(ctx.Pack.ResPackagePath)
+	if err != nil {
+		return fmt.Errorf("Failed to read RPM package: %s", err)
+	}
+
+	diff, err := getPackageFilesDiff(relPaths, ctx.Pack.PackageFilesDir, headerFiles, payloadFiles)
+	if err != nil {
+		return err
+	}
+
+	if len(diff) > 0 {
+		return fmt.Errorf("RPM package doesn't match package files:\n  %s", strings.Join(diff, "\n  "))
+	}
+
+	return nil
+}
+
+// getPackageFilesDiff compares files from the header and from the payload
+// with the package files. Paths are absolute paths in the package
+func getPackageFilesDiff(relPaths []string, dirPath string,
+	headerFiles, payloadFiles map[string]rpmFileInfo) ([]string, error) {
+
+	var diff []string
+
+	checkFile := func(path string, where string, file rpmFileInfo, size int64, digest string) {
+		if !file.IsRegular {
+			diff = append(diff, fmt.Sprintf("%s: isn't a regular file in %s", path, where))
+			return
+		}
+
+		if file.Size != size {
+			diff = append(diff, fmt.Sprintf("%s: size in %s is %d, expected %d", path, where, file.Size, size))
+		}
+
+		if file.Digest != digest {
+			diff = append(diff, fmt.Sprintf("%s: digest in %s is %q, expected %q", path, where, file.Digest, digest))
+		}
+	}
+
+	for _, relPath := range relPaths {
+		path := "/" + filepath.ToSlash(relPath)
+
+		headerFile, inHeader := headerFiles[path]
+		payloadFile, inPayload := payloadFiles[path]
+
+		delete(headerFiles, path)
+		delete(payloadFiles, path)
+
+		if !inHeader {
+			diff = append(diff, fmt.Sprintf("%s: missed in header", path))
+		}
+
+		if !inPayload {
+			diff = append(diff, fmt.Sprintf("%s: missed in payload", path))
+		}
+
+		filePath := filepath.Join(dirPath, relPath)
+		fileInfo, err := os.Lstat(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get %s file info: %s", relPath, err)
+		}
+
+		// sizes and digests are checked only for regular files
+		if !fileInfo.Mode().IsRegular() {
+			continue
+		}
+
+		digest, err := common.FileMD5Hex(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get %s file MD5 hex: %s", relPath, err)
+		}
+
+		if inHeader {
+			checkFile(path, "header", headerFile, fileInfo.Size(), digest)
+		}
+
+		if inPayload {
+			checkFile(path, "payload", payloadFile, fileInfo.Size(), digest)
+		}
+	}
+
+	// files that aren't present in package files dir
+	for _, unexpected := range []struct {
+		Where string
+		Files map[string]rpmFileInfo
+	}{
+		{"header", headerFiles},
+		{"payload", payloadFiles},
+	} {
+		paths := make([]string, 0, len(unexpected.Files))
+		for path := range unexpected.Files {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			diff = append(diff, fmt.Sprintf("%s: unexpected file in %s", path, unexpected.Where))
+		}
+	}
+
+	return diff, nil
+}
+
+// readRpmFiles returns files described in the RPM package header
+// and files stored in the package payload
+func readRpmFiles(packagePath string) (map[string]rpmFileInfo, map[string]rpmFileInfo, error) {
+	packageFile, err := os.Open(packagePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer packageFile.Close()
+
+	r := bufio.NewReader(packageFile)
+
+	// lead
+	lead := make([]byte, leadSize)
+	if _, err := io.ReadFull(r, lead); err != nil {
+		return nil, nil, fmt.Errorf("Failed to read lead: %s", err)
+	}
+
+	if !bytes.Equal(lead[:len(leadMagic)], leadMagic) {
+		return nil, nil, fmt.Errorf("Invalid lead magic")
+	}
+
+	// signature is aligned to 8 bytes
+	_, signatureSize, err := readTagSet(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to read signature: %s", err)
+	}
+
+	if _, err := io.CopyN(ioutil.Discard, r, int64((8-signatureSize%8)%8)); err != nil {
+		return nil, nil, fmt.Errorf("Failed to read signature padding: %s", err)
+	}
+
+	// header
+	headerTags, _, err := readTagSet(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to read header: %s", err)
+	}
+
+	headerFiles, err := getHeaderFiles(headerTags)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to get files from header: %s", err)
+	}
+
+	// payload
+	payloadReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to decompress payload: %s", err)
+	}
+	defer payloadReader.Close()
+
+	payloadFiles, err := readCpioNewcFiles(payloadReader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to read payload: %s", err)
+	}
+
+	return headerFiles, payloadFiles, nil
+}
+
+// readTagSet reads the tag set packed by packTagSet.
+// Only values of the string, string array and integer types are unpacked.
+// The tag set size is returned to compute the padding
+func readTagSet(r io.Reader) (map[int]rpmTagType, int, error) {
+	tagSetHeader := make([]byte, tagSetHeaderSize)
+	if _, err := io.ReadFull(r, tagSetHeader); err != nil {
+		return nil, 0, err
+	}
+
+	if !bytes.Equal(tagSetHeader[:len(headerMagic)], headerMagic) {
+		return nil, 0, fmt.Errorf("Invalid tag set magic")
+	}
+
+	tagsNum := int(binary.BigEndian.Uint32(tagSetHeader[8:12]))
+	dataLen := int(binary.BigEndian.Uint32(tagSetHeader[12:16]))
+
+	index := make([]byte, tagsNum*tagSetIndexSize)
+	if _, err := io.ReadFull(r, index); err != nil {
+		return nil, 0, err
+	}
+
+	data := make([]byte, dataLen)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, 0, err
+	}
+
+	tags := make(map[int]rpmTagType, tagsNum)
+	for i := 0; i < tagsNum; i++ {
+		tagIndex := index[i*tagSetIndexSize : (i+1)*tagSetIndexSize]
+
+		tag := rpmTagType{
+			ID:   int(int32(binary.BigEndian.Uint32(tagIndex[0:4]))),
+			Type: rpmValueType(binary.BigEndian.Uint32(tagIndex[4:8])),
+		}
+		offset := int(int32(binary.BigEndian.Uint32(tagIndex[8:12])))
+		count := int(int32(binary.BigEndian.Uint32(tagIndex[12:16])))
+
+		if offset < 0 || offset > dataLen || count < 0 {
+			return nil, 0, fmt.Errorf("Tag %d has invalid offset or count", tag.ID)
+		}
+
+		value, err := unpackTagValue(tag.Type, data[offset:], count)
+		if err != nil {
+			return nil, 0, fmt.Errorf("Failed to unpack tag %d: %s", tag.ID, err)
+		}
+		tag.Value = value
+
+		tags[tag.ID] = tag
+	}
+
+	return tags, tagSetHeaderSize + len(index) + len(data), nil
+}
+
+func unpackTagValue(tagType rpmValueType, data []byte, count int) (interface{}, error) {
+	switch tagType {
+	case rpmTypeString:
+		value, _, err := unpackString(data)
+		return value, err
+
+	case rpmTypeStringArray, rpmTypeI18nstring:
+		values := make([]string, 0, count)
+		for i := 0; i < count; i++ {
+			value, rest, err := unpackString(data)
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, value)
+			data = rest
+		}
+
+		return values, nil
+
+	case rpmTypeInt16:
+		if len(data) < count*2 {
+			return nil, fmt.Errorf("Data is too short")
+		}
+
+		values := make([]int16, count)
+		for i := range values {
+			values[i] = int16(binary.BigEndian.Uint16(data[i*2:]))
+		}
+
+		return values, nil
+
+	case rpmTypeInt32:
+		if len(data) < count*4 {
+			return nil, fmt.Errorf("Data is too short")
+		}
+
+		values := make([]int32, count)
+		for i := range values {
+			values[i] = int32(binary.BigEndian.Uint32(data[i*4:]))
+		}
+
+		return values, nil
+	}
+
+	// other values aren't used on verification
+	return nil, nil
+}
+
+func unpackString(data []byte) (string, []byte, error) {
+	end := bytes.IndexByte(data, 0)
+	if end < 0 {
+		return "", nil, fmt.Errorf("String isn't terminated with a null byte")
+	}
+
+	return string(data[:end]), data[end+1:], nil
+}
+
+// getHeaderFiles returns files described in the header by absolute paths
+func getHeaderFiles(tags map[int]rpmTagType) (map[string]rpmFileInfo, error) {
+	var dirNames, baseNames, fileDigests []string
+	var dirIndexes, fileSizes []int32
+	var fileModes []int16
+
+	for _, tagValue := range []struct {
+		ID    int
+		Value interface{}
+	}{
+		{tagDirNames, &dirNames},
+		{tagBaseNames, &baseNames},
+		{tagFileDigests, &fileDigests},
+		{tagDirIndexes, &dirIndexes},
+		{tagFileSizes, &fileSizes},
+		{tagFileModes, &fileModes},
+	} {
+		tag, found := tags[tagValue.ID]
+		if !found {
+			return nil, fmt.Errorf("Tag %d is missed", tagValue.ID)
+		}
+
+		var ok bool
+		switch value := tagValue.Value.(type) {
+		case *[]string:
+			*value, ok = tag.Value.([]string)
+		case *[]int32:
+			*value, ok = tag.Value.([]int32)
+		case *[]int16:
+			*value, ok = tag.Value.([]int16)
+		}
+
+		if !ok {
+			return nil, fmt.Errorf("Tag %d has unexpected type %d", tagValue.ID, tag.Type)
+		}
+	}
+
+	filesNum := len(baseNames)
+	if len(dirIndexes) != filesNum || len(fileSizes) != filesNum ||
+		len(fileDigests) != filesNum || len(fileModes) != filesNum {
+		return nil, fmt.Errorf("Files tags have different lengths")
+	}
+
+	files := make(map[string]rpmFileInfo, filesNum)
+	for i, baseName := range baseNames {
+		dirIndex := int(dirIndexes[i])
+		if dirIndex < 0 || dirIndex >= len(dirNames) {
+			return nil, fmt.Errorf("File %s has invalid dir index %d", baseName, dirIndex)
+		}
+
+		path := filepath.ToSlash(filepath.Clean(dirNames[dirIndex] + baseName))
+		isRegular := uint16(fileModes[i])&fileTypeMask == regularFileType
+
+		file := rpmFileInfo{IsRegular: isRegular}
+		if isRegular {
+			file.Size = int64(uint32(fileSizes[i]))
+			file.Digest = fileDigests[i]
+		}
+
+		files[path] = file
+	}
+
+	return files, nil
+}
+
+// readCpioNewcFiles reads the CPIO archive in the newc format
+// and returns the archive files by absolute paths.
+// Digests of the regular files are computed on reading
+func readCpioNewcFiles(r io.Reader) (map[string]rpmFileInfo, error) {
+	files := make(map[string]rpmFileInfo)
+
+	for {
+		header := make([]byte, cpioNewcHeaderSize)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return nil, fmt.Errorf("Failed to read entry header: %s", err)
+		}
+
+		if string(header[:len(cpioNewcMagic)]) != cpioNewcMagic {
+			return nil, fmt.Errorf("Invalid entry magic")
+		}
+
+		var fields [13]int64
+		for i := range fields {
+			fieldOffset := len(cpioNewcMagic) + i*8
+			field, err := strconv.ParseUint(string(header[fieldOffset:fieldOffset+8]), 16, 32)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to parse entry header: %s", err)
+			}
+
+			fields[i] = int64(field)
+		}
+
+		mode, size, nameSize := fields[1], fields[6], fields[11]
+		if nameSize < 1 {
+			return nil, fmt.Errorf("Entry has invalid name size")
+		}
+
+		name := make([]byte, nameSize)
+		if _, err := io.ReadFull(r, name); err != nil {
+			return nil, fmt.Errorf("Failed to read entry name: %s", err)
+		}
+		entryName := string(name[:nameSize-1])
+
+		// header with name and data are padded to the multiple of 4
+		namePadding := len(getCpioPadding(cpioNewcHeaderSize+nameSize, 4))
+		if _, err := io.CopyN(ioutil.Discard, r, int64(namePadding)); err != nil {
+			return nil, fmt.Errorf("Failed to read %s entry: %s", entryName, err)
+		}
+
+		if entryName == cpioTrailerName {
+			break
+		}
+
+		isRegular := mode&fileTypeMask == regularFileType
+		hash := md5.New()
+		if _, err := io.CopyN(hash, r, size); err != nil {
+			return nil, fmt.Errorf("Failed to read %s entry data: %s", entryName, err)
+		}
+
+		if _, err := io.CopyN(ioutil.Discard, r, int64(len(getCpioPadding(size, 4)))); err != nil {
+			return nil, fmt.Errorf("Failed to read %s entry: %s", entryName, err)
+		}
+
+		file := rpmFileInfo{IsRegular: isRegular}
+		if isRegular {
+			file.Size = size
+			file.Digest = fmt.Sprintf("%x", hash.Sum(nil))
+		}
+
+		files["/"+strings.TrimPrefix(entryName, "./")] = file
+	}
+
+	return files, nil
+}
@@ -0,0 +1,58 @@
+package rpm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "rpm")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	var ctx context.Ctx
+	ctx.Project.Name = "myapp"
+	ctx.Pack.Version = "1.0.0"
+	ctx.Pack.Release = "0"
+	ctx.Tarantool.TarantoolIsEnterprise = true
+
+	ctx.Cli.TmpDir = filepath.Join(tmpDir, "tmp")
+	ctx.Pack.PackageFilesDir = filepath.Join(tmpDir, "package-files")
+	ctx.Pack.ResPackagePath = filepath.Join(tmpDir, "myapp-1.0.0-0.rpm")
+
+	assert.Nil(os.MkdirAll(ctx.Cli.TmpDir, 0755))
+	createReproducibleTestTree(t, ctx.Pack.PackageFilesDir)
+
+	assert.Nil(Pack(&ctx))
+	assert.Nil(Verify(&ctx))
+
+	appDir := filepath.Join(ctx.Pack.PackageFilesDir, "usr", "share", "tarantool", "myapp")
+
+	// package files are changed after packing
+	assert.Nil(ioutil.WriteFile(filepath.Join(appDir, "init.lua"), []byte("print('bye')\n"), 0644))
+	assert.Nil(ioutil.WriteFile(filepath.Join(appDir, "new.lua"), []byte(""), 0644))
+	assert.Nil(os.Remove(filepath.Join(appDir, "link.lua")))
+
+	assert.EqualError(Verify(&ctx), "RPM package doesn't match package files:\n"+
+		"  /usr/share/tarantool/myapp/init.lua: size in header is 15, expected 13\n"+
+		`  /usr/share/tarantool/myapp/init.lua: digest in header is "100e360cc768f15a35ed9ddf05e354ce", `+
+		`expected "28dbf1c263f734d9d8a1b8218c57cd64"`+"\n"+
+		"  /usr/share/tarantool/myapp/init.lua: size in payload is 15, expected 13\n"+
+		`  /usr/share/tarantool/myapp/init.lua: digest in payload is "100e360cc768f15a35ed9ddf05e354ce", `+
+		`expected "28dbf1c263f734d9d8a1b8218c57cd64"`+"\n"+
+		"  /usr/share/tarantool/myapp/new.lua: missed in header\n"+
+		"  /usr/share/tarantool/myapp/new.lua: missed in payload\n"+
+		"  /usr/share/tarantool/myapp/link.lua: unexpected file in header\n"+
+		"  /usr/share/tarantool/myapp/link.lua: unexpected file in payload",
+	)
+}
@@ -6,28 +6,32 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/apex/log"
 
 	"github.com/tarantool/cartridge-cli/cli/common"
 	"github.com/tarantool/cartridge-cli/cli/context"
 )
 
 var (
+	expectedCheckInterval = 1 * time.Second
+	socketCheckInterval   = 200 * time.Millisecond
+
 	confFilePatterns = []string{
 		"*.yml",
 		"*.yaml",
 	}
 )
 
-func CollectInstancesFromConf(ctx *context.Ctx) ([]string, error) {
-	var instances []string
-
-	// collect conf files
+func getConfFilePaths(confPath string) ([]string, error) {
 	var confFilePaths []string
-	if fileInfo, err := os.Stat(ctx.Running.ConfPath); err != nil {
+
+	if fileInfo, err := os.Stat(confPath); err != nil {
 		return nil, fmt.Errorf("Failed to use conf path: %s", err)
 	} else if fileInfo.IsDir() {
 		for _, pattern := range confFilePatterns {
-			paths, err := filepath.Glob(filepath.Join(ctx.Running.ConfPath, pattern))
+			paths, err := filepath.Glob(filepath.Join(confPath, pattern))
 			if err != nil {
 				return nil, err
 			}
@@ -35,7 +39,19 @@ func CollectInstancesFromConf(ctx *context.Ctx) ([]string, error) {
 			confFilePaths = append(confFilePaths, paths...)
 		}
 	} else {
-		confFilePaths = append(confFilePaths, ctx.Running.ConfPath)
+		confFilePaths = append(confFilePaths, confPath)
+	}
+
+	return confFilePaths, nil
+}
+
+func CollectInstancesFromConf(ctx *context.Ctx) ([]string, error) {
+	var instances []string
+
+	// collect conf files
+	confFilePaths, err := getConfFilePaths(ctx.Running.ConfPath)
+	if err != nil {
+		return nil, err
 	}
 
 	addedInstances := make(map[string]struct{})
@@ -115,3 +131,60 @@ func buildNotifySocket(process *Process) error {
 	process.env = append(process.env, formatEnv("NOTIFY_SOCKET", process.notifySockPath))
 	return nil
 }
+
+// checkInstancesExpected compares running instances count with expected one.
+// If waitTimeout is specified, getRunningCount is called every checkInterval
+// until expected count is reached or timeout is exceeded
+func checkInstancesExpected(runningCount, expected int,
+	getRunningCount func() (int, error), waitTimeout, checkInterval time.Duration) error {
+	var err error
+
+	if runningCount == expected {
+		log.Infof("%d instance(s) are running as expected", runningCount)
+		return nil
+	}
+
+	if waitTimeout > 0 {
+		log.Infof("Waiting for %d instance(s) to be running...", expected)
+
+		deadline := time.Now().Add(waitTimeout)
+		for time.Now().Before(deadline) {
+			time.Sleep(checkInterval)
+
+			if runningCount, err = getRunningCount(); err != nil {
+				return err
+			}
+
+			if runningCount == expected {
+				log.Infof("%d instance(s) are running as expected", runningCount)
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("Expected %d running instance(s), but %d are running", expected, runningCount)
+}
+
+// waitSocketAvailable waits until it's possible to connect to the specified socket.
+// checkProcess is called before each attempt to fail fast if the process is stopped
+func waitSocketAvailable(socketPath string, timeout time.Duration, checkProcess func() error) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		if err := checkProcess(); err != nil {
+			return err
+		}
+
+		conn, err := net.DialTimeout("unix", socketPath, socketCheckInterval)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+
+		if timeout != 0 && time.Now().After(deadline) {
+			return fmt.Errorf("Socket %s isn't available after %s: %s", socketPath, timeout, err)
+		}
+
+		time.Sleep(socketCheckInterval)
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"net"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/tarantool/cartridge-cli/cli/context"
@@ -195,3 +197,100 @@ func TestCollectProcesses(t *testing.T) {
 		getProcessesIDs(processes),
 	)
 }
+
+func getRunningCountMock(states []int) func() (int, error) {
+	i := 0
+	return func() (int, error) {
+		if i < len(states) {
+			i++
+		}
+		return states[i-1], nil
+	}
+}
+
+func TestCheckInstancesExpected(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	checkInterval := 10 * time.Millisecond
+
+	var err error
+
+	noCalls := func() (int, error) {
+		return 0, fmt.Errorf("Shouldn't be called")
+	}
+
+	// count matches
+	err = checkInstancesExpected(3, 3, noCalls, 0, checkInterval)
+	assert.Nil(err)
+
+	err = checkInstancesExpected(0, 0, noCalls, 0, checkInterval)
+	assert.Nil(err)
+
+	// count doesn't match w/o waiting
+	err = checkInstancesExpected(2, 3, noCalls, 0, checkInterval)
+	assert.EqualError(err, "Expected 3 running instance(s), but 2 are running")
+
+	err = checkInstancesExpected(4, 3, noCalls, 0, checkInterval)
+	assert.EqualError(err, "Expected 3 running instance(s), but 4 are running")
+
+	// expected count is reached while waiting
+	err = checkInstancesExpected(1, 3, getRunningCountMock([]int{1, 2, 3}), time.Second, checkInterval)
+	assert.Nil(err)
+
+	// expected count isn't reached while waiting
+	err = checkInstancesExpected(1, 3, getRunningCountMock([]int{1, 2}), 100*time.Millisecond, checkInterval)
+	assert.EqualError(err, "Expected 3 running instance(s), but 2 are running")
+
+	// failed to get running count
+	err = checkInstancesExpected(1, 3, noCalls, time.Second, checkInterval)
+	assert.EqualError(err, "Shouldn't be called")
+}
+
+func TestWaitSocketAvailable(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "sockets")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	socketPath := filepath.Join(tmpDir, "myapp.router.control")
+	processIsRunning := func() error { return nil }
+
+	// socket is started with delay
+	listenerCh := make(chan net.Listener, 1)
+	go func() {
+		time.Sleep(500 * time.Millisecond)
+		listener, err := net.Listen("unix", socketPath)
+		if err != nil {
+			listenerCh <- nil
+			return
+		}
+		listenerCh <- listener
+	}()
+
+	// timeout is reached before socket is started
+	err = waitSocketAvailable(socketPath, 100*time.Millisecond, processIsRunning)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), fmt.Sprintf("Socket %s isn't available after 100ms", socketPath))
+
+	// socket is started before timeout
+	assert.Nil(waitSocketAvailable(socketPath, 5*time.Second, processIsRunning))
+
+	listener := <-listenerCh
+	assert.NotNil(listener)
+	if listener != nil {
+		listener.Close()
+	}
+
+	// process is stopped
+	err = waitSocketAvailable(socketPath, 5*time.Second, func() error {
+		return fmt.Errorf("Process seems to be stopped")
+	})
+	assert.EqualError(err, "Process seems to be stopped")
+}
@@ -0,0 +1,104 @@
+package running
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// lookupUser finds the user by name or UID
+func lookupUser(runUser string) (*user.User, error) {
+	if _, err := strconv.Atoi(runUser); err == nil {
+		if u, err := user.LookupId(runUser); err == nil {
+			return u, nil
+		}
+	}
+
+	return user.Lookup(runUser)
+}
+
+// lookupGroup finds the group by name or GID
+func lookupGroup(runGroup string) (*user.Group, error) {
+	if _, err := strconv.Atoi(runGroup); err == nil {
+		if g, err := user.LookupGroupId(runGroup); err == nil {
+			return g, nil
+		}
+	}
+
+	return user.LookupGroup(runGroup)
+}
+
+func parseID(id string) (uint32, error) {
+	parsed, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(parsed), nil
+}
+
+// getRunCredential returns the credential of the instances processes.
+// The user primary group is used if the group isn't specified.
+// Nil is returned if neither user nor group is specified or they are
+// the current effective ones (setting the credential requires root privileges
+// even if it isn't changed, since supplementary groups are reset).
+// An error is returned if the current process can't set the credential
+func getRunCredential(runUser, runGroup string) (*syscall.Credential, error) {
+	if runUser == "" && runGroup == "" {
+		return nil, nil
+	}
+
+	credential := syscall.Credential{
+		Uid: uint32(os.Geteuid()),
+		Gid: uint32(os.Getegid()),
+	}
+
+	if runUser != "" {
+		u, err := lookupUser(runUser)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to find user %q: %s", runUser, err)
+		}
+
+		if credential.Uid, err = parseID(u.Uid); err != nil {
+			return nil, fmt.Errorf("Failed to parse user %q UID: %s", runUser, err)
+		}
+
+		if credential.Gid, err = parseID(u.Gid); err != nil {
+			return nil, fmt.Errorf("Failed to parse user %q GID: %s", runUser, err)
+		}
+	}
+
+	if runGroup != "" {
+		g, err := lookupGroup(runGroup)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to find group %q: %s", runGroup, err)
+		}
+
+		if credential.Gid, err = parseID(g.Gid); err != nil {
+			return nil, fmt.Errorf("Failed to parse group %q GID: %s", runGroup, err)
+		}
+	}
+
+	if credential.Uid == uint32(os.Geteuid()) && credential.Gid == uint32(os.Getegid()) {
+		return nil, nil
+	}
+
+	if os.Geteuid() != 0 {
+		return nil, fmt.Errorf("Root privileges are required to run instances as another user or group")
+	}
+
+	return &credential, nil
+}
+
+// chownPaths changes the owner of the paths to the credential user and group
+func chownPaths(credential *syscall.Credential, paths ...string) error {
+	for _, path := range paths {
+		if err := os.Chown(path, int(credential.Uid), int(credential.Gid)); err != nil {
+			return fmt.Errorf("Failed to change %s owner: %s", path, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package running
+
+import (
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRunCredential(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	credential, err := getRunCredential("", "")
+	assert.Nil(err)
+	assert.Nil(credential)
+
+	currentUser, err := user.Current()
+	assert.Nil(err)
+
+	currentGroup, err := user.LookupGroupId(currentUser.Gid)
+	assert.Nil(err)
+
+	expUID, _ := strconv.Atoi(currentUser.Uid)
+	expGID, _ := strconv.Atoi(currentUser.Gid)
+
+	// current user and group don't require the credential
+	isCurrent := expUID == os.Geteuid() && expGID == os.Getegid()
+
+	// by name and ID
+	for _, runUser := range []string{currentUser.Username, currentUser.Uid} {
+		credential, err = getRunCredential(runUser, "")
+		if !assert.Nil(err, runUser) {
+			continue
+		}
+
+		if isCurrent {
+			assert.Nil(credential, runUser)
+		} else if assert.NotNil(credential, runUser) {
+			assert.Equal(uint32(expUID), credential.Uid)
+			assert.Equal(uint32(expGID), credential.Gid)
+		}
+	}
+
+	for _, runGroup := range []string{currentGroup.Name, currentGroup.Gid} {
+		credential, err = getRunCredential("", runGroup)
+		if !assert.Nil(err, runGroup) {
+			continue
+		}
+
+		if expGID == os.Getegid() {
+			assert.Nil(credential, runGroup)
+		} else if assert.NotNil(credential, runGroup) {
+			assert.Equal(uint32(os.Geteuid()), credential.Uid)
+			assert.Equal(uint32(expGID), credential.Gid)
+		}
+	}
+
+	credential, err = getRunCredential(strconv.Itoa(os.Geteuid()), strconv.Itoa(os.Getegid()))
+	assert.Nil(err)
+	assert.Nil(credential)
+
+	_, err = getRunCredential("cartridge-unknown-user", "")
+	assert.NotNil(err)
+	assert.Contains(err.Error(), `Failed to find user "cartridge-unknown-user"`)
+
+	_, err = getRunCredential("", "cartridge-unknown-group")
+	assert.NotNil(err)
+	assert.Contains(err.Error(), `Failed to find group "cartridge-unknown-group"`)
+
+	if os.Geteuid() != 0 {
+		_, err = getRunCredential("root", "")
+		assert.EqualError(err, "Root privileges are required to run instances as another user or group")
+	} else if nobody, err := user.Lookup("nobody"); err == nil {
+		credential, err = getRunCredential("nobody", "")
+		if assert.Nil(err) && assert.NotNil(credential) {
+			assert.Equal(nobody.Uid, strconv.Itoa(int(credential.Uid)))
+			assert.Equal(nobody.Gid, strconv.Itoa(int(credential.Gid)))
+		}
+	}
+}
+
+func TestChownPaths(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "run")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	filePath := filepath.Join(tmpDir, "myapp.instance-1.pid")
+	assert.Nil(ioutil.WriteFile(filePath, nil, 0644))
+
+	credential := &syscall.Credential{
+		Uid: uint32(os.Geteuid()),
+		Gid: uint32(os.Getegid()),
+	}
+	assert.Nil(chownPaths(credential, tmpDir, filePath))
+
+	err = chownPaths(credential, filepath.Join(tmpDir, "none"))
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Failed to change")
+}
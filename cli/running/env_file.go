@@ -0,0 +1,197 @@
+package running
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+var (
+	envNameRgx = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	envDoubleQuotedEscapes = map[byte]string{
+		'n':  "\n",
+		't':  "\t",
+		'r':  "\r",
+		'"':  `"`,
+		'\\': `\`,
+	}
+)
+
+// readEnvFiles reads variables from the env files.
+// Files are merged in the specified order, so the latter files
+// override variables of the former ones
+func readEnvFiles(paths []string) (map[string]string, error) {
+	vars := make(map[string]string)
+
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to open env file: %s", err)
+		}
+
+		err = parseEnvFile(f, path, vars)
+		f.Close()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return vars, nil
+}
+
+// parseEnvFile parses KEY=VALUE lines and puts variables to vars.
+// Empty lines and lines started with # are skipped, "export " prefix is allowed.
+// Values can be single-quoted (as is) or double-quoted (\n, \t, \r, \" and \\ are unescaped).
+// Unquoted values are trimmed, # preceded by a whitespace starts a comment
+func parseEnvFile(r io.Reader, path string, vars map[string]string) error {
+	scanner := bufio.NewScanner(r)
+
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+
+		key, value, skip, err := parseEnvLine(scanner.Text())
+		if err != nil {
+			return fmt.Errorf("Failed to parse env file %s: line %d: %s", path, lineNum, err)
+		}
+
+		if !skip {
+			vars[key] = value
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("Failed to read env file %s: %s", path, err)
+	}
+
+	return nil
+}
+
+func parseEnvLine(line string) (string, string, bool, error) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", "", true, nil
+	}
+
+	line = strings.TrimPrefix(line, "export ")
+
+	parts := strings.SplitN(line, "=", 2)
+	if len(parts) != 2 {
+		return "", "", false, fmt.Errorf("Expected KEY=VALUE, found %q", line)
+	}
+
+	key := strings.TrimSpace(parts[0])
+	if !envNameRgx.MatchString(key) {
+		return "", "", false, fmt.Errorf("Invalid variable name %q", key)
+	}
+
+	value, err := parseEnvValue(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return "", "", false, fmt.Errorf("Invalid %s value: %s", key, err)
+	}
+
+	return key, value, false, nil
+}
+
+func parseEnvValue(rawValue string) (string, error) {
+	if rawValue == "" {
+		return "", nil
+	}
+
+	var value strings.Builder
+	var rest string
+
+	switch quote := rawValue[0]; quote {
+	case '\'':
+		end := strings.IndexByte(rawValue[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("Unterminated single-quoted string")
+		}
+
+		value.WriteString(rawValue[1 : end+1])
+		rest = rawValue[end+2:]
+	case '"':
+		closed := false
+
+		i := 1
+		for ; i < len(rawValue); i++ {
+			c := rawValue[i]
+
+			if c == '"' {
+				closed = true
+				break
+			}
+
+			if c == '\\' && i+1 < len(rawValue) {
+				if unescaped, found := envDoubleQuotedEscapes[rawValue[i+1]]; found {
+					value.WriteString(unescaped)
+					i++
+					continue
+				}
+			}
+
+			value.WriteByte(c)
+		}
+
+		if !closed {
+			return "", fmt.Errorf("Unterminated double-quoted string")
+		}
+
+		rest = rawValue[i+1:]
+	default:
+		if commentStart := strings.Index(rawValue, " #"); commentStart >= 0 {
+			rawValue = rawValue[:commentStart]
+		}
+		if commentStart := strings.Index(rawValue, "\t#"); commentStart >= 0 {
+			rawValue = rawValue[:commentStart]
+		}
+
+		return strings.TrimSpace(rawValue), nil
+	}
+
+	// only a comment can follow the quoted value
+	rest = strings.TrimSpace(rest)
+	if rest != "" && !strings.HasPrefix(rest, "#") {
+		return "", fmt.Errorf("Unexpected characters after the quoted string: %q", rest)
+	}
+
+	return value.String(), nil
+}
+
+// getEnvFilesVarsEnv returns variables read from the env files
+// sorted by names to make instances environment stable
+func getEnvFilesVarsEnv(ctx *context.Ctx) []string {
+	keys := make([]string, 0, len(ctx.Running.EnvFilesVars))
+	for key := range ctx.Running.EnvFilesVars {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	env := make([]string, len(keys))
+	for i, key := range keys {
+		env[i] = formatEnv(key, ctx.Running.EnvFilesVars[key])
+	}
+
+	return env
+}
+
+// getLookupEnvFunc returns the function that looks up
+// the variable in the env files first, then in the environment
+func getLookupEnvFunc(ctx *context.Ctx) lookupEnvFunc {
+	return func(key string) (string, bool) {
+		if value, found := ctx.Running.EnvFilesVars[key]; found {
+			return value, true
+		}
+
+		return os.LookupEnv(key)
+	}
+}
@@ -0,0 +1,103 @@
+package running
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	content := `# comment line
+
+TARANTOOL_CLUSTER_COOKIE=secret
+export TARANTOOL_HTTP_PORT = 8081
+UNQUOTED=some value # comment
+HASH=value#not-a-comment
+EMPTY=
+SINGLE='it is \n as is' # comment
+DOUBLE="line\n\"quoted\" \\ \$HOME"
+EQUALS=a=b
+`
+
+	vars := make(map[string]string)
+	assert.Nil(parseEnvFile(strings.NewReader(content), ".env", vars))
+	assert.Equal(map[string]string{
+		"TARANTOOL_CLUSTER_COOKIE": "secret",
+		"TARANTOOL_HTTP_PORT":      "8081",
+		"UNQUOTED":                 "some value",
+		"HASH":                     "value#not-a-comment",
+		"EMPTY":                    "",
+		"SINGLE":                   `it is \n as is`,
+		"DOUBLE":                   "line\n\"quoted\" \\ \\$HOME",
+		"EQUALS":                   "a=b",
+	}, vars)
+
+	// malformed lines
+	badLines := map[string]string{
+		"KEY":               `line 2: Expected KEY=VALUE, found "KEY"`,
+		"1KEY=value":        `line 2: Invalid variable name "1KEY"`,
+		"MY-KEY=value":      `line 2: Invalid variable name "MY-KEY"`,
+		"KEY='value":        `line 2: Invalid KEY value: Unterminated single-quoted string`,
+		`KEY="value\"`:      `line 2: Invalid KEY value: Unterminated double-quoted string`,
+		`KEY="value" other`: `line 2: Invalid KEY value: Unexpected characters after the quoted string: "other"`,
+	}
+
+	for badLine, expErr := range badLines {
+		err := parseEnvFile(strings.NewReader("# comment\n"+badLine+"\n"), ".env", map[string]string{})
+		assert.EqualError(err, "Failed to parse env file .env: "+expErr)
+	}
+}
+
+func TestReadEnvFiles(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "env-files")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	firstPath := filepath.Join(dir, ".env")
+	secondPath := filepath.Join(dir, ".env.local")
+
+	assert.Nil(ioutil.WriteFile(firstPath, []byte("FIRST=1\nCOMMON=first\n"), 0644))
+	assert.Nil(ioutil.WriteFile(secondPath, []byte("SECOND=2\nCOMMON=second\n"), 0644))
+
+	// files are merged in order
+	vars, err := readEnvFiles([]string{firstPath, secondPath})
+	assert.Nil(err)
+	assert.Equal(map[string]string{"FIRST": "1", "SECOND": "2", "COMMON": "second"}, vars)
+
+	vars, err = readEnvFiles([]string{secondPath, firstPath})
+	assert.Nil(err)
+	assert.Equal(map[string]string{"FIRST": "1", "SECOND": "2", "COMMON": "first"}, vars)
+
+	_, err = readEnvFiles([]string{filepath.Join(dir, "non-existent")})
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Failed to open env file")
+
+	// sorted env is passed to processes
+	ctx := &context.Ctx{}
+	ctx.Running.EnvFilesVars = vars
+	assert.Equal([]string{"COMMON=first", "FIRST=1", "SECOND=2"}, getEnvFilesVarsEnv(ctx))
+
+	// env files are looked up before the environment
+	lookupEnv := getLookupEnvFunc(ctx)
+
+	value, found := lookupEnv("FIRST")
+	assert.True(found)
+	assert.Equal("1", value)
+
+	_, found = lookupEnv("CARTRIDGE_CLI_NON_EXISTENT_VAR")
+	assert.False(found)
+}
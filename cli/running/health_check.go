@@ -0,0 +1,140 @@
+package running
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apex/log"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+)
+
+const (
+	healthyBoxStatus = "running"
+
+	// box.info isn't available until box.cfg is called
+	boxStatusFuncBody = `
+if type(box.cfg) == 'function' then
+	return 'unconfigured'
+end
+return box.info.status
+`
+)
+
+var (
+	healthCheckInterval = 500 * time.Millisecond
+)
+
+// getBoxStatus returns box.info.status of the instance
+// evaluated over its console socket
+func getBoxStatus(consoleSock string, timeout time.Duration) (string, error) {
+	conn, err := common.ConnectToTarantoolSocket(consoleSock)
+	if err != nil {
+		return "", fmt.Errorf("Failed to connect to %s: %s", consoleSock, err)
+	}
+	defer conn.Close()
+
+	res, err := common.EvalTarantoolConn(conn, boxStatusFuncBody, common.ConnOpts{
+		ReadTimeout: timeout,
+	})
+	if err != nil {
+		return "", fmt.Errorf("Failed to get box status: %s", err)
+	}
+
+	status, ok := res.(string)
+	if !ok {
+		return "", fmt.Errorf("Box status isn't a string: %v", res)
+	}
+
+	return status, nil
+}
+
+// waitHealthy waits until box.info.status of the instance is "running".
+// Zero timeout means no timeout
+func waitHealthy(consoleSock string, timeout time.Duration, checkProcess func() error) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		if err := checkProcess(); err != nil {
+			return err
+		}
+
+		status, err := getBoxStatus(consoleSock, healthCheckInterval)
+		if err == nil && status == healthyBoxStatus {
+			return nil
+		}
+
+		if timeout != 0 && time.Now().After(deadline) {
+			if err != nil {
+				return fmt.Errorf("Instance isn't healthy after %s: %s", timeout, err)
+			}
+			return fmt.Errorf("Instance isn't healthy after %s: box status is %q", timeout, status)
+		}
+
+		time.Sleep(healthCheckInterval)
+	}
+}
+
+// WaitHealthy waits until the process reports a healthy box status
+func (process *Process) WaitHealthy(timeout time.Duration) error {
+	return waitHealthy(process.consoleSock, timeout, process.checkIsRunning)
+}
+
+func waitProcessHealthy(process *Process, timeout time.Duration, resCh common.ResChan) {
+	if err := process.WaitHealthy(timeout); err != nil {
+		resCh <- common.Result{
+			ID:     process.ID,
+			Status: common.ResStatusFailed,
+			Error:  err,
+		}
+		return
+	}
+
+	resCh <- common.Result{
+		ID:     process.ID,
+		Status: common.ResStatusOk,
+		Messages: []common.ResultMessage{
+			common.GetInfoMessage("Box status is %q", healthyBoxStatus),
+		},
+	}
+}
+
+// WaitHealthy waits until all processes report a healthy box status.
+// Instances that didn't come up in time are listed in the error
+func (set *ProcessesSet) WaitHealthy(timeout time.Duration) error {
+	log.Infof("Waiting for instances to be healthy")
+
+	resCh := make(common.ResChan)
+
+	for _, process := range *set {
+		go waitProcessHealthy(process, timeout, resCh)
+	}
+
+	var failedIDs []string
+
+	// wait for all processes result
+	for i := 0; i < len(*set); i++ {
+		select {
+		case res := <-resCh:
+			if res.Status == common.ResStatusFailed {
+				log.Errorf("%s", res.FormatError())
+				failedIDs = append(failedIDs, res.ID)
+				continue
+			}
+
+			log.Infof(res.String())
+			for _, message := range res.Messages {
+				log.Infof("%s: %s", res.ID, message.Text)
+			}
+		}
+	}
+
+	if len(failedIDs) > 0 {
+		sort.Strings(failedIDs)
+		return fmt.Errorf("Some instances aren't healthy: %s", strings.Join(failedIDs, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package running
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// startStatusConsoleSock starts the console that answers each eval
+// with the box status. The first `loadingAnswers` evals return "loading"
+func startStatusConsoleSock(t *testing.T, sockPath string, status string, loadingAnswers int32) net.Listener {
+	listener, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Fatalf("Failed to listen socket: %s", err)
+	}
+
+	var answers int32
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(conn net.Conn) {
+				defer conn.Close()
+
+				if _, err := conn.Write([]byte(testGreeting)); err != nil {
+					return
+				}
+
+				reader := bufio.NewReader(conn)
+				if _, err := reader.ReadString('\n'); err != nil {
+					return
+				}
+
+				answerStatus := status
+				if atomic.AddInt32(&answers, 1) <= loadingAnswers {
+					answerStatus = "loading"
+				}
+
+				conn.Write([]byte(fmt.Sprintf("---\n- success: true\n  data: %s\n...\n", answerStatus)))
+			}(conn)
+		}
+	}()
+
+	return listener
+}
+
+func TestWaitHealthy(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	runDir, err := ioutil.TempDir("", "run")
+	assert.Nil(err)
+	defer os.RemoveAll(runDir)
+
+	healthySock := filepath.Join(runDir, "myapp.healthy.control")
+	orphanSock := filepath.Join(runDir, "myapp.orphan.control")
+
+	healthyListener := startStatusConsoleSock(t, healthySock, "running", 2)
+	defer healthyListener.Close()
+
+	orphanListener := startStatusConsoleSock(t, orphanSock, "orphan", 0)
+	defer orphanListener.Close()
+
+	processIsRunning := func() error { return nil }
+
+	status, err := getBoxStatus(healthySock, time.Second)
+	assert.Nil(err)
+	assert.Equal("loading", status)
+
+	assert.Nil(waitHealthy(healthySock, 5*time.Second, processIsRunning))
+
+	err = waitHealthy(orphanSock, 200*time.Millisecond, processIsRunning)
+	assert.EqualError(err, `Instance isn't healthy after 200ms: box status is "orphan"`)
+
+	err = waitHealthy(filepath.Join(runDir, "none.control"), 200*time.Millisecond, processIsRunning)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Failed to connect")
+
+	// process exit stops waiting
+	err = waitHealthy(healthySock, 0, func() error { return fmt.Errorf("Process seems to be stopped") })
+	assert.EqualError(err, "Process seems to be stopped")
+}
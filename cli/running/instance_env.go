@@ -0,0 +1,84 @@
+package running
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+const (
+	instanceNameEnv  = "TARANTOOL_INSTANCE_NAME"
+	clusterCookieEnv = "TARANTOOL_CLUSTER_COOKIE"
+	advertiseURIEnv  = "TARANTOOL_ADVERTISE_URI"
+	httpPortEnv      = "TARANTOOL_HTTP_PORT"
+)
+
+type lookupEnvFunc func(key string) (string, bool)
+
+// instanceOption is the instance option that can be specified
+// via the cartridge start flag or the environment variable
+type instanceOption struct {
+	EnvName   string
+	FlagName  string
+	FlagValue string
+	// PerInstance options can't be shared by many instances
+	PerInstance bool
+}
+
+func getInstanceOptions(ctx *context.Ctx) []instanceOption {
+	httpPort := ""
+	if ctx.Running.HTTPPort != 0 {
+		httpPort = strconv.Itoa(ctx.Running.HTTPPort)
+	}
+
+	return []instanceOption{
+		{EnvName: clusterCookieEnv, FlagName: "cluster-cookie", FlagValue: ctx.Running.ClusterCookie},
+		{EnvName: advertiseURIEnv, FlagName: "advertise-uri", FlagValue: ctx.Running.AdvertiseURI, PerInstance: true},
+		{EnvName: httpPortEnv, FlagName: "http-port", FlagValue: httpPort, PerInstance: true},
+	}
+}
+
+// getInstanceOptionsEnv returns the instance options environment variables.
+// Flags values have greater priority than the environment ones
+// (including ones read from env files).
+// Both have greater priority than the instances config values,
+// since cartridge prefers environment variables to the config
+func getInstanceOptionsEnv(ctx *context.Ctx, lookupEnv lookupEnvFunc) []string {
+	env := make([]string, 0)
+
+	for _, opt := range getInstanceOptions(ctx) {
+		if opt.FlagValue != "" {
+			env = append(env, formatEnv(opt.EnvName, opt.FlagValue))
+		} else if value, found := lookupEnv(opt.EnvName); found {
+			env = append(env, formatEnv(opt.EnvName, value))
+		}
+	}
+
+	return env
+}
+
+// checkInstanceOptions checks that per-instance options
+// are specified via flags or environment only if one instance is started.
+// Otherwise, all started instances get the same value and collide
+func checkInstanceOptions(ctx *context.Ctx, lookupEnv lookupEnvFunc) error {
+	if len(ctx.Running.Instances) <= 1 {
+		return nil
+	}
+
+	for _, opt := range getInstanceOptions(ctx) {
+		if !opt.PerInstance {
+			continue
+		}
+
+		if opt.FlagValue != "" {
+			return fmt.Errorf("--%s flag can be used only if one instance is started", opt.FlagName)
+		}
+
+		if _, found := lookupEnv(opt.EnvName); found {
+			return fmt.Errorf("%s is set in the environment, it can be used only if one instance is started", opt.EnvName)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package running
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func getMapLookupEnvFunc(env map[string]string) lookupEnvFunc {
+	return func(key string) (string, bool) {
+		value, found := env[key]
+		return value, found
+	}
+}
+
+func TestGetInstanceOptionsEnv(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	ctx := &context.Ctx{}
+
+	// nothing is specified
+	assert.Equal([]string{}, getInstanceOptionsEnv(ctx, getMapLookupEnvFunc(nil)))
+
+	// environment variables are passed
+	lookupEnv := getMapLookupEnvFunc(map[string]string{
+		"TARANTOOL_CLUSTER_COOKIE": "env-cookie",
+		"TARANTOOL_HTTP_PORT":      "8081",
+		"TARANTOOL_MEMTX_MEMORY":   "100",
+	})
+	assert.Equal([]string{
+		"TARANTOOL_CLUSTER_COOKIE=env-cookie",
+		"TARANTOOL_HTTP_PORT=8081",
+	}, getInstanceOptionsEnv(ctx, lookupEnv))
+
+	// flags override environment variables
+	ctx.Running.ClusterCookie = "flag-cookie"
+	ctx.Running.AdvertiseURI = "localhost:3301"
+	assert.Equal([]string{
+		"TARANTOOL_CLUSTER_COOKIE=flag-cookie",
+		"TARANTOOL_ADVERTISE_URI=localhost:3301",
+		"TARANTOOL_HTTP_PORT=8081",
+	}, getInstanceOptionsEnv(ctx, lookupEnv))
+
+	ctx.Running.HTTPPort = 8082
+	assert.Equal([]string{
+		"TARANTOOL_CLUSTER_COOKIE=flag-cookie",
+		"TARANTOOL_ADVERTISE_URI=localhost:3301",
+		"TARANTOOL_HTTP_PORT=8082",
+	}, getInstanceOptionsEnv(ctx, lookupEnv))
+}
+
+func TestCheckInstanceOptions(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	ctx := &context.Ctx{}
+	ctx.Running.Instances = []string{"router"}
+	ctx.Running.ClusterCookie = "secret"
+	ctx.Running.AdvertiseURI = "localhost:3301"
+	ctx.Running.HTTPPort = 8081
+
+	// one instance
+	assert.Nil(checkInstanceOptions(ctx, getMapLookupEnvFunc(nil)))
+
+	// many instances
+	ctx.Running.Instances = []string{"router", "storage"}
+	assert.EqualError(
+		checkInstanceOptions(ctx, getMapLookupEnvFunc(nil)),
+		"--advertise-uri flag can be used only if one instance is started",
+	)
+
+	ctx.Running.AdvertiseURI = ""
+	assert.EqualError(
+		checkInstanceOptions(ctx, getMapLookupEnvFunc(nil)),
+		"--http-port flag can be used only if one instance is started",
+	)
+
+	// cluster cookie can be shared
+	ctx.Running.HTTPPort = 0
+	assert.Nil(checkInstanceOptions(ctx, getMapLookupEnvFunc(map[string]string{
+		"TARANTOOL_CLUSTER_COOKIE": "secret",
+	})))
+
+	// per-instance values from env can't be shared
+	assert.EqualError(
+		checkInstanceOptions(ctx, getMapLookupEnvFunc(map[string]string{
+			"TARANTOOL_ADVERTISE_URI": "localhost:3301",
+		})),
+		"TARANTOOL_ADVERTISE_URI is set in the environment, it can be used only if one instance is started",
+	)
+
+	assert.EqualError(
+		checkInstanceOptions(ctx, getMapLookupEnvFunc(map[string]string{
+			"TARANTOOL_HTTP_PORT": "8081",
+		})),
+		"TARANTOOL_HTTP_PORT is set in the environment, it can be used only if one instance is started",
+	)
+
+	// one instance can use per-instance values from env
+	ctx.Running.Instances = []string{"router"}
+	assert.Nil(checkInstanceOptions(ctx, getMapLookupEnvFunc(map[string]string{
+		"TARANTOOL_ADVERTISE_URI": "localhost:3301",
+		"TARANTOOL_HTTP_PORT":     "8081",
+	})))
+}
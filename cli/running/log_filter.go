@@ -0,0 +1,108 @@
+package running
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Tarantool log levels in order of decreasing severity
+	logLevels = []string{"FATAL", "SYSERROR", "ERROR", "CRIT", "WARN", "INFO", "VERBOSE", "DEBUG"}
+
+	// plain log line marker, e.g. "main/101/init.lua W> message"
+	logLevelMarkers = map[string]string{
+		"F": "FATAL",
+		"!": "SYSERROR",
+		"E": "ERROR",
+		"C": "CRIT",
+		"W": "WARN",
+		"I": "INFO",
+		"V": "VERBOSE",
+		"D": "DEBUG",
+	}
+
+	logLevelMarkerRgx = regexp.MustCompile(`\s([F!ECWIVD])>\s`)
+	jsonLogLevelRgx   = regexp.MustCompile(`"level":\s*"([A-Z]+)"`)
+)
+
+// logFilter describes which log lines should be shown.
+// Line is shown if its level is at least as severe as the specified one
+// and it matches the regular expression
+type logFilter struct {
+	level   int
+	grepRgx *regexp.Regexp
+}
+
+func getLogLevelIndex(level string) int {
+	for i, logLevel := range logLevels {
+		if logLevel == level {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// newLogFilter returns nil if no filtering is required
+func newLogFilter(level string, grep string) (*logFilter, error) {
+	if level == "" && grep == "" {
+		return nil, nil
+	}
+
+	filter := logFilter{
+		level: -1,
+	}
+
+	if level != "" {
+		filter.level = getLogLevelIndex(strings.ToUpper(level))
+		if filter.level < 0 {
+			return nil, fmt.Errorf("Unknown log level %q. Supported levels are %s",
+				level, strings.Join(logLevels, ", "))
+		}
+	}
+
+	if grep != "" {
+		var err error
+		if filter.grepRgx, err = regexp.Compile(grep); err != nil {
+			return nil, fmt.Errorf("Invalid grep regular expression: %s", err)
+		}
+	}
+
+	return &filter, nil
+}
+
+// getLogLineLevel returns the level index of the plain or JSON log line.
+// -1 is returned if the line level can't be parsed
+func getLogLineLevel(line string) int {
+	if matches := logLevelMarkerRgx.FindStringSubmatch(line); matches != nil {
+		return getLogLevelIndex(logLevelMarkers[matches[1]])
+	}
+
+	if matches := jsonLogLevelRgx.FindStringSubmatch(line); matches != nil {
+		return getLogLevelIndex(matches[1])
+	}
+
+	return -1
+}
+
+// Match checks if the line should be shown.
+// Lines without level are skipped if level is specified
+func (filter *logFilter) Match(line string) bool {
+	if filter == nil {
+		return true
+	}
+
+	if filter.level >= 0 {
+		lineLevel := getLogLineLevel(line)
+		if lineLevel < 0 || lineLevel > filter.level {
+			return false
+		}
+	}
+
+	if filter.grepRgx != nil && !filter.grepRgx.MatchString(line) {
+		return false
+	}
+
+	return true
+}
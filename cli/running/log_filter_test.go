@@ -0,0 +1,87 @@
+package running
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLogFilter(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	filter, err := newLogFilter("", "")
+	assert.Nil(err)
+	assert.Nil(filter)
+	assert.True(filter.Match("any line"))
+
+	filter, err = newLogFilter("warn", "")
+	assert.Nil(err)
+	assert.Equal(getLogLevelIndex("WARN"), filter.level)
+	assert.Nil(filter.grepRgx)
+
+	_, err = newLogFilter("WARNING", "")
+	assert.EqualError(err, `Unknown log level "WARNING". `+
+		"Supported levels are FATAL, SYSERROR, ERROR, CRIT, WARN, INFO, VERBOSE, DEBUG")
+
+	_, err = newLogFilter("", "[abc")
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Invalid grep regular expression")
+}
+
+func TestGetLogLineLevel(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	cases := map[string]string{
+		"2021-01-14 12:00:00.123 [12345] main/101/init.lua I> Instance started":       "INFO",
+		"2021-01-14 12:00:00.123 [12345] main/101/init.lua W> Membership is outdated": "WARN",
+		"2021-01-14 12:00:00.123 [12345] main/101/init.lua E> ER_NO_SUCH_USER":        "ERROR",
+		"2021-01-14 12:00:00.123 [12345] main/101/init.lua !> SystemError":            "SYSERROR",
+		`{"time": "2021-01-14T12:00:00.123+0300", "level": "CRIT", "message": "x"}`:   "CRIT",
+	}
+
+	for line, level := range cases {
+		assert.Equal(getLogLevelIndex(level), getLogLineLevel(line), line)
+	}
+
+	assert.Equal(-1, getLogLineLevel("stack traceback:"))
+	assert.Equal(-1, getLogLineLevel("a -> b"))
+}
+
+func TestLogFilterMatch(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	infoLine := "2021-01-14 12:00:00.123 [12345] main/101/init.lua I> Instance started"
+	warnLine := "2021-01-14 12:00:00.123 [12345] main/101/init.lua W> Membership is outdated"
+	errLine := "2021-01-14 12:00:00.123 [12345] main/101/init.lua E> Instance failed"
+	unparsedLine := "stack traceback: Instance failed"
+
+	// level
+	filter, err := newLogFilter("WARN", "")
+	assert.Nil(err)
+	assert.False(filter.Match(infoLine))
+	assert.True(filter.Match(warnLine))
+	assert.True(filter.Match(errLine))
+	assert.False(filter.Match(unparsedLine))
+
+	// grep
+	filter, err = newLogFilter("", "Instance (started|failed)")
+	assert.Nil(err)
+	assert.True(filter.Match(infoLine))
+	assert.False(filter.Match(warnLine))
+	assert.True(filter.Match(errLine))
+	assert.True(filter.Match(unparsedLine))
+
+	// both
+	filter, err = newLogFilter("WARN", "Instance")
+	assert.Nil(err)
+	assert.False(filter.Match(infoLine))
+	assert.False(filter.Match(warnLine))
+	assert.True(filter.Match(errLine))
+	assert.False(filter.Match(unparsedLine))
+}
@@ -0,0 +1,212 @@
+package running
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/apex/log"
+	"github.com/fatih/color"
+	psutil "github.com/shirou/gopsutil/process"
+)
+
+type pidFileStateType int
+
+const (
+	pidFileMissing pidFileStateType = iota
+	pidFileEmpty
+	pidFileInvalid
+	pidFileUnknown
+	pidFileAlive
+	pidFileStale
+	pidFileRecycled
+)
+
+var (
+	pidFileStateStrings = map[pidFileStateType]string{
+		pidFileMissing:  color.New(color.FgCyan).Sprintf("NO PID FILE"),
+		pidFileEmpty:    color.New(color.FgCyan).Sprintf("EMPTY"),
+		pidFileInvalid:  color.New(color.FgRed).Sprintf("INVALID"),
+		pidFileUnknown:  color.New(color.FgYellow).Sprintf("UNKNOWN"),
+		pidFileAlive:    color.New(color.FgGreen).Sprintf("OK"),
+		pidFileStale:    color.New(color.FgRed).Sprintf("STALE"),
+		pidFileRecycled: color.New(color.FgRed).Sprintf("STALE"),
+	}
+)
+
+// getProcessCmdlineFunc returns the command line of the process with specified PID.
+// alive is false if there is no such process
+type getProcessCmdlineFunc func(pid int) (alive bool, cmdline string, err error)
+
+// pidFileCheck describes the state of the instance PID file
+type pidFileCheck struct {
+	ID      string
+	PidFile string
+
+	State   pidFileStateType
+	PID     int
+	Cmdline string
+	Error   error
+}
+
+// IsStale returns true if PID file exists,
+// but it doesn't point to the running tarantool process
+func (check *pidFileCheck) IsStale() bool {
+	switch check.State {
+	case pidFileInvalid, pidFileStale, pidFileRecycled:
+		return true
+	default:
+		return false
+	}
+}
+
+func (check *pidFileCheck) String() string {
+	stateStr := pidFileStateStrings[check.State]
+
+	switch check.State {
+	case pidFileMissing:
+		return fmt.Sprintf("%s: %s", check.ID, stateStr)
+	case pidFileEmpty:
+		return fmt.Sprintf("%s: %s (%s)", check.ID, stateStr, check.PidFile)
+	case pidFileInvalid:
+		return fmt.Sprintf("%s: %s (%s: %s)", check.ID, stateStr, check.PidFile, check.Error)
+	case pidFileUnknown:
+		return fmt.Sprintf("%s: %s (PID %d is alive, but it can't be checked: %s)",
+			check.ID, stateStr, check.PID, check.Error)
+	case pidFileAlive:
+		return fmt.Sprintf("%s: %s (PID %d is alive)", check.ID, stateStr, check.PID)
+	case pidFileStale:
+		return fmt.Sprintf("%s: %s (PID %d is not alive)", check.ID, stateStr, check.PID)
+	case pidFileRecycled:
+		return fmt.Sprintf("%s: %s (PID %d is not a tarantool process: %s)",
+			check.ID, stateStr, check.PID, check.Cmdline)
+	default:
+		return fmt.Sprintf("%s: PID file state %d", check.ID, check.State)
+	}
+}
+
+// processExists checks the kill(pid, 0) result.
+// EPERM means that the process exists, but it belongs to another user
+func processExists(signalErr error) bool {
+	return signalErr == nil || errors.Is(signalErr, syscall.EPERM)
+}
+
+func getProcessCmdline(pid int) (bool, string, error) {
+	osProcess, err := psutil.NewProcess(int32(pid))
+	if err != nil {
+		return false, "", nil
+	}
+
+	if err := osProcess.SendSignal(syscall.Signal(0)); !processExists(err) {
+		return false, "", nil
+	}
+
+	cmdline, err := osProcess.Cmdline()
+	if err != nil {
+		return true, "", fmt.Errorf("Failed to get process %d command line: %s", pid, err)
+	}
+
+	return true, cmdline, nil
+}
+
+// isTarantoolCmdline checks that the process executable is tarantool.
+// Tarantool changes the process title, but it still starts with `tarantool`
+func isTarantoolCmdline(cmdline string) bool {
+	args := strings.Fields(cmdline)
+	if len(args) == 0 {
+		return false
+	}
+
+	return filepath.Base(args[0]) == "tarantool"
+}
+
+func checkPidFile(process *Process, getCmdline getProcessCmdlineFunc) *pidFileCheck {
+	check := &pidFileCheck{
+		ID:      process.ID,
+		PidFile: process.pidFile,
+	}
+
+	pidBytes, err := ioutil.ReadFile(process.pidFile)
+	if os.IsNotExist(err) {
+		check.State = pidFileMissing
+		return check
+	} else if err != nil {
+		check.State = pidFileInvalid
+		check.Error = fmt.Errorf("Failed to read PID file: %s", err)
+		return check
+	}
+
+	// the PID file is created before the PID is written,
+	// so the empty file is considered the same way as the missing one
+	pidStr := strings.TrimSpace(string(pidBytes))
+	if pidStr == "" {
+		check.State = pidFileEmpty
+		return check
+	}
+
+	check.PID, err = strconv.Atoi(pidStr)
+	if err != nil || check.PID <= 0 {
+		check.State = pidFileInvalid
+		check.Error = fmt.Errorf("PID file has unknown format")
+		return check
+	}
+
+	alive, cmdline, err := getCmdline(check.PID)
+	if err != nil {
+		check.State = pidFileInvalid
+		if alive {
+			check.State = pidFileUnknown
+		}
+
+		check.Error = err
+		return check
+	}
+
+	check.Cmdline = cmdline
+
+	switch {
+	case !alive:
+		check.State = pidFileStale
+	case !isTarantoolCmdline(cmdline):
+		check.State = pidFileRecycled
+	default:
+		check.State = pidFileAlive
+	}
+
+	return check
+}
+
+func (set *ProcessesSet) checkPidFiles(getCmdline getProcessCmdlineFunc) []*pidFileCheck {
+	checks := make([]*pidFileCheck, 0, len(*set))
+
+	for _, process := range *set {
+		checks = append(checks, checkPidFile(process, getCmdline))
+	}
+
+	return checks
+}
+
+// PidFilesCheck reports PID files state for all processes
+// and fails if some of them are stale
+func (set *ProcessesSet) PidFilesCheck() error {
+	var staleIDs []string
+
+	for _, check := range set.checkPidFiles(getProcessCmdline) {
+		log.Infof(check.String())
+
+		if check.IsStale() {
+			staleIDs = append(staleIDs, check.ID)
+		}
+	}
+
+	if len(staleIDs) > 0 {
+		return fmt.Errorf("Some instances PID files are stale: %s", strings.Join(staleIDs, ", "))
+	}
+
+	return nil
+}
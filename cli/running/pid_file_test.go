@@ -0,0 +1,130 @@
+package running
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getMockedProcessCmdline(cmdlines map[int]string) getProcessCmdlineFunc {
+	return func(pid int) (bool, string, error) {
+		if pid == 666 {
+			return true, "", fmt.Errorf("Permission denied")
+		}
+
+		cmdline, found := cmdlines[pid]
+		return found, cmdline, nil
+	}
+}
+
+func TestIsTarantoolCmdline(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	assert.True(isTarantoolCmdline("tarantool init.lua <running>: myapp@router"))
+	assert.True(isTarantoolCmdline("/usr/bin/tarantool init.lua"))
+	assert.False(isTarantoolCmdline("/usr/bin/python3 server.py"))
+	assert.False(isTarantoolCmdline("tarantoolctl start myapp"))
+	assert.False(isTarantoolCmdline(""))
+}
+
+func TestCheckPidFiles(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	runDir, err := ioutil.TempDir("", "run")
+	assert.Nil(err)
+	defer os.RemoveAll(runDir)
+
+	pidFiles := map[string]string{
+		"live":        "101",
+		"stale":       "102",
+		"recycled":    "103\n",
+		"invalid":     "abc",
+		"no-cmdline":  "666",
+		"empty-title": "104",
+		"empty":       "\n",
+	}
+
+	for name, content := range pidFiles {
+		pidFilePath := filepath.Join(runDir, fmt.Sprintf("myapp.%s.pid", name))
+		assert.Nil(ioutil.WriteFile(pidFilePath, []byte(content), 0644))
+	}
+
+	var processes ProcessesSet
+	for _, name := range []string{"live", "stale", "recycled", "invalid", "missing", "no-cmdline", "empty-title", "empty"} {
+		processes = append(processes, &Process{
+			ID:      fmt.Sprintf("myapp.%s", name),
+			pidFile: filepath.Join(runDir, fmt.Sprintf("myapp.%s.pid", name)),
+		})
+	}
+
+	getCmdline := getMockedProcessCmdline(map[int]string{
+		101: "tarantool init.lua <running>: myapp@live",
+		103: "/usr/bin/python3 server.py",
+		104: "",
+	})
+
+	checks := processes.checkPidFiles(getCmdline)
+	assert.Len(checks, len(processes))
+
+	expStates := []pidFileStateType{
+		pidFileAlive,
+		pidFileStale,
+		pidFileRecycled,
+		pidFileInvalid,
+		pidFileMissing,
+		pidFileUnknown,
+		pidFileRecycled,
+		pidFileEmpty,
+	}
+
+	expStale := []bool{false, true, true, true, false, false, true, false}
+
+	for i, check := range checks {
+		assert.Equal(processes[i].ID, check.ID)
+		assert.Equal(expStates[i], check.State, check.ID)
+		assert.Equal(expStale[i], check.IsStale(), check.ID)
+	}
+
+	// live
+	assert.Equal(101, checks[0].PID)
+	assert.Contains(checks[0].String(), "PID 101 is alive")
+
+	// stale
+	assert.Equal(102, checks[1].PID)
+	assert.Contains(checks[1].String(), "PID 102 is not alive")
+
+	// recycled
+	assert.Equal(103, checks[2].PID)
+	assert.Contains(checks[2].String(), "PID 103 is not a tarantool process: /usr/bin/python3 server.py")
+
+	// invalid
+	assert.Contains(checks[3].String(), "PID file has unknown format")
+
+	// failed to get command line
+	assert.Contains(checks[5].String(), "UNKNOWN")
+	assert.Contains(checks[5].String(), "PID 666 is alive, but it can't be checked: Permission denied")
+
+	// empty
+	assert.Contains(checks[7].String(), "EMPTY")
+}
+
+func TestProcessExists(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	assert.True(processExists(nil))
+	assert.True(processExists(syscall.EPERM))
+	assert.True(processExists(os.NewSyscallError("kill", syscall.EPERM)))
+	assert.False(processExists(syscall.ESRCH))
+	assert.False(processExists(fmt.Errorf("os: process already finished")))
+}
@@ -14,6 +14,7 @@ import (
 	"strings"
 	"syscall"
 	"time"
+	"unicode/utf8"
 
 	"github.com/apex/log"
 	"github.com/fatih/color"
@@ -32,15 +33,23 @@ const (
 	procStatusNotStarted
 	procStatusRunning
 	procStatusStopped
+	procStatusHung
+	procStatusUnreachable
 
 	notifyReady   = "READY=1"
 	notifyBufSize = 300
+
+	// instances can write huge single-line JSON logs,
+	// so logs are read from the end by bigger parts
+	logTailBufSize int64 = 64 * 1024
 )
 
 var (
 	statusStrings      map[ProcStatusType]string
 	notifyStatusRgx    *regexp.Regexp
 	notifyRetryTimeout = 500 * time.Millisecond
+	stopRetryTimeout   = 100 * time.Millisecond
+	killWaitTimeout    = 5 * time.Second
 )
 
 func init() {
@@ -50,6 +59,8 @@ func init() {
 	statusStrings[procStatusNotStarted] = color.New(color.FgCyan).Sprintf("NOT STARTED")
 	statusStrings[procStatusRunning] = color.New(color.FgGreen).Sprintf("RUNNING")
 	statusStrings[procStatusStopped] = color.New(color.FgYellow).Sprintf("STOPPED")
+	statusStrings[procStatusHung] = color.New(color.FgRed).Sprintf("HUNG")
+	statusStrings[procStatusUnreachable] = color.New(color.FgRed).Sprintf("UNREACHABLE")
 
 	notifyStatusRgx = regexp.MustCompile(`(?s:^STATUS=(.+)$)`)
 }
@@ -63,11 +74,19 @@ func getStatusStr(process *Process) string {
 	return fmt.Sprintf("%s: %s", process.ID, statusStr)
 }
 
+func getYoungWarnStr(process *Process, now time.Time) string {
+	uptime := process.Uptime(now).Truncate(time.Second)
+	return color.New(color.FgYellow).Sprintf("(uptime %s)", uptime)
+}
+
 type Process struct {
 	ID     string
 	Status ProcStatusType
 	Error  error
 
+	// name is the instance name (or the stateboard name)
+	name string
+
 	entrypoint string
 
 	runDir      string
@@ -82,9 +101,13 @@ type Process struct {
 
 	env []string
 
+	// credential is set to run the process as another user
+	credential *syscall.Credential
+
 	cmd       *exec.Cmd
 	pid       int
 	osProcess *psutil.Process
+	startTime time.Time
 }
 
 func (process *Process) SetPidAndStatus() {
@@ -110,7 +133,8 @@ func (process *Process) SetPidAndStatus() {
 			return
 		}
 
-		if len(pidBytes) == 0 {
+		// the PID file is created before the PID is written
+		if len(strings.TrimSpace(string(pidBytes))) == 0 {
 			process.Status = procStatusNotStarted
 			return
 		}
@@ -142,10 +166,16 @@ func (process *Process) SetPidAndStatus() {
 		return
 	}
 
-	if err := process.osProcess.SendSignal(syscall.Signal(0)); err != nil {
+	if err := process.osProcess.SendSignal(syscall.Signal(0)); !processExists(err) {
 		process.Status = procStatusStopped
 	} else {
 		process.Status = procStatusRunning
+
+		if createTime, err := process.osProcess.CreateTime(); err != nil {
+			log.Debugf("Failed to get process %d create time: %s", process.pid, err)
+		} else {
+			process.startTime = time.Unix(0, createTime*int64(time.Millisecond))
+		}
 	}
 }
 
@@ -153,6 +183,25 @@ func (process *Process) IsRunning() bool {
 	return process.Status == procStatusRunning
 }
 
+// Uptime returns time since process start.
+// Zero is returned if process start time is unknown
+func (process *Process) Uptime(now time.Time) time.Duration {
+	if process.startTime.IsZero() {
+		return 0
+	}
+
+	return now.Sub(process.startTime)
+}
+
+// IsYoung checks if running process uptime is less than specified age
+func (process *Process) IsYoung(age time.Duration, now time.Time) bool {
+	if !process.IsRunning() || process.startTime.IsZero() {
+		return false
+	}
+
+	return process.Uptime(now) < age
+}
+
 func (process *Process) Start(daemonize bool) error {
 	var err error
 
@@ -176,11 +225,23 @@ func (process *Process) Start(daemonize bool) error {
 		}
 	}
 
+	// instance should be able to write its data, sockets and PID file
+	ownedPaths := []string{process.runDir, process.workDir}
+	if daemonize {
+		ownedPaths = append(ownedPaths, process.notifySockPath)
+	}
+
 	ctx := goContext.Background()
 	process.cmd = exec.CommandContext(ctx, "tarantool", process.entrypoint)
 
 	process.cmd.Env = append(os.Environ(), process.env...)
 
+	if process.credential != nil {
+		process.cmd.SysProcAttr = &syscall.SysProcAttr{
+			Credential: process.credential,
+		}
+	}
+
 	// initialize logs writer
 	if !daemonize {
 		logsWriter := newColorizedWriter(process.ID)
@@ -202,6 +263,8 @@ func (process *Process) Start(daemonize bool) error {
 
 		process.cmd.Stdout = logFile
 		process.cmd.Stderr = logFile
+
+		ownedPaths = append(ownedPaths, process.logDir, process.logFile)
 	}
 
 	// create pid file
@@ -211,6 +274,14 @@ func (process *Process) Start(daemonize bool) error {
 	}
 	defer pidFile.Close()
 
+	ownedPaths = append(ownedPaths, process.pidFile)
+
+	if process.credential != nil {
+		if err := chownPaths(process.credential, ownedPaths...); err != nil {
+			return err
+		}
+	}
+
 	if err := process.cmd.Start(); err != nil {
 		return fmt.Errorf("Failed to start: %s", err)
 	}
@@ -293,6 +364,42 @@ func (process *Process) WaitReady(timeout time.Duration) error {
 	return nil
 }
 
+// checkIsRunning updates the process status and
+// returns an error if the process isn't running
+func (process *Process) checkIsRunning() error {
+	process.SetPidAndStatus()
+
+	switch process.Status {
+	case procStatusError:
+		return fmt.Errorf("Failed to check process status: %s", process.Error)
+	case procStatusNotStarted:
+		return fmt.Errorf("Process isn't statred")
+	case procStatusStopped:
+		return fmt.Errorf("Process seems to be stopped")
+	}
+
+	return nil
+}
+
+// WaitSocket waits until the process console socket accepts connections
+func (process *Process) WaitSocket(timeout time.Duration) error {
+	if process.notifyConn != nil {
+		defer process.notifyConn.Close()
+	}
+
+	if err := waitSocketAvailable(process.consoleSock, timeout, process.checkIsRunning); err != nil {
+		if process.Status == procStatusRunning {
+			log.Errorf("%s: Console socket wasn't started. Killing the process...", process.ID)
+			if err := process.Kill(); err != nil {
+				log.Warnf("Failed to kill process %s: %s", process.ID, err)
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
 func (process *Process) SendSignal(sig syscall.Signal) error {
 	if process.osProcess == nil {
 		return project.InternalError("Process %d is not running", process.pid)
@@ -312,6 +419,34 @@ func (process *Process) Stop(force bool) error {
 	return process.Kill()
 }
 
+// WaitStopped waits until the process exits.
+// Process status check errors are treated as the process is still stopping.
+// Zero timeout means no timeout
+func (process *Process) WaitStopped(timeout time.Duration) error {
+	timeStart := time.Now()
+
+	for {
+		process.SetPidAndStatus()
+
+		switch process.Status {
+		case procStatusStopped, procStatusNotStarted:
+			return nil
+		}
+
+		if timeout != 0 && time.Now().Sub(timeStart) > timeout {
+			// the status check error can be transient
+			// (e.g. the PID file is being removed), so it's reported only on timeout
+			if process.Status == procStatusError {
+				return fmt.Errorf("Failed to check process status: %s", process.Error)
+			}
+
+			return fmt.Errorf("Stop timeout was reached")
+		}
+
+		time.Sleep(stopRetryTimeout)
+	}
+}
+
 func (process *Process) Terminate() error {
 	return process.SendSignal(syscall.SIGTERM)
 }
@@ -332,6 +467,7 @@ func NewInstanceProcess(ctx *context.Ctx, instanceName string) *Process {
 	var process Process
 
 	process.ID = fmt.Sprintf("%s.%s", ctx.Project.Name, instanceName)
+	process.name = instanceName
 
 	process.entrypoint = getEntrypointPath(ctx.Running.AppDir, ctx.Running.Entrypoint)
 	process.runDir = ctx.Running.RunDir
@@ -343,24 +479,43 @@ func NewInstanceProcess(ctx *context.Ctx, instanceName string) *Process {
 
 	process.notifySockPath = project.GetInstanceNotifySockPath(ctx, instanceName)
 
+	// variables from env files can be overridden by the enforced ones
+	process.env = append(process.env, getEnvFilesVarsEnv(ctx)...)
+
 	process.env = append(process.env,
 		formatEnv("TARANTOOL_APP_NAME", ctx.Project.Name),
-		formatEnv("TARANTOOL_INSTANCE_NAME", instanceName),
+		formatEnv(instanceNameEnv, instanceName),
 		formatEnv("TARANTOOL_CFG", ctx.Running.ConfPath),
 		formatEnv("TARANTOOL_CONSOLE_SOCK", process.consoleSock),
 		formatEnv("TARANTOOL_PID_FILE", process.pidFile),
 		formatEnv("TARANTOOL_WORKDIR", process.workDir),
 	)
 
+	// options specified via flags override the environment ones
+	process.env = append(process.env, getInstanceOptionsEnv(ctx, getLookupEnvFunc(ctx))...)
+
 	process.SetPidAndStatus()
 
 	return &process
 }
 
+// IsInstanceRunning checks that the instance process is running.
+// Processes on the remote host (see --ssh) can't be checked,
+// so the instance is considered running if its console socket is forwarded
+func IsInstanceRunning(ctx *context.Ctx, instanceName string) bool {
+	if ctx.Connect.SSH != "" {
+		_, err := os.Stat(project.GetInstanceConsoleSock(ctx, instanceName))
+		return err == nil
+	}
+
+	return NewInstanceProcess(ctx, instanceName).IsRunning()
+}
+
 func NewStateboardProcess(ctx *context.Ctx) *Process {
 	var process Process
 
 	process.ID = ctx.Project.StateboardName
+	process.name = ctx.Project.StateboardName
 
 	process.entrypoint = getEntrypointPath(ctx.Running.AppDir, ctx.Running.StateboardEntrypoint)
 	process.runDir = ctx.Running.RunDir
@@ -372,6 +527,8 @@ func NewStateboardProcess(ctx *context.Ctx) *Process {
 
 	process.notifySockPath = project.GetStateboardNotifySockPath(ctx)
 
+	process.env = append(process.env, getEnvFilesVarsEnv(ctx)...)
+
 	process.env = append(process.env,
 		formatEnv("TARANTOOL_APP_NAME", ctx.Project.StateboardName),
 		formatEnv("TARANTOOL_CFG", ctx.Running.ConfPath),
@@ -385,12 +542,24 @@ func NewStateboardProcess(ctx *context.Ctx) *Process {
 	return &process
 }
 
-func (process *Process) Log(follow bool, n int) error {
+func (process *Process) Log(follow bool, n int, maxLineLength int, filter *logFilter,
+	done <-chan struct{}) error {
+	writer := newColorizedWriter(process.ID)
+	return process.writeLog(writer, follow, n, maxLineLength, filter, done)
+}
+
+// writeLog writes last n lines of the process log to the writer.
+// Lines that don't match the filter are skipped (n bounds lines before filtering).
+// Lines longer than maxLineLength bytes are truncated (if maxLineLength > 0).
+// In follow mode, the log file is reopened if it's rotated or truncated,
+// writing stops when done is closed
+func (process *Process) writeLog(writer io.Writer, follow bool, n int, maxLineLength int, filter *logFilter,
+	done <-chan struct{}) error {
 	if _, err := os.Stat(process.logFile); err != nil {
 		return fmt.Errorf("Failed to use process log file: %s", err)
 	}
 
-	offset, err := common.GetLastNLinesBegin(process.logFile, n)
+	offset, err := common.GetLastNLinesBeginWithBufSize(process.logFile, n, logTailBufSize)
 	if err != nil {
 		return fmt.Errorf("Failed to find offset in file: %s", err)
 	}
@@ -402,29 +571,80 @@ func (process *Process) Log(follow bool, n int) error {
 			Offset: offset,
 			Whence: io.SeekStart,
 		},
+		ReOpen: follow,
 		Logger: tail.DiscardingLogger,
 	})
 	if err != nil {
 		return fmt.Errorf("Failed to get logs tail: %s", err)
 	}
 
-	writer := newColorizedWriter(process.ID)
+	for {
+		select {
+		case line, ok := <-t.Lines:
+			if !ok {
+				return nil
+			}
+
+			// tail cuts only `\n`, so `\r` of `\r\n` line ending is left
+			lineText := strings.TrimSuffix(line.Text, "\r")
+
+			if !filter.Match(lineText) {
+				continue
+			}
 
-	for line := range t.Lines {
-		if _, err := writer.Write([]byte(line.Text + "\n")); err != nil {
-			return fmt.Errorf("Failed to write log line: %s", err)
+			lineText = truncateLogLine(lineText, maxLineLength)
+			if _, err := writer.Write([]byte(lineText + "\n")); err != nil {
+				return fmt.Errorf("Failed to write log line: %s", err)
+			}
+		case <-done:
+			t.Stop()
+			t.Cleanup()
+			return nil
 		}
 	}
+}
 
-	return nil
+// truncateLogLine cuts the line to maxLength bytes (keeping UTF-8 characters intact)
+// and adds the truncated bytes count marker
+func truncateLogLine(line string, maxLength int) string {
+	if maxLength <= 0 || len(line) <= maxLength {
+		return line
+	}
+
+	cutPos := maxLength
+	for cutPos > 0 && !utf8.RuneStart(line[cutPos]) {
+		cutPos--
+	}
+
+	return fmt.Sprintf("%s…[truncated %d bytes]", line[:cutPos], len(line)-cutPos)
 }
 
-func (process *Process) Clean() common.Result {
-	res := common.Result{
-		ID: process.ID,
+// SaveLog writes last n lines of the process log to the
+// <outputDir>/<instance-name>.log file.
+// Lines are filtered and truncated the same way as by Log
+func (process *Process) SaveLog(n int, maxLineLength int, filter *logFilter, outputDir string) (string, error) {
+	if _, err := os.Stat(process.logFile); err != nil {
+		return "", fmt.Errorf("Failed to use process log file: %s", err)
+	}
+
+	outputFilePath := filepath.Join(outputDir, fmt.Sprintf("%s.log", process.name))
+
+	outputFile, err := os.Create(outputFilePath)
+	if err != nil {
+		return "", fmt.Errorf("Failed to create output file: %s", err)
+	}
+	defer outputFile.Close()
+
+	if err := process.writeLog(outputFile, false, n, maxLineLength, filter, nil); err != nil {
+		return "", fmt.Errorf("Failed to write logs to %s: %s", outputFilePath, err)
 	}
 
-	pathsToDelete := []string{
+	return outputFilePath, nil
+}
+
+// getCleanPaths returns the process files removed on clean
+func (process *Process) getCleanPaths() []string {
+	return []string{
 		process.logFile,
 		process.workDir,
 		process.consoleSock,
@@ -432,6 +652,31 @@ func (process *Process) Clean() common.Result {
 		process.pidFile,
 		// PID file can be deleted since we don't allow to clean running instances data
 	}
+}
+
+// getExistingCleanPaths returns the process files that exist and would be removed on clean
+func (process *Process) getExistingCleanPaths() ([]string, error) {
+	var paths []string
+
+	for _, path := range process.getCleanPaths() {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+func (process *Process) Clean() common.Result {
+	res := common.Result{
+		ID: process.ID,
+	}
+
+	pathsToDelete := process.getCleanPaths()
 
 	var nonExistedFiles []string
 	var errors []string
@@ -1,9 +1,17 @@
 package running
 
 import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/tarantool/cartridge-cli/cli/common"
 	"github.com/tarantool/cartridge-cli/cli/context"
 )
 
@@ -119,3 +127,497 @@ func TestPathToEntrypoint(t *testing.T) {
 	process = NewStateboardProcess(ctx)
 	assert.Equal("/abs/path/to/stateboard.init.lua", process.entrypoint)
 }
+
+func TestProcessIsYoung(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	now := time.Now()
+	process := &Process{}
+
+	// not running
+	process.Status = procStatusStopped
+	process.startTime = now.Add(-5 * time.Second)
+	assert.False(process.IsYoung(time.Minute, now))
+
+	// running, start time is unknown
+	process.Status = procStatusRunning
+	process.startTime = time.Time{}
+	assert.EqualValues(0, process.Uptime(now))
+	assert.False(process.IsYoung(time.Minute, now))
+
+	// running less than threshold
+	process.startTime = now.Add(-5 * time.Second)
+	assert.Equal(5*time.Second, process.Uptime(now))
+	assert.True(process.IsYoung(time.Minute, now))
+
+	// running longer than threshold
+	process.startTime = now.Add(-2 * time.Minute)
+	assert.Equal(2*time.Minute, process.Uptime(now))
+	assert.False(process.IsYoung(time.Minute, now))
+}
+
+func TestStatusAgeWarn(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	now := time.Now()
+
+	youngProcess := &Process{
+		ID:        "myapp.router",
+		Status:    procStatusRunning,
+		startTime: now.Add(-3 * time.Second),
+	}
+
+	oldProcess := &Process{
+		ID:        "myapp.storage",
+		Status:    procStatusRunning,
+		startTime: now.Add(-time.Hour),
+	}
+
+	assert.Contains(getYoungWarnStr(youngProcess, now), "(uptime 3s)")
+
+	processes := ProcessesSet{youngProcess, oldProcess}
+	assert.Nil(processes.Status(time.Minute))
+	assert.Nil(processes.Status(0))
+
+	errorProcess := &Process{
+		ID:     "myapp.stateboard",
+		Status: procStatusError,
+	}
+
+	processes.Add(errorProcess)
+	assert.NotNil(processes.Status(time.Minute))
+}
+
+func TestStatusExitCode(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	now := time.Now()
+
+	youngProcess := &Process{
+		ID:        "myapp.router",
+		Status:    procStatusRunning,
+		startTime: now.Add(-3 * time.Second),
+	}
+
+	oldProcess := &Process{
+		ID:        "myapp.storage",
+		Status:    procStatusRunning,
+		startTime: now.Add(-time.Hour),
+	}
+
+	processes := ProcessesSet{youngProcess, oldProcess}
+
+	// young instances fail the command only if --age-warn is set
+	assert.Nil(processes.checkRunning(0, now))
+	assert.Nil(processes.checkRunning(time.Second, now))
+	assert.EqualError(processes.checkRunning(time.Minute, now),
+		"Some instances are running less than 1m0s: myapp.router")
+
+	stoppedProcess := &Process{
+		ID:     "myapp.stateboard",
+		Status: procStatusStopped,
+	}
+
+	processes.Add(stoppedProcess)
+	assert.EqualError(processes.checkRunning(0, now), "Some instances aren't running: myapp.stateboard")
+	assert.EqualError(processes.checkRunning(time.Minute, now), "Some instances aren't running: myapp.stateboard")
+}
+
+func TestSaveLogs(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "logs")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	logDir := filepath.Join(tmpDir, "log")
+	outputDir := filepath.Join(tmpDir, "output")
+	assert.Nil(os.Mkdir(logDir, 0755))
+
+	processes := ProcessesSet{}
+	expLogs := make(map[string]string)
+
+	for _, instanceName := range []string{"router", "storage"} {
+		process := &Process{
+			ID:      fmt.Sprintf("myapp.%s", instanceName),
+			Status:  procStatusRunning,
+			name:    instanceName,
+			logFile: filepath.Join(logDir, fmt.Sprintf("myapp.%s.log", instanceName)),
+		}
+
+		var logLines []string
+		for i := 0; i < 10; i++ {
+			logLines = append(logLines, fmt.Sprintf("%s line %d", instanceName, i))
+		}
+
+		logContent := strings.Join(logLines, "\n") + "\n"
+		assert.Nil(ioutil.WriteFile(process.logFile, []byte(logContent), 0644))
+
+		expLogs[instanceName] = strings.Join(logLines[7:], "\n") + "\n"
+		processes.Add(process)
+	}
+
+	assert.Nil(processes.SaveLogs(3, 0, nil, outputDir))
+
+	outputFiles, err := ioutil.ReadDir(outputDir)
+	assert.Nil(err)
+	assert.Len(outputFiles, 2)
+	assert.Equal("router.log", outputFiles[0].Name())
+	assert.Equal("storage.log", outputFiles[1].Name())
+
+	for instanceName, expLog := range expLogs {
+		logContent, err := ioutil.ReadFile(filepath.Join(outputDir, fmt.Sprintf("%s.log", instanceName)))
+		assert.Nil(err)
+		assert.Equal(expLog, string(logContent))
+	}
+
+	// lines are filtered and truncated
+	filter, err := newLogFilter("", "line [89]")
+	assert.Nil(err)
+
+	assert.Nil(processes.SaveLogs(3, 10, filter, outputDir))
+
+	logContent, err := ioutil.ReadFile(filepath.Join(outputDir, "router.log"))
+	assert.Nil(err)
+	assert.Equal("router lin…[truncated 3 bytes]\nrouter lin…[truncated 3 bytes]\n", string(logContent))
+
+	// log file doesn't exist
+	processes = ProcessesSet{
+		&Process{
+			ID:      "myapp.unknown",
+			Status:  procStatusNotStarted,
+			name:    "unknown",
+			logFile: filepath.Join(logDir, "myapp.unknown.log"),
+		},
+	}
+
+	assert.EqualError(processes.SaveLogs(3, 0, nil, outputDir), "Failed to save some instances logs")
+}
+
+func TestTruncateLogLine(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	assert.Equal("short line", truncateLogLine("short line", 0))
+	assert.Equal("short line", truncateLogLine("short line", 10))
+	assert.Equal("short…[truncated 5 bytes]", truncateLogLine("short line", 5))
+
+	// multibyte characters aren't cut
+	assert.Equal("ab…[truncated 4 bytes]", truncateLogLine("abвг", 3))
+	assert.Equal("abв…[truncated 2 bytes]", truncateLogLine("abвг", 4))
+}
+
+func TestWriteLogMaxLineLength(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "logs")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	hugeLineLength := 5 * 1024 * 1024
+	hugeLine := strings.Repeat("x", hugeLineLength)
+
+	logLines := []string{
+		"line before",
+		hugeLine,
+		"line after 1",
+		"line after 2",
+	}
+
+	process := &Process{
+		ID:      "myapp.instance",
+		logFile: filepath.Join(tmpDir, "myapp.instance.log"),
+	}
+	logContent := strings.Join(logLines, "\n") + "\n"
+	assert.Nil(ioutil.WriteFile(process.logFile, []byte(logContent), 0644))
+
+	// all lines
+	var buf bytes.Buffer
+	assert.Nil(process.writeLog(&buf, false, len(logLines), 10, nil, nil))
+	assert.Equal(fmt.Sprintf(`line before
+xxxxxxxxxx…[truncated %d bytes]
+line after 1
+line after 2
+`, hugeLineLength-10), buf.String())
+
+	// huge line is counted as one line
+	buf.Reset()
+	assert.Nil(process.writeLog(&buf, false, 3, 10, nil, nil))
+	assert.Equal(fmt.Sprintf(`xxxxxxxxxx…[truncated %d bytes]
+line after 1
+line after 2
+`, hugeLineLength-10), buf.String())
+
+	buf.Reset()
+	assert.Nil(process.writeLog(&buf, false, 2, 10, nil, nil))
+	assert.Equal("line after 1\nline after 2\n", buf.String())
+
+	// no truncation
+	buf.Reset()
+	assert.Nil(process.writeLog(&buf, false, len(logLines), 0, nil, nil))
+	assert.Equal(logContent, buf.String())
+}
+
+func TestWriteLogCRLF(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "logs")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	process := &Process{
+		ID:      "myapp.instance",
+		logFile: filepath.Join(tmpDir, "myapp.instance.log"),
+	}
+	logContent := "line 1\r\nline 2\nline 3\r\nline 4\r\n"
+	assert.Nil(ioutil.WriteFile(process.logFile, []byte(logContent), 0644))
+
+	var buf bytes.Buffer
+	assert.Nil(process.writeLog(&buf, false, 4, 0, nil, nil))
+	assert.Equal("line 1\nline 2\nline 3\nline 4\n", buf.String())
+
+	buf.Reset()
+	assert.Nil(process.writeLog(&buf, false, 2, 0, nil, nil))
+	assert.Equal("line 3\nline 4\n", buf.String())
+}
+
+func TestWaitStopped(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "run")
+	if err != nil {
+		t.Fatalf("Failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// no PID file
+	process := &Process{
+		ID:      "myapp.instance-1",
+		pidFile: filepath.Join(tmpDir, "myapp.instance-1.pid"),
+	}
+	assert.Nil(process.WaitStopped(time.Second))
+	assert.Equal(procStatusNotStarted, process.Status)
+
+	// invalid PID file
+	assert.Nil(ioutil.WriteFile(process.pidFile, []byte("not-a-pid"), 0644))
+	assert.Contains(process.WaitStopped(300*time.Millisecond).Error(), "Failed to check process status")
+
+	// status check error is transient
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		os.Remove(process.pidFile)
+	}()
+
+	assert.Nil(process.WaitStopped(5 * time.Second))
+	assert.Equal(procStatusNotStarted, process.Status)
+}
+
+func TestStopNotRunning(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	// not running processes are skipped
+	processes := ProcessesSet{
+		&Process{ID: "myapp.instance-1", Status: procStatusStopped},
+		&Process{ID: "myapp.instance-2", Status: procStatusNotStarted},
+	}
+	assert.Nil(processes.Stop(false, time.Second))
+	assert.Nil(processes.Stop(true, 0))
+
+	processes.Add(&Process{
+		ID:     "myapp.instance-3",
+		Status: procStatusError,
+		Error:  fmt.Errorf("PID file exists with unknown format"),
+	})
+	assert.EqualError(processes.Stop(false, time.Second), "Failed to stop some instances")
+}
+
+func TestStartParallelism(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	// one process failure doesn't abort the others
+	var processes ProcessesSet
+	for i := 1; i <= 5; i++ {
+		processes.Add(&Process{
+			ID:         fmt.Sprintf("myapp.instance-%d", i),
+			Status:     procStatusNotStarted,
+			entrypoint: "not-exists.lua",
+		})
+	}
+
+	processes.Add(&Process{ID: "myapp.instance-6", Status: procStatusRunning})
+	processes.Add(&Process{
+		ID:     "myapp.instance-7",
+		Status: procStatusError,
+		Error:  fmt.Errorf("PID file exists with unknown format"),
+	})
+
+	for _, parallelism := range []int{1, 2, 10} {
+		assert.EqualError(processes.Start(true, time.Second, false, parallelism),
+			"Failed to start some instances", parallelism)
+	}
+}
+
+func TestSortResultsByID(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	results := []common.Result{
+		{ID: "myapp.router", Status: common.ResStatusOk},
+		{ID: "myapp-stateboard", Status: common.ResStatusOk},
+		{ID: "myapp.s1-master", Status: common.ResStatusFailed},
+		{ID: "myapp.s1-replica", Status: common.ResStatusOk},
+	}
+
+	sortResultsByID(results)
+
+	var ids []string
+	for _, res := range results {
+		ids = append(ids, res.ID)
+	}
+
+	assert.Equal([]string{"myapp-stateboard", "myapp.router", "myapp.s1-master", "myapp.s1-replica"}, ids)
+}
+
+func TestWriteLogFilter(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "logs")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	logLines := []string{
+		"main/101/init.lua E> first error",
+		"main/101/init.lua I> info",
+		"main/101/init.lua W> warning",
+		"stack traceback:",
+		"main/101/init.lua E> second error",
+	}
+
+	process := &Process{
+		ID:      "myapp.instance",
+		logFile: filepath.Join(tmpDir, "myapp.instance.log"),
+	}
+	assert.Nil(ioutil.WriteFile(process.logFile, []byte(strings.Join(logLines, "\n")+"\n"), 0644))
+
+	filter, err := newLogFilter("WARN", "")
+	assert.Nil(err)
+
+	var buf bytes.Buffer
+	assert.Nil(process.writeLog(&buf, false, len(logLines), 0, filter, nil))
+	assert.Equal("main/101/init.lua E> first error\n"+
+		"main/101/init.lua W> warning\n"+
+		"main/101/init.lua E> second error\n", buf.String())
+
+	// lines count bounds lines before filtering
+	buf.Reset()
+	assert.Nil(process.writeLog(&buf, false, 3, 0, filter, nil))
+	assert.Equal("main/101/init.lua W> warning\n"+
+		"main/101/init.lua E> second error\n", buf.String())
+}
+
+func TestWriteLogFollow(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "logs")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	process := &Process{
+		ID:      "myapp.instance",
+		logFile: filepath.Join(tmpDir, "myapp.instance.log"),
+	}
+	assert.Nil(ioutil.WriteFile(process.logFile, []byte("line 1\nline 2\n"), 0644))
+
+	var buf bytes.Buffer
+	done := make(chan struct{})
+	errCh := make(chan error)
+
+	go func() {
+		errCh <- process.writeLog(&buf, true, 1, 0, nil, done)
+	}()
+
+	time.Sleep(200 * time.Millisecond)
+
+	logFile, err := os.OpenFile(process.logFile, os.O_APPEND|os.O_WRONLY, 0644)
+	assert.Nil(err)
+	_, err = logFile.WriteString("line 3\n")
+	assert.Nil(err)
+	assert.Nil(logFile.Close())
+
+	time.Sleep(500 * time.Millisecond)
+
+	// following is stopped
+	close(done)
+
+	select {
+	case err := <-errCh:
+		assert.Nil(err)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Following the log wasn't stopped")
+	}
+
+	assert.Equal("line 2\nline 3\n", buf.String())
+}
+
+func TestCleanDryRun(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "clean")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	ctx := &context.Ctx{}
+	ctx.Project.Name = "myapp"
+	ctx.Running.RunDir = filepath.Join(tmpDir, "run")
+	ctx.Running.DataDir = filepath.Join(tmpDir, "data")
+	ctx.Running.LogDir = filepath.Join(tmpDir, "log")
+
+	process := NewInstanceProcess(ctx, "instance-1")
+	assert.Equal(procStatusNotStarted, process.Status)
+
+	assert.Nil(os.MkdirAll(ctx.Running.LogDir, 0755))
+	assert.Nil(os.MkdirAll(filepath.Join(process.workDir, "snap"), 0755))
+	assert.Nil(ioutil.WriteFile(process.logFile, []byte("log\n"), 0644))
+
+	runningProcess := NewInstanceProcess(ctx, "instance-2")
+	runningProcess.Status = procStatusRunning
+	assert.Nil(ioutil.WriteFile(runningProcess.logFile, []byte("log\n"), 0644))
+
+	processes := ProcessesSet{process, runningProcess}
+
+	var buf bytes.Buffer
+	assert.Nil(processes.CleanDryRun(&buf))
+	assert.Equal(fmt.Sprintf("%s\n%s\n", process.logFile, process.workDir), buf.String())
+
+	// nothing is removed
+	_, err = os.Stat(process.logFile)
+	assert.Nil(err)
+	_, err = os.Stat(filepath.Join(process.workDir, "snap"))
+	assert.Nil(err)
+}
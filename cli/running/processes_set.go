@@ -2,6 +2,12 @@ package running
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/apex/log"
@@ -14,48 +20,80 @@ func (set *ProcessesSet) Add(processes ...*Process) {
 	*set = append(*set, processes...)
 }
 
-func startProcess(process *Process, daemonize bool, timeout time.Duration, resCh common.ResChan) {
+// spawnProcess starts the process. In daemonize mode, it waits
+// until the process is ready (or its console socket is available).
+// The result is returned if the process start is finished,
+// nil means that the process is started in foreground
+func spawnProcess(process *Process, daemonize bool, timeout time.Duration, waitSocket bool) *common.Result {
 	if process.Status == procStatusError {
-		resCh <- common.Result{
+		return &common.Result{
 			ID:     process.ID,
 			Status: common.ResStatusFailed,
 			Error:  process.Error,
 		}
-		return
 	}
 
 	if process.Status == procStatusRunning {
-		resCh <- common.Result{
+		return &common.Result{
 			ID:     process.ID,
 			Status: common.ResStatusSkipped,
 			Error:  fmt.Errorf("Process is already running"),
 		}
-		return
 	}
 
 	if err := process.Start(daemonize); err != nil {
-		resCh <- common.Result{
+		return &common.Result{
 			ID:     process.ID,
 			Status: common.ResStatusFailed,
 			Error:  fmt.Errorf("Failed to start: %s", err),
 		}
-		return
+	}
+
+	if daemonize && waitSocket {
+		if err := process.WaitSocket(timeout); err != nil {
+			return &common.Result{
+				ID:     process.ID,
+				Status: common.ResStatusFailed,
+				Error:  fmt.Errorf("Failed to wait console socket is available: %s", err),
+			}
+		}
+
+		return &common.Result{
+			ID:     process.ID,
+			Status: common.ResStatusOk,
+			Messages: []common.ResultMessage{
+				common.GetInfoMessage("Console socket %s is available", process.consoleSock),
+			},
+		}
 	}
 
 	if daemonize {
 		if err := process.WaitReady(timeout); err != nil {
-			resCh <- common.Result{
+			return &common.Result{
 				ID:     process.ID,
 				Status: common.ResStatusFailed,
 				Error:  fmt.Errorf("Failed to wait process is ready: %s", err),
 			}
-			return
 		}
 
-		resCh <- common.Result{
+		return &common.Result{
 			ID:     process.ID,
 			Status: common.ResStatusOk,
 		}
+	}
+
+	return nil
+}
+
+// startProcess starts the process and frees the worker slot taken from workersCh
+// when the process is started. Process started in foreground is waited for exit
+func startProcess(process *Process, daemonize bool, timeout time.Duration, waitSocket bool,
+	workersCh chan struct{}, resCh common.ResChan) {
+	res := spawnProcess(process, daemonize, timeout, waitSocket)
+	<-workersCh
+
+	if res != nil {
+		resCh <- *res
 		return
 	}
 
@@ -73,11 +111,26 @@ func startProcess(process *Process, daemonize bool, timeout time.Duration, resCh
 	}
 }
 
-func (set *ProcessesSet) Start(daemonize bool, timeout time.Duration) error {
-	resCh := make(chan common.Result)
+func logStartResult(res common.Result) {
+	log.Infof(res.String())
+	for _, message := range res.Messages {
+		log.Infof("%s: %s", res.ID, message.Text)
+	}
+}
+
+// Start starts the processes concurrently, at most parallelism processes
+// are being started at the same time.
+// In daemonize mode, results are reported sorted by the instance names
+// after all processes are started, one process failure doesn't abort the others.
+// Otherwise, processes exits are reported as they happen
+func (set *ProcessesSet) Start(daemonize bool, timeout time.Duration, waitSocket bool, parallelism int) error {
+	resCh := make(common.ResChan, len(*set))
+	workersCh := make(chan struct{}, parallelism)
 
 	for _, process := range *set {
-		go startProcess(process, daemonize, timeout, resCh)
+		// take the worker slot, it's freed when the process is started
+		workersCh <- struct{}{}
+		go startProcess(process, daemonize, timeout, waitSocket, workersCh, resCh)
 
 		// wait for process to print logs
 		if !daemonize {
@@ -85,27 +138,39 @@ func (set *ProcessesSet) Start(daemonize bool, timeout time.Duration) error {
 		}
 	}
 
-	var errors []error
+	if !daemonize {
+		// wait for all processes exit
+		for i := 0; i < len(*set); i++ {
+			res := <-resCh
+			logStartResult(res)
 
-	// wait for all processes result
-	for i := 0; i < len(*set); i++ {
-		select {
-		case res := <-resCh:
-			log.Infof(res.String())
 			if res.Error != nil {
-				if !daemonize {
-					log.Errorf("%s: %s", res.ID, res.Error)
-				} else {
-					errors = append(errors, res.FormatError())
-				}
+				log.Errorf("%s: %s", res.ID, res.Error)
 			}
 		}
-	}
 
-	if !daemonize {
 		return fmt.Errorf("All instances exited")
 	}
 
+	results := make([]common.Result, 0, len(*set))
+
+	// wait for all processes result
+	for i := 0; i < len(*set); i++ {
+		results = append(results, <-resCh)
+	}
+
+	sortResultsByID(results)
+
+	var errors []error
+
+	for _, res := range results {
+		logStartResult(res)
+
+		if res.Error != nil {
+			errors = append(errors, res.FormatError())
+		}
+	}
+
 	if len(errors) > 0 {
 		for _, err := range errors {
 			log.Errorf("%s", err)
@@ -116,47 +181,124 @@ func (set *ProcessesSet) Start(daemonize bool, timeout time.Duration) error {
 	return nil
 }
 
-func (set *ProcessesSet) Stop(force bool) error {
-	var errors []error
-	var warnings []error
+// sortResultsByID sorts results by the instances names
+// to make the output stable
+func sortResultsByID(results []common.Result) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].ID < results[j].ID
+	})
+}
 
-	for _, process := range *set {
-		var res common.Result
+func stopProcess(process *Process, force bool, timeout time.Duration, resCh common.ResChan) {
+	if process.Status == procStatusError {
+		resCh <- common.Result{
+			ID:     process.ID,
+			Status: common.ResStatusFailed,
+			Error:  process.Error,
+		}
+		return
+	}
 
-		if process.Status == procStatusError {
-			res = common.Result{
-				ID:     process.ID,
-				Status: common.ResStatusFailed,
-				Error:  process.Error,
-			}
-		} else if process.Status == procStatusStopped || process.Status == procStatusNotStarted {
-			res = common.Result{
-				ID:     process.ID,
-				Status: common.ResStatusSkipped,
-				Error:  fmt.Errorf("Process is not running"),
-			}
-		} else if err := process.Stop(force); err != nil {
-			res = common.Result{
-				ID:     process.ID,
-				Status: common.ResStatusFailed,
-				Error:  fmt.Errorf("Failed to stop: %s", err),
-			}
-		} else {
-			res = common.Result{
-				ID:     process.ID,
-				Status: common.ResStatusOk,
-			}
+	if process.Status == procStatusStopped || process.Status == procStatusNotStarted {
+		resCh <- common.Result{
+			ID:     process.ID,
+			Status: common.ResStatusSkipped,
+			Error:  fmt.Errorf("Process is not running"),
 		}
+		return
+	}
 
-		if res.Status == common.ResStatusFailed {
-			errors = append(errors, res.FormatError())
+	if err := process.Stop(force); err != nil {
+		resCh <- common.Result{
+			ID:     process.ID,
+			Status: common.ResStatusFailed,
+			Error:  fmt.Errorf("Failed to stop: %s", err),
 		}
+		return
+	}
+
+	if force {
+		timeout = killWaitTimeout
+	}
 
-		if res.Status == common.ResStatusSkipped {
-			warnings = append(warnings, res.FormatError())
+	err := process.WaitStopped(timeout)
+	if err == nil {
+		resCh <- common.Result{
+			ID:     process.ID,
+			Status: common.ResStatusOk,
 		}
+		return
+	}
 
-		log.Infof(res.String())
+	if force || !process.IsRunning() {
+		resCh <- common.Result{
+			ID:     process.ID,
+			Status: common.ResStatusFailed,
+			Error:  fmt.Errorf("Failed to wait for process exit: %s", err),
+		}
+		return
+	}
+
+	// escalate to SIGKILL
+	if err := process.Kill(); err != nil {
+		resCh <- common.Result{
+			ID:     process.ID,
+			Status: common.ResStatusFailed,
+			Error:  fmt.Errorf("Process didn't exit in %s, failed to kill: %s", timeout, err),
+		}
+		return
+	}
+
+	if err := process.WaitStopped(killWaitTimeout); err != nil {
+		resCh <- common.Result{
+			ID:     process.ID,
+			Status: common.ResStatusFailed,
+			Error:  fmt.Errorf("Failed to wait for process exit after SIGKILL: %s", err),
+		}
+		return
+	}
+
+	resCh <- common.Result{
+		ID:     process.ID,
+		Status: common.ResStatusOk,
+		Messages: []common.ResultMessage{
+			common.GetWarnMessage("Process didn't exit in %s, SIGKILL was sent", timeout),
+		},
+	}
+}
+
+// Stop sends SIGTERM (SIGKILL if force is set) to the processes
+// and waits for them to exit. Processes that don't exit in the timeout
+// after SIGTERM are killed. Zero timeout means no timeout
+func (set *ProcessesSet) Stop(force bool, timeout time.Duration) error {
+	resCh := make(common.ResChan)
+
+	for _, process := range *set {
+		go stopProcess(process, force, timeout, resCh)
+	}
+
+	var errors []error
+	var warnings []error
+	var killedIDs []string
+
+	// wait for all processes result
+	for i := 0; i < len(*set); i++ {
+		select {
+		case res := <-resCh:
+			if res.Status == common.ResStatusFailed {
+				errors = append(errors, res.FormatError())
+			}
+
+			if res.Status == common.ResStatusSkipped {
+				warnings = append(warnings, res.FormatError())
+			}
+
+			log.Infof(res.String())
+			for _, message := range res.Messages {
+				log.Warnf("%s: %s", res.ID, message.Text)
+				killedIDs = append(killedIDs, res.ID)
+			}
+		}
 	}
 
 	if len(warnings) > 0 {
@@ -165,6 +307,11 @@ func (set *ProcessesSet) Stop(force bool) error {
 		}
 	}
 
+	if len(killedIDs) > 0 {
+		sort.Strings(killedIDs)
+		log.Warnf("Instances required SIGKILL: %s", strings.Join(killedIDs, ", "))
+	}
+
 	if len(errors) > 0 {
 		for _, err := range errors {
 			log.Errorf("%s", err)
@@ -175,15 +322,50 @@ func (set *ProcessesSet) Stop(force bool) error {
 	return nil
 }
 
-func (set *ProcessesSet) Status() error {
+func (set *ProcessesSet) Status(ageWarn time.Duration) error {
 	var errors []string
+	var youngProcesses []string
+	var hungProcesses []string
+	var unreachableProcesses []string
+
+	now := time.Now()
 
 	for _, process := range *set {
 		if process.Status == procStatusError {
 			errors = append(errors, fmt.Sprintf("%s: %s", process.ID, process.Error))
 		}
 
-		log.Infof(getStatusStr(process))
+		if process.Status == procStatusHung {
+			hungProcesses = append(hungProcesses, process.ID)
+		}
+
+		if process.Status == procStatusUnreachable {
+			unreachableProcesses = append(unreachableProcesses, process.ID)
+		}
+
+		statusStr := getStatusStr(process)
+
+		if ageWarn > 0 && process.IsYoung(ageWarn, now) {
+			statusStr = fmt.Sprintf("%s %s", statusStr, getYoungWarnStr(process, now))
+			youngProcesses = append(youngProcesses, process.ID)
+		}
+
+		log.Infof(statusStr)
+	}
+
+	if len(youngProcesses) > 0 {
+		log.Warnf("Some instances are running less than %s: %s",
+			ageWarn, strings.Join(youngProcesses, ", "))
+	}
+
+	if len(hungProcesses) > 0 {
+		log.Warnf("Some instances are alive, but their console sockets don't respond: %s",
+			strings.Join(hungProcesses, ", "))
+	}
+
+	if len(unreachableProcesses) > 0 {
+		log.Warnf("Some instances are alive, but their console sockets can't be dialed: %s",
+			strings.Join(unreachableProcesses, ", "))
 	}
 
 	if len(errors) > 0 {
@@ -196,6 +378,45 @@ func (set *ProcessesSet) Status() error {
 	return nil
 }
 
+// checkRunning checks that all processes are running.
+// If ageWarn is set, processes that are running less than ageWarn
+// are considered failed too (e.g. they can be in a crash loop)
+func (set *ProcessesSet) checkRunning(ageWarn time.Duration, now time.Time) error {
+	var notRunningProcesses []string
+	var youngProcesses []string
+
+	for _, process := range *set {
+		if !process.IsRunning() {
+			notRunningProcesses = append(notRunningProcesses, process.ID)
+		} else if ageWarn > 0 && process.IsYoung(ageWarn, now) {
+			youngProcesses = append(youngProcesses, process.ID)
+		}
+	}
+
+	if len(notRunningProcesses) > 0 {
+		return fmt.Errorf("Some instances aren't running: %s", strings.Join(notRunningProcesses, ", "))
+	}
+
+	if len(youngProcesses) > 0 {
+		return fmt.Errorf("Some instances are running less than %s: %s",
+			ageWarn, strings.Join(youngProcesses, ", "))
+	}
+
+	return nil
+}
+
+func (set *ProcessesSet) RunningCount() int {
+	count := 0
+
+	for _, process := range *set {
+		if process.IsRunning() {
+			count++
+		}
+	}
+
+	return count
+}
+
 func clearProcessData(process *Process, resCh common.ResChan) {
 	if process.Status == procStatusError {
 		resCh <- common.Result{
@@ -259,14 +480,44 @@ func (set *ProcessesSet) Clean() error {
 	return nil
 }
 
-func getProcessLogs(process *Process, follow bool, n int, resCh common.ResChan) {
+// CleanDryRun writes the files that would be removed by Clean to w.
+// Running instances aren't cleaned, so their files aren't listed
+func (set *ProcessesSet) CleanDryRun(w io.Writer) error {
+	log.Infof("Dry run: instances files aren't removed")
+
+	for _, process := range *set {
+		if process.Status == procStatusError {
+			log.Warnf("%s: %s", process.ID, process.Error)
+			continue
+		}
+
+		if process.Status == procStatusRunning {
+			log.Warnf("%s: Instance is running, its files wouldn't be removed", process.ID)
+			continue
+		}
+
+		paths, err := process.getExistingCleanPaths()
+		if err != nil {
+			return fmt.Errorf("%s: Failed to check files: %s", process.ID, err)
+		}
+
+		for _, path := range paths {
+			fmt.Fprintln(w, path)
+		}
+	}
+
+	return nil
+}
+
+func getProcessLogs(process *Process, follow bool, n int, maxLineLength int, filter *logFilter,
+	done <-chan struct{}, resCh common.ResChan) {
 	if process.Status == procStatusError {
 		resCh <- common.Result{
 			ID:     process.ID,
 			Status: common.ResStatusFailed,
 			Error:  process.Error,
 		}
-	} else if err := process.Log(follow, n); err != nil {
+	} else if err := process.Log(follow, n, maxLineLength, filter, done); err != nil {
 		resCh <- common.Result{
 			ID:     process.ID,
 			Status: common.ResStatusFailed,
@@ -280,11 +531,20 @@ func getProcessLogs(process *Process, follow bool, n int, resCh common.ResChan)
 	}
 }
 
-func (set *ProcessesSet) Log(follow bool, lines int) error {
+func (set *ProcessesSet) Log(follow bool, lines int, maxLineLength int, filter *logFilter) error {
 	resCh := make(chan common.Result)
+	done := make(chan struct{})
+
+	// stop following logs on Ctrl-C
+	var sigCh chan os.Signal
+	if follow {
+		sigCh = make(chan os.Signal, 1)
+		signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(sigCh)
+	}
 
 	for _, process := range *set {
-		go getProcessLogs(process, follow, lines, resCh)
+		go getProcessLogs(process, follow, lines, maxLineLength, filter, done, resCh)
 
 		// wait for process to print logs
 		time.Sleep(100 * time.Millisecond)
@@ -293,9 +553,10 @@ func (set *ProcessesSet) Log(follow bool, lines int) error {
 	var errors []error
 
 	// wait for all processes result
-	for i := 0; i < len(*set); i++ {
+	for i := 0; i < len(*set); {
 		select {
 		case res := <-resCh:
+			i++
 			log.Infof(res.String())
 			if res.Error != nil {
 				if follow {
@@ -304,6 +565,9 @@ func (set *ProcessesSet) Log(follow bool, lines int) error {
 					errors = append(errors, res.FormatError())
 				}
 			}
+		case <-sigCh:
+			close(done)
+			sigCh = nil
 		}
 	}
 
@@ -316,3 +580,44 @@ func (set *ProcessesSet) Log(follow bool, lines int) error {
 
 	return nil
 }
+
+// SaveLogs writes last lines of each process log to the separate file
+// in the output directory instead of printing them
+func (set *ProcessesSet) SaveLogs(lines int, maxLineLength int, filter *logFilter, outputDir string) error {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("Failed to create output directory: %s", err)
+	}
+
+	var errors []error
+
+	for _, process := range *set {
+		res := common.Result{
+			ID: process.ID,
+		}
+
+		if process.Status == procStatusError {
+			res.Status = common.ResStatusFailed
+			res.Error = process.Error
+		} else if outputFilePath, err := process.SaveLog(lines, maxLineLength, filter, outputDir); err != nil {
+			res.Status = common.ResStatusFailed
+			res.Error = fmt.Errorf("Failed to save logs: %s", err)
+		} else {
+			res.Status = common.ResStatusOk
+			log.Debugf("%s logs are written to %s", process.ID, outputFilePath)
+		}
+
+		log.Infof(res.String())
+		if res.Error != nil {
+			errors = append(errors, res.FormatError())
+		}
+	}
+
+	if len(errors) > 0 {
+		for _, err := range errors {
+			log.Errorf("%s", err)
+		}
+		return fmt.Errorf("Failed to save some instances logs")
+	}
+
+	return nil
+}
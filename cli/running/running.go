@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"time"
 
 	"github.com/apex/log"
 	"github.com/tarantool/cartridge-cli/cli/common"
@@ -54,6 +56,35 @@ func FillCtx(ctx *context.Ctx, args []string) error {
 		log.Warnf("Specified instances are ignored due to stateboard-only flag")
 	}
 
+	if len(ctx.Running.Instances) > 0 && !ctx.Running.StateboardOnly && !ctx.Running.AllowUnknownInstances {
+		if err := checkInstancesConfigured(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkInstancesConfigured checks that specified instances are described
+// in the instances configuration to catch typos in the instance names.
+// The check is skipped if the configuration doesn't exist or can't be read
+func checkInstancesConfigured(ctx *context.Ctx) error {
+	if _, err := os.Stat(ctx.Running.ConfPath); os.IsNotExist(err) {
+		log.Debugf("Instances configuration %s doesn't exist, specified instances aren't checked",
+			ctx.Running.ConfPath)
+		return nil
+	}
+
+	configuredInstances, err := CollectInstancesFromConf(ctx)
+	if err != nil {
+		log.Warnf("Failed to check that specified instances are configured: %s", err)
+		return nil
+	}
+
+	if err := common.CheckInstancesConfigured(ctx.Running.Instances, configuredInstances); err != nil {
+		return fmt.Errorf("%s (use --allow-unknown flag to skip this check)", err)
+	}
+
 	return nil
 }
 
@@ -64,6 +95,10 @@ func Start(ctx *context.Ctx) error {
 		return fmt.Errorf("Tarantool is required to start the application")
 	}
 
+	if ctx.Running.EnvFilesVars, err = readEnvFiles(ctx.Running.EnvFiles); err != nil {
+		return err
+	}
+
 	if !ctx.Running.StateboardOnly && len(ctx.Running.Instances) == 0 {
 		ctx.Running.Instances, err = CollectInstancesFromConf(ctx)
 		if err != nil {
@@ -71,6 +106,15 @@ func Start(ctx *context.Ctx) error {
 		}
 	}
 
+	if err := checkInstanceOptions(ctx, getLookupEnvFunc(ctx)); err != nil {
+		return err
+	}
+
+	credential, err := getRunCredential(ctx.Running.RunUser, ctx.Running.RunGroup)
+	if err != nil {
+		return err
+	}
+
 	processes, err := collectProcesses(ctx)
 	if err != nil {
 		return fmt.Errorf("Failed to collect instances processes: %s", err)
@@ -80,16 +124,32 @@ func Start(ctx *context.Ctx) error {
 		return fmt.Errorf("No instances to start")
 	}
 
+	for _, process := range *processes {
+		process.credential = credential
+	}
+
 	if _, err := os.Stat(filepath.Join(ctx.Running.AppDir, rocksDir)); os.IsNotExist(err) {
 		log.Warn(rocksDirMissedWarn)
 	} else if err != nil {
 		log.Warnf("Failed to check .rocks directory: %s", err)
 	}
 
-	if err := processes.Start(ctx.Running.Daemonize, ctx.Running.StartTimeout); err != nil {
+	parallelism := ctx.Running.StartParallelism
+	if parallelism <= 0 {
+		parallelism = runtime.NumCPU()
+	}
+
+	err = processes.Start(ctx.Running.Daemonize, ctx.Running.StartTimeout, ctx.Running.WaitSocket, parallelism)
+	if err != nil {
 		return err
 	}
 
+	if ctx.Running.WaitHealthy {
+		if err := processes.WaitHealthy(ctx.Running.WaitTimeout); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -112,13 +172,45 @@ func Stop(ctx *context.Ctx) error {
 		return fmt.Errorf("No instances specified")
 	}
 
-	if err := processes.Stop(ctx.Running.StopForced); err != nil {
+	if err := processes.Stop(ctx.Running.StopForced, ctx.Running.StopTimeout); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// Restart stops the specified instances, waits for them to exit
+// and starts them again
+func Restart(ctx *context.Ctx) error {
+	var err error
+
+	if err := common.CheckTarantoolBinaries(); err != nil {
+		return fmt.Errorf("Tarantool is required to restart the application")
+	}
+
+	if !ctx.Running.StateboardOnly && len(ctx.Running.Instances) == 0 {
+		ctx.Running.Instances, err = CollectInstancesFromConf(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed to get configured instances from conf: %s", err)
+		}
+	}
+
+	processes, err := collectProcesses(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to collect instances processes: %s", err)
+	}
+
+	if len(*processes) == 0 {
+		return fmt.Errorf("No instances specified")
+	}
+
+	if err := processes.Stop(ctx.Running.StopForced, ctx.Running.StopTimeout); err != nil {
+		return err
+	}
+
+	return Start(ctx)
+}
+
 func Status(ctx *context.Ctx) error {
 	var err error
 
@@ -138,10 +230,58 @@ func Status(ctx *context.Ctx) error {
 		return fmt.Errorf("No instances specified")
 	}
 
-	if err := processes.Status(); err != nil {
+	if ctx.Running.PidFileCheck {
+		return processes.PidFilesCheck()
+	}
+
+	if ctx.Running.SocketCheck {
+		processes.CheckSockets(socketCheckTimeout)
+	}
+
+	if ctx.Running.StatusFormat == StatusFormatJSON {
+		err = processes.StatusJSON(os.Stdout, ctx.Running.StatusJSONCompact, ctx.Running.AgeWarn)
+	} else {
+		err = processes.Status(ctx.Running.AgeWarn)
+	}
+
+	if err != nil {
 		return err
 	}
 
+	if ctx.Running.GroupTags {
+		instancesTags, err := CollectInstancesTagsFromConf(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed to get instances tags from conf: %s", err)
+		}
+
+		processes.TagsStatus(instancesTags)
+	}
+
+	if ctx.Running.StatusExitCode {
+		if err := processes.checkRunning(ctx.Running.AgeWarn, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if ctx.Running.CheckInstancesExpected {
+		getRunningCount := func() (int, error) {
+			processes, err := collectProcesses(ctx)
+			if err != nil {
+				return 0, fmt.Errorf("Failed to collect instances processes: %s", err)
+			}
+
+			return processes.RunningCount(), nil
+		}
+
+		err := checkInstancesExpected(
+			processes.RunningCount(), ctx.Running.InstancesExpected,
+			getRunningCount, ctx.Running.WaitForExpected, expectedCheckInterval,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -164,7 +304,20 @@ func Log(ctx *context.Ctx) error {
 		return fmt.Errorf("No instances specified")
 	}
 
-	if err := processes.Log(ctx.Running.LogFollow, ctx.Running.LogLines); err != nil {
+	filter, err := newLogFilter(ctx.Running.LogLevel, ctx.Running.LogGrep)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Running.LogOutputDir != "" {
+		return processes.SaveLogs(
+			ctx.Running.LogLines, ctx.Running.LogMaxLineLength, filter, ctx.Running.LogOutputDir,
+		)
+	}
+
+	if err := processes.Log(
+		ctx.Running.LogFollow, ctx.Running.LogLines, ctx.Running.LogMaxLineLength, filter,
+	); err != nil {
 		return err
 	}
 
@@ -190,6 +343,10 @@ func Clean(ctx *context.Ctx) error {
 		return fmt.Errorf("No instances specified")
 	}
 
+	if ctx.Running.CleanDryRun {
+		return processes.CleanDryRun(os.Stdout)
+	}
+
 	if err := processes.Clean(); err != nil {
 		return err
 	}
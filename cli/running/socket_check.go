@@ -0,0 +1,90 @@
+package running
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/apex/log"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+)
+
+const (
+	socketCheckTimeout = 1 * time.Second
+)
+
+// socketDialError is returned if the console socket can't be dialed
+// (e.g. it doesn't exist or nobody listens on it)
+type socketDialError struct {
+	err error
+}
+
+func (e *socketDialError) Error() string {
+	return fmt.Sprintf("Failed to dial: %s", e.err)
+}
+
+// pingConsoleSock sends an empty statement to the instance console
+// and waits for the response. The whole check should fit in the timeout
+func pingConsoleSock(consoleSock string, timeout time.Duration) error {
+	conn, err := net.DialTimeout("unix", consoleSock, timeout)
+	if err != nil {
+		return &socketDialError{err}
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(timeout)
+	conn.SetDeadline(deadline)
+
+	greeting := make([]byte, 1024)
+	if _, err := conn.Read(greeting); err != nil {
+		return fmt.Errorf("Failed to read Tarantool greeting: %s", err)
+	}
+
+	if _, err := conn.Write([]byte("\n")); err != nil {
+		return fmt.Errorf("Failed to send ping: %s", err)
+	}
+
+	readTimeout := time.Until(deadline)
+	if readTimeout <= 0 {
+		return fmt.Errorf("Timeout is reached")
+	}
+
+	if _, err := common.ReadFromConnYAML(conn, common.ConnOpts{ReadTimeout: readTimeout}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CheckSockets pings console sockets of the running processes.
+// Processes whose sockets accept the connection, but don't respond in time
+// are marked as hung, processes whose sockets can't be dialed are marked as unreachable
+func (set *ProcessesSet) CheckSockets(timeout time.Duration) {
+	var wg sync.WaitGroup
+
+	for _, process := range *set {
+		if !process.IsRunning() {
+			continue
+		}
+
+		wg.Add(1)
+		go func(process *Process) {
+			defer wg.Done()
+
+			err := pingConsoleSock(process.consoleSock, timeout)
+			if _, ok := err.(*socketDialError); ok {
+				log.Debugf("%s: Console socket %s is unreachable: %s", process.ID, process.consoleSock, err)
+
+				process.Status = procStatusUnreachable
+			} else if err != nil {
+				log.Debugf("%s: Console socket %s doesn't respond: %s", process.ID, process.consoleSock, err)
+
+				process.Status = procStatusHung
+			}
+		}(process)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,138 @@
+package running
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testGreeting = "Tarantool 2.5.1 (Lua console)\ntype 'help' for interactive help\n"
+)
+
+// startConsoleSock starts listening on the socket and serves connections.
+// If responsive is false, greeting is sent, but statements are never answered
+func startConsoleSock(t *testing.T, sockPath string, responsive bool) net.Listener {
+	listener, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Fatalf("Failed to listen socket: %s", err)
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(conn net.Conn) {
+				defer conn.Close()
+
+				if _, err := conn.Write([]byte(testGreeting)); err != nil {
+					return
+				}
+
+				reader := bufio.NewReader(conn)
+				if _, err := reader.ReadString('\n'); err != nil {
+					return
+				}
+
+				if responsive {
+					conn.Write([]byte("---\n...\n"))
+				} else {
+					time.Sleep(time.Second)
+				}
+			}(conn)
+		}
+	}()
+
+	return listener
+}
+
+func TestCheckSockets(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	runDir, err := ioutil.TempDir("", "run")
+	assert.Nil(err)
+	defer os.RemoveAll(runDir)
+
+	aliveSock := filepath.Join(runDir, "myapp.alive.control")
+	hungSock := filepath.Join(runDir, "myapp.hung.control")
+
+	aliveListener := startConsoleSock(t, aliveSock, true)
+	defer aliveListener.Close()
+
+	hungListener := startConsoleSock(t, hungSock, false)
+	defer hungListener.Close()
+
+	processes := ProcessesSet{
+		&Process{ID: "myapp.alive", Status: procStatusRunning, consoleSock: aliveSock},
+		&Process{ID: "myapp.hung", Status: procStatusRunning, consoleSock: hungSock},
+		&Process{
+			ID: "myapp.no-sock", Status: procStatusRunning,
+			consoleSock: filepath.Join(runDir, "myapp.no-sock.control"),
+		},
+		&Process{
+			ID: "myapp.stopped", Status: procStatusStopped,
+			consoleSock: filepath.Join(runDir, "myapp.stopped.control"),
+		},
+	}
+
+	processes.CheckSockets(200 * time.Millisecond)
+
+	assert.Equal(procStatusRunning, processes[0].Status)
+	assert.True(processes[0].IsRunning())
+
+	assert.Equal(procStatusHung, processes[1].Status)
+	assert.False(processes[1].IsRunning())
+
+	assert.Equal(procStatusUnreachable, processes[2].Status)
+	assert.False(processes[2].IsRunning())
+	assert.Equal(procStatusStopped, processes[3].Status)
+
+	assert.Equal(1, processes.RunningCount())
+}
+
+func TestPingConsoleSock(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	runDir, err := ioutil.TempDir("", "run")
+	assert.Nil(err)
+	defer os.RemoveAll(runDir)
+
+	aliveSock := filepath.Join(runDir, "alive.control")
+	hungSock := filepath.Join(runDir, "hung.control")
+
+	aliveListener := startConsoleSock(t, aliveSock, true)
+	defer aliveListener.Close()
+
+	hungListener := startConsoleSock(t, hungSock, false)
+	defer hungListener.Close()
+
+	assert.Nil(pingConsoleSock(aliveSock, 200*time.Millisecond))
+
+	start := time.Now()
+	err = pingConsoleSock(hungSock, 200*time.Millisecond)
+	assert.NotNil(err)
+	assert.True(time.Since(start) < time.Second)
+
+	err = pingConsoleSock(hungSock, 200*time.Millisecond)
+	_, isDialErr := err.(*socketDialError)
+	assert.False(isDialErr)
+
+	err = pingConsoleSock(filepath.Join(runDir, "none.control"), 200*time.Millisecond)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Failed to dial")
+	_, isDialErr = err.(*socketDialError)
+	assert.True(isDialErr)
+}
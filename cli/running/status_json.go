@@ -0,0 +1,124 @@
+package running
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	StatusFormatText = "text"
+	StatusFormatJSON = "json"
+
+	// StatusFormatTable is the alias of the text format
+	StatusFormatTable = "table"
+)
+
+var (
+	// statusNames are the statuses in the JSON output,
+	// the instance status that can't be got is reported as FAILED
+	statusNames = map[ProcStatusType]string{
+		procStatusError:       "FAILED",
+		procStatusNotStarted:  "NOT STARTED",
+		procStatusRunning:     "RUNNING",
+		procStatusStopped:     "STOPPED",
+		procStatusHung:        "HUNG",
+		procStatusUnreachable: "UNREACHABLE",
+	}
+)
+
+// processStatusJSON is the instance status in the JSON output.
+// Fields are encoded in the declaration order, so the output is stable
+type processStatusJSON struct {
+	ID            string `json:"id"`
+	Status        string `json:"status"`
+	PID           int    `json:"pid,omitempty"`
+	UptimeSeconds int64  `json:"uptime_seconds,omitempty"`
+	Warn          bool   `json:"warn,omitempty"`
+	Error         string `json:"error,omitempty"`
+	PidFile       string `json:"pid_file,omitempty"`
+	ConsoleSock   string `json:"console_sock,omitempty"`
+}
+
+// CheckStatusFormat checks that the status output format is supported
+func CheckStatusFormat(format string) error {
+	switch format {
+	case StatusFormatText, StatusFormatTable, StatusFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("Unknown format %q. Supported formats are %s and %s",
+			format, StatusFormatText, StatusFormatJSON)
+	}
+}
+
+// getProcessStatusJSON returns the process status.
+// Warn is set if the process is running less than ageWarn (see --age-warn)
+func getProcessStatusJSON(process *Process, ageWarn time.Duration, now time.Time) processStatusJSON {
+	status := processStatusJSON{
+		ID:          process.ID,
+		Status:      statusNames[process.Status],
+		PidFile:     process.pidFile,
+		ConsoleSock: process.consoleSock,
+	}
+
+	if status.Status == "" {
+		status.Status = fmt.Sprintf("Status %d", process.Status)
+	}
+
+	if process.IsRunning() {
+		status.PID = process.pid
+		status.UptimeSeconds = int64(process.Uptime(now) / time.Second)
+	}
+
+	if ageWarn > 0 && process.IsYoung(ageWarn, now) {
+		status.Warn = true
+	}
+
+	if process.Error != nil {
+		status.Error = process.Error.Error()
+	}
+
+	return status
+}
+
+// getStatuses returns processes status in the set order
+func (set *ProcessesSet) getStatuses(ageWarn time.Duration, now time.Time) []processStatusJSON {
+	statuses := make([]processStatusJSON, 0, len(*set))
+
+	for _, process := range *set {
+		statuses = append(statuses, getProcessStatusJSON(process, ageWarn, now))
+	}
+
+	return statuses
+}
+
+// writeStatusJSON writes processes status to w
+func (set *ProcessesSet) writeStatusJSON(w io.Writer, compact bool, ageWarn time.Duration, now time.Time) error {
+	statuses := set.getStatuses(ageWarn, now)
+
+	var statusesJSON []byte
+	var err error
+
+	if compact {
+		statusesJSON, err = json.Marshal(statuses)
+	} else {
+		statusesJSON, err = json.MarshalIndent(statuses, "", "  ")
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed to encode instances status: %s", err)
+	}
+
+	if _, err := fmt.Fprintf(w, "%s\n", statusesJSON); err != nil {
+		return fmt.Errorf("Failed to write instances status: %s", err)
+	}
+
+	return nil
+}
+
+// StatusJSON writes processes status to w as a JSON array.
+// If compact is false, the output is indented
+func (set *ProcessesSet) StatusJSON(w io.Writer, compact bool, ageWarn time.Duration) error {
+	return set.writeStatusJSON(w, compact, ageWarn, time.Now())
+}
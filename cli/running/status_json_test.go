@@ -0,0 +1,121 @@
+package running
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusJSON(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	now := time.Now()
+
+	processes := ProcessesSet{
+		&Process{ID: "myapp.router", Status: procStatusRunning, pid: 101, startTime: now.Add(-90 * time.Second)},
+		&Process{ID: "myapp.storage", Status: procStatusStopped},
+		&Process{ID: "myapp.broken", Status: procStatusError, Error: fmt.Errorf("PID file exists with unknown format")},
+	}
+
+	var compactBuf bytes.Buffer
+	// not running instances don't fail the command, exit code is set by --exit-code
+	assert.Nil(processes.writeStatusJSON(&compactBuf, true, 0, now))
+
+	var prettyBuf bytes.Buffer
+	assert.Nil(processes.writeStatusJSON(&prettyBuf, false, 0, now))
+
+	// compact output is a single line
+	compactOutput := compactBuf.String()
+	assert.True(strings.HasSuffix(compactOutput, "\n"))
+	assert.NotContains(strings.TrimSuffix(compactOutput, "\n"), "\n")
+
+	assert.Equal(
+		`[{"id":"myapp.router","status":"RUNNING","pid":101,"uptime_seconds":90},`+
+			`{"id":"myapp.storage","status":"STOPPED"},`+
+			`{"id":"myapp.broken","status":"FAILED","error":"PID file exists with unknown format"}]`+"\n",
+		compactOutput,
+	)
+
+	// pretty output is indented
+	prettyOutput := prettyBuf.String()
+	assert.Contains(prettyOutput, "\n  {\n    \"id\": \"myapp.router\",\n")
+
+	// parsed content is the same
+	var compactParsed, prettyParsed []map[string]interface{}
+	assert.Nil(json.Unmarshal(compactBuf.Bytes(), &compactParsed))
+	assert.Nil(json.Unmarshal(prettyBuf.Bytes(), &prettyParsed))
+
+	assert.Len(compactParsed, 3)
+	assert.Equal(compactParsed, prettyParsed)
+}
+
+func TestStatusJSONPaths(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	processes := ProcessesSet{
+		&Process{
+			ID:          "myapp.router",
+			Status:      procStatusStopped,
+			pidFile:     "/var/run/tarantool/myapp.router.pid",
+			consoleSock: "/var/run/tarantool/myapp.router.control",
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.Nil(processes.writeStatusJSON(&buf, true, 0, time.Now()))
+
+	assert.Equal(
+		`[{"id":"myapp.router","status":"STOPPED",`+
+			`"pid_file":"/var/run/tarantool/myapp.router.pid",`+
+			`"console_sock":"/var/run/tarantool/myapp.router.control"}]`+"\n",
+		buf.String(),
+	)
+}
+
+func TestStatusJSONAgeWarn(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	now := time.Now()
+
+	processes := ProcessesSet{
+		&Process{ID: "myapp.router", Status: procStatusRunning, pid: 101, startTime: now.Add(-30 * time.Second)},
+		&Process{ID: "myapp.storage", Status: procStatusRunning, pid: 102, startTime: now.Add(-90 * time.Second)},
+	}
+
+	// young instances don't fail the command
+	var buf bytes.Buffer
+	assert.Nil(processes.writeStatusJSON(&buf, true, time.Minute, now))
+
+	assert.Equal(
+		`[{"id":"myapp.router","status":"RUNNING","pid":101,"uptime_seconds":30,"warn":true},`+
+			`{"id":"myapp.storage","status":"RUNNING","pid":102,"uptime_seconds":90}]`+"\n",
+		buf.String(),
+	)
+
+	// age isn't checked if it isn't specified
+	buf.Reset()
+	assert.Nil(processes.writeStatusJSON(&buf, true, 0, now))
+	assert.NotContains(buf.String(), "warn")
+}
+
+func TestCheckStatusFormat(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	assert.Nil(CheckStatusFormat("text"))
+	assert.Nil(CheckStatusFormat("json"))
+	assert.Nil(CheckStatusFormat("table"))
+	assert.EqualError(CheckStatusFormat("yaml"), `Unknown format "yaml". Supported formats are text and json`)
+}
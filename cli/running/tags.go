@@ -0,0 +1,149 @@
+package running
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/fatih/color"
+
+	"github.com/tarantool/cartridge-cli/cli/common"
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+const (
+	tagsConfKey = "tags"
+
+	tagHealthHealthy  = "HEALTHY"
+	tagHealthDegraded = "DEGRADED"
+	tagHealthDown     = "DOWN"
+)
+
+var (
+	tagHealthStrings = map[string]string{
+		tagHealthHealthy:  color.New(color.FgGreen).Sprintf(tagHealthHealthy),
+		tagHealthDegraded: color.New(color.FgYellow).Sprintf(tagHealthDegraded),
+		tagHealthDown:     color.New(color.FgRed).Sprintf(tagHealthDown),
+	}
+)
+
+// tagRollup describes the state of the instances marked with the tag
+type tagRollup struct {
+	Tag     string
+	Running int
+	Total   int
+}
+
+// Health returns HEALTHY if all tagged instances are running,
+// DOWN if none of them is running and DEGRADED otherwise
+func (rollup *tagRollup) Health() string {
+	switch rollup.Running {
+	case rollup.Total:
+		return tagHealthHealthy
+	case 0:
+		return tagHealthDown
+	default:
+		return tagHealthDegraded
+	}
+}
+
+func (rollup *tagRollup) String() string {
+	return fmt.Sprintf("%s: %d/%d running %s",
+		rollup.Tag, rollup.Running, rollup.Total, tagHealthStrings[rollup.Health()])
+}
+
+// CollectInstancesTagsFromConf returns tags specified in the `tags` section
+// of the instances configuration files by processes IDs (<app-name>.<instance-name>)
+func CollectInstancesTagsFromConf(ctx *context.Ctx) (map[string][]string, error) {
+	instancesTags := make(map[string][]string)
+
+	confFilePaths, err := getConfFilePaths(ctx.Running.ConfPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, confFilePath := range confFilePaths {
+		instancesMap, err := common.ParseYmlFile(confFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read configuration from file: %s", err)
+		}
+
+		for instanceID, instanceConfRaw := range instancesMap {
+			if !strings.HasPrefix(instanceID, fmt.Sprintf("%s.", ctx.Project.Name)) || instanceConfRaw == nil {
+				continue
+			}
+
+			instanceConf, err := common.ConvertToMapWithStringKeys(instanceConfRaw)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to parse %s configuration: %s", instanceID, err)
+			}
+
+			tagsRaw, found := instanceConf[tagsConfKey]
+			if !found {
+				continue
+			}
+
+			tags, err := common.ConvertToStringsSlice(tagsRaw)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to parse %s tags: %s", instanceID, err)
+			}
+
+			instancesTags[instanceID] = tags
+		}
+	}
+
+	return instancesTags, nil
+}
+
+// getTagsRollups returns rollups sorted by tag.
+// Instance with multiple tags is counted under each of them
+func getTagsRollups(processes *ProcessesSet, instancesTags map[string][]string) []*tagRollup {
+	rollupsByTags := make(map[string]*tagRollup)
+
+	for _, process := range *processes {
+		countedTags := make(map[string]bool)
+
+		for _, tag := range instancesTags[process.ID] {
+			if countedTags[tag] {
+				continue
+			}
+			countedTags[tag] = true
+
+			rollup, found := rollupsByTags[tag]
+			if !found {
+				rollup = &tagRollup{Tag: tag}
+				rollupsByTags[tag] = rollup
+			}
+
+			rollup.Total++
+			if process.IsRunning() {
+				rollup.Running++
+			}
+		}
+	}
+
+	rollups := make([]*tagRollup, 0, len(rollupsByTags))
+	for _, rollup := range rollupsByTags {
+		rollups = append(rollups, rollup)
+	}
+
+	sort.Slice(rollups, func(i, j int) bool {
+		return rollups[i].Tag < rollups[j].Tag
+	})
+
+	return rollups
+}
+
+func (set *ProcessesSet) TagsStatus(instancesTags map[string][]string) {
+	rollups := getTagsRollups(set, instancesTags)
+	if len(rollups) == 0 {
+		log.Warnf("Specified instances have no tags")
+		return
+	}
+
+	log.Infof("Status by tags:")
+	for _, rollup := range rollups {
+		log.Infof(rollup.String())
+	}
+}
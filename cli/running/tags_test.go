@@ -0,0 +1,104 @@
+package running
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarantool/cartridge-cli/cli/context"
+)
+
+func TestGetTagsRollups(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	processes := ProcessesSet{
+		&Process{ID: "myapp.router-1", Status: procStatusRunning},
+		&Process{ID: "myapp.router-2", Status: procStatusStopped},
+		&Process{ID: "myapp.s1-master", Status: procStatusRunning},
+		&Process{ID: "myapp.s1-replica", Status: procStatusRunning},
+		&Process{ID: "myapp.s2-master", Status: procStatusNotStarted},
+		&Process{ID: "myapp.untagged", Status: procStatusRunning},
+	}
+
+	instancesTags := map[string][]string{
+		"myapp.router-1":   {"router", "api"},
+		"myapp.router-2":   {"router", "api", "router"},
+		"myapp.s1-master":  {"storage"},
+		"myapp.s1-replica": {"storage"},
+		"myapp.s2-master":  {"storage", "cold"},
+	}
+
+	rollups := getTagsRollups(&processes, instancesTags)
+	assert.Equal([]*tagRollup{
+		{Tag: "api", Running: 1, Total: 2},
+		{Tag: "cold", Running: 0, Total: 1},
+		{Tag: "router", Running: 1, Total: 2},
+		{Tag: "storage", Running: 2, Total: 3},
+	}, rollups)
+
+	assert.Equal(tagHealthDegraded, rollups[0].Health())
+	assert.Equal(tagHealthDown, rollups[1].Health())
+	assert.Equal(tagHealthDegraded, rollups[2].Health())
+	assert.Equal(tagHealthDegraded, rollups[3].Health())
+
+	// all instances are running
+	for _, process := range processes {
+		process.Status = procStatusRunning
+	}
+
+	rollups = getTagsRollups(&processes, instancesTags)
+	for _, rollup := range rollups {
+		assert.Equal(rollup.Total, rollup.Running)
+		assert.Equal(tagHealthHealthy, rollup.Health())
+	}
+
+	// no tags
+	assert.Len(getTagsRollups(&processes, map[string][]string{}), 0)
+}
+
+func TestCollectInstancesTagsFromConf(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "conf")
+	assert.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	var ctx context.Ctx
+	ctx.Project.Name = "myapp"
+	ctx.Running.ConfPath = filepath.Join(tmpDir, "instances.yml")
+
+	confContent := `
+myapp.router:
+  advertise_uri: localhost:3301
+  tags: [router, api]
+myapp.s1-master:
+  advertise_uri: localhost:3302
+  tags:
+    - storage
+myapp.s1-replica:
+  advertise_uri: localhost:3303
+otherapp.router:
+  tags: [router]
+`
+	assert.Nil(ioutil.WriteFile(ctx.Running.ConfPath, []byte(confContent), 0644))
+
+	instancesTags, err := CollectInstancesTagsFromConf(&ctx)
+	assert.Nil(err)
+	assert.Equal(map[string][]string{
+		"myapp.router":    {"router", "api"},
+		"myapp.s1-master": {"storage"},
+	}, instancesTags)
+
+	// bad tags
+	assert.Nil(ioutil.WriteFile(ctx.Running.ConfPath, []byte("myapp.router:\n  tags: router\n"), 0644))
+
+	_, err = CollectInstancesTagsFromConf(&ctx)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Failed to parse myapp.router tags")
+}
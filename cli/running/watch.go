@@ -0,0 +1,61 @@
+package running
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/apex/log"
+)
+
+const (
+	clearScreenSeq = "\033[H\033[2J"
+)
+
+// Watch clears the terminal and calls redraw every interval
+// until SIGINT or SIGTERM is received, like watch(1) does.
+// Terminal resize causes the immediate redraw.
+// Redraw errors are shown, but don't stop watching
+func Watch(interval time.Duration, redraw func() error) {
+	stopCh := make(chan os.Signal, 1)
+	signal.Notify(stopCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(stopCh)
+
+	resizeCh := make(chan os.Signal, 1)
+	signal.Notify(resizeCh, syscall.SIGWINCH)
+	defer signal.Stop(resizeCh)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	watchLoop(os.Stdout, interval, redraw, ticker.C, resizeCh, stopCh)
+}
+
+func watchLoop(w io.Writer, interval time.Duration, redraw func() error,
+	tickCh <-chan time.Time, resizeCh <-chan os.Signal, stopCh <-chan os.Signal) {
+
+	draw := func() {
+		fmt.Fprint(w, clearScreenSeq)
+		fmt.Fprintf(w, "Every %s: cartridge status    %s\n\n", interval, time.Now().Format(time.RFC1123))
+
+		if err := redraw(); err != nil {
+			log.Error(err.Error())
+		}
+	}
+
+	draw()
+
+	for {
+		select {
+		case <-tickCh:
+			draw()
+		case <-resizeCh:
+			draw()
+		case <-stopCh:
+			return
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package running
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchLoop(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	tickCh := make(chan time.Time, 1)
+	resizeCh := make(chan os.Signal, 1)
+	stopCh := make(chan os.Signal, 1)
+
+	tickCh <- time.Now()
+	resizeCh <- syscall.SIGWINCH
+
+	// initial draw, tick and resize redraws
+	redrawsCount := 0
+	redraw := func() error {
+		redrawsCount++
+		if redrawsCount == 3 {
+			stopCh <- os.Interrupt
+		}
+
+		return fmt.Errorf("Redraw failed")
+	}
+
+	var buf bytes.Buffer
+	watchLoop(&buf, 2*time.Second, redraw, tickCh, resizeCh, stopCh)
+
+	assert.Equal(3, redrawsCount)
+	assert.Equal(3, strings.Count(buf.String(), clearScreenSeq))
+	assert.Equal(3, strings.Count(buf.String(), "Every 2s: cartridge status"))
+}
@@ -2,6 +2,7 @@ package running
 
 import (
 	"bytes"
+	"hash/fnv"
 	"io"
 	"os"
 	"regexp"
@@ -10,8 +11,7 @@ import (
 )
 
 var (
-	currentPrefixColor = 0
-	prefixColors       = []color.Attribute{
+	prefixColors = []color.Attribute{
 		color.FgHiBlue,
 		color.FgHiCyan,
 		color.FgHiMagenta,
@@ -89,19 +89,23 @@ func (w *ColorizedWriter) Close() error {
 	return nil
 }
 
-func nexPrefixColor() *color.Color {
-	c := color.New(prefixColors[currentPrefixColor%len(prefixColors)])
-	currentPrefixColor++
+// getPrefixColorAttr returns the prefix color chosen by the prefix hash,
+// so the same instance has the same color on each run
+func getPrefixColorAttr(prefix string) color.Attribute {
+	hash := fnv.New32a()
+	hash.Write([]byte(prefix))
 
-	return c
+	return prefixColors[hash.Sum32()%uint32(len(prefixColors))]
 }
 
+// newColorizedWriter returns the writer that prefixes each line.
+// Colors aren't used if stdout isn't a terminal or color.NoColor is set
 func newColorizedWriter(prefix string) *ColorizedWriter {
 	writer := ColorizedWriter{
 		out: os.Stdout,
 	}
 
-	prefixColor := nexPrefixColor()
+	prefixColor := color.New(getPrefixColorAttr(prefix))
 	writer.prefix = prefixColor.Sprintf("%s | ", prefix)
 	return &writer
 }
@@ -62,3 +62,20 @@ func TestWrite(t *testing.T) {
 	assert.Equal(len(logBytes), n)
 	assert.Equal(getLogsWithPrefix(id, logs), out.String())
 }
+
+func TestGetPrefixColorAttr(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	ids := []string{"myapp.router", "myapp.s1-master", "myapp.s1-replica", "myapp-stateboard"}
+
+	for _, id := range ids {
+		colorAttr := getPrefixColorAttr(id)
+		assert.Contains(prefixColors, colorAttr, id)
+
+		// color is the same for each writer
+		assert.Equal(colorAttr, getPrefixColorAttr(id), id)
+		assert.Equal(newColorizedWriter(id).prefix, newColorizedWriter(id).prefix, id)
+	}
+}
@@ -27,6 +27,14 @@ type FileTreeTemplate struct {
 	Dirs  []DirTemplate
 }
 
+// RenderedEntry describes a file or a directory created on template instantiating
+type RenderedEntry struct {
+	Path    string
+	Mode    os.FileMode
+	IsDir   bool
+	Content string
+}
+
 // Template is the interface that has Instantiate method
 type Template interface {
 	Instantiate(destDir string, ctx interface{}) error
@@ -78,14 +86,68 @@ func (tmpl *FileTreeTemplate) Instantiate(destDir string, ctx interface{}) error
 	return nil
 }
 
-// Instantiate instantiates file template
-func (t *FileTemplate) Instantiate(destDir string, ctx interface{}) error {
-	var err error
+// Render renders file tree template in memory, nothing is written on disk.
+// Entries paths are relative to the destination directory,
+// directories go first as on instantiating
+func (tmpl *FileTreeTemplate) Render(ctx interface{}) ([]RenderedEntry, error) {
+	var entries []RenderedEntry
+
+	for _, d := range tmpl.Dirs {
+		dirPath, err := GetTemplatedStr(&d.Path, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get dir path by template: %s", d.Path)
+		}
+
+		entries = append(entries, RenderedEntry{
+			Path:  filepath.Clean(dirPath),
+			Mode:  d.Mode,
+			IsDir: true,
+		})
+	}
+
+	for _, t := range tmpl.Files {
+		filePath, content, err := t.Render(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to render file %s: %s", t.Path, err)
+		}
 
+		entries = append(entries, RenderedEntry{
+			Path:    filepath.Clean(filePath),
+			Mode:    t.Mode,
+			Content: content,
+		})
+	}
+
+	return entries, nil
+}
+
+// Render returns templated file path and content
+func (t *FileTemplate) Render(ctx interface{}) (string, string, error) {
 	// get a file path
 	filePath, err := GetTemplatedStr(&t.Path, ctx)
 	if err != nil {
-		return fmt.Errorf("Failed to get file path by template: %s", t.Path)
+		return "", "", fmt.Errorf("Failed to get file path by template: %s", t.Path)
+	}
+
+	// get templated content
+	fileContentTmpl, err := template.New("content").Parse(t.Content)
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to parse a file content template: %s", t.Path)
+	}
+
+	contentBuf := new(bytes.Buffer)
+	if err := fileContentTmpl.Execute(contentBuf, ctx); err != nil {
+		return "", "", fmt.Errorf("Failed to template a file %s content: %s", t.Path, err)
+	}
+
+	return filePath, contentBuf.String(), nil
+}
+
+// Instantiate instantiates file template
+func (t *FileTemplate) Instantiate(destDir string, ctx interface{}) error {
+	filePath, content, err := t.Render(ctx)
+	if err != nil {
+		return err
 	}
 
 	// create a file
@@ -97,13 +159,8 @@ func (t *FileTemplate) Instantiate(destDir string, ctx interface{}) error {
 	defer f.Close()
 
 	// write templated content to file
-	fileContentTmpl, err := template.New("content").Parse(t.Content)
-	if err != nil {
-		return fmt.Errorf("Failed to parse a file content template: %s", t.Path)
-	}
-
-	if err := fileContentTmpl.Execute(f, ctx); err != nil {
-		return fmt.Errorf("Failed to template a file %s content: %s", t.Path, err)
+	if _, err := f.WriteString(content); err != nil {
+		return fmt.Errorf("Failed to write file %s content: %s", t.Path, err)
 	}
 
 	return nil
@@ -19,6 +19,7 @@ require (
 	github.com/gogo/protobuf v1.3.1 // indirect
 	github.com/hashicorp/go-version v1.2.0
 	github.com/hpcloud/tail v1.0.0
+	github.com/klauspost/compress v1.11.7
 	github.com/magefile/mage v1.9.0
 	github.com/mattn/go-isatty v0.0.12
 	github.com/opencontainers/go-digest v1.0.0 // indirect
@@ -32,6 +33,7 @@ require (
 	github.com/spf13/pflag v1.0.5
 	github.com/stretchr/testify v1.6.1
 	github.com/yuin/gopher-lua v0.0.0-20191220021717-ab39c6098bdb
+	golang.org/x/crypto v0.0.0-20201221181555-eec23a3978ad
 	golang.org/x/lint v0.0.0-20200302205851-738671d3881b // indirect
 	golang.org/x/sys v0.0.0-20200918174421-af09f7315aff
 	golang.org/x/tools v0.0.0-20200609124132-5359b67ffbdf // indirect
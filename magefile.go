@@ -203,7 +203,7 @@ func GenerateGoCode() error {
 	return nil
 }
 
-// Generate completion scripts for bash and zsh
+// Generate completion scripts for bash, zsh, fish and PowerShell
 func GenCompletion() error {
 	if err := Build(); err != nil {
 		return err
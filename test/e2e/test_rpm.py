@@ -98,3 +98,84 @@ def test_rpm(container_with_installed_rpm, tmpdir):
     container.start()
     check_systemd_service(container, project, http_port, tmpdir)
     container.stop()
+
+
+def test_rpm_header_signature(cartridge_cmd, project_without_dependencies, docker_client, tmpdir, request):
+    project = project_without_dependencies
+
+    # generate GPG key
+    gnupg_home = os.path.join(tmpdir, 'gnupg')
+    os.makedirs(gnupg_home, mode=0o700)
+
+    env = os.environ.copy()
+    env['GNUPGHOME'] = gnupg_home
+
+    key_user_id = 'test@cartridge-cli.io'
+    key_params = '\n'.join([
+        '%no-protection',
+        'Key-Type: RSA',
+        'Key-Length: 2048',
+        'Name-Real: Cartridge CLI Test',
+        'Name-Email: {}'.format(key_user_id),
+        'Expire-Date: 0',
+        '%commit',
+    ])
+
+    process = subprocess.run(['gpg', '--batch', '--gen-key'], input=key_params.encode('utf-8'), env=env)
+    assert process.returncode == 0, "Failed to generate GPG key"
+
+    build_path = os.path.join(tmpdir, 'build_image')
+    os.makedirs(build_path)
+
+    with open(os.path.join(build_path, 'key.asc'), 'w') as f:
+        process = subprocess.run(['gpg', '--armor', '--export', key_user_id], stdout=f, env=env)
+        assert process.returncode == 0, "Failed to export GPG public key"
+
+    # pack signed RPM
+    cmd = [
+        cartridge_cmd,
+        "pack", "rpm",
+        "--sign-key", key_user_id,
+        "--sign-header",
+        project.path,
+    ]
+
+    if platform.system() == 'Darwin':
+        cmd.append("--use-docker")
+
+    process = subprocess.run(cmd, cwd=tmpdir, env=env)
+    assert process.returncode == 0, "Error during creating of signed rpm archive with project"
+
+    filepath = find_archive(tmpdir, project.name, 'rpm')
+    assert filepath is not None, "RPM archive isn't found in work directory"
+
+    shutil.copy(filepath, build_path)
+    rpm_filename = os.path.basename(filepath)
+
+    # verify signatures using rpmkeys
+    with open(os.path.join(build_path, 'Dockerfile'), 'w') as f:
+        f.write('\n'.join([
+            "FROM centos:7",
+            "COPY key.asc {} /opt/".format(rpm_filename),
+            "RUN rpmkeys --import /opt/key.asc",
+        ]))
+
+    image_name = '%s-test-rpm-signature' % project.name
+    build_image(build_path, image_name)
+
+    request.addfinalizer(lambda: delete_image(docker_client, image_name))
+
+    output = docker_client.containers.run(
+        image_name,
+        command='rpmkeys -Kv /opt/{}'.format(rpm_filename),
+        remove=True,
+    ).decode('utf-8')
+
+    header_signature_lines = [
+        line.strip() for line in output.splitlines()
+        if line.strip().startswith('Header V4 RSA/SHA256 Signature')
+    ]
+
+    assert len(header_signature_lines) == 1, output
+    assert header_signature_lines[0].endswith('OK'), output
+    assert 'NOT OK' not in output
@@ -106,6 +106,20 @@ local func_rets_non_str = {
     call = function() return 666 end,
 }
 
+local func_rets_table = {
+    usage = 'func_rets_table usage',
+    call = function()
+        return {
+            name = 'Elizabeth',
+            age = 23,
+            cakes = {'cheesecake', 'tiramisu'},
+            loves_cakes = true,
+            opaque = newproxy(),
+            callback = function() end,
+        }
+    end,
+}
+
 local func_conflicting = {
     usage = 'func_conflicting usage',
     args = {},
@@ -116,7 +130,7 @@ local func_conflicting = {
 
 local conflicting_names = {
     "name", "instance", "run_dir", "data_dir", "list", "help",
-    "debug", "quiet", "verbose",
+    "debug", "quiet", "verbose", "format",
 }
 
 for _, argname in ipairs(conflicting_names) do
@@ -164,6 +178,7 @@ assert(cli_admin.register('func_no_args', func_no_args.usage, func_no_args.args,
 assert(cli_admin.register('func_long_arg', func_long_arg.usage, func_long_arg.args, func_long_arg.call))
 assert(cli_admin.register('func_rets_str', func_rets_str.usage, func_rets_str.args, func_rets_str.call))
 assert(cli_admin.register('func_rets_non_str', func_rets_non_str.usage, func_rets_non_str.args, func_rets_non_str.call))
+assert(cli_admin.register('func_rets_table', func_rets_table.usage, func_rets_table.args, func_rets_table.call))
 assert(cli_admin.register('func_conflicting', func_conflicting.usage, func_conflicting.args, func_conflicting.call))
 assert(cli_admin.register('func_rets_err', func_rets_err.usage, func_rets_err.args, func_rets_err.call))
 assert(cli_admin.register('func_raises_err', func_raises_err.usage, func_raises_err.args, func_raises_err.call))
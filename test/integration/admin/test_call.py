@@ -1,3 +1,6 @@
+import json
+import subprocess
+
 from utils import run_command_and_get_output
 from utils import get_log_lines
 
@@ -174,3 +177,59 @@ def test_print(cartridge_cmd, custom_admin_running_instances, tmpdir):
     assert get_log_lines(output) == iterations_output + [
         '• I am some great result',
     ]
+
+
+def test_func_rets_json(cartridge_cmd, custom_admin_running_instances, tmpdir):
+    project = custom_admin_running_instances['project']
+    run_dir = project.get_run_dir()
+
+    cmd = [
+        cartridge_cmd, 'admin',
+        '--name', project.name,
+        '--run-dir', run_dir,
+        '--format', 'json',
+        'func_rets_table',
+    ]
+    process = subprocess.run(cmd, cwd=tmpdir, stdout=subprocess.PIPE, stderr=subprocess.PIPE)
+    assert process.returncode == 0
+
+    assert json.loads(process.stdout.decode('utf-8')) == {
+        'name': 'Elizabeth',
+        'age': 23,
+        'cakes': ['cheesecake', 'tiramisu'],
+        'loves_cakes': True,
+        'opaque': '<non-serializable: userdata>',
+        'callback': '<non-serializable: function>',
+    }
+
+    # pushed messages don't get into stdout
+    cmd = [
+        cartridge_cmd, 'admin',
+        '--name', project.name,
+        '--run-dir', run_dir,
+        '--format', 'json',
+        'func_print',
+    ]
+    process = subprocess.run(cmd, cwd=tmpdir, stdout=subprocess.PIPE, stderr=subprocess.PIPE)
+    assert process.returncode == 0
+
+    assert json.loads(process.stdout.decode('utf-8')) == 'I am some great result'
+    assert 'Iteration 1 (pushed)' in process.stderr.decode('utf-8')
+
+
+def test_bad_format(cartridge_cmd, custom_admin_running_instances, tmpdir):
+    project = custom_admin_running_instances['project']
+    run_dir = project.get_run_dir()
+
+    cmd = [
+        cartridge_cmd, 'admin',
+        '--name', project.name,
+        '--run-dir', run_dir,
+        '--format', 'yaml',
+        'func_rets_table',
+    ]
+    rc, output = run_command_and_get_output(cmd, cwd=tmpdir)
+    assert rc == 1
+
+    assert 'Invalid argument "yaml" for "--format" flag: Unsupported format "yaml". ' + \
+        'Supported formats are: json' in output
@@ -134,7 +134,7 @@ def test_conflicting_argnames(cartridge_cmd, custom_admin_running_instances, adm
     assert rc == exp_rc
 
     argnames = [
-        "debug", "help", "instance", "list", "name", "quiet", "run_dir", "verbose",
+        "debug", "format", "help", "instance", "list", "name", "quiet", "run_dir", "verbose",
     ]
     exp_err = 'Function has arguments with names that conflict with `cartridge admin` flags: %s' % ', '.join(
         ['"%s"' % argname for argname in argnames]
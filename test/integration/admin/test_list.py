@@ -27,4 +27,5 @@ def test_list(cartridge_cmd, custom_admin_running_instances, tmpdir):
         'func_rets_err      func_rets_err usage',
         'func_rets_non_str  func_rets_non_str usage',
         'func_rets_str      func_rets_str usage',
+        'func_rets_table    func_rets_table usage',
     ]
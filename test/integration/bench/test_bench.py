@@ -0,0 +1,28 @@
+import pytest
+
+from utils import run_command_and_get_output
+
+
+@pytest.mark.parametrize('flags,exp_err', [
+    (['--connections', '0'], 'Invalid argument 0 for "--connections" flag: should be positive'),
+    (['--count', '-1'], 'Invalid argument -1 for "--count" flag: should be positive'),
+    (['--count', '10', '--duration', '1s'], 'You can specify only one of --count and --duration options'),
+    (['--duration', '0s'], 'Invalid argument "0s" for "--duration" flag: should be positive'),
+    (['--payload-size', '-1'], 'Invalid argument -1 for "--payload-size" flag: should be positive'),
+    (['--read-ratio', '1.5'], 'Invalid argument 1.5 for "--read-ratio" flag: should be in range [0, 1]'),
+    (['--format', 'yaml'], 'Invalid argument "yaml" for "--format" flag: Unknown format "yaml"'),
+])
+def test_bad_flags(cartridge_cmd, tmpdir, flags, exp_err):
+    cmd = [cartridge_cmd, 'bench', 'localhost:3301'] + flags
+
+    rc, output = run_command_and_get_output(cmd, cwd=tmpdir)
+    assert rc == 1
+    assert exp_err in output
+
+
+def test_bad_uri(cartridge_cmd, tmpdir):
+    cmd = [cartridge_cmd, 'bench', 'bad-host:3301', '--count', '1']
+
+    rc, output = run_command_and_get_output(cmd, cwd=tmpdir)
+    assert rc == 1
+    assert 'Failed to connect to bad-host:3301' in output
@@ -13,6 +13,8 @@ def test_completion(cartridge_cmd, tmpdir):
     comp_names = [
         "completion/bash/cartridge",
         "completion/zsh/_cartridge",
+        "completion/fish/cartridge.fish",
+        "completion/powershell/cartridge.ps1",
     ]
 
     for comp_name in comp_names:
@@ -2,6 +2,8 @@ from integration.connect.utils import assert_successful_piped_commands
 from integration.connect.utils import assert_error
 from integration.connect.utils import assert_exited_piped_commands
 from integration.connect.utils import assert_session_push_commands
+from integration.connect.utils import Command
+from integration.connect.utils import run_commands_in_pipe
 
 
 def test_bad_uri(cartridge_cmd, project_with_instances):
@@ -36,6 +38,7 @@ def test_uri_piped(cartridge_cmd, project_with_instances):
         cartridge_cmd, 'connect', router.advertise_uri,
         '--username', 'admin',
         '--password', '%s-cluster-cookie' % project.name,
+        '--format', 'yaml',
     ]
 
     assert_successful_piped_commands(project, cmd, exp_connect='%s.%s' % (project.name, router.name))
@@ -50,11 +53,38 @@ def test_socket_piped(cartridge_cmd, project_with_instances):
 
     cmd = [
         cartridge_cmd, 'connect', console_sock_path,
+        '--format', 'yaml',
     ]
 
     assert_successful_piped_commands(project, cmd, exp_connect='%s.%s' % (project.name, router.name))
 
 
+def test_socket_piped_default_format(cartridge_cmd, project_with_instances):
+    project = project_with_instances.project
+    instances = project_with_instances.instances
+
+    router = instances['router']
+    console_sock_path = project.get_console_sock(router.name)
+
+    cmd = [
+        cartridge_cmd, 'connect', console_sock_path,
+    ]
+
+    commands = [
+        Command('return 666', lua_output='666'),
+        Command("return {a = 1, b = 'str'}", lua_output='{a = 1, b = "str"}'),
+    ]
+
+    rc, output = run_commands_in_pipe(project, cmd, commands)
+    assert rc == 0
+
+    connected_line, commands_output = output.split('\n', maxsplit=1)
+    assert connected_line == 'connected to %s.%s' % (project.name, router.name)
+
+    exp_output = '\n'.join(c.exp_output for c in commands)+'\n'
+    assert commands_output == exp_output
+
+
 def test_socket_no_title(cartridge_cmd, project_with_instances_no_cartridge):
     project = project_with_instances_no_cartridge.project
     instances = project_with_instances_no_cartridge.instances
@@ -64,6 +94,7 @@ def test_socket_no_title(cartridge_cmd, project_with_instances_no_cartridge):
 
     cmd = [
         cartridge_cmd, 'connect', console_sock_path,
+        '--format', 'yaml',
     ]
 
     assert_successful_piped_commands(project, cmd, exp_connect=console_sock_path)
@@ -107,6 +138,7 @@ def test_socket_session_push(cartridge_cmd, project_with_instances):
 
     cmd = [
         cartridge_cmd, 'connect', console_sock_path,
+        '--format', 'yaml',
     ]
 
     assert_session_push_commands(project, cmd, exp_connect='%s.%s' % (project.name, router.name))
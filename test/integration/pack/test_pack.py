@@ -217,6 +217,7 @@ def test_deb(deb_archive, tmpdir):
             assert 'chown root:root /etc/systemd/system/{}.service'.format(project.name) in postinst_script
             assert 'chown root:root /etc/systemd/system/{}@.service'.format(project.name) in postinst_script
             assert 'chown root:root /usr/lib/tmpfiles.d/{}.conf'.format(project.name) in postinst_script
+            assert 'chown root:root /usr/lib/sysusers.d/{}.conf'.format(project.name) in postinst_script
 
 
 @pytest.mark.parametrize('unit', ['unit', 'instantiated-unit', 'stateboard-unit'])
@@ -0,0 +1,90 @@
+import os
+import subprocess
+
+import yaml
+
+from utils import check_instances_running
+from utils import write_conf
+from utils import run_command_and_get_output
+
+from project import patch_cartridge_proc_titile
+
+
+def test_repair_reload_config(cartridge_cmd, start_stop_cli, project_with_cartridge, tmpdir):
+    project = project_with_cartridge
+    cli = start_stop_cli
+
+    cmd = [
+        cartridge_cmd,
+        "build",
+        project.path
+    ]
+    process = subprocess.run(cmd, cwd=tmpdir)
+    assert process.returncode == 0, "Error during building the project"
+
+    # patch cartridge.cfg to don't change process title
+    patch_cartridge_proc_titile(project)
+
+    # start instances
+    INSTANCE1 = 'instance-1'
+    INSTANCE2 = 'instance-2'
+
+    ID1 = project.get_instance_id(INSTANCE1)
+    ID2 = project.get_instance_id(INSTANCE2)
+
+    cfg = {
+        ID1: {
+            'advertise_uri': 'localhost:3301',
+            'http_port': 8081,
+        },
+        ID2: {
+            'advertise_uri': 'localhost:3302',
+            'http_port': 8082,
+        },
+    }
+
+    cfg_path = project.get_cfg_path()
+    write_conf(cfg_path, cfg)
+
+    cli.start(project, daemonized=True)
+    check_instances_running(cli, project, [INSTANCE1, INSTANCE2], daemonized=True)
+
+    # corrupt instances config
+    corrupted_cfg = {
+        ID1: {
+            'advertise_uri': 'localhost:3301',
+        },
+        'other-app.router': {
+            'advertise_uri': 'localhost:3401',
+        },
+    }
+    write_conf(cfg_path, corrupted_cfg)
+
+    cmd = [
+        cartridge_cmd, 'repair', 'reload-config',
+        '--name', project.name,
+        '--run-dir', project.get_run_dir(),
+        '--cfg', cfg_path,
+    ]
+
+    # dry run
+    rc, output = run_command_and_get_output(cmd + ['--dry-run'], cwd=tmpdir)
+    assert rc == 0, output
+
+    with open(cfg_path) as f:
+        assert yaml.safe_load(f) == corrupted_cfg
+
+    rc, output = run_command_and_get_output(cmd, cwd=tmpdir)
+    assert rc == 0, output
+
+    exp_cfg = cfg.copy()
+    exp_cfg['other-app.router'] = corrupted_cfg['other-app.router']
+
+    with open(cfg_path) as f:
+        assert yaml.safe_load(f) == exp_cfg
+
+    backup_path = '%s.bak' % cfg_path
+    assert os.path.exists(backup_path)
+
+    with open(backup_path) as f:
+        assert yaml.safe_load(f) == corrupted_cfg
@@ -17,6 +17,24 @@ APPNAME = 'myapp'
 OTHER_APP_NAME = 'other-app'
 
 
+def is_current_leader(conf, instance_uuid):
+    topology_conf = conf if conf.get('topology') is None else conf['topology']
+
+    instance_conf = topology_conf['servers'][instance_uuid]
+    if instance_conf == 'expelled':
+        return False
+
+    replicaset_conf = topology_conf['replicasets'].get(instance_conf['replicaset_uuid'])
+    if replicaset_conf is None:
+        return False
+
+    leaders = replicaset_conf['master']
+    if isinstance(leaders, str):
+        return leaders == instance_uuid
+
+    return len(leaders) > 0 and leaders[0] == instance_uuid
+
+
 def test_remove_uuid_does_not_exist(cartridge_cmd, clusterwide_conf_non_existent_instance, tmpdir):
     data_dir = os.path.join(tmpdir, 'tmp', 'data')
     os.makedirs(data_dir)
@@ -42,6 +60,46 @@ def test_remove_uuid_does_not_exist(cartridge_cmd, clusterwide_conf_non_existent
     )
 
 
+@pytest.mark.parametrize('conf_type', ['srv-last-in-rpl', 'leader-is-string'])
+def test_remove_leader(cartridge_cmd, conf_type, tmpdir,
+                       clusterwide_conf_srv_last_in_rpl,
+                       clusterwide_conf_current_leader_is_string):
+    data_dir = os.path.join(tmpdir, 'tmp', 'data')
+    os.makedirs(data_dir)
+
+    configs = {
+        'srv-last-in-rpl': clusterwide_conf_srv_last_in_rpl,
+        'leader-is-string': clusterwide_conf_current_leader_is_string,
+    }
+
+    config = configs[conf_type]
+    old_conf = copy.deepcopy(config.conf)
+    instance_uuid = config.instance_uuid
+
+    assert is_current_leader(old_conf, instance_uuid)
+
+    instances = ['instance-1', 'instance-2']
+    conf_paths = write_instances_topology_conf(data_dir, APPNAME, old_conf, instances)
+
+    cmd = [
+        cartridge_cmd, 'repair', 'remove-instance',
+        '--name', APPNAME,
+        '--data-dir', data_dir,
+        instance_uuid,
+    ]
+
+    rc, output = run_command_and_get_output(cmd, cwd=tmpdir)
+    assert rc == 1
+
+    assert_for_instances_group(
+        get_logs(output), instances, lambda line:
+        "Instance %s is the leader of replicaset %s" % (instance_uuid, config.replicaset_uuid) in line
+    )
+
+    # check config wasn't changed
+    assert_conf_not_changed(conf_paths, old_conf)
+
+
 @pytest.mark.parametrize('conf_type', [
     'simple', 'disabled', 'expelled', 'not-in-leaders', 'non-existent-rpl',
     'srv-last-in-rpl', 'srv-last-in-leaders', 'leader-is-string', 'one-file-config',
@@ -93,6 +151,10 @@ def test_remove(cartridge_cmd, conf_type, tmpdir,
         instance_uuid,
     ]
 
+    # replicaset leader is removed only with --remove-leader
+    if is_current_leader(old_conf, instance_uuid):
+        cmd.append('--remove-leader')
+
     rc, output = run_command_and_get_output(cmd, cwd=tmpdir)
     assert rc == 0
 
@@ -147,6 +209,9 @@ def test_remove_dry_run(cartridge_cmd, conf_type, tmpdir,
         instance_uuid,
     ]
 
+    if is_current_leader(old_conf, instance_uuid):
+        cmd.append('--remove-leader')
+
     rc, output = run_command_and_get_output(cmd, cwd=tmpdir)
     assert rc == 0
 
@@ -52,6 +52,32 @@ def test_bad_args(cartridge_cmd, conf_type, tmpdir,
     assert_for_instances_group(get_logs(output), instances, lambda line: exp_error in line)
 
 
+@pytest.mark.parametrize('new_uri', ['new-uri', ':666', 'new-uri:port', 'new-uri:70000'])
+def test_bad_uri(cartridge_cmd, new_uri, tmpdir, clusterwide_conf_simple):
+    data_dir = os.path.join(tmpdir, 'tmp', 'data')
+    os.makedirs(data_dir)
+
+    config = clusterwide_conf_simple
+
+    instances = ['instance-1', 'instance-2']
+    conf_paths = write_instances_topology_conf(data_dir, APPNAME, config.conf, instances)
+
+    cmd = [
+        cartridge_cmd, 'repair', 'set-advertise-uri',
+        '--name', APPNAME,
+        '--data-dir', data_dir,
+        config.instance_uuid, new_uri,
+    ]
+
+    rc, output = run_command_and_get_output(cmd, cwd=tmpdir)
+    assert rc == 1
+    assert 'Invalid advertise URI "%s"' % new_uri in output
+
+    # configs aren't changed
+    for conf_path in conf_paths:
+        assert not os.path.exists('%s.bak' % conf_path)
+
+
 @pytest.mark.parametrize('conf_type', ['simple', 'srv-disabled', 'one-file-config'])
 def test_set_uri(cartridge_cmd, conf_type, tmpdir,
                  clusterwide_conf_simple,
@@ -633,6 +633,38 @@ def test_project_with_non_existent_script(start_stop_cli, project_without_depend
     assert any(["Can't use instance entrypoint" in msg for msg in logs])
 
 
+def test_start_env_files(start_stop_cli, project_without_dependencies):
+    project = project_without_dependencies
+    cli = start_stop_cli
+
+    INSTANCE1 = 'instance-1'
+
+    env_file_path = os.path.join(project.path, '.env.local')
+    with open(env_file_path, 'w') as f:
+        f.write('\n'.join([
+            '# comment',
+            'TARANTOOL_CLUSTER_COOKIE=secret',
+            'TARANTOOL_HTTP_PORT',
+        ]))
+
+    logs = cli.start(project, [INSTANCE1], daemonized=True, env_files=[env_file_path],
+                     capture_output=True, exp_rc=1)
+    assert any([
+        'Failed to parse env file %s: line 3: Expected KEY=VALUE' % env_file_path in msg
+        for msg in logs
+    ])
+
+    logs = cli.start(project, [INSTANCE1], daemonized=True, env_files=['non-existent.env'],
+                     capture_output=True, exp_rc=1)
+    assert any(['Failed to open env file' in msg for msg in logs])
+
+    with open(env_file_path, 'w') as f:
+        f.write('TARANTOOL_CLUSTER_COOKIE="secret"  # comment\n')
+
+    cli.start(project, [INSTANCE1], daemonized=True, env_files=[env_file_path])
+    check_instances_running(cli, project, [INSTANCE1], daemonized=True)
+
+
 def test_start_with_timeout(start_stop_cli, project_without_dependencies):
     project = project_without_dependencies
     cli = start_stop_cli
@@ -118,7 +118,7 @@ class Cli():
 
     def start(self, project, instances=[], daemonized=False, stateboard=False, stateboard_only=False,
               cfg=None, script=None, run_dir=None, data_dir=None, log_dir=None, timeout=None,
-              capture_output=False, exp_rc=0):
+              env_files=[], capture_output=False, exp_rc=0):
         cmd = [self._cartridge_cmd, 'start']
         if daemonized:
             cmd.append('-d')
@@ -138,6 +138,8 @@ class Cli():
             cmd.extend(['--data-dir', data_dir])
         if log_dir is not None:
             cmd.extend(['--log-dir', log_dir])
+        for env_file in env_files:
+            cmd.extend(['--env-file', env_file])
 
         cmd.extend(instances)
 
@@ -430,6 +432,8 @@ def assert_filemode(project, filepath, filemode):
         assert filemode & 0o777 == 0o644
     elif filepath.startswith('/usr/lib/tmpfiles.d/'):
         assert filemode & 0o777 == 0o644
+    elif filepath.startswith('/usr/lib/sysusers.d/'):
+        assert filemode & 0o777 == 0o644
     elif filepath.startswith('/usr/share/tarantool/'):
         # a+r for files, a+rx for directories
         required_bits = 0o555 if os.path.isdir(filepath) else 0o444
@@ -440,7 +444,7 @@ def assert_filemodes(project, basedir):
     known_dirs = {
         'etc', 'etc/systemd', 'etc/systemd/system',
         'usr', 'usr/share', 'usr/share/tarantool',
-        'usr/lib', 'usr/lib/tmpfiles.d'
+        'usr/lib', 'usr/lib/tmpfiles.d', 'usr/lib/sysusers.d'
     }
     filenames = recursive_listdir(basedir) - known_dirs
 
@@ -568,7 +572,8 @@ def check_package_files(project, basedir):
             for prefix in [
                 os.path.join('usr/share/tarantool', project.name),
                 'etc/systemd/system',
-                'usr/lib/tmpfiles.d'
+                'usr/lib/tmpfiles.d',
+                'usr/lib/sysusers.d',
             ]
         ])
 
@@ -588,6 +593,11 @@ def check_package_files(project, basedir):
     with open(project_tmpfiles_conf_file) as f:
         assert f.read().find('d /var/run/tarantool') != -1
 
+    # check sysusers conf
+    project_sysusers_conf_file = os.path.join(basedir, 'usr/lib/sysusers.d', '%s.conf' % project.name)
+    with open(project_sysusers_conf_file) as f:
+        assert f.read().find('u tarantool') != -1
+
     # check version file
     validate_version_file(project, distribution_dir)
 